@@ -701,6 +701,50 @@ func sortedMethodNames(typ *types.Interface) []string {
 	return list
 }
 
+// sortedEmbeddeds returns the non-interface elements embedded in typ,
+// such as unions and approximation terms, sorted by their string form.
+// Methods of embedded interfaces are part of the method set of typ and
+// are reported by sortedMethodNames; their elements are included here.
+func (w *Walker) sortedEmbeddeds(typ *types.Interface) []string {
+	n := typ.NumEmbeddeds()
+	list := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		emb := typ.EmbeddedType(i)
+		if named, _ := emb.(*types.Named); named != nil && named.Obj().Pkg() == nil && named.Obj().Name() == "comparable" {
+			list = append(list, "comparable")
+			continue
+		}
+		switch u := emb.Underlying().(type) {
+		case *types.Interface:
+			list = append(list, w.sortedEmbeddeds(u)...)
+		case *types.Union:
+			list = append(list, w.typeString(u))
+		default:
+			list = append(list, w.typeString(emb))
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// writeTypeParams writes the type parameter list tparams, including the
+// constraints if withConstraints is set.
+func (w *Walker) writeTypeParams(buf *bytes.Buffer, tparams *types.TParamList, withConstraints bool) {
+	buf.WriteByte('[')
+	for i, n := 0, tparams.Len(); i < n; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		tpar := tparams.At(i)
+		w.writeType(buf, tpar)
+		if withConstraints {
+			buf.WriteByte(' ')
+			w.writeType(buf, tpar.Constraint())
+		}
+	}
+	buf.WriteByte(']')
+}
+
 func (w *Walker) writeType(buf *bytes.Buffer, typ types.Type) {
 	switch typ := typ.(type) {
 	case *types.Basic:
@@ -758,13 +802,26 @@ func (w *Walker) writeType(buf *bytes.Buffer, typ types.Type) {
 
 	case *types.Interface:
 		buf.WriteString("interface{")
-		if typ.NumMethods() > 0 {
+		elems := append(sortedMethodNames(typ), w.sortedEmbeddeds(typ)...)
+		if len(elems) > 0 {
 			buf.WriteByte(' ')
-			buf.WriteString(strings.Join(sortedMethodNames(typ), ", "))
+			buf.WriteString(strings.Join(elems, ", "))
 			buf.WriteByte(' ')
 		}
 		buf.WriteString("}")
 
+	case *types.Union:
+		for i, n := 0, typ.Len(); i < n; i++ {
+			if i > 0 {
+				buf.WriteString(" | ")
+			}
+			term := typ.Term(i)
+			if term.Tilde() {
+				buf.WriteByte('~')
+			}
+			w.writeType(buf, term.Type())
+		}
+
 	case *types.Map:
 		buf.WriteString("map[")
 		w.writeType(buf, typ.Key())
@@ -794,6 +851,21 @@ func (w *Walker) writeType(buf *bytes.Buffer, typ types.Type) {
 			buf.WriteByte('.')
 		}
 		buf.WriteString(typ.Obj().Name())
+		if targs := typ.TArgs(); targs.Len() > 0 {
+			buf.WriteByte('[')
+			for i := 0; i < targs.Len(); i++ {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				w.writeType(buf, targs.At(i))
+			}
+			buf.WriteByte(']')
+		}
+
+	case *types.TypeParam:
+		// Type parameter names are not part of the API and may
+		// change, so use the parameter index instead.
+		fmt.Fprintf(buf, "$%d", typ.Index())
 
 	default:
 		panic(fmt.Sprintf("unknown type %T", typ))
@@ -801,6 +873,9 @@ func (w *Walker) writeType(buf *bytes.Buffer, typ types.Type) {
 }
 
 func (w *Walker) writeSignature(buf *bytes.Buffer, sig *types.Signature) {
+	if tparams := sig.TParams(); tparams.Len() > 0 {
+		w.writeTypeParams(buf, tparams, true)
+	}
 	w.writeParams(buf, sig.Params(), sig.Variadic())
 	switch res := sig.Results(); res.Len() {
 	case 0:
@@ -868,6 +943,14 @@ func (w *Walker) emitObj(obj types.Object) {
 func (w *Walker) emitType(obj *types.TypeName) {
 	name := obj.Name()
 	typ := obj.Type()
+	if named, _ := typ.(*types.Named); named != nil {
+		if tparams := named.TParams(); tparams.Len() > 0 {
+			var buf bytes.Buffer
+			buf.WriteString(name)
+			w.writeTypeParams(&buf, tparams, true)
+			name = buf.String()
+		}
+	}
 	if obj.IsAlias() {
 		w.emitf("type %s = %s", name, w.typeString(typ))
 		return
@@ -960,13 +1043,14 @@ func (w *Walker) emitIfaceType(name string, typ *types.Interface) {
 		return
 	}
 
-	if len(methodNames) == 0 {
+	sort.Strings(methodNames)
+	elems := append(methodNames, w.sortedEmbeddeds(typ)...)
+	if len(elems) == 0 {
 		w.emitf("type %s interface {}", name)
 		return
 	}
 
-	sort.Strings(methodNames)
-	w.emitf("type %s interface { %s }", name, strings.Join(methodNames, ", "))
+	w.emitf("type %s interface { %s }", name, strings.Join(elems, ", "))
 }
 
 func (w *Walker) emitFunc(f *types.Func) {
@@ -990,7 +1074,13 @@ func (w *Walker) emitMethod(m *types.Selection) {
 			log.Fatalf("exported method with unexported receiver base type: %s", m)
 		}
 	}
-	w.emitf("method (%s) %s%s", w.typeString(recv), m.Obj().Name(), w.signatureString(sig))
+	tps := ""
+	if rparams := sig.RParams(); rparams.Len() > 0 {
+		var buf bytes.Buffer
+		w.writeTypeParams(&buf, rparams, false)
+		tps = buf.String()
+	}
+	w.emitf("method (%s%s) %s%s", w.typeString(recv), tps, m.Obj().Name(), w.signatureString(sig))
 }
 
 func (w *Walker) emitf(format string, args ...interface{}) {
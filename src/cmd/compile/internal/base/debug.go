@@ -26,10 +26,13 @@ var Debug DebugFlags
 // Each setting is name=value; for ints, name is short for name=1.
 type DebugFlags struct {
 	Append               int    `help:"print information about append compilation"`
+	CheckDeterminism     int    `help:"type check twice and report differences between the results"`
 	Checkptr             int    `help:"instrument unsafe pointer conversions"`
 	Closure              int    `help:"print information about closure compilation"`
+	Counters             int    `help:"print type checker counters at the end of compilation"`
 	DclStack             int    `help:"run internal dclstack check"`
 	Defer                int    `help:"print information about defer compilation"`
+	Deprecated           int    `help:"report uses of deprecated objects declared in the package being compiled"`
 	DisableNil           int    `help:"disable nil checks"`
 	DumpPtrs             int    `help:"show Node pointers values in dump output"`
 	DwarfInl             int    `help:"print information about DWARF inlined function creation"`
@@ -42,11 +45,13 @@ type DebugFlags struct {
 	NoOpenDefer          int    `help:"disable open-coded defers"`
 	PCTab                string `help:"print named pc-value table"`
 	Panic                int    `help:"show all compiler panics"`
+	ReduceCrash          int    `help:"print a reduced program when the type checker crashes"`
 	Slice                int    `help:"print information about slice compilation"`
 	SoftFloat            int    `help:"force compiler to emit soft-float code"`
 	SyncFrames           int    `help:"how many writer stack frames to include at sync points in unified export data"`
 	TypeAssert           int    `help:"print information about type assertion inlining"`
 	TypecheckInl         int    `help:"eager typechecking of inline function bodies"`
+	TypedDump            string `help:"print syntax tree of named declaration (name or type.method) with type checker information"`
 	Unified              int    `help:"enable unified IR construction"`
 	UnifiedQuirks        int    `help:"enable unified IR construction's quirks mode"`
 	WB                   int    `help:"print information about write barriers"`
@@ -7,6 +7,7 @@ package base
 import (
 	"fmt"
 	"io"
+	"runtime"
 	"strings"
 	"time"
 )
@@ -16,15 +17,23 @@ var Timer Timings
 // Timings collects the execution times of labeled phases
 // which are added trough a sequence of Start/Stop calls.
 // Events may be associated with each phase via AddEvent.
+// If memory statistics are enabled via EnableMemStats, the
+// amount of memory allocated in each phase is recorded as well.
 type Timings struct {
 	list   []timestamp
 	events map[int][]*event // lazily allocated
+	mem    bool             // record memory statistics
 }
 
 type timestamp struct {
 	time  time.Time
 	label string
 	start bool
+
+	// memory statistics, if recorded
+	mem     bool
+	alloc   uint64 // cumulative bytes allocated
+	mallocs uint64 // cumulative count of heap objects allocated
 }
 
 type event struct {
@@ -33,7 +42,22 @@ type event struct {
 }
 
 func (t *Timings) append(labels []string, start bool) {
-	t.list = append(t.list, timestamp{time.Now(), strings.Join(labels, ":"), start})
+	ts := timestamp{time: time.Now(), label: strings.Join(labels, ":"), start: start}
+	if t.mem {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		ts.mem = true
+		ts.alloc = m.TotalAlloc
+		ts.mallocs = m.Mallocs
+	}
+	t.list = append(t.list, ts)
+}
+
+// EnableMemStats enables the recording of memory statistics
+// for phases started or stopped from now on. Recording memory
+// statistics briefly stops the world at each Start and Stop.
+func (t *Timings) EnableMemStats() {
+	t.mem = true
 }
 
 // Start marks the beginning of a new phase and implicitly stops the previous phase.
@@ -156,6 +180,50 @@ func (t *Timings) Write(w io.Writer, prefix string) {
 	}
 }
 
+// WriteMem prints the amount of memory allocated in each phase to w,
+// one line per phase. The prefix is printed at the start of each line.
+// Phases with the same label are reported once, with their allocations
+// accumulated. Only phases for which memory statistics were recorded
+// (see EnableMemStats) are reported.
+func (t *Timings) WriteMem(w io.Writer, prefix string) {
+	type allocs struct {
+		bytes, count uint64
+	}
+	var labels []string
+	m := make(map[string]*allocs)
+	for i := 1; i < len(t.list); i++ {
+		pt, qt := &t.list[i-1], &t.list[i]
+		if !pt.mem || !qt.mem {
+			continue
+		}
+		// see Write for how phases are labeled
+		var label string
+		if pt.start {
+			label = pt.label
+			if !qt.start && qt.label != "" {
+				label += ":" + qt.label
+			}
+		} else if !qt.start {
+			label = qt.label
+		}
+		if label == "" {
+			continue // unaccounted
+		}
+		a := m[label]
+		if a == nil {
+			a = new(allocs)
+			m[label] = a
+			labels = append(labels, label)
+		}
+		a.bytes += qt.alloc - pt.alloc
+		a.count += qt.mallocs - pt.mallocs
+	}
+	for _, label := range labels {
+		a := m[label]
+		fmt.Fprintf(w, "%s%s = %d bytes in %d allocs\n", prefix, label, a.bytes, a.count)
+	}
+}
+
 func commonPrefix(a, b string) string {
 	i := 0
 	for i < len(a) && i < len(b) && a[i] == b[i] {
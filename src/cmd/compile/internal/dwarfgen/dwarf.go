@@ -23,7 +23,7 @@ import (
 	"cmd/internal/src"
 )
 
-func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope, dwarf.InlCalls) {
+func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope, dwarf.InlCalls, []dwarf.TypeParam) {
 	fn := curfn.(*ir.Func)
 
 	if fn.Nname != nil {
@@ -96,6 +96,7 @@ func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope,
 	}
 
 	decls, dwarfVars := createDwarfVars(fnsym, isODCLFUNC, fn, apdecls)
+	tparams := createDwarfTypeParams(fnsym, fn)
 
 	// For each type referenced by the functions auto vars but not
 	// already referenced by a dwarf var, attach an R_USETYPE relocation to
@@ -124,7 +125,22 @@ func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope,
 	if base.Flag.GenDwarfInl > 0 {
 		inlcalls = assembleInlines(fnsym, dwarfVars)
 	}
-	return scopes, inlcalls
+	return scopes, inlcalls, tparams
+}
+
+// createDwarfTypeParams returns the DWARF type parameters of fn, which
+// are only present if fn is an instantiation of a generic function.
+func createDwarfTypeParams(fnsym *obj.LSym, fn *ir.Func) []dwarf.TypeParam {
+	var tparams []dwarf.TypeParam
+	for i, f := range fn.TypeArgs {
+		fnsym.Func().RecordAutoType(reflectdata.TypeLinksym(f.Type))
+		tparams = append(tparams, dwarf.TypeParam{
+			Name:      f.Sym.Name,
+			Type:      base.Ctxt.Lookup(dwarf.InfoPrefix + types.TypeSymName(f.Type)),
+			DictIndex: i,
+		})
+	}
+	return tparams
 }
 
 func declPos(decl *ir.Name) src.XPos {
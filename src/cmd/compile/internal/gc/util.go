@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"fmt"
 	"os"
 	"runtime"
 	"runtime/pprof"
@@ -36,6 +37,8 @@ func startProfile() {
 		if err != nil {
 			base.Fatalf("%v", err)
 		}
+		// Attribute allocations to compiler phases; see below.
+		base.Timer.EnableMemStats()
 		base.AtExit(func() {
 			// Profile all outstanding allocations.
 			runtime.GC()
@@ -46,6 +49,11 @@ func startProfile() {
 			if err := pprof.Lookup("heap").WriteTo(f, writeLegacyFormat); err != nil {
 				base.Fatalf("%v", err)
 			}
+			// Append the allocations of each phase as comments,
+			// which profile readers ignore, in the style of the
+			// runtime.MemStats section of the legacy format.
+			fmt.Fprintf(f, "\n# Phases\n")
+			base.Timer.WriteMem(f, "# ")
 		})
 	} else {
 		// Not doing memory profiling; disable it entirely.
@@ -5,10 +5,13 @@
 package importer
 
 import (
+	"bufio"
 	"bytes"
+	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/types2"
 	"fmt"
 	"internal/testenv"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -606,3 +609,132 @@ func lookupObj(t *testing.T, scope *types2.Scope, name string) types2.Object {
 	t.Fatalf("%s not found", name)
 	return nil
 }
+
+func TestUnionInstances(t *testing.T) {
+	skipSpecialPlatforms(t)
+
+	// This package only handles gc export data.
+	if runtime.Compiler != "gc" {
+		t.Skipf("gc-built packages not available (compiler = %s)", runtime.Compiler)
+	}
+
+	// On windows, we have to set the -D option for the compiler to avoid having a drive
+	// letter and an illegal ':' in the import path - just skip it (see also issue #3483).
+	if runtime.GOOS == "windows" {
+		t.Skip("avoid dealing with relative paths/drive letters on windows")
+	}
+
+	pkg := compileAndImportPkg(t, "unioninst")
+	list := lookupObj(t, pkg.Scope(), "List").Type().(*types2.Named)
+
+	// The union terms of C are instances of the imported generic types.
+	iface := lookupObj(t, pkg.Scope(), "C").Type().Underlying().(*types2.Interface)
+	u := iface.EmbeddedType(0).(*types2.Union)
+	if u.Len() != 4 {
+		t.Fatalf("got %d union terms; want 4", u.Len())
+	}
+	for i, targ := range []types2.Type{types2.Typ[types2.Int], types2.Typ[types2.String]} {
+		want, err := types2.Instantiate(nil, list, []types2.Type{targ}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := u.Term(i).Type(); !types2.Identical(got, want) {
+			t.Errorf("got term %s; want %s", got, want)
+		}
+	}
+
+	// The imported constraints are usable.
+	const src = `
+package p
+
+import "unioninst"
+
+func _() {
+	unioninst.F(unioninst.List[string]{})
+	unioninst.F(unioninst.Pair[string, int]{})
+	unioninst.H[int](unioninst.List[int]{})
+	unioninst.H[int](unioninst.Pair[string, int]{})
+}
+`
+	f, err := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imp := importerFunc(func(path string) (*types2.Package, error) { return pkg, nil })
+	conf := types2.Config{Importer: imp}
+	if _, err := conf.Check("p", []*syntax.File{f}, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+type importerFunc func(path string) (*types2.Package, error)
+
+func (f importerFunc) Import(path string) (*types2.Package, error) { return f(path) }
+
+func TestImportTypeSets(t *testing.T) {
+	skipSpecialPlatforms(t)
+
+	// This package only handles gc export data.
+	if runtime.Compiler != "gc" {
+		t.Skipf("gc-built packages not available (compiler = %s)", runtime.Compiler)
+	}
+
+	tmpdir := mktmpdir(t)
+	defer os.RemoveAll(tmpdir)
+	outname := compile(t, "testdata", "typesets.go", filepath.Join(tmpdir, "testdata"))
+
+	f, err := os.Open(outname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	buf := bufio.NewReader(f)
+	if _, err := FindExportData(buf); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 || data[0] != 'i' {
+		t.Fatalf("unexpected export data format")
+	}
+
+	_, typeSets, err := ImportDataTypeSets(make(map[string]*types2.Package), string(data[1:]), "typesets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, ts := range typeSets {
+		s := ts.Name + ":"
+		if ts.Comparable {
+			s += " comparable;"
+		}
+		if ts.Terms == nil {
+			s += " 𝓤;"
+		} else {
+			var terms []string
+			for _, term := range ts.Terms {
+				terms = append(terms, term.String())
+			}
+			s += " {" + strings.Join(terms, " | ") + "};"
+		}
+		for _, m := range ts.Methods {
+			s += " " + m.Name() + ";"
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"Empty: {};",
+		"Integer: comparable; {~int | ~int8};",
+		"Key: comparable; 𝓤; m;",
+		"Mixed: comparable; {typesets.MyInt | ~string};",
+		"Number: comparable; {~int | ~int8 | ~float64};",
+		"Small: comparable; {int | ~int8 | float64};",
+		"Stringer: comparable; {~string}; String;",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got type sets\n\t%s\nwant\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}
@@ -49,6 +49,8 @@ const (
 	iexportVersionGenerics = iexportVersionPosCol
 
 	iexportVersionCurrent = iexportVersionGenerics
+
+	iexportVersionOrdered = 3
 )
 
 type ident struct {
@@ -58,6 +60,17 @@ type ident struct {
 
 const predeclReserved = 32
 
+// predeclReservedFor returns the number of type offsets reserved for
+// predeclared types in export data of the given format version.
+// Version iexportVersionOrdered reserves an additional offset for the
+// constraint ordered.
+func predeclReservedFor(version int64) uint64 {
+	if version == iexportVersionOrdered {
+		return predeclReserved + 1
+	}
+	return predeclReserved
+}
+
 type itag uint64
 
 const (
@@ -78,11 +91,32 @@ const (
 
 const io_SeekCurrent = 1 // io.SeekCurrent (not defined in Go 1.4)
 
-// iImportData imports a package from the serialized package data
-// and returns the number of bytes consumed and a reference to the package.
+// ImportData imports a package from the serialized package data
+// and returns a reference to the package.
 // If the export data version is not recognized or the format is otherwise
 // compromised, an error is returned.
-func ImportData(imports map[string]*types2.Package, data, path string) (pkg *types2.Package, err error) {
+func ImportData(imports map[string]*types2.Package, data, path string) (*types2.Package, error) {
+	pkg, _, err := iImportData(imports, data, path, false)
+	return pkg, err
+}
+
+// A TypeSet describes the normalized type set of an exported constraint
+// interface type, as recorded in export data.
+type TypeSet struct {
+	Name       string         // name of the constraint type
+	Comparable bool           // each type in the type set is comparable
+	Terms      []*types2.Term // pairwise disjoint type terms; nil if not restricted by terms
+	Methods    []*types2.Func // full method set of the constraint
+}
+
+// ImportDataTypeSets is like ImportData but also returns the normalized
+// type sets of the package's exported constraint interfaces, sorted by
+// name. The data must have been written by the current compiler.
+func ImportDataTypeSets(imports map[string]*types2.Package, data, path string) (*types2.Package, []*TypeSet, error) {
+	return iImportData(imports, data, path, true)
+}
+
+func iImportData(imports map[string]*types2.Package, data, path string, wantTypeSets bool) (pkg *types2.Package, typeSets []*TypeSet, err error) {
 	const currentVersion = iexportVersionCurrent
 	version := int64(-1)
 	defer func() {
@@ -99,7 +133,7 @@ func ImportData(imports map[string]*types2.Package, data, path string) (pkg *typ
 
 	version = int64(r.uint64())
 	switch version {
-	case /* iexportVersionGenerics, */ iexportVersionPosCol, iexportVersionGo1_11:
+	case /* iexportVersionGenerics, */ iexportVersionPosCol, iexportVersionGo1_11, iexportVersionOrdered:
 	default:
 		if version > iexportVersionGenerics {
 			errorf("unstable iexport format version %d, just rebuild compiler and std library", version)
@@ -121,6 +155,8 @@ func ImportData(imports map[string]*types2.Package, data, path string) (pkg *typ
 		ipath:         path,
 		version:       int(version),
 
+		predeclReserved: predeclReservedFor(version),
+
 		stringData:   stringData,
 		pkgCache:     make(map[uint64]*types2.Package),
 		posBaseCache: make(map[uint64]*syntax.PosBase),
@@ -136,6 +172,9 @@ func ImportData(imports map[string]*types2.Package, data, path string) (pkg *typ
 	for i, pt := range predeclared {
 		p.typCache[uint64(i)] = pt
 	}
+	if version == iexportVersionOrdered {
+		p.typCache[predeclReserved] = types2.Universe.Lookup("ordered").Type()
+	}
 
 	pkgList := make([]*types2.Package, r.uint64())
 	for i := range pkgList {
@@ -188,16 +227,75 @@ func ImportData(imports map[string]*types2.Package, data, path string) (pkg *typ
 	sort.Sort(byPath(list))
 	localpkg.SetImports(list)
 
+	if wantTypeSets {
+		// skip the compiler's inline body index
+		for nPkgs := r.uint64(); nPkgs > 0; nPkgs-- {
+			r.uint64() // package path
+			for nSyms := r.uint64(); nSyms > 0; nSyms-- {
+				r.uint64() // name
+				r.uint64() // offset
+			}
+		}
+		typeSets = p.typeSets(r, localpkg)
+	}
+
 	// package was imported completely and without errors
 	localpkg.MarkComplete()
 
-	return localpkg, nil
+	return localpkg, typeSets, nil
+}
+
+// typeSets reads the type sets of the exported constraint interfaces
+// of pkg from r.
+func (p *iimporter) typeSets(r *intReader, pkg *types2.Package) []*TypeSet {
+	list := make([]*TypeSet, r.uint64())
+	for i := range list {
+		ts := &TypeSet{Name: p.stringAt(r.uint64())}
+		ts.Comparable = r.uint64() != 0
+		if all := r.uint64() != 0; !all {
+			ts.Terms = make([]*types2.Term, r.uint64())
+			for j := range ts.Terms {
+				tilde := r.uint64() != 0
+				ts.Terms[j] = types2.NewTerm(tilde, p.typAt(r.uint64(), nil))
+			}
+		}
+
+		// The methods are looked up in the method set of the
+		// (imported) constraint.
+		obj := pkg.Scope().Lookup(ts.Name)
+		if obj == nil {
+			errorf("type set for unknown type %s", ts.Name)
+		}
+		iface, _ := obj.Type().Underlying().(*types2.Interface)
+		if iface == nil {
+			errorf("type set for non-interface type %s", ts.Name)
+		}
+		ts.Methods = make([]*types2.Func, r.uint64())
+		for j := range ts.Methods {
+			mpkg := p.pkgAt(r.uint64())
+			name := p.stringAt(r.uint64())
+			var m *types2.Func
+			for k := 0; k < iface.NumMethods(); k++ {
+				if f := iface.Method(k); f.Name() == name && (f.Exported() || f.Pkg() == mpkg) {
+					m = f
+					break
+				}
+			}
+			if m == nil {
+				errorf("method %s not in method set of %s", name, ts.Name)
+			}
+			ts.Methods[j] = m
+		}
+		list[i] = ts
+	}
+	return list
 }
 
 type iimporter struct {
-	exportVersion int64
-	ipath         string
-	version       int
+	exportVersion   int64
+	ipath           string
+	version         int
+	predeclReserved uint64 // see predeclReservedFor
 
 	stringData   string
 	pkgCache     map[uint64]*types2.Package
@@ -267,15 +365,15 @@ func (p *iimporter) typAt(off uint64, base *types2.Named) types2.Type {
 		return t
 	}
 
-	if off < predeclReserved {
+	if off < p.predeclReserved {
 		errorf("predeclared type missing from cache: %v", off)
 	}
 
 	r := &importReader{p: p}
 	// Reader.Reset is not available in Go 1.4.
 	// Use bytes.NewReader for now.
-	// r.declReader.Reset(p.declData[off-predeclReserved:])
-	r.declReader = *strings.NewReader(p.declData[off-predeclReserved:])
+	// r.declReader.Reset(p.declData[off-p.predeclReserved:])
+	r.declReader = *strings.NewReader(p.declData[off-p.predeclReserved:])
 	t := r.doType(base)
 
 	if base == nil || !isInterface(t) {
@@ -0,0 +1,38 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typesets
+
+type Integer interface{ ~int | ~int8 }
+
+type Number interface{ Integer | ~float64 }
+
+type Small interface {
+	Number
+	int | ~int8 | float64 | string
+}
+
+type Stringer interface {
+	~string
+	String() string
+}
+
+type Key interface {
+	comparable
+	m()
+}
+
+type Empty interface {
+	int
+	string
+}
+
+type MyInt int
+
+type Mixed interface{ MyInt | ~string }
+
+// Method sets are not constraints and have no recorded type set.
+type Plain interface{ m() }
+
+type unexported interface{ ~int }
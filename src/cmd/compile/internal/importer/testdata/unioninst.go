@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unioninst
+
+type List[T any] []T
+
+type Pair[K comparable, V any] struct {
+	K K
+	V V
+}
+
+type C interface {
+	List[int] | List[string] | ~[]float64 | Pair[string, int]
+}
+
+type G[T any] interface {
+	List[T] | Pair[string, T]
+}
+
+func F[P C](p P) {}
+
+func H[T any, P G[T]](p P) {}
@@ -96,6 +96,13 @@ type Func struct {
 	DebugInfo  interface{}
 	LSym       *obj.LSym // Linker object in this function's native ABI (Func.ABI)
 
+	// TypeArgs is set for stenciled instantiations of generic functions
+	// and methods. It lists the type parameters of the generic function,
+	// each with the shape type it was instantiated with. The runtime type
+	// of the concrete type argument for TypeArgs[i] is held in entry i of
+	// the dictionary.
+	TypeArgs []*types.Field
+
 	Inl *Inline
 
 	// Closgen tracks how many closures have been generated within
@@ -0,0 +1,185 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+)
+
+// checkDeterminism implements -d=checkdeterminism. It type-checks
+// files a second time with the configuration conf, and reports a fatal
+// error if the reported errors, the recorded type information, or the
+// package's exported API differ from the results pkg, info, and errs
+// of the first run.
+//
+// Map iteration order differs between runs, and the second run is
+// perturbed further by shifting heap addresses and triggering garbage
+// collections at different points, so that results that depend on map
+// order or object identity are likely to differ. The export data itself
+// is written from the IR later; the package summary compared here
+// (objects, methods, and their order) stands in for it.
+func checkDeterminism(conf types2.Config, files []*syntax.File, pkg *types2.Package, info *types2.Info, errs []types2.Error) {
+	var errs2 []types2.Error
+	conf.Error = func(err error) {
+		errs2 = append(errs2, err.(types2.Error))
+	}
+	conf.Phase = func(string) {
+		runtime.GC()
+		perturbHeap()
+	}
+
+	perturbHeap()
+	pkg2 := types2.NewPackage(pkg.Path(), "")
+	info2 := &types2.Info{
+		Types:      make(map[syntax.Expr]types2.TypeAndValue),
+		Defs:       make(map[*syntax.Name]types2.Object),
+		Uses:       make(map[*syntax.Name]types2.Object),
+		Selections: make(map[*syntax.SelectorExpr]*types2.Selection),
+		Implicits:  make(map[syntax.Node]types2.Object),
+		Inferred:   make(map[syntax.Expr]types2.Inferred),
+	}
+	types2.NewChecker(&conf, pkg2, info2).Files(files)
+
+	// Errors are reported in order; the order must not change.
+	var msgs, msgs2 []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	for _, err := range errs2 {
+		msgs2 = append(msgs2, err.Error())
+	}
+	diffDeterminism("errors", msgs, msgs2)
+
+	diffDeterminism("type information", sortedLines(dumpInfo(info)), sortedLines(dumpInfo(info2)))
+	diffDeterminism("package summary", dumpPackage(pkg), dumpPackage(pkg2))
+}
+
+// perturbHeap allocates a small random amount of memory so that the
+// addresses of subsequently allocated objects change.
+var perturbHeap = func() func() {
+	var keep [][]byte
+	return func() {
+		keep = append(keep, make([]byte, 1+rand.Intn(4096)))
+	}
+}()
+
+// diffDeterminism reports a fatal error listing the lines that differ
+// between the results a and b of two type-checking runs.
+func diffDeterminism(what string, a, b []string) {
+	if len(a) == len(b) {
+		same := true
+		for i := range a {
+			if a[i] != b[i] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return
+		}
+	}
+
+	// Report the lines from the point where the results diverge.
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	const max = 10
+	var buf strings.Builder
+	for j := i; j < len(a) && j < i+max; j++ {
+		fmt.Fprintf(&buf, "\n\t- %s", a[j])
+	}
+	for j := i; j < len(b) && j < i+max; j++ {
+		fmt.Fprintf(&buf, "\n\t+ %s", b[j])
+	}
+	base.Fatalf("-d=checkdeterminism: %s differs between type-checking runs:%s", what, buf.String())
+}
+
+func sortedLines(lines []string) []string {
+	sort.Strings(lines)
+	return lines
+}
+
+// canonical removes the unique subscripts that distinguish type
+// parameters in type strings; they differ between runs.
+func canonical(s string) string {
+	return strings.Map(func(r rune) rune {
+		if '₀' <= r && r <= '₉' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// dumpInfo returns the contents of info, one entry per line.
+// Expressions are identified by their position and kind only;
+// printing them would be quadratic for deeply nested expressions.
+func dumpInfo(info *types2.Info) []string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, canonical(fmt.Sprintf(format, args...)))
+	}
+	for x, tv := range info.Types {
+		add("type %s %T: %s %v", x.Pos(), x, tv.Type, tv.Value)
+	}
+	for name, obj := range info.Defs {
+		add("def %s %s: %v", name.Pos(), name.Value, obj)
+	}
+	for name, obj := range info.Uses {
+		add("use %s %s: %v", name.Pos(), name.Value, obj)
+	}
+	for n, obj := range info.Implicits {
+		add("implicit %s: %v", n.Pos(), obj)
+	}
+	for sel, s := range info.Selections {
+		add("selection %s %s: %v", sel.Pos(), sel.Sel.Value, s)
+	}
+	for x, inf := range info.Inferred {
+		var targs []string
+		for i := 0; i < inf.TArgs.Len(); i++ {
+			targs = append(targs, inf.TArgs.At(i).String())
+		}
+		add("inferred %s %T: [%s] %s", x.Pos(), x, strings.Join(targs, ", "), inf.Sig)
+	}
+	return lines
+}
+
+// dumpPackage returns a summary of the objects declared in pkg and of
+// the methods of its types, in the order in which they would be
+// exported.
+func dumpPackage(pkg *types2.Package) []string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, canonical(fmt.Sprintf(format, args...)))
+	}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		add("%s", types2.ObjectString(obj, nil))
+		tname, _ := obj.(*types2.TypeName)
+		if tname == nil {
+			continue
+		}
+		if named, _ := tname.Type().(*types2.Named); named != nil {
+			for i := 0; i < named.NumMethods(); i++ {
+				add("\tmethod %s", named.Method(i))
+			}
+		}
+		if iface, _ := tname.Type().Underlying().(*types2.Interface); iface != nil {
+			for i := 0; i < iface.NumMethods(); i++ {
+				add("\tinterface method %s", iface.Method(i))
+			}
+		}
+	}
+	return lines
+}
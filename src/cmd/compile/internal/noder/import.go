@@ -224,6 +224,13 @@ func parseImportPath(pathLit *syntax.BasicLit) (string, error) {
 // readImportFile reads the import file for the given package path and
 // returns its types.Pkg representation. If packages is non-nil, the
 // types2.Package representation is also returned.
+//
+// Imported packages are never type-checked again: their export data
+// already contains the results of checking them, and the go command
+// caches it, keyed by the content hashes of the sources and the build
+// configuration, so that unchanged dependencies are not recompiled.
+// A separate type-checking cache in the compiler would only duplicate
+// that cache.
 func readImportFile(path string, target *ir.Package, check *types2.Checker, packages map[string]*types2.Package) (pkg1 *types.Pkg, pkg2 *types2.Package, err error) {
 	path, err = resolveImportPath(path)
 	if err != nil {
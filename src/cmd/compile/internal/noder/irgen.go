@@ -6,7 +6,9 @@ package noder
 
 import (
 	"fmt"
+	"internal/buildcfg"
 	"os"
+	"strings"
 
 	"cmd/compile/internal/base"
 	"cmd/compile/internal/dwarfgen"
@@ -42,6 +44,10 @@ func checkFiles(noders []*noder) (posMap, *types2.Package, *types2.Info) {
 		IgnoreLabels:          true, // parser already checked via syntax.CheckBranches mode
 		CompilerErrorMessages: true, // use error strings matching existing compiler errors
 		AllowTypeLists:        true, // remove this line once all tests use type set syntax
+		OperatorConstraints:   buildcfg.Experiment.OperatorConstraints,
+		DefaultTypeArgs:       buildcfg.Experiment.DefaultTypeArgs,
+		MethodTypeParams:      buildcfg.Experiment.MethodTypeParams,
+		AliasTypeParams:       buildcfg.Experiment.AliasTypeParams,
 		Error: func(err error) {
 			terr := err.(types2.Error)
 			base.ErrorfAt(m.makeXPos(terr.Pos), "%s", terr.Msg)
@@ -59,10 +65,64 @@ func checkFiles(noders []*noder) (posMap, *types2.Package, *types2.Info) {
 		Inferred:   make(map[syntax.Expr]types2.Inferred),
 		// expand as needed
 	}
+	if base.Debug.Deprecated != 0 {
+		info.Deprecated = make(map[*syntax.Name]types2.Object)
+	}
+
+	if base.Flag.MemProfile != "" {
+		// Attribute memory to the type checker's phases. The
+		// phases are not timed otherwise, because lazily computed
+		// interface type sets would lead to many short phases.
+		conf.Phase = func(phase string) {
+			base.Timer.Start("fe", "types2", phase)
+		}
+	}
+
+	var errs []types2.Error
+	if base.Debug.CheckDeterminism != 0 {
+		report := conf.Error
+		conf.Error = func(err error) {
+			errs = append(errs, err.(types2.Error))
+			report(err)
+		}
+	}
 
 	pkg := types2.NewPackage(base.Ctxt.Pkgpath, "")
 	importer.check = types2.NewChecker(&conf, pkg, info)
+	if base.Debug.ReduceCrash != 0 {
+		defer reduceCrash(conf, files)
+	}
 	err := importer.check.Files(files)
+	if conf.Phase != nil {
+		base.Timer.Start("fe", "irgen")
+	}
+
+	if base.Debug.CheckDeterminism != 0 {
+		checkDeterminism(conf, files, pkg, info, errs)
+	}
+
+	if base.Debug.TypedDump != "" {
+		typedDump(base.Debug.TypedDump, files, info)
+	}
+
+	// Only objects declared in the package being compiled have doc
+	// comments; export data doesn't record them.
+	for id, obj := range info.Deprecated {
+		msg := strings.TrimPrefix(obj.Deprecated(), "Deprecated: ")
+		base.WarnfAt(m.makeXPos(id.Pos()), "%s is deprecated: %s", id.Value, strings.ReplaceAll(msg, "\n", " "))
+	}
+
+	if base.Debug.Counters != 0 {
+		check := importer.check
+		base.AtExit(func() {
+			c := check.Counters()
+			fmt.Printf("types2 counters for %s:\n", base.Ctxt.Pkgpath)
+			fmt.Printf("\tinstances\t%d\n", c.Instances)
+			fmt.Printf("\tinterfaces\t%d\n", c.Interfaces)
+			fmt.Printf("\tunions\t%d\n", c.Unions)
+			fmt.Printf("\tdelayed\t%d\n", c.Delayed)
+		})
+	}
 
 	base.ExitIfErrors()
 	if err != nil {
@@ -81,6 +141,28 @@ func check2(noders []*noder) {
 		os.Exit(0)
 	}
 
+	if buildcfg.Experiment.MethodTypeParams {
+		// Methods with type parameters can only be type-checked.
+		for _, p := range noders {
+			for _, decl := range p.file.DeclList {
+				if decl, ok := decl.(*syntax.FuncDecl); ok && decl.Recv != nil && len(decl.TParamList) > 0 {
+					base.ErrorfAt(m.makeXPos(decl.Pos()), "cannot compile method %s with type parameters", decl.Name.Value)
+				}
+			}
+		}
+		base.ExitIfErrors()
+	}
+
+	if buildcfg.Experiment.AliasTypeParams {
+		// Parameterized aliases can only be type-checked.
+		for _, obj := range info.Defs {
+			if obj, ok := obj.(*types2.TypeName); ok && obj.TParams() != nil {
+				base.ErrorfAt(m.makeXPos(obj.Pos()), "cannot compile parameterized alias %s", obj.Name())
+			}
+		}
+		base.ExitIfErrors()
+	}
+
 	g := irgen{
 		target: typecheck.Target,
 		self:   pkg,
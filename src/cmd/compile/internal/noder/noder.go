@@ -38,6 +38,9 @@ func LoadPackage(filenames []string) {
 	if supportsGenerics {
 		mode |= syntax.AllowGenerics
 	}
+	if base.Debug.Deprecated != 0 {
+		mode |= syntax.DocComments // for types2.Info.Deprecated
+	}
 
 	// Limit the number of simultaneously open files.
 	sem := make(chan struct{}, runtime.GOMAXPROCS(0)+10)
@@ -0,0 +1,274 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+)
+
+// reduceCrash implements -d=reducecrash. It must be deferred directly
+// while files are type-checked with the configuration conf. If the type
+// checker panics, reduceCrash type-checks smaller and smaller versions
+// of files, removing files, declarations, and statements for as long as
+// the checker still panics with the same value at the same place. It
+// prints the smallest version found to stderr, in txtar format, and
+// then continues panicking.
+//
+// The reduced files are printed from the syntax tree, so comments are
+// lost and positions change; a crash that depends on them may not
+// reproduce with the printed program.
+func reduceCrash(conf types2.Config, files []*syntax.File) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	crash := panicDesc(p)
+
+	conf.Error = func(error) {}
+	conf.Phase = nil
+	conf.Trace = false
+	r := reducer{conf: conf, files: append([]*syntax.File(nil), files...), crash: crash}
+	if r.check() != crash {
+		// The panic depends on more than the files; give up.
+		fmt.Fprintf(os.Stderr, "cannot reduce type checker crash: %s\n", crash)
+		panic(p)
+	}
+	for r.changed = true; r.changed; {
+		r.changed = false
+		r.pass()
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "reduced type checker crash (%d checks): %s\n", r.checks, crash)
+	for _, file := range r.files {
+		fmt.Fprintf(&buf, "-- %s --\n", filepath.Base(file.Pos().RelFilename()))
+		syntax.Fprint(&buf, file, 0)
+		buf.WriteByte('\n')
+	}
+	os.Stderr.Write(buf.Bytes())
+	panic(p)
+}
+
+// panicDesc returns a description of the panic value p, which must have
+// just been recovered by the caller of panicDesc: the value and the
+// function that panicked.
+func panicDesc(p interface{}) string {
+	pcs := make([]uintptr, 100)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	fn := "unknown function"
+	for panicking := false; ; {
+		frame, more := frames.Next()
+		if panicking {
+			fn = frame.Function
+		}
+		// The types2 checker recovers and re-panics in some cases,
+		// so use the frame below the innermost runtime.gopanic.
+		panicking = frame.Function == "runtime.gopanic"
+		if !more {
+			break
+		}
+	}
+	return fmt.Sprintf("%v in %s", p, fn)
+}
+
+// A reducer reduces the files of a package for as long as type-checking
+// them crashes in the same way.
+type reducer struct {
+	conf    types2.Config
+	files   []*syntax.File
+	crash   string // description of the crash to preserve
+	checks  int    // number of type-checking runs
+	changed bool   // set if the last pass removed something
+}
+
+// check type-checks the files and returns a description of the crash,
+// or the empty string if there was none.
+func (r *reducer) check() (crash string) {
+	r.checks++
+	defer func() {
+		if p := recover(); p != nil {
+			crash = panicDesc(p)
+		}
+	}()
+	info := &types2.Info{
+		Types:      make(map[syntax.Expr]types2.TypeAndValue),
+		Defs:       make(map[*syntax.Name]types2.Object),
+		Uses:       make(map[*syntax.Name]types2.Object),
+		Selections: make(map[*syntax.SelectorExpr]*types2.Selection),
+		Implicits:  make(map[syntax.Node]types2.Object),
+		Scopes:     make(map[syntax.Node]*types2.Scope),
+		Inferred:   make(map[syntax.Expr]types2.Inferred),
+	}
+	pkg := types2.NewPackage(base.Ctxt.Pkgpath, "")
+	types2.NewChecker(&r.conf, pkg, info).Files(r.files)
+	return ""
+}
+
+func (r *reducer) crashes() bool {
+	return r.check() == r.crash
+}
+
+// reduce removes elements from a list of length n for as long as the
+// checker crashes. set(keep) must set the list to the elements i of the
+// original list for which keep[i] is set. Elements are removed in
+// chunks of decreasing size, starting with the entire list.
+func (r *reducer) reduce(n int, set func(keep []bool)) {
+	keep := make([]bool, n)
+	for i := range keep {
+		keep[i] = true
+	}
+	for size := n; size > 0; size /= 2 {
+		for i := 0; i < n; i += size {
+			trial := append([]bool(nil), keep...)
+			removed := false
+			for j := i; j < i+size && j < n; j++ {
+				removed = removed || trial[j]
+				trial[j] = false
+			}
+			if !removed {
+				continue
+			}
+			set(trial)
+			if r.crashes() {
+				keep = trial
+				r.changed = true
+			}
+		}
+	}
+	set(keep)
+}
+
+// pass makes one reduction pass over the files, and then over the
+// declarations and statements that remain.
+func (r *reducer) pass() {
+	files := r.files
+	r.reduce(len(files), func(keep []bool) {
+		r.files = nil
+		for i, file := range files {
+			if keep[i] {
+				r.files = append(r.files, file)
+			}
+		}
+	})
+
+	for _, file := range r.files {
+		r.decls(&file.DeclList)
+		for _, decl := range file.DeclList {
+			if decl, ok := decl.(*syntax.FuncDecl); ok && decl.Body != nil {
+				r.stmts(&decl.Body.List)
+			}
+		}
+	}
+}
+
+func (r *reducer) decls(list *[]syntax.Decl) {
+	decls := *list
+	r.reduce(len(decls), func(keep []bool) {
+		*list = nil
+		for i, decl := range decls {
+			if keep[i] {
+				*list = append(*list, decl)
+			}
+		}
+	})
+}
+
+func (r *reducer) stmts(list *[]syntax.Stmt) {
+	stmts := *list
+	r.reduce(len(stmts), func(keep []bool) {
+		*list = nil
+		for i, stmt := range stmts {
+			if keep[i] {
+				*list = append(*list, stmt)
+			}
+		}
+	})
+	for i, stmt := range *list {
+		// Try to replace a control statement with one of its blocks.
+		for _, block := range blocks(stmt) {
+			(*list)[i] = block
+			if r.crashes() {
+				r.changed = true
+				break
+			}
+			(*list)[i] = stmt
+		}
+		r.stmt((*list)[i])
+	}
+}
+
+// blocks returns the blocks of the if or for statement stmt.
+func blocks(stmt syntax.Stmt) []syntax.Stmt {
+	switch s := stmt.(type) {
+	case *syntax.IfStmt:
+		if s.Else != nil {
+			return []syntax.Stmt{s.Then, s.Else}
+		}
+		return []syntax.Stmt{s.Then}
+	case *syntax.ForStmt:
+		return []syntax.Stmt{s.Body}
+	}
+	return nil
+}
+
+// stmt reduces the statements nested in stmt.
+func (r *reducer) stmt(stmt syntax.Stmt) {
+	switch s := stmt.(type) {
+	case *syntax.BlockStmt:
+		r.stmts(&s.List)
+	case *syntax.LabeledStmt:
+		r.stmt(s.Stmt)
+	case *syntax.DeclStmt:
+		r.decls(&s.DeclList)
+	case *syntax.IfStmt:
+		if s.Else != nil {
+			els := s.Else
+			s.Else = nil
+			if r.crashes() {
+				r.changed = true
+			} else {
+				s.Else = els
+				r.stmt(els)
+			}
+		}
+		r.stmts(&s.Then.List)
+	case *syntax.ForStmt:
+		r.stmts(&s.Body.List)
+	case *syntax.SwitchStmt:
+		clauses := s.Body
+		r.reduce(len(clauses), func(keep []bool) {
+			s.Body = nil
+			for i, c := range clauses {
+				if keep[i] {
+					s.Body = append(s.Body, c)
+				}
+			}
+		})
+		for _, c := range s.Body {
+			r.stmts(&c.Body)
+		}
+	case *syntax.SelectStmt:
+		clauses := s.Body
+		r.reduce(len(clauses), func(keep []bool) {
+			s.Body = nil
+			for i, c := range clauses {
+				if keep[i] {
+					s.Body = append(s.Body, c)
+				}
+			}
+		})
+		for _, c := range s.Body {
+			r.stmts(&c.Body)
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"cmd/internal/src"
 	"fmt"
 	"go/constant"
+	"strings"
 )
 
 // Enable extra consistency checks.
@@ -623,6 +624,12 @@ func (g *irgen) getInstantiation(nameNode *ir.Name, shapes []*types.Type, isMeth
 	return info.fun
 }
 
+// tparamName returns the source name of the type parameter with symbol
+// sym, without the subscript added by types2.
+func tparamName(sym *types.Sym) string {
+	return strings.TrimRight(sym.Name, "₀₁₂₃₄₅₆₇₈₉")
+}
+
 // Struct containing info needed for doing the substitution as we create the
 // instantiation of a generic function with specified type arguments.
 type subster struct {
@@ -685,6 +692,12 @@ func (g *irgen) genericSubst(newsym *types.Sym, nameNode *ir.Name, shapes []*typ
 		defnMap: make(map[ir.Node][]**ir.Name),
 	}
 
+	// Record the type parameters and their shapes for debug info.
+	newf.TypeArgs = make([]*types.Field, len(tparams))
+	for i, tp := range tparams {
+		newf.TypeArgs[i] = types.NewField(gf.Pos(), typecheck.Lookup(tparamName(tp.Sym())), shapes[i])
+	}
+
 	newf.Dcl = make([]*ir.Name, 0, len(gf.Dcl)+1)
 
 	// Create the needed dictionary param
@@ -0,0 +1,200 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+)
+
+// typedDump implements -d=typeddump=name. It prints the syntax trees of
+// the package-level declarations named name to stdout, one node per
+// line, annotated with the type information recorded in info: the mode,
+// type, and constant value of expressions, the objects defined, used,
+// or implicitly declared by nodes (with the position of their
+// declaration), selections, and inferred type arguments. A method is
+// named by its receiver base type and method name, as in T.m.
+//
+// The dump shows what the type checker decided before the IR is
+// generated, which helps to debug discrepancies between the two.
+func typedDump(name string, files []*syntax.File, info *types2.Info) {
+	var buf strings.Builder
+	found := false
+	for _, file := range files {
+		for _, decl := range file.DeclList {
+			if declNamed(decl, name) {
+				found = true
+				fmt.Fprintf(&buf, "typed dump of %s:\n", name)
+				dumpTyped(&buf, decl, info)
+			}
+		}
+	}
+	if !found {
+		base.Warn("-d=typeddump: no declaration named %s", name)
+		return
+	}
+	os.Stdout.WriteString(buf.String())
+}
+
+// declNamed reports whether decl declares name.
+func declNamed(decl syntax.Decl, name string) bool {
+	switch decl := decl.(type) {
+	case *syntax.ConstDecl:
+		return namesContain(decl.NameList, name)
+	case *syntax.VarDecl:
+		return namesContain(decl.NameList, name)
+	case *syntax.TypeDecl:
+		return decl.Name.Value == name
+	case *syntax.FuncDecl:
+		if decl.Recv == nil {
+			return decl.Name.Value == name
+		}
+		if base := recvBaseName(decl.Recv.Type); base != "" {
+			return base+"."+decl.Name.Value == name
+		}
+	}
+	return false
+}
+
+func namesContain(names []*syntax.Name, name string) bool {
+	for _, n := range names {
+		if n.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// recvBaseName returns the name of the base type of the receiver type
+// expression x, which has the form [*]T or [*]T[P, ...], or "" if x
+// is invalid.
+func recvBaseName(x syntax.Expr) string {
+	for {
+		switch t := x.(type) {
+		case *syntax.ParenExpr:
+			x = t.X
+		case *syntax.Operation:
+			if t.Op != syntax.Mul || t.Y != nil {
+				return ""
+			}
+			x = t.X
+		case *syntax.IndexExpr:
+			x = t.X
+		case *syntax.Name:
+			return t.Value
+		default:
+			return ""
+		}
+	}
+}
+
+// dumpTyped writes the annotated syntax tree of n to buf, indenting
+// each node by its depth.
+func dumpTyped(buf *strings.Builder, n syntax.Node, info *types2.Info) {
+	depth := 0
+	syntax.Inspect(n, func(n syntax.Node) bool {
+		if n == nil {
+			depth--
+			return false
+		}
+		fmt.Fprintf(buf, "%s%s %T%s", strings.Repeat("  ", depth), n.Pos(), n, nodeDetail(n))
+		for _, a := range typedAnnotations(n, info) {
+			fmt.Fprintf(buf, "\n%s  | %s", strings.Repeat("  ", depth), a)
+		}
+		buf.WriteByte('\n')
+		depth++
+		return true
+	})
+}
+
+// nodeDetail returns the identifier, literal, or operator of n, if any,
+// to identify n in the dump.
+func nodeDetail(n syntax.Node) string {
+	switch n := n.(type) {
+	case *syntax.Name:
+		return " " + n.Value
+	case *syntax.BasicLit:
+		return " " + n.Value
+	case *syntax.Operation:
+		return " " + n.Op.String()
+	case *syntax.AssignStmt:
+		if n.Op != 0 {
+			return " " + n.Op.String() + "="
+		}
+	}
+	return ""
+}
+
+// typedAnnotations returns the type information recorded in info for
+// the node n.
+func typedAnnotations(n syntax.Node, info *types2.Info) []string {
+	var list []string
+	if x, _ := n.(syntax.Expr); x != nil {
+		if tv, ok := info.Types[x]; ok {
+			s := fmt.Sprintf("%s %s", tvMode(tv), tv.Type)
+			if tv.Value != nil {
+				s += " = " + tv.Value.ExactString()
+			}
+			list = append(list, s)
+		}
+		if inf, ok := info.Inferred[x]; ok {
+			list = append(list, fmt.Sprintf("inferred %s %s", inf.TArgs, inf.Sig))
+		}
+	}
+	if id, _ := n.(*syntax.Name); id != nil {
+		if obj, ok := info.Defs[id]; ok {
+			list = append(list, "def "+objLink(obj))
+		}
+		if obj, ok := info.Uses[id]; ok {
+			list = append(list, "use "+objLink(obj))
+		}
+	}
+	if obj, ok := info.Implicits[n]; ok {
+		list = append(list, "implicit "+objLink(obj))
+	}
+	if x, _ := n.(*syntax.SelectorExpr); x != nil {
+		if sel, ok := info.Selections[x]; ok {
+			list = append(list, fmt.Sprintf("selection %s %v", sel, sel.Index()))
+		}
+	}
+	return list
+}
+
+// tvMode describes the mode of the operand tv.
+func tvMode(tv types2.TypeAndValue) string {
+	switch {
+	case tv.IsVoid():
+		return "void"
+	case tv.IsType():
+		return "type"
+	case tv.IsBuiltin():
+		return "builtin"
+	case tv.IsNil():
+		return "nil"
+	case tv.Value != nil:
+		return "constant"
+	case tv.Addressable():
+		return "variable"
+	case tv.HasOk():
+		return "commaok"
+	}
+	return "value"
+}
+
+// objLink describes obj and the position of its declaration.
+func objLink(obj types2.Object) string {
+	if obj == nil {
+		return "<nil>"
+	}
+	if !obj.Pos().IsKnown() {
+		return obj.String()
+	}
+	return fmt.Sprintf("%s @ %s", obj, obj.Pos())
+}
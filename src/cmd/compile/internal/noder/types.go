@@ -211,7 +211,11 @@ func (g *irgen) typ0(typ types2.Type) *types.Type {
 			methods[i] = types.NewField(g.pos(m), g.selector(m), mtyp)
 		}
 
-		return types.NewInterface(g.tpkg(typ), append(embeddeds, methods...))
+		t := types.NewInterface(g.tpkg(typ), append(embeddeds, methods...))
+		if typ.IsConstraint() {
+			t.SetTypeSet(g.typeSet(typ))
+		}
+		return t
 
 	case *types2.TypeParam:
 		// Save the name of the type parameter in the sym of the type.
@@ -280,7 +284,7 @@ func (g *irgen) fillinMethods(typ *types2.Named, ntyp *types.Type) {
 
 	methods := make([]*types.Field, typ.NumMethods())
 	for i := range methods {
-		m := typ.Method(i)
+		m := typ.Method(i).Origin() // the method of the generic type; instantiated below
 		recvType := deref2(types2.AsSignature(m.Type()).Recv().Type())
 		var meth *ir.Name
 		if m.Pkg() != g.self {
@@ -335,6 +339,24 @@ func (g *irgen) fillinMethods(typ *types2.Named, ntyp *types.Type) {
 	}
 }
 
+// typeSet translates the normalized type set of the types2 constraint
+// interface typ, for inclusion in export data.
+func (g *irgen) typeSet(typ *types2.Interface) *types.TypeSet {
+	ts := &types.TypeSet{Comparable: typ.IsComparable()}
+	terms := typ.NormalTerms()
+	if terms == nil {
+		ts.All = true
+		return ts
+	}
+	ts.Terms = make([]*types.Type, len(terms))
+	ts.Tildes = make([]bool, len(terms))
+	for i, t := range terms {
+		ts.Terms[i] = g.typ1(t.Type())
+		ts.Tildes[i] = t.Tilde()
+	}
+	return ts
+}
+
 func (g *irgen) signature(recv *types.Field, sig *types2.Signature) *types.Type {
 	tparams2 := sig.TParams()
 	tparams := make([]*types.Field, tparams2.Len())
@@ -0,0 +1,128 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements CommentMap, which associates comments with
+// the declarations and fields of a syntax tree.
+
+package syntax
+
+// A CommentMap maps a declaration or field node (a Decl or *Field) to
+// the list of comment groups associated with it, in source order. The
+// comment groups which are not associated with such a node are mapped
+// to the File.
+type CommentMap map[Node][]*CommentGroup
+
+// NewCommentMap associates each comment group of the file f, which
+// must have been parsed with the Comments mode, with a declaration
+// or field node, and returns the resulting comment map. Declarations
+// include local declarations in function bodies; fields include struct
+// fields, interface methods and embedded elements, parameters, results,
+// and type parameters.
+//
+// A comment group g is associated with the node n, among the nodes
+// within the innermost declaration, field, block, struct type, or
+// interface type enclosing g (or among all nodes if there is none), if
+//
+//	- g starts on the line on which n ends (a trailing comment), or
+//	- n starts on the line following g or on the line on which g ends
+//	  (a doc comment), or
+//	- g starts on the line following n.
+//
+// These rules are applied in order. Otherwise, g is associated with the
+// innermost declaration or field enclosing g or, if there is none, with
+// the next top-level declaration, or with the previous one at the end of
+// the file. Comment groups preceding the package clause, and the groups
+// of files without declarations, are associated with f.
+func NewCommentMap(f *File) CommentMap {
+	// Collect the declaration and field nodes in source order, together
+	// with the nodes delimiting the comments they may be associated with.
+	var nodes []commentNode
+	Inspect(f, func(n Node) bool {
+		switch n.(type) {
+		case Decl, *Field:
+			nodes = append(nodes, commentNode{n, StartPos(n), EndPos(n), true})
+		case *BlockStmt, *StructType, *InterfaceType:
+			nodes = append(nodes, commentNode{n, StartPos(n), EndPos(n), false})
+		}
+		return true
+	})
+
+	m := make(CommentMap)
+	for _, g := range f.Comments {
+		var n Node = f
+		if f.PkgName == nil || g.End.Cmp(f.PkgName.Pos()) > 0 {
+			if x := associate(nodes, g); x != nil {
+				n = x
+			}
+		}
+		m[n] = append(m[n], g)
+	}
+	return m
+}
+
+// A commentNode is a node of a syntax tree with its extent. Comments are
+// only associated with declaration and field nodes (if decl is set).
+type commentNode struct {
+	n          Node
+	start, end Pos
+	decl       bool
+}
+
+// encloses reports whether x encloses the comment group g.
+func (x *commentNode) encloses(g *CommentGroup) bool {
+	return x.start.Cmp(g.Pos) < 0 && g.End.Cmp(x.end) <= 0
+}
+
+// associate returns the node with which the comment group g is
+// associated, or nil (see NewCommentMap).
+func associate(nodes []commentNode, g *CommentGroup) Node {
+	// Find the innermost node enclosing g, and the innermost declaration
+	// or field enclosing g. Since the nodes are in source order and nested
+	// nodes follow their parents, they are the last ones found.
+	var encl, enclDecl *commentNode
+	for i := range nodes {
+		if x := &nodes[i]; x.encloses(g) {
+			encl = x
+			if x.decl {
+				enclDecl = x
+			}
+		}
+	}
+
+	// Find the declarations and fields within encl ending last before g
+	// (prev) and starting first after g (next). Among nodes ending or
+	// starting at the same position, the outermost one is chosen.
+	var prev, next *commentNode
+	for i := range nodes {
+		x := &nodes[i]
+		if !x.decl || x == encl {
+			continue
+		}
+		if encl != nil && (x.start.Cmp(encl.start) < 0 || encl.end.Cmp(x.end) < 0) {
+			continue // x is not within encl
+		}
+		if x.end.Cmp(g.Pos) <= 0 && (prev == nil || prev.end.Cmp(x.end) < 0) {
+			prev = x
+		}
+		if g.End.Cmp(x.start) <= 0 && (next == nil || x.start.Cmp(next.start) < 0) {
+			next = x
+		}
+	}
+
+	switch {
+	case prev != nil && prev.end.Line() == g.Pos.Line():
+		return prev.n
+	case next != nil && next.start.Line() <= g.End.Line()+1:
+		return next.n
+	case prev != nil && prev.end.Line()+1 == g.Pos.Line():
+		return prev.n
+	case enclDecl != nil:
+		return enclDecl.n
+	case next != nil:
+		return next.n
+	case prev != nil:
+		return prev.n
+	}
+	return nil
+}
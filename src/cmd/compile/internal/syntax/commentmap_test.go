@@ -0,0 +1,128 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCommentMap(t *testing.T) {
+	const src = `// Copyright (file)
+
+// Package p (file)
+package p
+
+// A (A)
+const A = 0 // A (A)
+
+// detached (T)
+
+// T (T)
+type T struct {
+	// x (x)
+	x int // x (x)
+
+	y int
+	// after y (y)
+
+	// detached in struct (T)
+
+	z int
+}
+
+// M (M)
+func (T) M(
+	a int, // a (a)
+) {
+	// in body (M)
+	x := 1
+
+	// v (v)
+	var v int
+	_, _ = x, v
+}
+
+var (
+	B = 1 // B (B)
+	C = 2
+)
+
+// end (C)
+`
+
+	f, err := Parse(NewFileBase("p.go"), strings.NewReader(src), nil, nil, Comments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Comments) != 16 {
+		t.Errorf("got %d comment groups; want 16", len(f.Comments))
+	}
+
+	// Each comment names the node it is expected to be associated with.
+	cmap := NewCommentMap(f)
+	n := 0
+	for node, list := range cmap {
+		var name string
+		switch node := node.(type) {
+		case *File:
+			name = "file"
+		case *ConstDecl:
+			name = node.NameList[0].Value
+		case *VarDecl:
+			name = node.NameList[0].Value
+		case *TypeDecl:
+			name = node.Name.Value
+		case *FuncDecl:
+			name = node.Name.Value
+		case *Field:
+			name = node.Name.Value
+		default:
+			t.Errorf("comment associated with %T", node)
+			continue
+		}
+		for _, g := range list {
+			n++
+			if want := "(" + name + ")"; !strings.HasSuffix(g.Text(), want) {
+				t.Errorf("%s: comment %q associated with %s", g.Pos, g.Text(), name)
+			}
+		}
+	}
+	if n != len(f.Comments) {
+		t.Errorf("got %d associated comment groups; want %d", n, len(f.Comments))
+	}
+
+	// Without the Comments mode, no comments are recorded.
+	f, err = Parse(NewFileBase("p.go"), strings.NewReader(src), nil, nil, DocComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Comments != nil {
+		t.Errorf("got comments %v without Comments mode", f.Comments)
+	}
+}
+
+func TestCommentGroups(t *testing.T) {
+	const src = `package p
+
+var x int // a
+// b
+// c
+
+/* d */ /* e */ var y int /* f */
+`
+	f, err := Parse(nil, strings.NewReader(src), nil, nil, Comments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var groups []string
+	for _, g := range f.Comments {
+		groups = append(groups, fmt.Sprint(g.List))
+	}
+	if got, want := strings.Join(groups, " "), "[// a] [// b // c] [/* d */ /* e */] [/* f */]"; got != want {
+		t.Errorf("got comment groups %s; want %s", got, want)
+	}
+}
@@ -4,6 +4,8 @@
 
 package syntax
 
+import "strings"
+
 // ----------------------------------------------------------------------------
 // Nodes
 
@@ -17,6 +19,9 @@ type Node interface {
 	//    associated with that production; usually the left-most one
 	//    ('[' for IndexExpr, 'if' for IfStmt, etc.)
 	Pos() Pos
+	// SetPos sets the position returned by Pos. It permits synthesized
+	// nodes, which are not created by the parser, to be positioned.
+	SetPos(Pos)
 	aNode()
 }
 
@@ -26,8 +31,9 @@ type node struct {
 	pos Pos
 }
 
-func (n *node) Pos() Pos { return n.pos }
-func (*node) aNode()     {}
+func (n *node) Pos() Pos       { return n.pos }
+func (n *node) SetPos(pos Pos) { n.pos = pos }
+func (*node) aNode()           {}
 
 // ----------------------------------------------------------------------------
 // Files
@@ -35,9 +41,12 @@ func (*node) aNode()     {}
 // package PkgName; DeclList[0], DeclList[1], ...
 type File struct {
 	Pragma   Pragma
+	GoBuild  string // expression of the //go:build constraint of the file (e.g. "linux && amd64"), or ""
 	PkgName  *Name
 	DeclList []Decl
+	Comments []*CommentGroup // all comments in source order (Comments mode only)
 	EOF      Pos
+	src      *fileSource // source and chunks of the file (Incremental mode only)
 	node
 }
 
@@ -53,7 +62,8 @@ type (
 	//              Path
 	// LocalPkgName Path
 	ImportDecl struct {
-		Group        *Group // nil means not part of a group
+		Group        *Group        // nil means not part of a group
+		Doc          *CommentGroup // nil means no doc comment
 		Pragma       Pragma
 		LocalPkgName *Name     // including "."; nil means no rename present
 		Path         *BasicLit // Path.Bad || Path.Kind == StringLit; nil means no path
@@ -64,7 +74,8 @@ type (
 	// NameList      = Values
 	// NameList Type = Values
 	ConstDecl struct {
-		Group    *Group // nil means not part of a group
+		Group    *Group        // nil means not part of a group
+		Doc      *CommentGroup // nil means no doc comment
 		Pragma   Pragma
 		NameList []*Name
 		Type     Expr // nil means no type
@@ -74,7 +85,8 @@ type (
 
 	// Name Type
 	TypeDecl struct {
-		Group      *Group // nil means not part of a group
+		Group      *Group        // nil means not part of a group
+		Doc        *CommentGroup // nil means no doc comment
 		Pragma     Pragma
 		Name       *Name
 		TParamList []*Field // nil means no type parameters
@@ -87,7 +99,8 @@ type (
 	// NameList Type = Values
 	// NameList      = Values
 	VarDecl struct {
-		Group    *Group // nil means not part of a group
+		Group    *Group        // nil means not part of a group
+		Doc      *CommentGroup // nil means no doc comment
 		Pragma   Pragma
 		NameList []*Name
 		Type     Expr // nil means no type
@@ -100,6 +113,7 @@ type (
 	// func Receiver Name Type { Body }
 	// func Receiver Name Type
 	FuncDecl struct {
+		Doc        *CommentGroup // nil means no doc comment
 		Pragma     Pragma
 		Recv       *Field // nil means regular function
 		Name       *Name
@@ -116,7 +130,7 @@ func (*decl) aDecl() {}
 
 // All declarations belonging to the same group point to the same Group node.
 type Group struct {
-	_ int // not empty so we are guaranteed different Group instances
+	Doc *CommentGroup // nil means no doc comment
 }
 
 // ----------------------------------------------------------------------------
@@ -129,6 +143,57 @@ func NewName(pos Pos, value string) *Name {
 	return n
 }
 
+// The following constructors create expression nodes at the given
+// position, as the parser would, for use by code synthesizing syntax
+// trees. The positions of other nodes may be set with Node.SetPos.
+
+// NewBasicLit returns a new literal of the given kind and value.
+func NewBasicLit(pos Pos, kind LitKind, value string) *BasicLit {
+	x := new(BasicLit)
+	x.pos = pos
+	x.Kind = kind
+	x.Value = value
+	return x
+}
+
+// NewOperation returns a new operation x op y, or op x if y is nil.
+// The position is also used as the operator position.
+func NewOperation(pos Pos, op Operator, x, y Expr) *Operation {
+	z := new(Operation)
+	z.pos = pos
+	z.Op = op
+	z.OpPos = pos
+	z.X = x
+	z.Y = y
+	return z
+}
+
+// NewSelectorExpr returns a new selector expression x.sel.
+func NewSelectorExpr(pos Pos, x Expr, sel *Name) *SelectorExpr {
+	z := new(SelectorExpr)
+	z.pos = pos
+	z.X = x
+	z.Sel = sel
+	return z
+}
+
+// NewCallExpr returns a new call fun(args...).
+func NewCallExpr(pos Pos, fun Expr, args ...Expr) *CallExpr {
+	x := new(CallExpr)
+	x.pos = pos
+	x.Fun = fun
+	x.ArgList = args
+	return x
+}
+
+// NewListExpr returns a new expression list with the given elements.
+func NewListExpr(pos Pos, elems ...Expr) *ListExpr {
+	x := new(ListExpr)
+	x.pos = pos
+	x.ElemList = elems
+	return x
+}
+
 type (
 	Expr interface {
 		Node
@@ -138,6 +203,7 @@ type (
 	// Placeholder for an expression that failed to parse
 	// correctly and where we can't provide a better node.
 	BadExpr struct {
+		End Pos // position following the source skipped by the parser, if any
 		expr
 	}
 
@@ -225,9 +291,12 @@ type (
 		expr
 	}
 
+	// Op X
+	// X Op Y
 	Operation struct {
-		Op   Operator
-		X, Y Expr // Y == nil means unary expression
+		Op    Operator
+		OpPos Pos  // position of Op (the same as Pos() for operations created by the parser)
+		X, Y  Expr // Y == nil means unary expression
 		expr
 	}
 
@@ -274,9 +343,11 @@ type (
 
 	// Name Type
 	//      Type
+	//      Name Type = Default
 	Field struct {
-		Name *Name // nil means anonymous field/parameter (structs/parameters), or embedded interface (interfaces)
-		Type Expr  // field names declared in a list share the same Type (identical pointers)
+		Name    *Name // nil means anonymous field/parameter (structs/parameters), or embedded interface (interfaces)
+		Type    Expr  // field names declared in a list share the same Type (identical pointers)
+		Default Expr  // default type of a type parameter, or nil; shared like Type (experimental)
 		node
 	}
 
@@ -338,6 +409,13 @@ type (
 		simpleStmt
 	}
 
+	// Placeholder for a statement that failed to parse
+	// correctly; the parser skipped the source up to End.
+	BadStmt struct {
+		End Pos
+		stmt
+	}
+
 	LabeledStmt struct {
 		Label *Name
 		Stmt  Stmt
@@ -477,3 +555,42 @@ type Comment struct {
 	Text string
 	Next *Comment
 }
+
+// A CommentGroup represents a sequence of comments with no other
+// tokens and no empty lines between them. Comment groups are only
+// collected if the parser is invoked with the DocComments mode, in
+// which case they are attached to the declarations they document,
+// or with the Comments mode, in which case all of them are recorded
+// in File.Comments.
+type CommentGroup struct {
+	List []string // comment texts, including the comment markers (//, /* and */)
+	Pos  Pos      // position of the first comment
+	End  Pos      // position immediately following the last comment
+}
+
+// Text returns the text of the comment group with the comment
+// markers, a single leading blank of each comment, trailing
+// white space, and leading and trailing empty lines removed.
+// Lines are separated by newlines.
+func (g *CommentGroup) Text() string {
+	if g == nil {
+		return ""
+	}
+	var lines []string
+	for _, c := range g.List {
+		c = commentText(c)
+		if strings.HasPrefix(c, " ") {
+			c = c[1:]
+		}
+		for _, line := range strings.Split(c, "\n") {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
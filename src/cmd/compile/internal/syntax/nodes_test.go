@@ -309,6 +309,11 @@ func testPos(t *testing.T, list []test, prefix, suffix string, extract func(*Fil
 			t.Errorf("pos error: %s: pos = %d, want %d (%s)", src, pos, index+colbase, test.nodetyp)
 			continue
 		}
+
+		// the operator position of an operation is its position
+		if op, _ := node.(*Operation); op != nil && op.OpPos != op.Pos() {
+			t.Errorf("pos error: %s: OpPos = %s, want %s (%s)", src, op.OpPos, op.Pos(), test.nodetyp)
+		}
 	}
 }
 
@@ -327,3 +332,46 @@ func typeOf(n Node) string {
 	}
 	return k
 }
+
+func TestNewNodes(t *testing.T) {
+	const src = `package p; var _ = fmt.Println(x + 1, "s")`
+	f, err := Parse(nil, strings.NewReader(src), nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := f.DeclList[0].(*VarDecl).Values.(*CallExpr)
+	sel := call.Fun.(*SelectorExpr)
+	sum := call.ArgList[0].(*Operation)
+	one := sum.Y.(*BasicLit)
+	str := call.ArgList[1].(*BasicLit)
+
+	// build the same expression with the node constructors
+	x := NewCallExpr(call.Pos(),
+		NewSelectorExpr(sel.Pos(), NewName(sel.X.Pos(), "fmt"), NewName(sel.Sel.Pos(), "Println")),
+		NewOperation(sum.Pos(), Add, NewName(sum.X.Pos(), "x"), NewBasicLit(one.Pos(), IntLit, "1")),
+		NewBasicLit(str.Pos(), StringLit, `"s"`),
+	)
+
+	if got, want := String(x), String(call); got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+	positions := func(n Node) (list []string) {
+		Inspect(n, func(n Node) bool {
+			if n != nil {
+				list = append(list, fmt.Sprintf("%s@%s", typeOf(n), n.Pos()))
+			}
+			return true
+		})
+		return
+	}
+	if got, want := positions(x), positions(call); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got positions %v; want %v", got, want)
+	}
+
+	// synthesized nodes may be positioned later
+	l := NewListExpr(Pos{}, x.ArgList...)
+	l.SetPos(sum.Pos())
+	if got := l.Pos(); got != sum.Pos() {
+		t.Errorf("got position %s; want %s", got, sum.Pos())
+	}
+}
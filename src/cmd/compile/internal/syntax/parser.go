@@ -5,6 +5,7 @@
 package syntax
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strconv"
@@ -26,9 +27,25 @@ type parser struct {
 	errcnt int      // number of errors encountered
 	pragma Pragma   // pragmas
 
+	inHeader bool   // set while scanning the file header (before the package clause)
+	goBuild  string // expression of the first //go:build line in the file header, or ""
+
 	fnest  int    // function nesting level (for error handling)
 	xnest  int    // expression nesting level (for complit ambiguity resolution)
 	indent []byte // tracing support
+
+	// comment collection (DocComments and Comments mode only)
+	comments    *CommentGroup   // most recently collected comment group, or nil
+	commentLine uint            // source line on which the most recent comment ends
+	trailing    bool            // set if the comment group started after a token on the same line
+	allComments []*CommentGroup // all comment groups (Comments mode only)
+
+	// incremental parsing (Incremental mode only)
+	text    bytes.Buffer                       // source read so far (Parse only)
+	errors  []reportedError                    // errors reported so far
+	chunks  []chunk                            // chunks parsed so far
+	reparse bool                               // set if the current chunk must always be parsed again
+	resync  func(line uint, imports bool) bool // if set, reports whether to stop before a chunk (Reparse only)
 }
 
 func (p *parser) init(file *PosBase, r io.Reader, errh ErrorHandler, pragh PragmaHandler, mode Mode) {
@@ -36,6 +53,10 @@ func (p *parser) init(file *PosBase, r io.Reader, errh ErrorHandler, pragh Pragm
 	p.errh = errh
 	p.mode = mode
 	p.pragh = pragh
+	scanMode := directives
+	if mode&(DocComments|Comments) != 0 {
+		scanMode = comments
+	}
 	p.scanner.init(
 		r,
 		// Error and directive handler for scanner.
@@ -49,8 +70,13 @@ func (p *parser) init(file *PosBase, r io.Reader, errh ErrorHandler, pragh Pragm
 				return
 			}
 
-			// otherwise it must be a comment containing a line or go: directive.
-			// //line directives must be at the start of the line (column colbase).
+			// Otherwise it must be a comment. Unless all comments are reported
+			// (DocComments or Comments mode), it must be a comment containing a
+			// line or go: directive. //line directives must be at the start of
+			// the line (column colbase).
+			if mode&(DocComments|Comments) != 0 {
+				p.addComment(line, col, msg)
+			}
 			// /*line*/ directives can be anywhere in the line.
 			text := commentText(msg)
 			if (col == colbase || msg[1] == '*') && strings.HasPrefix(text, "line ") {
@@ -69,22 +95,90 @@ func (p *parser) init(file *PosBase, r io.Reader, errh ErrorHandler, pragh Pragm
 				return
 			}
 
+			// //go:build lines must be at the start of the line
+			// and precede the package clause.
+			if p.inHeader && p.goBuild == "" && col == colbase && msg[1] == '/' && isGoBuild(text) {
+				p.goBuild = strings.TrimSpace(text[len("go:build"):])
+			}
+
 			// go: directive (but be conservative and test)
 			if pragh != nil && strings.HasPrefix(text, "go:") {
+				p.reparse = true
 				p.pragma = pragh(p.posAt(line, col+2), p.scanner.blank, text, p.pragma) // +2 to skip over // or /*
 			}
 		},
-		directives,
+		scanMode,
 	)
 
 	p.base = file
 	p.first = nil
 	p.errcnt = 0
 	p.pragma = nil
+	p.inHeader = true
+	p.goBuild = ""
 
 	p.fnest = 0
 	p.xnest = 0
 	p.indent = nil
+
+	p.comments = nil
+	p.commentLine = 0
+	p.trailing = false
+	p.allComments = nil
+
+	p.errors = nil
+	p.chunks = nil
+	p.reparse = false
+	p.resync = nil
+}
+
+// isGoBuild reports whether the comment text (without the leading //)
+// is a //go:build line.
+func isGoBuild(text string) bool {
+	const prefix = "go:build"
+	return strings.HasPrefix(text, prefix) && len(text) > len(prefix) && (text[len(prefix)] == ' ' || text[len(prefix)] == '\t')
+}
+
+// addComment adds the comment text at (line, col) to the current
+// comment group, or starts a new comment group if the comment is
+// separated from the previous one by an empty line or a token, or
+// if it starts its line and the current group follows a token.
+func (p *parser) addComment(line, col uint, text string) {
+	endLine, endCol := line, col+uint(len(text))
+	if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+		endLine += uint(strings.Count(text, "\n"))
+		endCol = colbase + uint(len(text)-(i+1))
+	}
+
+	// If the comment doesn't start its line, a token precedes it
+	// on the same line unless it is preceded by another comment.
+	if p.comments == nil || line > p.commentLine+1 || !p.scanner.blank && line != p.commentLine || p.trailing && p.scanner.blank {
+		p.comments = &CommentGroup{Pos: p.posAt(line, col)}
+		p.trailing = !p.scanner.blank
+		if p.mode&Comments != 0 {
+			p.allComments = append(p.allComments, p.comments)
+		}
+	}
+	p.comments.List = append(p.comments.List, text)
+	p.comments.End = p.posAt(endLine, endCol)
+	p.commentLine = endLine
+}
+
+// takeDoc returns the comment group documenting the declaration
+// starting at the current token, if any, and clears the collected
+// comments from the parser state. A comment group documents a
+// declaration if it ends on the line immediately preceding the
+// declaration and doesn't follow a token on its first line.
+func (p *parser) takeDoc() *CommentGroup {
+	doc := p.comments
+	if doc == nil {
+		return nil
+	}
+	p.comments = nil
+	if p.trailing || p.commentLine+1 != p.line {
+		return nil
+	}
+	return doc
 }
 
 // takePragma returns the current parsed pragmas
@@ -101,6 +195,7 @@ func (p *parser) takePragma() Pragma {
 // to be reported as unused.
 func (p *parser) clearPragma() {
 	if p.pragma != nil {
+		p.reparse = true
 		p.pragh(p.pos(), p.scanner.blank, "", p.pragma)
 		p.pragma = nil
 	}
@@ -110,6 +205,7 @@ func (p *parser) clearPragma() {
 // The base's filename, line, and column values are extracted from text
 // which is positioned at (tline, tcol) (only needed for error messages).
 func (p *parser) updateBase(pos Pos, tline, tcol uint, text string) {
+	p.reparse = true // line bases are not moved with the chunk if it is reused
 	i, n, ok := trailingDigits(text)
 	if i == 0 {
 		return // ignore (not a line directive)
@@ -224,6 +320,9 @@ func (p *parser) errorAt(pos Pos, msg string) {
 		p.first = err
 	}
 	p.errcnt++
+	if p.mode&Incremental != 0 {
+		p.errors = append(p.errors, reportedError{err, false})
+	}
 	if p.errh == nil {
 		panic(p.first)
 	}
@@ -236,8 +335,13 @@ func (p *parser) syntaxErrorAt(pos Pos, msg string) {
 		p.print("syntax error: " + msg)
 	}
 
-	if p.tok == _EOF && p.first != nil {
-		return // avoid meaningless follow-up errors
+	if p.tok == _EOF {
+		// Whether an error is reported at EOF depends on the preceding
+		// errors, so the chunk must be parsed again if it is reused.
+		p.reparse = true
+		if p.first != nil {
+			return // avoid meaningless follow-up errors
+		}
 	}
 
 	// add punctuation etc. as needed to msg
@@ -310,6 +414,27 @@ const stopset uint64 = 1<<_Break |
 	1<<_Type |
 	1<<_Var
 
+// The stmtStart set contains the tokens that may start a statement,
+// or end a statement list.
+const stmtStart uint64 = stopset |
+	1<<_Name |
+	1<<_Literal |
+	1<<_Operator |
+	1<<_Star |
+	1<<_Arrow |
+	1<<_Func |
+	1<<_Lparen |
+	1<<_Lbrack |
+	1<<_Lbrace |
+	1<<_Struct |
+	1<<_Map |
+	1<<_Chan |
+	1<<_Interface |
+	1<<_Semi |
+	1<<_Rbrace |
+	1<<_Case |
+	1<<_Default
+
 // Advance consumes tokens until it finds a token of the stopset or followlist.
 // The stopset is only considered if we are inside a function (p.fnest > 0).
 // The followlist is the list of valid tokens that can follow a production;
@@ -382,6 +507,9 @@ func (p *parser) fileOrNil() *File {
 
 	f := new(File)
 	f.pos = p.pos()
+	p.inHeader = false
+	f.GoBuild = p.goBuild
+	p.addChunk(chunk{line: linebase})
 
 	// PackageClause
 	if !p.got(_Package) {
@@ -397,9 +525,34 @@ func (p *parser) fileOrNil() *File {
 		return nil
 	}
 
+	p.declList(f, true)
+	// p.tok == _EOF
+
+	p.clearPragma()
+	f.EOF = p.pos()
+	f.Comments = p.allComments
+	p.endChunk()
+	if p.mode&Incremental != 0 {
+		f.src = &fileSource{p.file, p.text.Bytes(), p.errh, p.pragh, p.mode, p.errors, p.chunks}
+	}
+
+	return f
+}
+
+// declList parses the import declarations, if imports is set, and the
+// top-level declarations following them, and appends them to f.DeclList.
+// It stops at EOF, or before a chunk at which p.resync reports to stop.
+func (p *parser) declList(f *File, imports bool) {
+	if trace {
+		defer p.trace("declList")()
+	}
+
 	// { ImportDecl ";" }
-	for p.got(_Import) {
+	for imports && p.got(_Import) {
 		f.DeclList = p.appendGroup(f.DeclList, p.importDecl)
+		if p.boundary(f, true) {
+			return
+		}
 		p.want(_Semi)
 	}
 
@@ -425,6 +578,7 @@ func (p *parser) fileOrNil() *File {
 			}
 
 		default:
+			p.reparse = true // the error depends on the previous declaration
 			if p.tok == _Lbrace && len(f.DeclList) > 0 && isEmptyFuncDecl(f.DeclList[len(f.DeclList)-1]) {
 				// opening { of function declaration on next line
 				p.syntaxError("unexpected semicolon or newline before {")
@@ -439,17 +593,56 @@ func (p *parser) fileOrNil() *File {
 		// since comments before may set pragmas for the next function decl.
 		p.clearPragma()
 
+		if p.boundary(f, false) {
+			return
+		}
 		if p.tok != _EOF && !p.got(_Semi) {
 			p.syntaxError("after top level declaration")
 			p.advance(_Const, _Type, _Var, _Func)
 		}
 	}
-	// p.tok == _EOF
+}
 
-	p.clearPragma()
-	f.EOF = p.pos()
+// boundary is called before consuming the ';' terminating a top-level
+// declaration; imports reports whether import declarations may follow. In Incremental mode, if a new chunk starts
+// after the ';', boundary reports whether p.resync requests to stop
+// before it and, if not, records the chunk.
+func (p *parser) boundary(f *File, imports bool) bool {
+	if p.mode&Incremental == 0 || p.tok != _Semi || p.lit != "newline" || p.base != p.file {
+		return false
+	}
+	// The ';' must be the newline ending the line (and not a multi-line
+	// comment), and the comments collected so far must be unrelated to
+	// the following lines, so that the parser state at the beginning of
+	// the next line is the same as at the beginning of a file.
+	if line, col := p.source.pos(); line != p.line+1 || col != colbase {
+		return false
+	}
+	if p.comments != nil && !p.trailing && p.commentLine == p.line {
+		return false
+	}
+	if p.resync != nil && p.resync(p.line+1, imports) {
+		return true
+	}
+	p.addChunk(chunk{p.line + 1, len(f.DeclList), len(p.allComments), len(p.errors), imports, false})
+	return false
+}
 
-	return f
+// addChunk ends the current chunk, if any, and starts the chunk c
+// (Incremental mode only).
+func (p *parser) addChunk(c chunk) {
+	if p.mode&Incremental != 0 {
+		p.endChunk()
+		p.chunks = append(p.chunks, c)
+	}
+}
+
+// endChunk ends the current chunk, if any.
+func (p *parser) endChunk() {
+	if n := len(p.chunks); n > 0 {
+		p.chunks[n-1].reparse = p.chunks[n-1].reparse || p.reparse
+		p.reparse = false
+	}
 }
 
 func isEmptyFuncDecl(dcl Decl) bool {
@@ -498,6 +691,7 @@ func (p *parser) list(sep, close token, f func() bool) Pos {
 func (p *parser) appendGroup(list []Decl, f func(*Group) Decl) []Decl {
 	if p.tok == _Lparen {
 		g := new(Group)
+		g.Doc = p.takeDoc()
 		p.clearPragma()
 		p.next() // must consume "(" after calling clearPragma!
 		p.list(_Semi, _Rparen, func() bool {
@@ -524,6 +718,7 @@ func (p *parser) importDecl(group *Group) Decl {
 	d := new(ImportDecl)
 	d.pos = p.pos()
 	d.Group = group
+	d.Doc = p.takeDoc()
 	d.Pragma = p.takePragma()
 
 	switch p.tok {
@@ -557,6 +752,7 @@ func (p *parser) constDecl(group *Group) Decl {
 	d := new(ConstDecl)
 	d.pos = p.pos()
 	d.Group = group
+	d.Doc = p.takeDoc()
 	d.Pragma = p.takePragma()
 
 	d.NameList = p.nameList(p.name())
@@ -579,6 +775,7 @@ func (p *parser) typeDecl(group *Group) Decl {
 	d := new(TypeDecl)
 	d.pos = p.pos()
 	d.Group = group
+	d.Doc = p.takeDoc()
 	d.Pragma = p.takePragma()
 
 	d.Name = p.name()
@@ -596,12 +793,9 @@ func (p *parser) typeDecl(group *Group) Decl {
 			x := p.expr()
 			p.xnest--
 			if name0, ok := x.(*Name); p.mode&AllowGenerics != 0 && ok && p.tok != _Rbrack {
-				// generic type
+				// generic type or parameterized alias
 				d.TParamList = p.paramList(name0, _Rbrack, true)
-				pos := p.pos()
-				if p.gotAssign() {
-					p.syntaxErrorAt(pos, "generic type cannot be alias")
-				}
+				d.Alias = p.gotAssign()
 				d.Type = p.typeOrNil()
 			} else {
 				// x is the array length expression
@@ -636,6 +830,7 @@ func (p *parser) varDecl(group *Group) Decl {
 	d := new(VarDecl)
 	d.pos = p.pos()
 	d.Group = group
+	d.Doc = p.takeDoc()
 	d.Pragma = p.takePragma()
 
 	d.NameList = p.nameList(p.name())
@@ -663,6 +858,7 @@ func (p *parser) funcDeclOrNil() *FuncDecl {
 
 	f := new(FuncDecl)
 	f.pos = p.pos()
+	f.Doc = p.takeDoc()
 	f.Pragma = p.takePragma()
 
 	if p.got(_Lparen) {
@@ -711,7 +907,14 @@ func (p *parser) funcBody() *BlockStmt {
 	// as it may lead to spurious errors (e.g., see test/switch2.go) or
 	// possibly crashes due to incomplete syntax trees.
 	if p.mode&CheckBranches != 0 && errcnt == p.errcnt {
-		checkBranches(body, p.errh)
+		errh := p.errh
+		if p.mode&Incremental != 0 {
+			errh = func(err error) {
+				p.errors = append(p.errors, reportedError{err.(Error), true})
+				p.errh(err)
+			}
+		}
+		checkBranches(body, errh)
 	}
 
 	return body
@@ -736,6 +939,7 @@ func (p *parser) binaryExpr(prec int) Expr {
 	for (p.tok == _Operator || p.tok == _Star) && p.prec > prec {
 		t := new(Operation)
 		t.pos = p.pos()
+		t.OpPos = t.pos
 		t.Op = p.op
 		tprec := p.prec
 		p.next()
@@ -758,6 +962,7 @@ func (p *parser) unaryExpr() Expr {
 		case Mul, Add, Sub, Not, Xor:
 			x := new(Operation)
 			x.pos = p.pos()
+			x.OpPos = x.pos
 			x.Op = p.op
 			p.next()
 			x.X = p.unaryExpr()
@@ -766,6 +971,7 @@ func (p *parser) unaryExpr() Expr {
 		case And:
 			x := new(Operation)
 			x.pos = p.pos()
+			x.OpPos = x.pos
 			x.Op = And
 			p.next()
 			// unaryExpr may have returned a parenthesized composite literal
@@ -827,6 +1033,7 @@ func (p *parser) unaryExpr() Expr {
 		// x is not a channel type => we have a receive op
 		o := new(Operation)
 		o.pos = pos
+		o.OpPos = o.pos
 		o.Op = Recv
 		o.X = x
 		return o
@@ -940,6 +1147,7 @@ func (p *parser) operand(keep_parens bool) Expr {
 		x := p.badExpr()
 		p.syntaxError("expecting expression")
 		p.advance(_Rparen, _Rbrack, _Rbrace)
+		x.End = p.pos()
 		return x
 	}
 
@@ -1220,6 +1428,7 @@ func (p *parser) type_() Expr {
 func newIndirect(pos Pos, typ Expr) Expr {
 	o := new(Operation)
 	o.pos = pos
+	o.OpPos = o.pos
 	o.Op = Mul
 	o.X = typ
 	return o
@@ -1803,6 +2012,7 @@ func (p *parser) embeddedElem(f *Field) *Field {
 	for p.tok == _Operator && p.op == Or {
 		t := new(Operation)
 		t.pos = p.pos()
+		t.OpPos = t.pos
 		t.Op = Or
 		p.next()
 		t.X = f.Type
@@ -1822,6 +2032,7 @@ func (p *parser) embeddedTerm() Expr {
 	if p.tok == _Operator && p.op == Tilde {
 		t := new(Operation)
 		t.pos = p.pos()
+		t.OpPos = t.pos
 		t.Op = Tilde
 		p.next()
 		t.X = p.type_()
@@ -1839,7 +2050,9 @@ func (p *parser) embeddedTerm() Expr {
 }
 
 // ParameterDecl = [ IdentifierList ] [ "..." ] Type .
-func (p *parser) paramDeclOrNil(name *Name) *Field {
+// If typeSet is set, the parameter is a type parameter and its
+// constraint may be a constraint literal such as ~int or int | string.
+func (p *parser) paramDeclOrNil(name *Name, typeSet bool) *Field {
 	if trace {
 		defer p.trace("paramDecl")()
 	}
@@ -1875,6 +2088,13 @@ func (p *parser) paramDeclOrNil(name *Name) *Field {
 		f.Name = name
 	}
 
+	if typeSet && p.tok == _Operator && p.op == Tilde {
+		// constraint literal
+		f.Type = p.embeddedElem(nil).Type
+		p.typeParamDefault(f)
+		return f
+	}
+
 	if p.tok == _DotDotDot {
 		t := new(DotsType)
 		t.pos = p.pos()
@@ -1889,6 +2109,13 @@ func (p *parser) paramDeclOrNil(name *Name) *Field {
 	}
 
 	f.Type = p.typeOrNil()
+	if typeSet && f.Type != nil && p.tok == _Operator && p.op == Or {
+		// constraint literal
+		p.embeddedElem(f)
+	}
+	if typeSet && f.Type != nil {
+		p.typeParamDefault(f)
+	}
 	if f.Name != nil || f.Type != nil {
 		return f
 	}
@@ -1898,6 +2125,20 @@ func (p *parser) paramDeclOrNil(name *Name) *Field {
 	return nil
 }
 
+// typeParamDefault parses an optional default type "= Type"
+// following the constraint of type parameter f.
+func (p *parser) typeParamDefault(f *Field) {
+	if p.tok != _Assign {
+		return
+	}
+	p.next()
+	f.Default = p.typeOrNil()
+	if f.Default == nil {
+		f.Default = p.badExpr()
+		p.syntaxError("missing default type")
+	}
+}
+
 // Parameters    = "(" [ ParameterList [ "," ] ] ")" .
 // ParameterList = ParameterDecl { "," ParameterDecl } .
 // "(" or "[" has already been consumed.
@@ -1908,9 +2149,12 @@ func (p *parser) paramList(name *Name, close token, requireNames bool) (list []*
 		defer p.trace("paramList")()
 	}
 
+	// type parameter constraints may be constraint literals
+	typeSet := p.mode&AllowGenerics != 0 && close == _Rbrack && requireNames
+
 	var named int // number of parameters that have an explicit name and type/bound
 	p.list(_Comma, close, func() bool {
-		par := p.paramDeclOrNil(name)
+		par := p.paramDeclOrNil(name, typeSet)
 		name = nil // 1st name was consumed if present
 		if par != nil {
 			if debug && par.Name == nil && par.Type == nil {
@@ -1928,7 +2172,7 @@ func (p *parser) paramList(name *Name, close token, requireNames bool) (list []*
 		return
 	}
 
-	// distribute parameter types (len(list) > 0)
+	// distribute parameter types and defaults (len(list) > 0)
 	if named == 0 && !requireNames {
 		// all unnamed => found names are named types
 		for _, par := range list {
@@ -1940,16 +2184,18 @@ func (p *parser) paramList(name *Name, close token, requireNames bool) (list []*
 	} else if named != len(list) {
 		// some named => all must have names and types
 		var pos Pos // left-most error position (or unknown)
-		var typ Expr
+		var typ, def Expr
 		for i := len(list) - 1; i >= 0; i-- {
 			if par := list[i]; par.Type != nil {
 				typ = par.Type
+				def = par.Default
 				if par.Name == nil {
 					pos = typ.Pos()
 					par.Name = NewName(pos, "_")
 				}
 			} else if typ != nil {
 				par.Type = typ
+				par.Default = def
 			} else {
 				// par.Type == nil && typ == nil => we only have a par.Name
 				pos = par.Name.Pos()
@@ -1978,6 +2224,12 @@ func (p *parser) badExpr() *BadExpr {
 	return b
 }
 
+func (p *parser) badStmt() *BadStmt {
+	s := new(BadStmt)
+	s.pos = p.pos()
+	return s
+}
+
 // ----------------------------------------------------------------------------
 // Statements
 
@@ -2264,6 +2516,9 @@ done:
 			b := new(BadExpr)
 			b.pos = semi.pos
 			cond = b
+		} else if keyword == _If {
+			// an error was reported already (missing '{')
+			cond = p.badExpr()
 		}
 	case *ExprStmt:
 		cond = s.X
@@ -2282,6 +2537,11 @@ done:
 			str = String(s)
 		}
 		p.syntaxErrorAt(s.Pos(), fmt.Sprintf("cannot use %s as value", str))
+		if keyword == _If {
+			b := new(BadExpr)
+			b.pos = s.Pos()
+			cond = b
+		}
 	}
 
 	p.xnest = outer
@@ -2306,8 +2566,11 @@ func (p *parser) ifStmt() *IfStmt {
 		case _Lbrace:
 			s.Else = p.blockStmt("")
 		default:
+			b := p.badStmt()
 			p.syntaxError("else must be followed by if or statement block")
-			p.advance(_Name, _Rbrace)
+			p.advance(_Semi, _Name, _Rbrace)
+			b.End = p.pos()
+			s.Else = b
 		}
 	}
 
@@ -2541,10 +2804,23 @@ func (p *parser) stmtList() (l []Stmt) {
 	}
 
 	for p.tok != _EOF && p.tok != _Rbrace && p.tok != _Case && p.tok != _Default {
+		errcnt := p.errcnt
 		s := p.stmtOrNil()
 		p.clearPragma()
 		if s == nil {
-			break
+			if p.errcnt != errcnt || p.tok == _Package || p.tok == _Import {
+				break // error reported already, or probably a missing }
+			}
+			// Skip the tokens which cannot start a statement so that
+			// the remaining statements of the list are not lost.
+			b := p.badStmt()
+			p.syntaxError("expecting statement")
+			for p.next(); p.tok != _EOF && !contains(stmtStart, p.tok); p.next() {
+			}
+			b.End = p.pos()
+			l = append(l, b)
+			p.got(_Semi) // avoid spurious empty statement
+			continue
 		}
 		l = append(l, s)
 		// ";" is optional before "}"
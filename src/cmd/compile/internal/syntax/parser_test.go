@@ -375,3 +375,191 @@ func TestLineDirectives(t *testing.T) {
 		}
 	}
 }
+
+func TestDocComments(t *testing.T) {
+	const src = `// package doc (not collected)
+package p
+
+// imports
+import "fmt"
+
+// A is a constant.
+// It has a two-line doc comment.
+const A = 0
+
+/* B is a variable. */
+var B = fmt.Sprint(A) // trailing comment
+func C() {}
+
+// group doc
+type (
+	// D is a type.
+	D int
+
+	E int // E has no doc comment
+
+	// not a doc comment
+
+	F int
+)
+
+// G is a method.
+func (D) G() {
+	// H is local.
+	var H int
+	_ = H
+}
+`
+
+	f, err := Parse(NewFileBase("doc.go"), strings.NewReader(src), nil, nil, DocComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := make(map[string]string)
+	var groupDoc string
+	Inspect(f, func(n Node) bool {
+		var name, doc string
+		var g *CommentGroup
+		switch n := n.(type) {
+		case *ImportDecl:
+			name, g = n.Path.Value, n.Doc
+		case *ConstDecl:
+			name, g = n.NameList[0].Value, n.Doc
+		case *VarDecl:
+			name, g = n.NameList[0].Value, n.Doc
+		case *TypeDecl:
+			name, g = n.Name.Value, n.Doc
+			if n.Group != nil && n.Group.Doc != nil {
+				groupDoc = n.Group.Doc.Text()
+			}
+		case *FuncDecl:
+			name, g = n.Name.Value, n.Doc
+		default:
+			return true
+		}
+		if g != nil {
+			doc = g.Text()
+			if !g.Pos.IsKnown() || g.End.Cmp(g.Pos) <= 0 {
+				t.Errorf("%s: invalid doc comment extent [%s, %s]", name, g.Pos, g.End)
+			}
+		}
+		docs[name] = doc
+		return true
+	})
+
+	want := map[string]string{
+		`"fmt"`: "imports",
+		"A":     "A is a constant.\nIt has a two-line doc comment.",
+		"B":     "B is a variable.",
+		"C":     "",
+		"D":     "D is a type.",
+		"E":     "",
+		"F":     "",
+		"G":     "G is a method.",
+		"H":     "H is local.",
+	}
+	for name, doc := range want {
+		if got, ok := docs[name]; !ok {
+			t.Errorf("%s: declaration not found", name)
+		} else if got != doc {
+			t.Errorf("%s: got doc %q; want %q", name, got, doc)
+		}
+	}
+	if groupDoc != "group doc" {
+		t.Errorf("got group doc %q; want %q", groupDoc, "group doc")
+	}
+
+	// Without DocComments mode, no comments are collected.
+	f, err = Parse(NewFileBase("doc.go"), strings.NewReader(src), nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Inspect(f, func(n Node) bool {
+		if d, _ := n.(*ConstDecl); d != nil && d.Doc != nil {
+			t.Errorf("%s: unexpected doc comment", d.NameList[0].Value)
+		}
+		return true
+	})
+}
+
+func TestGoBuild(t *testing.T) {
+	for _, test := range []struct {
+		src, want string
+	}{
+		{"package p", ""},
+		{"//go:build linux\n\npackage p", "linux"},
+		{"// Copyright\n\n//go:build linux && (amd64 || arm64)\n// +build linux\n\npackage p", "linux && (amd64 || arm64)"},
+		{"//go:build a\n//go:build b\npackage p", "a"},                     // only the first line counts
+		{"/*go:build a */ package p", ""},                                  // not a line comment
+		{"//go:buildx\npackage p", ""},                                     // not a //go:build line
+		{"package p\n//go:build linux\nfunc f()", ""},                      // not in the file header
+		{"//line x.go:10\n//go:build ignore\npackage p", "ignore"},         // line directives don't matter
+		{"//go:build linux\r\npackage p", "linux"},                         // Windows line ending
+		{"//go:build\tlinux \npackage p", "linux"},                         // surrounding blanks are trimmed
+		{"const x = 0 //go:build linux\npackage p", ""},                    // invalid file
+		{" //go:build linux\npackage p", ""},                               // not at the start of the line
+		{"//go:build !windows\npackage p\n//go:build linux\n", "!windows"}, // later lines ignored
+	} {
+		f, _ := Parse(nil, strings.NewReader(test.src), nil, nil, 0)
+		got := ""
+		if f != nil {
+			got = f.GoBuild
+		}
+		if got != test.want {
+			t.Errorf("%q: got //go:build %q; want %q", test.src, got, test.want)
+		}
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	for _, test := range []struct {
+		body  string // function body
+		stmts string // statements of the body
+		bad   string // source spanned by the bad statement or expression
+	}{
+		{"x := 1; ); y := 2", "AssignStmt BadStmt AssignStmt", ")"},
+		{"x := 1; ) ] ; y := 2", "AssignStmt BadStmt AssignStmt", ") ] "},
+		{"else { g() }", "BadStmt BlockStmt", "else "},
+		{"f(); :; return", "ExprStmt BadStmt ReturnStmt", ":"},
+		{"if x {} else ); y := 2", "IfStmt AssignStmt", ")"},
+		{"_ = f(, y)", "AssignStmt", ", y"},
+	} {
+		src := "package p; func _() { " + test.body + " }"
+		var errs []error
+		f, _ := Parse(nil, strings.NewReader(src), func(err error) { errs = append(errs, err) }, nil, 0)
+		if len(errs) != 1 {
+			t.Errorf("%s: got errors %v; want 1 error", test.body, errs)
+		}
+		if f == nil || len(f.DeclList) != 1 {
+			t.Errorf("%s: function declaration not found", test.body)
+			continue
+		}
+
+		var stmts []string
+		for _, s := range f.DeclList[0].(*FuncDecl).Body.List {
+			stmts = append(stmts, strings.TrimPrefix(fmt.Sprintf("%T", s), "*syntax."))
+		}
+		if got := strings.Join(stmts, " "); got != test.stmts {
+			t.Errorf("%s: got statements %s; want %s", test.body, got, test.stmts)
+		}
+
+		bad := "<none>"
+		Inspect(f, func(n Node) bool {
+			var start, end Pos
+			switch n := n.(type) {
+			case *BadStmt:
+				start, end = n.Pos(), n.End
+			case *BadExpr:
+				start, end = n.Pos(), n.End
+			default:
+				return true
+			}
+			bad = src[start.Col()-1 : end.Col()-1]
+			return false
+		})
+		if bad != test.bad {
+			t.Errorf("%s: got bad source %q; want %q", test.body, bad, test.bad)
+		}
+	}
+}
@@ -79,6 +79,7 @@ func StartPos(n Node) Pos {
 
 		// statements
 		// case *EmptyStmt:
+		// case *BadStmt:
 		// case *LabeledStmt:
 		// case *BlockStmt:
 		// case *ExprStmt:
@@ -172,6 +173,9 @@ func EndPos(n Node) Pos {
 
 		// expressions
 		case *BadExpr:
+			if n.End.IsKnown() {
+				return n.End
+			}
 			return n.Pos()
 		case *Name:
 			p := n.Pos()
@@ -266,6 +270,8 @@ func EndPos(n Node) Pos {
 		// statements
 		case *EmptyStmt:
 			return n.Pos()
+		case *BadStmt:
+			return n.End
 		case *LabeledStmt:
 			m = n.Stmt
 		case *BlockStmt:
@@ -558,6 +558,9 @@ func (p *printer) printRawNode(n Node) {
 	case *EmptyStmt:
 		// nothing to print
 
+	case *BadStmt:
+		p.print(_Name, "<bad stmt>")
+
 	case *LabeledStmt:
 		p.print(outdent, n.Label, _Colon, indent, newline, n.Stmt)
 
@@ -770,6 +773,10 @@ func (p *printer) printFields(fields []*Field, tags []*BasicLit, i, j int) {
 		}
 		p.print(blank)
 		p.printNode(fields[i].Type)
+		if d := fields[i].Default; d != nil {
+			p.print(blank, _Assign, blank)
+			p.printNode(d)
+		}
 	}
 	if i < len(tags) && tags[i] != nil {
 		p.print(blank)
@@ -779,15 +786,16 @@ func (p *printer) printFields(fields []*Field, tags []*BasicLit, i, j int) {
 
 func (p *printer) printFieldList(fields []*Field, tags []*BasicLit, sep token) {
 	i0 := 0
-	var typ Expr
+	var typ, def Expr
 	for i, f := range fields {
-		if f.Name == nil || f.Type != typ {
+		if f.Name == nil || f.Type != typ || f.Default != def {
 			if i0 < i {
 				p.printFields(fields, tags, i0, i)
 				p.print(sep, newline)
 				i0 = i
 			}
 			typ = f.Type
+			def = f.Default
 		}
 	}
 	p.printFields(fields, tags, i0, len(fields))
@@ -67,6 +67,15 @@ var stringTests = []string{
 	"package p; func _[A, B, C interface{m()}]()",
 	"package p; func _[T any, A, B, C interface{m()}, X, Y, Z interface{type int}]()",
 
+	// type parameters with default types
+	"package p; type _[K comparable, V any = int] struct{}",
+	"package p; type _[A, B any = []int, C interface{m()} = T] struct{}",
+	"package p; func _[A any, B any = []A]()",
+
+	// parameterized aliases
+	"package p; type _[P any] = []P",
+	"package p; type _[K comparable, V any] = map[K]V",
+
 	// methods with generic receiver types
 	"package p; func (R[T]) _()",
 	"package p; func (*R[A, B, C]) _()",
@@ -0,0 +1,311 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements Reparse, which parses a file again after its
+// source was edited, reusing the declarations not affected by the edits.
+
+package syntax
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// An Edit replaces the bytes src[Start:End] of a source src with New.
+type Edit struct {
+	Start, End int    // byte offsets, with 0 <= Start <= End <= len(src)
+	New        string // replacement text
+}
+
+// A fileSource holds the source of a file parsed in Incremental mode,
+// and the information needed to parse parts of it again.
+type fileSource struct {
+	base   *PosBase
+	text   []byte
+	errh   ErrorHandler
+	pragh  PragmaHandler
+	mode   Mode
+	errors []reportedError // errors reported for the file, in order
+	chunks []chunk         // chunks of the file, in source order
+}
+
+// A reportedError is an error reported by the parser in Incremental mode.
+type reportedError struct {
+	Error
+	branch bool // reported by checkBranches, and not returned by Parse
+}
+
+// A chunk is a sequence of source lines which the parser can parse
+// independently of the preceding lines. The first chunk of a file
+// starts at its beginning and includes the package clause and the first
+// declaration. Each other chunk starts at the beginning of a line
+// following a declaration, in the same parser state as after the package
+// clause. A chunk extends up to the next one or, for the last chunk, to
+// the end of the file.
+type chunk struct {
+	line     uint // first line of the chunk
+	decls    int  // index of the first declaration of the chunk in File.DeclList
+	comments int  // index of the first comment group of the chunk in File.Comments
+	errors   int  // index of the first error reported for the chunk
+	imports  bool // whether the chunk may start with import declarations
+	reparse  bool // whether the chunk must always be parsed again
+}
+
+// Reparse applies the edits to the source of the file old, which must
+// have been returned by Parse (or Reparse) in Incremental mode, and
+// parses the new source again, with the position base, error handler,
+// pragma handler, and mode used for old. The edits must be sorted by
+// offset and must not overlap. The result, including the errors reported
+// and returned, is the same as the one of Parse for the new source.
+//
+// Only the parts of the source affected by the edits are parsed again:
+// A file is split into chunks, each of which consists of the lines of one
+// or more consecutive declarations (and the comments preceding them).
+// Parsing starts at each chunk affected by an edit, and stops at the next
+// chunk which is not affected anymore. The declarations and comment
+// groups of all other chunks are reused, and their positions adjusted, and
+// the errors reported for them are reported again. Chunks for which the
+// pragma handler was called, and chunks whose errors depend on preceding
+// chunks, are always parsed again. If the first chunk, which contains the
+// package clause and the first declaration, is affected, the entire source
+// is parsed again.
+//
+// The declarations reused by Reparse are shared with old, which must
+// not be used anymore.
+func Reparse(old *File, edits []Edit) (_ *File, first error) {
+	src := old.src
+	if src == nil {
+		return nil, errors.New("file was not parsed in Incremental mode")
+	}
+	offset := 0
+	for _, e := range edits {
+		if e.Start < offset || e.End < e.Start || e.End > len(src.text) {
+			return nil, errors.New("invalid, unsorted, or overlapping edits")
+		}
+		offset = e.End
+	}
+
+	// apply edits
+	var buf bytes.Buffer
+	offset = 0
+	for _, e := range edits {
+		buf.Write(src.text[offset:e.Start])
+		buf.WriteString(e.New)
+		offset = e.End
+	}
+	buf.Write(src.text[offset:])
+	text := buf.Bytes()
+
+	// Determine the chunks not affected by the edits, and the offsets and
+	// lines at which the chunks start in the new source. An edit affects a
+	// chunk if it changes its source or inserts source at its start. (If an
+	// edit changes the source preceding a chunk, the chunk is only reused if
+	// parsing the preceding source stops at its start.)
+	type span struct {
+		offset int  // byte offset of the chunk in the new source
+		delta  int  // line delta of the chunk in the new source
+		reuse  bool // set if the chunk is not affected by the edits
+	}
+	lines := lineOffsets(src.text)
+	spans := make([]span, len(src.chunks))
+	resume := make(map[uint]int) // first line in new source -> chunk not affected
+	i, offDelta, lineDelta := 0, 0, 0
+	for j, c := range src.chunks {
+		start, end := lines[c.line-linebase], len(src.text)+1
+		if j+1 < len(src.chunks) {
+			end = lines[src.chunks[j+1].line-linebase]
+		}
+		for ; i < len(edits) && (edits[i].End < start || edits[i].End == start && edits[i].Start < start); i++ {
+			e := edits[i]
+			offDelta += len(e.New) - (e.End - e.Start)
+			lineDelta += strings.Count(e.New, "\n") - bytes.Count(src.text[e.Start:e.End], []byte("\n"))
+		}
+		reuse := !c.reparse && (i == len(edits) || edits[i].Start >= end)
+		spans[j] = span{start + offDelta, lineDelta, reuse}
+		if reuse && j > 0 {
+			resume[shiftLine(c.line, lineDelta)] = j
+		}
+	}
+	if len(spans) == 0 || !spans[0].reuse {
+		return Parse(src.base, bytes.NewReader(text), src.errh, src.pragh, src.mode)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if err, ok := p.(Error); ok {
+				first = err
+				return
+			}
+			panic(p)
+		}
+	}()
+
+	f := new(File)
+	f.pos = old.pos
+	f.Pragma = old.Pragma
+	f.GoBuild = old.GoBuild
+	f.PkgName = old.PkgName
+
+	var (
+		p        parser
+		comments []*CommentGroup
+		errs     []reportedError
+		chunks   []chunk
+	)
+	for j := 0; j < len(src.chunks); {
+		c, s := src.chunks[j], spans[j]
+		line := shiftLine(c.line, s.delta)
+
+		if s.reuse {
+			next := chunk{decls: len(old.DeclList), comments: len(old.Comments), errors: len(src.errors)}
+			if j+1 < len(src.chunks) {
+				next = src.chunks[j+1]
+			}
+			chunks = append(chunks, chunk{line, len(f.DeclList), len(comments), len(errs), c.imports, false})
+			sh := shifter{s.delta, make(map[interface{}]bool)}
+			for _, d := range old.DeclList[c.decls:next.decls] {
+				sh.node(d)
+				f.DeclList = append(f.DeclList, d)
+			}
+			for _, g := range old.Comments[c.comments:next.comments] {
+				sh.group(g)
+				comments = append(comments, g)
+			}
+			for _, err := range src.errors[c.errors:next.errors] {
+				err.Pos = sh.pos(err.Pos)
+				errs = append(errs, err)
+				if first == nil && !err.branch {
+					first = err.Error
+				}
+				src.errh(err.Error) // errors were reported, so errh != nil
+			}
+			if j+1 == len(src.chunks) {
+				f.EOF = sh.pos(old.EOF)
+			}
+			j++
+			continue
+		}
+
+		// Parse the source starting with chunk j up to the next chunk
+		// that is reused, if any. Parsing is resumed in the same state as
+		// after parsing the preceding chunks from the beginning.
+		p.init(src.base, bytes.NewReader(text[s.offset:]), src.errh, src.pragh, src.mode)
+		p.source.line = line - linebase
+		p.inHeader = false
+		p.first = first
+		p.allComments, p.errors, p.chunks = comments, errs, chunks
+		j = len(src.chunks)
+		p.resync = func(line uint, imports bool) bool {
+			if k, ok := resume[line]; ok && src.chunks[k].imports == imports {
+				j = k
+				return true
+			}
+			return false
+		}
+		p.addChunk(chunk{line, len(f.DeclList), len(comments), len(errs), c.imports, false})
+		p.next()
+		p.declList(f, c.imports)
+		if p.tok == _EOF {
+			p.clearPragma()
+			f.EOF = p.pos()
+		}
+		p.endChunk()
+		comments, errs, chunks, first = p.allComments, p.errors, p.chunks, p.first
+	}
+
+	f.Comments = comments
+	f.src = &fileSource{src.base, text, src.errh, src.pragh, src.mode, errs, chunks}
+	return f, first
+}
+
+// lineOffsets returns the byte offsets of the lines of text.
+func lineOffsets(text []byte) []int {
+	offsets := []int{0}
+	for i, b := range text {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+func shiftLine(line uint, delta int) uint {
+	return uint(int(line) + delta)
+}
+
+// A shifter moves the nodes and comment groups of a reused chunk
+// by delta lines.
+type shifter struct {
+	delta int
+	seen  map[interface{}]bool // nodes and comment groups moved already
+}
+
+func (s *shifter) pos(pos Pos) Pos {
+	if s.delta == 0 || !pos.IsKnown() {
+		return pos
+	}
+	return MakePos(pos.Base(), shiftLine(pos.Line(), s.delta), pos.Col())
+}
+
+func (s *shifter) group(g *CommentGroup) {
+	if g != nil && !s.seen[g] {
+		s.seen[g] = true
+		g.Pos = s.pos(g.Pos)
+		g.End = s.pos(g.End)
+	}
+}
+
+func (s *shifter) node(root Node) {
+	if s.delta == 0 {
+		return
+	}
+	Inspect(root, func(n Node) bool {
+		// Nodes may be shared (see Walk); move them only once.
+		if n == nil || s.seen[n] {
+			return false
+		}
+		s.seen[n] = true
+		n.SetPos(s.pos(n.Pos()))
+
+		switch n := n.(type) {
+		case *ImportDecl:
+			s.decl(n.Group, n.Doc)
+		case *ConstDecl:
+			s.decl(n.Group, n.Doc)
+		case *TypeDecl:
+			s.decl(n.Group, n.Doc)
+		case *VarDecl:
+			s.decl(n.Group, n.Doc)
+		case *FuncDecl:
+			s.group(n.Doc)
+		case *BadExpr:
+			n.End = s.pos(n.End)
+		case *CompositeLit:
+			n.Rbrace = s.pos(n.Rbrace)
+		case *Operation:
+			n.OpPos = s.pos(n.OpPos)
+		case *BadStmt:
+			n.End = s.pos(n.End)
+		case *BlockStmt:
+			n.Rbrace = s.pos(n.Rbrace)
+		case *SwitchStmt:
+			n.Rbrace = s.pos(n.Rbrace)
+		case *SelectStmt:
+			n.Rbrace = s.pos(n.Rbrace)
+		case *CaseClause:
+			n.Colon = s.pos(n.Colon)
+		case *CommClause:
+			n.Colon = s.pos(n.Colon)
+		}
+		return true
+	})
+}
+
+func (s *shifter) decl(group *Group, doc *CommentGroup) {
+	if group != nil {
+		s.group(group.Doc)
+	}
+	s.group(doc)
+}
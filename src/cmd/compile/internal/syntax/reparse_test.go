@@ -0,0 +1,215 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const reparseSrc = `// Package p.
+package p
+
+import "fmt"
+
+// A is a constant.
+const A = 1
+
+type T struct {
+	x int
+}
+
+func (T) m() {
+	fmt.Println(A)
+}
+
+var (
+	x = 1
+	y = 2 // y
+)
+
+func f() {
+	/* multi-line
+	   comment */
+}
+`
+
+func TestReparse(t *testing.T) {
+	for _, test := range []struct {
+		edits  []string // pairs of (unique) old and new source text
+		reused int      // number of declarations reused
+	}{
+		{nil, 7},
+		{[]string{"fmt.Println(A)", "fmt.Println(A, A)\n\tprintln()"}, 6},
+		{[]string{"type T struct", "var z int\n\ntype T struct"}, 6},
+		{[]string{"func (T) m() {\n\tfmt.Println(A)\n}\n", ""}, 4},
+		{[]string{"const A = 1", "const A = 2", "y = 2", "y = 3"}, 4},
+		{[]string{"\nconst A", "\nimport \"os\"\nconst A"}, 6},
+		{[]string{"}\n\nfunc (T) m()", "}; func (T) m()"}, 5},
+		{[]string{"   comment */\n}\n", "   comment */\n}\n\nfunc g() {}\n"}, 6},
+		{[]string{"type T", "/* type T"}, 2},
+		{[]string{"x = 1", "x = "}, 5},
+		{[]string{"x int\n}", "x int\n"}, 5},
+		{[]string{"package p", "package q"}, 0},
+	} {
+		var errors []string
+		errh := func(err error) { errors = append(errors, err.Error()) }
+		const mode = Incremental | Comments
+		old, _ := Parse(NewFileBase("p.go"), strings.NewReader(reparseSrc), errh, nil, mode)
+
+		// apply the edits
+		var edits []Edit
+		src := reparseSrc
+		for i := 0; i < len(test.edits); i += 2 {
+			start := strings.Index(reparseSrc, test.edits[i])
+			edits = append(edits, Edit{start, start + len(test.edits[i]), test.edits[i+1]})
+			src = strings.Replace(src, test.edits[i], test.edits[i+1], 1)
+		}
+
+		errors = nil
+		want, wantErr := Parse(NewFileBase("p.go"), strings.NewReader(src), errh, nil, mode)
+		wantErrors := errors
+
+		oldDecls := make(map[Decl]bool)
+		for _, d := range old.DeclList {
+			oldDecls[d] = true
+		}
+		errors = nil
+		got, gotErr := Reparse(old, edits)
+		if got == nil {
+			t.Errorf("%q: Reparse failed: %v", test.edits, gotErr)
+			continue
+		}
+
+		if g, w := dumpFile(got), dumpFile(want); g != w {
+			t.Errorf("%q: got\n%s\nwant\n%s", test.edits, g, w)
+		}
+		if g, w := fmt.Sprint(errors), fmt.Sprint(wantErrors); g != w {
+			t.Errorf("%q: got errors %s; want %s", test.edits, g, w)
+		}
+		if fmt.Sprint(gotErr) != fmt.Sprint(wantErr) {
+			t.Errorf("%q: got error %v; want %v", test.edits, gotErr, wantErr)
+		}
+		reused := 0
+		for _, d := range got.DeclList {
+			if oldDecls[d] {
+				reused++
+			}
+		}
+		if reused != test.reused {
+			t.Errorf("%q: %d declarations reused; want %d", test.edits, reused, test.reused)
+		}
+
+		// The result may be parsed again, too.
+		errors = nil
+		again, _ := Reparse(got, []Edit{{len(src), len(src), "var _ = 0\n"}})
+		want, _ = Parse(NewFileBase("p.go"), strings.NewReader(src+"var _ = 0\n"), errh, nil, mode)
+		if g, w := dumpFile(again), dumpFile(want); g != w {
+			t.Errorf("%q: reparse again: got\n%s\nwant\n%s", test.edits, g, w)
+		}
+	}
+}
+
+func TestReparsePragmas(t *testing.T) {
+	const src = `package p
+
+var _ int
+
+//go:noinline
+func f() {}
+
+func g() {}
+`
+	var pragmas []string
+	pragh := func(pos Pos, blank bool, text string, current Pragma) Pragma {
+		pragmas = append(pragmas, fmt.Sprintf("%s: %s", pos, text))
+		return text
+	}
+	old, err := Parse(NewFileBase("p.go"), strings.NewReader(src), nil, pragh, Incremental)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := old.DeclList[2]
+
+	// The pragma handler sees the pragmas of reused chunks again.
+	pragmas = nil
+	f, err := Reparse(old, []Edit{{len(src), len(src), "\nvar x int\n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(pragmas), "[p.go:5:3: go:noinline]"; got != want {
+		t.Errorf("got pragmas %s; want %s", got, want)
+	}
+	if got := f.DeclList[1].(*FuncDecl).Pragma; got != "go:noinline" {
+		t.Errorf("got pragma %v; want go:noinline", got)
+	}
+	if f.DeclList[2] != g {
+		t.Errorf("declaration not reused")
+	}
+
+	// Edits before the package clause cause the entire file to be parsed again.
+	f, err = Reparse(f, []Edit{{0, 0, "// Package p.\n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.DeclList[2] == g {
+		t.Errorf("declaration reused")
+	}
+	if got, want := f.DeclList[2].Pos().String(), "p.go:9:6"; got != want {
+		t.Errorf("got position %s; want %s", got, want)
+	}
+
+	if _, err := Reparse(f, []Edit{{2, 3, ""}, {0, 1, ""}}); err == nil {
+		t.Errorf("no error for unsorted edits")
+	}
+}
+
+// dumpFile returns a dump of the nodes of the syntax tree f, including
+// all positions and comment groups.
+func dumpFile(f *File) string {
+	var buf strings.Builder
+	Inspect(f, func(n Node) bool {
+		if n == nil {
+			fmt.Fprintln(&buf, "end")
+			return false
+		}
+		fmt.Fprintf(&buf, "%T @ %s", n, n.Pos())
+		switch n := n.(type) {
+		case *Name:
+			fmt.Fprintf(&buf, " %s", n.Value)
+		case *BasicLit:
+			fmt.Fprintf(&buf, " %s", n.Value)
+		case *Operation:
+			fmt.Fprintf(&buf, " %s", n.Op)
+		}
+		v := reflect.ValueOf(n).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			x := v.Field(i)
+			if !x.CanInterface() {
+				continue // unexported
+			}
+			switch x := x.Interface().(type) {
+			case Pos:
+				fmt.Fprintf(&buf, " %s", x)
+			case *CommentGroup:
+				if x != nil {
+					fmt.Fprintf(&buf, " %s-%s", x.Pos, x.End)
+				}
+			case *Group:
+				if x != nil && x.Doc != nil {
+					fmt.Fprintf(&buf, " %s-%s", x.Doc.Pos, x.Doc.End)
+				}
+			}
+		}
+		fmt.Fprintln(&buf)
+		return true
+	})
+	for _, g := range f.Comments {
+		fmt.Fprintf(&buf, "%s-%s %q\n", g.Pos, g.End, g.List)
+	}
+	return buf.String()
+}
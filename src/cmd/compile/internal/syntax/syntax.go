@@ -18,6 +18,9 @@ const (
 	CheckBranches Mode = 1 << iota // check correct use of labels, break, continue, and goto statements
 	AllowGenerics
 	AllowTypeLists // requires AllowGenerics; remove once 1.18 is out
+	DocComments    // collect doc comments and attach them to declarations
+	Comments       // collect all comments in File.Comments (see NewCommentMap); implies DocComments
+	Incremental    // retain the source and the information needed by Reparse
 )
 
 // Error describes a syntax error. Error implements the error interface.
@@ -78,6 +81,9 @@ func Parse(base *PosBase, src io.Reader, errh ErrorHandler, pragh PragmaHandler,
 	}()
 
 	var p parser
+	if mode&Incremental != 0 {
+		src = io.TeeReader(src, &p.text)
+	}
 	p.init(base, src, errh, pragh, mode)
 	p.next()
 	return p.fileOrNil(), p.first
@@ -89,7 +89,9 @@ func (w walker) node(n Node) {
 		if n.LocalPkgName != nil {
 			w.node(n.LocalPkgName)
 		}
-		w.node(n.Path)
+		if n.Path != nil {
+			w.node(n.Path)
+		}
 
 	case *ConstDecl:
 		w.nameList(n.NameList)
@@ -212,6 +214,9 @@ func (w walker) node(n Node) {
 			w.node(n.Name)
 		}
 		w.node(n.Type)
+		if n.Default != nil {
+			w.node(n.Default)
+		}
 
 	case *InterfaceType:
 		w.fieldList(n.MethodList)
@@ -229,6 +234,7 @@ func (w walker) node(n Node) {
 
 	// statements
 	case *EmptyStmt: // nothing to do
+	case *BadStmt: // nothing to do
 
 	case *LabeledStmt:
 		w.node(n.Label)
@@ -35,6 +35,20 @@
 //         }
 //     }
 //
+//     TypeSets []struct{ // follows the compiler's inline body index (see below)
+//         Name       stringOff
+//         Comparable bool
+//         All        bool
+//         Terms      []struct{ // omitted if All is set
+//             Tilde bool
+//             Type  typeOff
+//         }
+//         Methods []struct{
+//             PkgPath stringOff
+//             Name    stringOff
+//         }
+//     }
+//
 //     Fingerprint [8]byte
 //
 // uvarint means a uint64 written out using uvarint encoding.
@@ -99,6 +113,8 @@
 // predeclReserved, then it indicates the index into the predeclared
 // types list (see predeclared in bexport.go for order). Otherwise,
 // subtracting predeclReserved yields the offset of a type descriptor.
+// In format version iexportVersionOrdered, predeclReserved is one
+// larger, and the last reserved index denotes the constraint ordered.
 //
 // Value means a type and type-specific value. See
 // (*exportWriter).value for details.
@@ -188,6 +204,13 @@
 //     }
 //
 //
+// TypeSets describes the normalized type sets of the exported constraint
+// interface types declared in the package, sorted by name, for tools
+// that want to reason about constraints without recomputing type sets.
+// Terms are pairwise disjoint; All indicates that the type set is not
+// restricted by type terms, and Methods lists the full method set.
+//
+//
 // Pos encodes a file:line:column triple, incorporating a simple delta
 // encoding scheme within a data object. See exportWriter.pos for
 // details.
@@ -225,6 +248,7 @@ import (
 // 0: Go1.11 encoding
 // 1: added column details to Pos
 // 2: added information for generic function/types (currently unstable)
+// 3: reserved a predeclared type index for ordered (experimental)
 const (
 	iexportVersionGo1_11 = 0
 	iexportVersionPosCol = 1
@@ -233,12 +257,28 @@ const (
 	iexportVersionGenerics = iexportVersionPosCol
 
 	iexportVersionCurrent = iexportVersionGenerics
+
+	// iexportVersionOrdered is written instead of iexportVersionCurrent
+	// with GOEXPERIMENT=operatorconstraints (i.e., if types.OrderedType
+	// is set).
+	iexportVersionOrdered = 3
 )
 
 // predeclReserved is the number of type offsets reserved for types
 // implicitly declared in the universe block.
 const predeclReserved = 32
 
+// predeclReservedFor returns the number of type offsets reserved for
+// predeclared types in export data of the given format version.
+// Version iexportVersionOrdered reserves an additional offset, with
+// index predeclReserved, for the constraint ordered.
+func predeclReservedFor(version uint64) uint64 {
+	if version == iexportVersionOrdered {
+		return predeclReserved + 1
+	}
+	return predeclReserved
+}
+
 // An itag distinguishes the kind of type that was written into the
 // indexed export format.
 type itag uint64
@@ -280,6 +320,7 @@ func WriteExports(out io.Writer, extensions bool) {
 		inlineIndex: map[*types.Sym]uint64{},
 		typIndex:    map[*types.Type]uint64{},
 		extensions:  extensions,
+		version:     iexportVersionCurrent,
 	}
 
 	for i, pt := range predeclared() {
@@ -288,6 +329,10 @@ func WriteExports(out io.Writer, extensions bool) {
 	if len(p.typIndex) > predeclReserved {
 		base.Fatalf("too many predeclared types: %d > %d", len(p.typIndex), predeclReserved)
 	}
+	if types.OrderedType != nil {
+		p.version = iexportVersionOrdered
+		p.typIndex[types.OrderedType] = predeclReserved
+	}
 
 	// Initialize work queue with exported declarations.
 	for _, n := range Target.Exports {
@@ -301,12 +346,20 @@ func WriteExports(out io.Writer, extensions bool) {
 		p.doDecl(p.declTodo.PopLeft())
 	}
 
-	// Append indices to data0 section.
+	// The type set terms may refer to additional declarations.
+	tw := p.newWriter()
+	tw.writeTypeSets()
+	for !p.declTodo.Empty() {
+		p.doDecl(p.declTodo.PopLeft())
+	}
+
+	// Append indices and type sets to data0 section.
 	dataLen := uint64(p.data0.Len())
 	w := p.newWriter()
 	w.writeIndex(p.declIndex, true)
 	w.writeIndex(p.inlineIndex, false)
 	w.flush()
+	tw.flush()
 
 	if *base.Flag.LowerV {
 		fmt.Printf("export: hdr strings %v, data %v, index %v\n", p.strings.Len(), dataLen, p.data0.Len())
@@ -315,7 +368,7 @@ func WriteExports(out io.Writer, extensions bool) {
 	// Assemble header.
 	var hdr intWriter
 	hdr.WriteByte('i')
-	hdr.uint64(iexportVersionCurrent)
+	hdr.uint64(p.version)
 	hdr.uint64(uint64(p.strings.Len()))
 	hdr.uint64(dataLen)
 
@@ -386,6 +439,50 @@ func (w *exportWriter) writeIndex(index map[*types.Sym]uint64, mainIndex bool) {
 	}
 }
 
+// writeTypeSets writes out the normalized type sets of the exported
+// constraint interfaces declared in the local package.
+func (w *exportWriter) writeTypeSets() {
+	var list []*ir.Name
+	for _, n := range Target.Exports {
+		if n.Op() == ir.OTYPE && n.Sym().Pkg == types.LocalPkg {
+			if t := n.Type(); t.IsInterface() && t.Underlying().TypeSet() != nil {
+				list = append(list, n)
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Sym().Name < list[j].Sym().Name
+	})
+
+	w.uint64(uint64(len(list)))
+	for _, n := range list {
+		t := n.Type().Underlying()
+		ts := t.TypeSet()
+		w.string(n.Sym().Name)
+		w.bool(ts.Comparable)
+		if !w.bool(ts.All) {
+			w.uint64(uint64(len(ts.Terms)))
+			for i, term := range ts.Terms {
+				w.bool(ts.Tildes[i])
+				w.typ(term)
+			}
+		}
+		var methods []*types.Field
+		for _, m := range t.AllMethods().Slice() {
+			// Skip the pseudo-method of comparable; it is
+			// accounted for by Comparable.
+			if m.Sym.Name != "==" {
+				methods = append(methods, m)
+			}
+		}
+		w.uint64(uint64(len(methods)))
+		for _, m := range methods {
+			w.pkg(m.Sym.Pkg)
+			w.string(m.Sym.Name)
+		}
+	}
+}
+
 type iexporter struct {
 	// allPkgs tracks all packages that have been referenced by
 	// the export data, so we can ensure to include them in the
@@ -403,6 +500,7 @@ type iexporter struct {
 	typIndex    map[*types.Type]uint64
 
 	extensions bool
+	version    uint64 // export format version
 }
 
 // stringOff returns the offset of s within the string section.
@@ -574,6 +672,10 @@ func (p *iexporter) doDecl(n *ir.Name) {
 			// Do same for AnyType as for ErrorType.
 			underlying = types.AnyType
 		}
+		if types.OrderedType != nil && underlying == types.OrderedType.Underlying() {
+			// Do same for OrderedType as for ErrorType.
+			underlying = types.OrderedType
+		}
 		w.typ(underlying)
 
 		t := n.Type()
@@ -874,7 +976,7 @@ func (p *iexporter) typOff(t *types.Type) uint64 {
 		if *base.Flag.LowerV {
 			fmt.Printf("export: typ %v %v\n", rawOff, t)
 		}
-		off = predeclReserved + rawOff
+		off = predeclReservedFor(p.version) + rawOff
 		p.typIndex[t] = off
 	}
 	return off
@@ -119,6 +119,11 @@ func ReadImports(pkg *types.Pkg, data string) {
 	version := ird.uint64()
 	switch version {
 	case /* iexportVersionGenerics, */ iexportVersionPosCol, iexportVersionGo1_11:
+	case iexportVersionOrdered:
+		if types.OrderedType == nil {
+			base.Errorf("import %q: export data requires GOEXPERIMENT=operatorconstraints", pkg.Path)
+			base.ErrorExit()
+		}
 	default:
 		if version > iexportVersionGenerics {
 			base.Errorf("import %q: unstable export format version %d, just recompile", pkg.Path, version)
@@ -142,6 +147,8 @@ func ReadImports(pkg *types.Pkg, data string) {
 		exportVersion: version,
 		ipkg:          pkg,
 
+		predeclReserved: predeclReservedFor(version),
+
 		pkgCache:     map[uint64]*types.Pkg{},
 		posBaseCache: map[uint64]*src.PosBase{},
 		typCache:     map[uint64]*types.Type{},
@@ -153,6 +160,9 @@ func ReadImports(pkg *types.Pkg, data string) {
 	for i, pt := range predeclared() {
 		p.typCache[uint64(i)] = pt
 	}
+	if version == iexportVersionOrdered {
+		p.typCache[predeclReserved] = types.OrderedType
+	}
 
 	// Declaration index.
 	for nPkgs := ird.uint64(); nPkgs > 0; nPkgs-- {
@@ -201,8 +211,9 @@ func ReadImports(pkg *types.Pkg, data string) {
 }
 
 type iimporter struct {
-	exportVersion uint64
-	ipkg          *types.Pkg
+	exportVersion   uint64
+	predeclReserved uint64 // see predeclReservedFor
+	ipkg            *types.Pkg
 
 	pkgCache     map[uint64]*types.Pkg
 	posBaseCache map[uint64]*src.PosBase
@@ -701,10 +712,10 @@ func (r *importReader) exoticSym() *types.Sym {
 func (p *iimporter) typAt(off uint64) *types.Type {
 	t, ok := p.typCache[off]
 	if !ok {
-		if off < predeclReserved {
+		if off < p.predeclReserved {
 			base.Fatalf("predeclared type missing from cache: %d", off)
 		}
-		t = p.newReader(off-predeclReserved, nil).typ1()
+		t = p.newReader(off-p.predeclReserved, nil).typ1()
 		// Ensure size is calculated for imported types. Since CL 283313, the compiler
 		// does not compile the function immediately when it sees them. Instead, funtions
 		// are pushed to compile queue, then draining from the queue for compiling.
@@ -121,6 +121,9 @@ var (
 	ComparableType *Type
 	// Predeclared any interface type.
 	AnyType *Type
+	// Predeclared ordered interface type
+	// (only with GOEXPERIMENT=operatorconstraints).
+	OrderedType *Type
 
 	// Types to represent untyped string and boolean constants.
 	UntypedString = newType(TSTRING)
@@ -417,7 +420,17 @@ func (t *Type) StructType() *Struct {
 
 // Interface contains Type fields specific to interface types.
 type Interface struct {
-	pkg *Pkg
+	pkg     *Pkg
+	typeSet *TypeSet // normalized type set of a constraint interface; or nil
+}
+
+// A TypeSet describes the normalized type set of a constraint
+// interface as computed by types2. It is only recorded for export data.
+type TypeSet struct {
+	Comparable bool    // each type in the type set is comparable
+	All        bool    // the type set is not restricted by type terms
+	Terms      []*Type // pairwise disjoint type terms; empty if the type set is empty
+	Tildes     []bool  // whether Terms[i] is of form ~T
 }
 
 // Typeparam contains Type fields specific to typeparam types.
@@ -1837,6 +1850,19 @@ func NewInterface(pkg *Pkg, methods []*Field) *Type {
 	return t
 }
 
+// TypeSet returns the normalized type set recorded for interface t,
+// or nil if there is none.
+func (t *Type) TypeSet() *TypeSet {
+	t.wantEtype(TINTER)
+	return t.extra.(*Interface).typeSet
+}
+
+// SetTypeSet records the normalized type set of interface t.
+func (t *Type) SetTypeSet(ts *TypeSet) {
+	t.wantEtype(TINTER)
+	t.extra.(*Interface).typeSet = ts
+}
+
 // NewTypeParam returns a new type param with the specified sym (package and name)
 // and specified index within the typeparam list.
 func NewTypeParam(sym *Sym, index int) *Type {
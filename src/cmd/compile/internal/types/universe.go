@@ -7,6 +7,7 @@ package types
 import (
 	"cmd/compile/internal/base"
 	"cmd/internal/src"
+	"internal/buildcfg"
 )
 
 var basicTypes = [...]struct {
@@ -115,6 +116,16 @@ func InitTypes(defTypeName func(sym *Sym, typ *Type) Object) {
 		ResumeCheckSize()
 	}
 
+	// ordered type (interface)
+	// The ordering constraint is enforced by types2; here it is
+	// just an empty interface.
+	if base.Flag.G > 0 && buildcfg.Experiment.OperatorConstraints {
+		DeferCheckSize()
+		OrderedType = defBasic(TFORW, BuiltinPkg, "ordered")
+		OrderedType.SetUnderlying(NewInterface(NoPkg, []*Field{}))
+		ResumeCheckSize()
+	}
+
 	Types[TUNSAFEPTR] = defBasic(TUNSAFEPTR, UnsafePkg, "Pointer")
 
 	Types[TBLANK] = newType(TBLANK)
@@ -27,19 +27,36 @@ package types2
 import (
 	"bytes"
 	"cmd/compile/internal/syntax"
+	"errors"
 	"fmt"
 	"go/constant"
+	"io"
 )
 
 // An Error describes a type-checking error; it implements the error interface.
 // A "soft" error is an error that still permits a valid interpretation of a
 // package (such as "unused variable"); "hard" errors may lead to unpredictable
 // behavior if ignored.
+//
+// The Code of an error classifies it independently of its message, which
+// may change; for instance, all duplicate declarations are reported with
+// code DuplicateDecl. An error may refer to secondary positions, such as
+// the position of the other declaration for a duplicate declaration; these
+// are listed in Related, in order. Msg describes the secondary positions
+// on separate lines following the primary message; Primary is the primary
+// message alone, for tools that present the secondary positions
+// separately.
 type Error struct {
-	Pos  syntax.Pos // error position
-	Msg  string     // default error message, user-friendly
-	Full string     // full error message, for debugging (may contain internal details)
-	Soft bool       // if set, error is "soft"
+	Pos     syntax.Pos    // error position
+	End     syntax.Pos    // approximate end of the erroneous source range (see syntax.EndPos), or unknown
+	Msg     string        // default error message, user-friendly
+	Full    string        // full error message, for debugging (may contain internal details)
+	Soft    bool          // if set, error is "soft"
+	Warning bool          // if set, error is a warning which doesn't count as a type-checking error (see Config.Severity)
+	Fix     *SuggestedFix // suggested fix, or nil
+	Code    ErrorCode     // error code, or 0 if the error is not classified
+	Related []RelatedPos  // secondary positions, or nil
+	Primary string        // Msg without the descriptions of the Related positions
 }
 
 // Error returns an error string formatted as follows:
@@ -55,6 +72,29 @@ func (err Error) FullError() string {
 	return fmt.Sprintf("%s: %s", err.Pos, err.Full)
 }
 
+// A RelatedPos describes a secondary position of an Error.
+type RelatedPos struct {
+	Pos syntax.Pos // secondary position
+	Msg string     // description of the position, such as "other declaration of x"
+}
+
+// A SuggestedFix describes an edit of the source which fixes an error:
+// the source text from Pos up to (but excluding) End is to be replaced
+// with NewText.
+type SuggestedFix struct {
+	Pos, End syntax.Pos
+	NewText  string
+}
+
+// A Severity describes how soft errors are reported (see Config.Severity).
+type Severity int
+
+const (
+	SeverityError   Severity = iota // the error is a type-checking error (default)
+	SeverityWarning                 // the error is reported as a warning
+	SeverityIgnore                  // the error is not reported
+)
+
 // An ArgumentError holds an error that is associated with an argument.
 type ArgumentError struct {
 	index int
@@ -112,13 +152,45 @@ type Config struct {
 	// must follow the format "go%d.%d" (e.g. "go1.12") or ist must be
 	// empty; an empty string indicates the latest language version.
 	// If the format is invalid, invoking the type checker will cause a
-	// panic.
+	// panic. A file whose //go:build constraint requires a later Go
+	// version (such as "//go:build go1.18") may use the features of that
+	// version, which permits migrating a package to a newer version file
+	// by file.
 	GoVersion string
 
+	// If ModuleGoVersion is set, it is called with the path of the
+	// package to be checked before type-checking begins. Drivers may
+	// use it to supply the language version declared by the go
+	// directive of the go.mod file of the module containing the package.
+	// The result may follow the go.mod format "%d.%d" (e.g. "1.16") or
+	// the GoVersion format (e.g. "go1.16"); if it is not the empty string,
+	// it takes precedence over GoVersion for that package. An invalid
+	// result causes a panic, as does an invalid GoVersion.
+	ModuleGoVersion func(pkgPath string) string
+
 	// If IgnoreFuncBodies is set, function bodies are not
 	// type-checked.
 	IgnoreFuncBodies bool
 
+	// If IgnoreUnexportedFuncBodies is set, only the bodies of
+	// exported functions and of exported methods of exported types
+	// are type-checked. The resulting package is complete for the
+	// purpose of importing it, but its unexported function bodies
+	// may contain undetected errors. As with IgnoreFuncBodies,
+	// packages are not checked for unused imports.
+	IgnoreUnexportedFuncBodies bool
+
+	// If ConcurrentFuncBodies is set, the bodies of function declarations
+	// are type-checked concurrently, using up to GOMAXPROCS goroutines,
+	// once all package-level declarations are checked. The recorded type
+	// information is the same as without concurrency, except that the
+	// instances of generic types created in different function bodies are
+	// not necessarily shared. Errors in function bodies are reported after
+	// all other errors, in the order of the function declarations; calls
+	// of Error are not concurrent. ConcurrentFuncBodies is ignored if Trace
+	// or TraceWriter is set.
+	ConcurrentFuncBodies bool
+
 	// If FakeImportC is set, `import "C"` (for packages requiring Cgo)
 	// declares an empty "C" package and errors are omitted for qualified
 	// identifiers referring to package C (which won't find an object).
@@ -143,6 +215,102 @@ type Config struct {
 	//           the parser.
 	AllowTypeLists bool
 
+	// If ReportEmptyTypeSets is set, interfaces with a provably empty
+	// type set, such as interface{ ~int; ~string } or interface{ int; m() },
+	// are reported as soft errors. Such interfaces are valid, but if they
+	// are used as constraints, no type argument can ever satisfy them and
+	// errors otherwise only surface at instantiations.
+	ReportEmptyTypeSets bool
+
+	// If ReportDroppedTerms is set, a union embedded in an interface
+	// with methods is reported as a soft error if some of its terms are
+	// specific types (not ~T terms) which don't have all the methods,
+	// since those terms silently drop out of the interface's type set.
+	// If all terms of the type set drop out and ReportEmptyTypeSets is
+	// set, only the empty type set is reported.
+	ReportDroppedTerms bool
+
+	// If ReportUnused is set, type parameters of generic types and
+	// functions which are never referenced, and elements embedded in an
+	// interface which contribute no methods or type terms beyond those
+	// of the interface's other methods and elements (such as io.Reader
+	// in interface{ io.Reader; io.ReadCloser }), are reported as soft
+	// errors. Blank type parameters are not reported.
+	ReportUnused bool
+
+	// If OperatorConstraints is set, the predeclared constraint ordered
+	// is available. Its type set consists of all types that support the
+	// ordering operators <, <=, >, and >=, so these operators may be
+	// applied to values of type parameters constrained by ordered.
+	// This is an experimental feature (GOEXPERIMENT=operatorconstraints)
+	// and may change or disappear.
+	OperatorConstraints bool
+
+	// If UnionMethods is set, the terms of a union may be interfaces with
+	// methods, which the spec does not permit. The methods then belong to
+	// the type set of the union, which requires that all interface terms
+	// with methods have the same methods, and that all other terms are
+	// specific types (not ~T terms) which have these methods, as in
+	// interface{ T | interface{ ~int; String() string } } where T has a
+	// String method. Other unions with methods are reported as errors, as
+	// are unions with interfaces which are or embed comparable. This is
+	// an experimental feature and may change or disappear.
+	UnionMethods bool
+
+	// If DefaultTypeArgs is set, type parameters may declare a default
+	// type, as in [K comparable, V any = int]. A default type is used
+	// for a type argument that is omitted and cannot be inferred; it
+	// may refer to preceding type parameters. Only trailing type
+	// parameters may have defaults.
+	// This is an experimental feature (GOEXPERIMENT=defaulttypeargs)
+	// and may change or disappear.
+	DefaultTypeArgs bool
+
+	// If MethodTypeParams is set, methods may declare their own type
+	// parameters, in addition to the type parameters of their receiver.
+	// This is an experimental feature (GOEXPERIMENT=methodtypeparams)
+	// and may change or disappear.
+	MethodTypeParams bool
+
+	// If AliasTypeParams is set, alias declarations may declare type
+	// parameters, as in type A[P any] = []P. Such a parameterized alias
+	// must be instantiated when used; A[int] denotes []int.
+	// This is an experimental feature (GOEXPERIMENT=aliastypeparams)
+	// and may change or disappear.
+	AliasTypeParams bool
+
+	// MaxUnionTerms, MaxEmbeddingDepth, and MaxInterfaceMethods limit
+	// the complexity of interfaces, so that adversarial or generated
+	// inputs cannot exhaust time and memory while the checker computes
+	// type sets. They are the maximum number of terms of a union's type
+	// set, the maximum nesting depth of embedded interfaces, and the
+	// maximum number of methods of an interface, including embedded
+	// methods. Exceeding a limit is reported as an error. A limit of 0
+	// selects the default limit (100, 100, and 10000, respectively).
+	MaxUnionTerms       int
+	MaxEmbeddingDepth   int
+	MaxInterfaceMethods int
+
+	// If CompleteTypes is set, Info.Types records the type of every
+	// expression in the checked files, not only of the expressions
+	// that appear where an arbitrary expression is permitted. In
+	// particular, it records declared identifiers, including blank
+	// identifiers on the left-hand side of assignments, selectors in
+	// selector expressions, function types and receiver types of
+	// function declarations, and unions and ~T terms in constraints.
+	// Identifiers are recorded with the mode of the object they denote:
+	// variables are recorded as variables, constants with their values,
+	// type names as types, and functions as values.
+	//
+	// The following expressions are never recorded: ListExpr,
+	// KeyValueExpr, and TypeSwitchGuard nodes, which are syntactic
+	// groupings rather than expressions; identifiers denoting packages
+	// or labels; import paths; the symbol of a type switch guard, which
+	// has a different type in each clause (see Info.Implicits); and the
+	// "type" keyword of type lists. If the package has type errors,
+	// some expressions may not be recorded.
+	CompleteTypes bool
+
 	// If go115UsesCgo is set, the type checker expects the
 	// _cgo_gotypes.go file generated by running cmd/cgo to be
 	// provided as a package source file. Qualified identifiers
@@ -155,6 +323,27 @@ type Config struct {
 	// If Trace is set, a debug trace is printed to stdout.
 	Trace bool
 
+	// If TraceWriter is set, structured trace events describing the
+	// steps of type-checking, with their positions and durations, are
+	// written to it (see TraceEvent), independently of Trace.
+	TraceWriter io.Writer
+
+	// If Phase != nil, it is called with the name of each phase of
+	// type checking when the phase starts; the previous phase ends at
+	// that point. The phases are "initFiles", "collectObjects",
+	// "packageObjects", "processDelayed" (which includes checking
+	// function bodies), "funcBodies" (if ConcurrentFuncBodies is set
+	// and there are function bodies, which are checked in this phase
+	// instead), "initOrder", "unusedImports" and "recordUntyped".
+	// Interface type sets are computed lazily and may interrupt any
+	// phase; their computation is reported as phase "typeSets",
+	// followed by the interrupted phase again. Type sets computed while
+	// function bodies are checked concurrently are not reported.
+	//
+	// Phase is intended for attributing resources (such as time and
+	// memory) to the parts of the type checker that use them.
+	Phase func(phase string)
+
 	// If Error != nil, it is called with each error found
 	// during type checking; err has dynamic type Error.
 	// Secondary errors (for instance, to enumerate all types
@@ -164,6 +353,24 @@ type Config struct {
 	// error found.
 	Error func(err error)
 
+	// If SortErrors is set and Error != nil, the errors found while
+	// checking a set of files are collected and passed to Error in
+	// order of their positions once type checking is done (rather than
+	// in the order they are found, which depends on the order in which
+	// declarations are processed), with secondary errors following the
+	// errors they belong to. The error returned by Check is the first
+	// sorted error.
+	SortErrors bool
+
+	// If MaxErrors > 0 and Error != nil, type checking stops once
+	// MaxErrors errors have been passed to Error (or collected, if
+	// SortErrors is set). Unused variables and imports that are
+	// tolerated (see TolerateUnused) and other warnings (see Severity)
+	// don't count. If MaxErrors is 0,
+	// all errors are reported; if Error is nil, type checking stops
+	// with the first error regardless of MaxErrors.
+	MaxErrors int
+
 	// An importer is used to import packages referred to from
 	// import declarations.
 	// If the installed importer implements ImporterFrom, the type
@@ -173,12 +380,54 @@ type Config struct {
 	Importer Importer
 
 	// If Sizes != nil, it provides the sizing functions for package unsafe.
-	// Otherwise SizesFor("gc", "amd64") is used instead.
+	// Otherwise &StdSizes{WordSize: 8, MaxAlign: 8} is used instead; use
+	// SizesFor to compute sizes as a compiler does.
 	Sizes Sizes
 
+	// If Targets is set, constant expressions whose values depend on
+	// type sizes, alignments, or field offsets (via unsafe.Sizeof,
+	// Alignof, and Offsetof) are also evaluated with the Sizes of each
+	// target, in the same check. An error is reported if such a value
+	// overflows its type, is a zero divisor, or is an invalid array
+	// length for some target. Types are those for Sizes: an array
+	// whose length differs for some target has the length for Sizes.
+	// See also Info.TargetValues.
+	Targets []Target
+
 	// If DisableUnusedImportCheck is set, packages are not checked
 	// for unused imports.
 	DisableUnusedImportCheck bool
+
+	// If TolerateUnused is set, unused variables and imports are
+	// not considered errors: they are still passed to Error (as
+	// soft errors and warnings), but they don't stop type checking
+	// if Error is nil, and they are not returned as the error result
+	// of Check.
+	TolerateUnused bool
+
+	// Severity maps error codes to the severity of the soft errors with
+	// those codes (see Error.Soft), such as errors for language features
+	// which require a later Go version. Soft errors with severity
+	// SeverityWarning are treated like tolerated unused variables (see
+	// TolerateUnused): they are passed to Error as warnings (see
+	// Error.Warning), but they are not considered errors. Soft errors with
+	// severity SeverityIgnore are not reported at all. Hard errors are
+	// always errors.
+	Severity map[ErrorCode]Severity
+
+	// Predeclared lists additional predeclared objects for the
+	// checked package: types, constants, variables, and functions
+	// that are in scope in every file as if they were declared in the
+	// Universe scope. This permits drivers to check dialects of Go
+	// with additional predeclared identifiers; a function may serve as
+	// a built-in with a custom signature. The objects must have types,
+	// and should have no package, like the objects of the Universe
+	// scope. A predeclared object shadows an object of the Universe
+	// scope with the same name; of several objects with the same
+	// name, the first one is used. The objects are inserted into a
+	// scope that encloses the package scope for lookups only: the
+	// parent of the package scope remains the Universe scope.
+	Predeclared []Object
 }
 
 func srcimporter_setUsesCgo(conf *Config) {
@@ -206,7 +455,8 @@ type Info struct {
 	// expression x.f is found only in the Selections map, the
 	// identifier z in a variable declaration 'var z int' is found
 	// only in the Defs map, and identifiers denoting packages in
-	// qualified identifiers are collected in the Uses map.
+	// qualified identifiers are collected in the Uses map. With
+	// Config.CompleteTypes, nearly all expressions are recorded.
 	Types map[syntax.Expr]TypeAndValue
 
 	// Inferred maps calls of parameterized functions that use
@@ -215,6 +465,12 @@ type Info struct {
 	// an *ast.CallExpr (as in f(x)), or an *ast.IndexExpr (s in f[T]).
 	Inferred map[syntax.Expr]Inferred
 
+	// TargetValues maps constant expressions whose values differ for
+	// some of Config.Targets to their values for each of the targets,
+	// in the order of Config.Targets. The value for Config.Sizes is
+	// recorded in Types.
+	TargetValues map[syntax.Expr][]constant.Value
+
 	// Defs maps identifiers to the objects they define (including
 	// package names, dots "." of dot-imports, and blank "_" identifiers).
 	// For identifiers that do not denote objects (e.g., the package name
@@ -233,6 +489,16 @@ type Info struct {
 	// Invariant: Uses[id].Pos() != id.Pos()
 	Uses map[*syntax.Name]Object
 
+	// Deprecated maps identifiers to the deprecated objects they denote
+	// (see Object.Deprecated), including the selectors of qualified
+	// identifiers, field selections, and method selections. It contains
+	// a subset of the entries of Uses. Deprecated objects are only known
+	// if their doc comments are available, that is, for objects declared
+	// in source files parsed with syntax.DocComments. Export data doesn't
+	// record doc comments: uses of imported objects are only recorded if
+	// the importer type-checked their package from such source files.
+	Deprecated map[*syntax.Name]Object
+
 	// Implicits maps nodes to their implicitly declared objects, if any.
 	// The following node and object types may appear:
 	//
@@ -248,6 +514,68 @@ type Info struct {
 	// to their corresponding selections.
 	Selections map[*syntax.SelectorExpr]*Selection
 
+	// Conversions maps expressions to the implicit conversions of their
+	// values to interface types. Such conversions take place when a
+	// (non-nil) value whose type is not identical to an interface type
+	// is assigned to a variable of that interface type (including when
+	// passing arguments, returning results, sending values on channels,
+	// and specifying composite literal elements), or when a non-interface
+	// value is compared against an operand of that interface type.
+	//
+	// The source type of a conversion may be a concrete type, a type
+	// parameter, or another interface type. Untyped constants are
+	// recorded with their default type as the source type.
+	Conversions map[syntax.Expr]Conversion
+
+	// ImplicitOps maps selector, index, and slice expressions to the
+	// address and dereference operations that are implicitly applied
+	// to their operands. Expressions without implicit operations are
+	// omitted.
+	//
+	// For a selector x.f, the operations include those applied to the
+	// embedded fields on the path from x to f: an implicit dereference
+	// is recorded if x or any embedded field on the path is a pointer
+	// through which f is reached, and an implicit address operation is
+	// recorded if f is a method with a pointer receiver selected on a
+	// (addressable) non-pointer value. For an index expression a[i] or
+	// a slice expression a[i:j], an implicit dereference is recorded if
+	// a is a pointer to an array, and an implicit address operation is
+	// recorded if a is an (addressable) array that is sliced.
+	ImplicitOps map[syntax.Expr]ImplicitOp
+
+	// ImplicitInterfaces maps constraint literals to the implicit
+	// interfaces they stand for. A constraint literal is a type parameter
+	// constraint that is not written as an interface, such as ~int or
+	// int | string in [T ~int, U int | string]; it denotes the interface
+	// embedding it, here interface{ ~int } and interface{ int | string }.
+	// The position of such an interface is the position of the literal.
+	// Interfaces that are written explicitly are not recorded. See also
+	// Interface.IsImplicit.
+	ImplicitInterfaces map[syntax.Expr]*Interface
+
+	// NonNil records the expressions whose values are provably not nil,
+	// independently of control flow: calls of the built-ins new and make,
+	// composite literals, function literals, address operations &x, and
+	// parenthesized such expressions. It provides a common foundation for
+	// analyzers reasoning about nil values. Expressions which are not
+	// recorded may or may not be nil.
+	NonNil map[syntax.Expr]bool
+
+	// DeclaredTypes maps type declarations and interface type literals,
+	// including those in function bodies, to the types they declare or
+	// denote. It permits enumerating all defined types and interfaces of
+	// a package, for instance to generate mock implementations, without
+	// walking the syntax tree. The following node and type kinds appear:
+	//
+	//     node                     type
+	//
+	//     *syntax.TypeDecl         *Named (the type declared; aliases are not recorded)
+	//     *syntax.InterfaceType    *Interface
+	//
+	// Instances of generic types and implicit interfaces (see
+	// ImplicitInterfaces) are not recorded.
+	DeclaredTypes map[syntax.Node]Type
+
 	// Scopes maps syntax.Nodes to the scopes they define. Package scopes are not
 	// associated with a specific node but with all files belonging to a package.
 	// Thus, the package scope can be found in the type-checked Package object.
@@ -256,11 +584,15 @@ type Info struct {
 	// function scopes which in turn enclose statement and function literal scopes.
 	// Note that even though package-level functions are declared in the package
 	// scope, the function scopes are embedded in the file scope of the file
-	// containing the function declaration.
+	// containing the function declaration. The type parameters of generic types
+	// and functions are declared in a scope of their own, which is embedded in
+	// the file scope and encloses the respective function scope, if any.
 	//
 	// The following node types may appear in Scopes:
 	//
 	//     *syntax.File
+	//     *syntax.TypeDecl (type parameters of a generic type)
+	//     *syntax.FuncDecl (type parameters of a generic function or method)
 	//     *syntax.FuncType
 	//     *syntax.BlockStmt
 	//     *syntax.IfStmt
@@ -277,6 +609,16 @@ type Info struct {
 	// in source order. Variables without an initialization expression do not
 	// appear in this list.
 	InitOrder []*Initializer
+
+	// Dependencies maps each package-level object of the package (including
+	// methods) to the package-level objects its declaration refers to, in
+	// source order. A declaration refers to an object if its name, type, or
+	// initialization expression or function body denotes the object by an
+	// identifier, method selector, or method expression. Objects of other
+	// packages, and objects declared locally in a function, are not recorded.
+	// Dependencies are direct; the transitive closure, or the reverse graph
+	// of dependents, can be computed from this map.
+	Dependencies map[Object][]Object
 }
 
 // TypeOf returns the type of expression e, or nil if not found.
@@ -377,6 +719,42 @@ type Inferred struct {
 	Sig   *Signature
 }
 
+// A Conversion describes an implicit conversion of a value
+// of type From to the interface type To.
+type Conversion struct {
+	From Type // type of the converted value
+	To   Type // interface type the value is converted to
+}
+
+// An ImplicitOp describes the set of address and dereference
+// operations implicitly applied to the operand of an expression.
+type ImplicitOp uint8
+
+const (
+	ImplicitAddr  ImplicitOp = 1 << iota // the operand's address is taken implicitly, as in (&x).m
+	ImplicitDeref                        // the operand is dereferenced implicitly, as in (*p).f
+)
+
+// Addr reports whether op includes an implicit address operation.
+func (op ImplicitOp) Addr() bool { return op&ImplicitAddr != 0 }
+
+// Deref reports whether op includes an implicit dereference operation.
+func (op ImplicitOp) Deref() bool { return op&ImplicitDeref != 0 }
+
+func (op ImplicitOp) String() string {
+	switch op {
+	case 0:
+		return "none"
+	case ImplicitAddr:
+		return "addr"
+	case ImplicitDeref:
+		return "deref"
+	case ImplicitAddr | ImplicitDeref:
+		return "addr|deref"
+	}
+	return fmt.Sprintf("ImplicitOp(%d)", uint8(op))
+}
+
 // An Initializer describes a package-level variable, or a list of variables in case
 // of a multi-valued initialization expression, and the corresponding initialization
 // expression.
@@ -433,12 +811,126 @@ func ConvertibleTo(V, T Type) bool {
 	return x.convertibleTo(nil, T) // check not needed for non-constant x
 }
 
+// Representable reports whether the untyped value or constant described
+// by tv can be used as a value of type T, as in an assignment, using the
+// sizes of conf (conf may be nil). Only tv.Type and tv.Value are considered;
+// tv describes a constant if tv.Value != nil.
+//
+// If so, Representable returns the type the value assumes (T, or the default
+// type of tv.Type if T is an interface) and, for constants, the constant value
+// represented as a value of that type (e.g., rounded for floating-point types).
+// Otherwise, the error describes why the implicit conversion is not possible,
+// with the message the type checker reports. Typed values are never
+// representable.
+func (conf *Config) Representable(tv TypeAndValue, T Type) (Type, constant.Value, error) {
+	x := operand{mode: value, typ: tv.Type, val: tv.Value}
+	if x.val != nil {
+		x.mode = constant_
+	}
+	if !isUntyped(x.typ) {
+		return nil, nil, fmt.Errorf("%s is not untyped", &x)
+	}
+
+	target := T
+	if !x.isNil() && IsInterface(T) {
+		// use the default type, as is done for assignments
+		target = Default(x.typ)
+	}
+	if conf == nil {
+		conf = new(Config)
+	}
+	check := &Checker{conf: conf} // only the configuration is needed
+	typ, val, code := check.implicitTypeAndValue(&x, target)
+	if code != 0 {
+		return nil, nil, errors.New(sprintf(nil, invalidConversionFormat(code), &x, safeUnderlying(target)))
+	}
+	if target != T && !AssignableTo(typ, T) {
+		return nil, nil, errors.New(sprintf(nil, "cannot use %s as %s value: %s does not implement %s", &x, T, typ, T))
+	}
+	return typ, val, nil
+}
+
+// A ConstConversion describes the result of converting a constant
+// value to a basic type with Config.ConvertConst.
+type ConstConversion int
+
+const (
+	ConstOK        ConstConversion = iota // value is representable, possibly after rounding
+	ConstTruncated                        // value has a fractional or imaginary part which would be lost
+	ConstOverflow                         // value is out of range for the type
+	ConstMismatch                         // value kind doesn't match the type (e.g., a string value for a numeric type)
+)
+
+// ConvertConst converts the exact constant value x to the basic type typ,
+// using the sizes of conf (conf may be nil), as the type checker does for
+// constant operands. If x is representable as a value of typ, the result is
+// the value represented in typ (rounded for floating-point and complex types,
+// and an Int value for integer types) and ConstOK. Otherwise the result is
+// nil and the reason why x is not representable. Unknown values are always
+// representable.
+func (conf *Config) ConvertConst(x constant.Value, typ *Basic) (constant.Value, ConstConversion) {
+	if conf == nil {
+		conf = new(Config)
+	}
+	check := &Checker{conf: conf} // only the configuration is needed
+	v := x
+	if representableConst(x, check, typ, &v) {
+		return v, ConstOK
+	}
+	switch x.Kind() {
+	case constant.Int, constant.Float, constant.Complex:
+		if !isNumeric(typ) {
+			return nil, ConstMismatch
+		}
+		if isInteger(typ) && constant.ToInt(x).Kind() != constant.Int ||
+			!isComplex(typ) && constant.ToFloat(x).Kind() != constant.Float {
+			return nil, ConstTruncated
+		}
+		return nil, ConstOverflow
+	}
+	return nil, ConstMismatch
+}
+
 // Implements reports whether type V implements interface T.
 func Implements(V Type, T *Interface) bool {
 	f, _ := MissingMethod(V, T, true)
 	return f == nil
 }
 
+// Satisfies reports whether type T satisfies the constraint C, that is,
+// whether T may be used as type argument for a type parameter constrained
+// by C. Unlike Implements, Satisfies also considers the type terms and
+// the comparable and ordered requirements of C. Type parameters of C are
+// not substituted.
+func Satisfies(T Type, C *Interface) bool {
+	return Unsatisfied(T, C) == nil
+}
+
+// Unsatisfied returns nil if type T satisfies the constraint C, and an
+// error describing why T doesn't satisfy C otherwise (see Satisfies).
+func Unsatisfied(T Type, C *Interface) *UnsatisfiedError {
+	if C.Empty() {
+		return nil
+	}
+	return (*Checker)(nil).satisfiesIface(T, C, C)
+}
+
+// An UnsatisfiedError describes why a type does not satisfy a constraint.
+type UnsatisfiedError struct {
+	// Method is the method of the constraint that is missing, or that has
+	// the wrong signature, if any. In the latter case, Wrong is the method
+	// of the type.
+	Method, Wrong *Func
+
+	// Term is a term of the type set of a type parameter that is not in
+	// the type set of the constraint, if any.
+	Term *Term
+
+	msg string
+}
+
+func (err *UnsatisfiedError) Error() string { return err.msg }
+
 // Identical reports whether x and y are identical types.
 // Receivers of Signature types are ignored.
 func Identical(x, y Type) bool {
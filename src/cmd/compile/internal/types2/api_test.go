@@ -7,11 +7,17 @@ package types2_test
 import (
 	"bytes"
 	"cmd/compile/internal/syntax"
+	"encoding/json"
 	"fmt"
+	"go/constant"
 	"internal/testenv"
+	"internal/types/errors"
+	"io"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	. "cmd/compile/internal/types2"
@@ -691,6 +697,258 @@ func TestImplicitsInfo(t *testing.T) {
 	}
 }
 
+func TestConversionsInfo(t *testing.T) {
+	var tests = []struct {
+		src  string
+		want string // conversions, sorted by position: "expr: from -> to"
+	}{
+		{`package c0; var _ interface{} = 0`, "0: int -> interface{}"},
+		{`package c1; var x int; var _ interface{} = x`, "x: int -> interface{}"},
+		{`package c2; var _ interface{} = nil`, ""},
+		{`package c3; var x, y interface{}; var _ = x == y`, ""},
+		{`package c4; var x interface{}; var _ = x == 1.0`, "1.0: float64 -> interface{}"},
+		{`package c5; type I interface{ m() }; type J interface{ I; n() }; var j J; var _ I = j`, "j: c5.J -> c5.I"},
+		{`package c6; func f(...interface{}); func _() { f(1, "a") }`, `1: int -> interface{}, "a": string -> interface{}`},
+		{`package c7; func _() error { var p *T; return p }; type T struct{}; func (*T) Error() string { return "" }`, "p: *c7.T -> error"},
+		{`package c8; var _ = []interface{}{1, nil, 'a'}`, "1: int -> interface{}, 'a': rune -> interface{}"},
+		{`package c9; var ch chan error; var e error; func _() { ch <- e }`, ""},
+		{genericPkg + `c10; func _[P any](x P) { var _ interface{} = x }`, "x: generic_c10.P₁ -> interface{}"},
+	}
+
+	for _, test := range tests {
+		info := Info{
+			Conversions: make(map[syntax.Expr]Conversion),
+		}
+		name := mustTypecheck(t, "ConversionsInfo", test.src, &info)
+
+		var exprs []syntax.Expr
+		for x := range info.Conversions {
+			exprs = append(exprs, x)
+		}
+		sort.Slice(exprs, func(i, j int) bool {
+			return exprs[i].Pos().Cmp(exprs[j].Pos()) < 0
+		})
+
+		var list []string
+		for _, x := range exprs {
+			conv := info.Conversions[x]
+			list = append(list, fmt.Sprintf("%s: %s -> %s", syntax.String(x), conv.From, conv.To))
+		}
+		if got := strings.Join(list, ", "); got != test.want {
+			t.Errorf("package %s: got %q; want %q", name, got, test.want)
+		}
+	}
+}
+
+func TestImplicitOpsInfo(t *testing.T) {
+	const src = `
+package p
+
+type T struct{ f int; *E }
+type E struct{ g int }
+
+func (T) vm()  {}
+func (*T) pm() {}
+func (E) evm() {}
+func (*E) epm() {}
+
+type I interface{ m() }
+
+func _(t T, p *T, i I, a [4]int, pa *[4]int) {
+	_ = t.f
+	_ = p.f
+	_ = t.g
+	t.vm()
+	t.pm()
+	p.vm()
+	p.pm()
+	t.evm()
+	p.epm()
+	i.m()
+	_ = (*T).vm
+	_ = a[0]
+	_ = pa[0]
+	_ = a[:]
+	_ = pa[:]
+}
+`
+	info := Info{
+		ImplicitOps: make(map[syntax.Expr]ImplicitOp),
+	}
+	mustTypecheck(t, "ImplicitOpsInfo", src, &info)
+
+	want := map[string]string{
+		"p.f":     "deref",
+		"t.g":     "deref",
+		"t.pm":    "addr",
+		"p.vm":    "deref",
+		"t.evm":   "deref",
+		"p.epm":   "deref",
+		"(*T).vm": "deref",
+		"pa[0]":   "deref",
+		"a[:]":    "addr",
+		"pa[:]":   "deref",
+	}
+
+	for x, op := range info.ImplicitOps {
+		s := syntax.String(x)
+		if got := op.String(); got != want[s] {
+			t.Errorf("%s: got %s; want %s", s, got, want[s])
+		}
+		delete(want, s)
+	}
+	for s, op := range want {
+		t.Errorf("%s: missing %s", s, op)
+	}
+}
+
+func TestObjectDocs(t *testing.T) {
+	const src = `
+package p
+
+// strings is imported.
+import "strings"
+
+// C is a constant.
+const C = 0
+
+// G is a constant in a group.
+const (
+	G = 1
+)
+
+// The doc comment of a group with several declarations
+// doesn't document them.
+var (
+	G1 = 1
+	G2 = 2 // G2 is not documented.
+)
+
+// V is a variable.
+var V = strings.ToUpper("v")
+
+// T is a type.
+type T struct{}
+
+// M is a method.
+func (T) M() {}
+
+func F() {
+	// L is a local type.
+	type L int
+
+	// K is a local constant in a group.
+	const (
+		K = 0
+	)
+}
+
+var undocumented int
+`
+	f, err := syntax.Parse(syntax.NewFileBase("docs.go"), strings.NewReader(src), nil, nil, syntax.DocComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Info{
+		Defs:      make(map[*syntax.Name]Object),
+		Implicits: make(map[syntax.Node]Object),
+	}
+	conf := Config{Importer: defaultImporter()}
+	if _, err := conf.Check("p", []*syntax.File{f}, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	docs := make(map[string]string)
+	for _, obj := range info.Defs {
+		if obj != nil {
+			docs[obj.Name()] = obj.Doc().Text()
+		}
+	}
+	for _, obj := range info.Implicits {
+		docs[obj.Name()] = obj.Doc().Text()
+	}
+
+	for name, want := range map[string]string{
+		"strings":      "strings is imported.",
+		"C":            "C is a constant.",
+		"G":            "G is a constant in a group.",
+		"G1":           "",
+		"G2":           "",
+		"V":            "V is a variable.",
+		"T":            "T is a type.",
+		"M":            "M is a method.",
+		"F":            "",
+		"L":            "L is a local type.",
+		"K":            "K is a local constant in a group.",
+		"undocumented": "",
+	} {
+		if got := docs[name]; got != want {
+			t.Errorf("%s: got doc %q; want %q", name, got, want)
+		}
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	const src = genericPkg + `p
+
+// Old is an old constant.
+//
+// Deprecated: Use New instead.
+const Old = 0
+
+const New = 1
+
+// T is a type.
+//
+// Deprecated: Do not use T,
+// it is obsolete.
+type T struct{ f int }
+
+// M is a method.
+// Deprecated: a deprecation notice must start a paragraph.
+func (T) M() {}
+
+// G is a generic type.
+type G[P any] struct{}
+
+// Deprecated: Use F instead.
+func (G[P]) M() {}
+
+func f() {
+	var t T
+	t.M()
+	var g G[int]
+	g.M()
+	_ = Old + New
+	_ = t.f
+}
+`
+	f, err := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), nil, nil, syntax.AllowGenerics|syntax.DocComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Info{Deprecated: make(map[*syntax.Name]Object)}
+	var conf Config
+	if _, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for id, obj := range info.Deprecated {
+		got = append(got, fmt.Sprintf("%s: %s: %q", id.Pos(), id.Value, obj.Deprecated()))
+	}
+	sort.Strings(got)
+	want := []string{
+		`p.go:18:7: T: "Deprecated: Do not use T,\nit is obsolete."`,
+		`p.go:27:8: T: "Deprecated: Do not use T,\nit is obsolete."`,
+		`p.go:30:4: M: "Deprecated: Use F instead."`,
+		`p.go:31:6: Old: "Deprecated: Use New instead."`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n\t%s\nwant\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}
+
 func predString(tv TypeAndValue) string {
 	var buf bytes.Buffer
 	pred := func(b bool, s string) {
@@ -876,6 +1134,15 @@ func TestScopesInfo(t *testing.T) {
 		{`package p20; var s int; func _(a []int) { for i, x := range a { s += x; _ = i } }`, []string{
 			"file:", "func:a", "for:i x", "block:",
 		}},
+		{genericPkg + `p21; type T[P any, Q interface{ m(P) }] struct{}`, []string{
+			"file:", "tparams:P Q", "func:",
+		}},
+		{genericPkg + `p22; func _[P any](x P) {}`, []string{
+			"file:", "tparams:P", "func:x",
+		}},
+		{genericPkg + `p23; type T[P any] int; func (T[Q]) m(x Q) {}`, []string{
+			"file:", "tparams:P", "tparams:Q", "func:x",
+		}},
 	}
 
 	for _, test := range tests {
@@ -893,6 +1160,8 @@ func TestScopesInfo(t *testing.T) {
 			switch node.(type) {
 			case *syntax.File:
 				kind = "file"
+			case *syntax.TypeDecl, *syntax.FuncDecl:
+				kind = "tparams"
 			case *syntax.FuncType:
 				kind = "func"
 			case *syntax.BlockStmt:
@@ -1434,6 +1703,130 @@ func TestLookupFieldOrMethod(t *testing.T) {
 	}
 }
 
+func TestTypeParamMethods(t *testing.T) {
+	const src = genericPkg + `p
+
+type C interface {
+	D
+	~int | ~string
+	h()
+}
+
+type D interface{ f() }
+
+func g[T C](a T) {}
+`
+	pkg, err := pkgFor("test", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpar := pkg.Scope().Lookup("g").(*Func).Scope().Lookup("a").Type().(*TypeParam)
+
+	// The methods of T are the methods of its constraint interface,
+	// including the methods of embedded constraints, and they are found
+	// as if T were that interface.
+	iface := tpar.Interface()
+	if got := iface.NumMethods(); got != 2 {
+		t.Fatalf("got %d constraint methods; want 2", got)
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		obj, index, indirect := LookupFieldOrMethod(tpar, false, pkg, m.Name())
+		_, iindex, iindirect := LookupFieldOrMethod(iface, false, pkg, m.Name())
+		if obj != m || !sameSlice(index, iindex) || indirect != iindirect {
+			t.Errorf("%s: got %v, %v, %v; want %v, %v, %v", m.Name(), obj, index, indirect, m, iindex, iindirect)
+		}
+		if obj, _, _ := LookupFieldOrMethod(NewPointer(tpar), false, pkg, m.Name()); obj != nil {
+			t.Errorf("%s: got %v for pointer to type parameter; want none", m.Name(), obj)
+		}
+	}
+}
+
+func TestImplementsWitness(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface {
+	A()
+	B()
+	C()
+	D()
+	E() int
+}
+
+type Embedded struct{}
+
+func (Embedded) B() {}
+
+type T struct {
+	Embedded
+	D func()
+}
+
+func (T) A() {}
+func (*T) C() {}
+func (T) E() string { return "" }
+
+type G[P any] struct{}
+
+func (G[P]) A() {}
+func (*G[P]) E() P { var p P; return p }
+
+type J interface {
+	A()
+	E() int
+}
+`
+	pkg, err := pkgFor("witness", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := pkg.Scope()
+	I := scope.Lookup("I").Type().Underlying().(*Interface)
+	G, err := Instantiate(nil, scope.Lookup("G").Type(), []Type{Typ[Int]}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		V    Type
+		want string
+	}{
+		{scope.Lookup("T").Type(), "A:ok B:ok,promoted C:ptr D:missing E:wrong"},
+		// As for LookupFieldOrMethod, selecting on a pointer is always indirect.
+		{NewPointer(scope.Lookup("T").Type()), "A:ok,indirect B:ok,promoted,indirect C:ok,indirect D:missing E:wrong,indirect"},
+		{G, "A:ok B:missing C:missing D:missing E:ptr"},
+		{NewPointer(G), "A:ok,indirect B:missing C:missing D:missing E:ok,indirect"},
+		{scope.Lookup("J").Type(), "A:ok B:missing C:missing D:missing E:ok"},
+	} {
+		var list []string
+		for _, w := range ImplementsWitness(test.V, I) {
+			var s string
+			switch {
+			case w.Impl == nil:
+				s = "missing"
+			case w.PtrRecv:
+				s = "ptr"
+			case w.WrongType:
+				s = "wrong"
+			case w.OK():
+				s = "ok"
+			}
+			if w.Impl != nil && !w.PtrRecv {
+				if w.Promoted() {
+					s += ",promoted"
+				}
+				if w.Indirect {
+					s += ",indirect"
+				}
+			}
+			list = append(list, w.Method.Name()+":"+s)
+		}
+		if got := strings.Join(list, " "); got != test.want {
+			t.Errorf("%s: got %s; want %s", test.V, got, test.want)
+		}
+	}
+}
+
 func sameSlice(a, b []int) bool {
 	if len(a) != len(b) {
 		return false
@@ -1448,6 +1841,84 @@ func sameSlice(a, b []int) bool {
 
 // TestScopeLookupParent ensures that (*Scope).LookupParent returns
 // the correct result at various positions within the source.
+func TestMethodSetWithPaths(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface{ m() }
+type J interface {
+	I
+	n()
+}
+type K interface {
+	J
+	interface{ o() }
+}
+
+type S struct{}
+
+func (S) a()  {}
+func (*S) b() {}
+
+type E struct {
+	*S
+	J
+}
+
+type T struct {
+	E
+	a int // shadows S.a
+}
+
+func (T) c() {}
+
+type G[P any] struct{ val P }
+
+func (G[P]) get() P { var p P; return p }
+
+type U struct{ G[int] }
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(name string) Type { return pkg.Scope().Lookup(name).Type() }
+	qf := RelativeTo(pkg)
+
+	for _, test := range []struct {
+		typ  Type
+		want string
+	}{
+		{lookup("S"), "a"},
+		{NewPointer(lookup("S")), "a (indirect); b (indirect)"},
+		{lookup("K"), "m via J, I; n via J; o via interface{o()}"},
+		{lookup("T"), "b via E, *S (indirect); c; m via E, J, I; n via E, J"},
+		{lookup("U"), "get via G[int]: func (G[int]).get() int"},
+		{Typ[Int], ""},
+	} {
+		var list []string
+		for _, m := range MethodSetWithPaths(test.typ) {
+			s := m.Method.Name()
+			if len(m.Path) > 0 {
+				var path []string
+				for _, typ := range m.Path {
+					path = append(path, TypeString(typ, qf))
+				}
+				s += " via " + strings.Join(path, ", ")
+			}
+			if m.Indirect {
+				s += " (indirect)"
+			}
+			if m.Method.Name() == "get" {
+				s += ": " + ObjectString(m.Method, qf)
+			}
+			list = append(list, s)
+		}
+		if got := strings.Join(list, "; "); got != test.want {
+			t.Errorf("%s: got %s; want %s", test.typ, got, test.want)
+		}
+	}
+}
+
 func TestScopeLookupParent(t *testing.T) {
 	imports := make(testImporter)
 	conf := Config{Importer: imports}
@@ -1945,3 +2416,2483 @@ func TestInstanceIdentity(t *testing.T) {
 		t.Errorf("mismatching types: a.A: %s, b.B: %s", a.Type(), b.Type())
 	}
 }
+
+func TestTolerateUnused(t *testing.T) {
+	const src = `package p
+
+import "math"
+
+func f() {
+	x := 0
+	var y int
+	_ = len(z)
+}
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unused variables and imports are reported as soft errors
+	// but the result of Check is the first hard error.
+	var list []string
+	conf := Config{TolerateUnused: true, Importer: defaultImporter()}
+	conf.Error = func(err error) {
+		e := err.(Error)
+		list = append(list, fmt.Sprintf("%s (soft = %v)", e.Msg, e.Soft))
+	}
+	_, err = conf.Check("p", []*syntax.File{f}, nil)
+	if err == nil || !strings.Contains(err.Error(), "undeclared name: z") {
+		t.Errorf("got error %v; want undeclared name: z", err)
+	}
+	want := []string{
+		"undeclared name: z (soft = false)",
+		"x declared but not used (soft = true)",
+		"y declared but not used (soft = true)",
+		`"math" imported but not used (soft = true)`,
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("got errors %q; want %q", list, want)
+	}
+
+	// Unused variables and imports alone don't make checking fail.
+	f, err = parseSrc("p.go", "package p; import \"math\"; func f() { x := 0 }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, handler := range []func(error){nil, func(error) {}} {
+		conf.Error = handler
+		if _, err := conf.Check("p", []*syntax.File{f}, nil); err != nil {
+			t.Errorf("got error %v; want none", err)
+		}
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	const src = `package p
+
+import "math"
+
+func f() int { return "f" }
+
+var x int = "x"
+
+type T interface {
+	m()
+	m()
+}
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		conf  Config
+		first string
+	}{
+		{Config{}, "3:8"},
+		{Config{TolerateUnused: true}, "5:23"},
+		{Config{ConcurrentFuncBodies: true}, "3:8"},
+	} {
+		var list []string
+		conf := test.conf
+		conf.SortErrors = true
+		conf.Importer = defaultImporter()
+		conf.Error = func(err error) {
+			pos := err.(Error).Pos
+			list = append(list, fmt.Sprintf("%d:%d", pos.Line(), pos.Col()))
+		}
+		_, err = conf.Check("p", []*syntax.File{f}, nil)
+		if got, want := strings.Join(list, " "), "3:8 5:23 7:13 11:2"; got != want {
+			t.Errorf("got errors at %s; want %s", got, want)
+		}
+		if pos := err.(Error).Pos; fmt.Sprintf("%d:%d", pos.Line(), pos.Col()) != test.first {
+			t.Errorf("got first error %s; want error at %s", err, test.first)
+		}
+	}
+}
+
+func TestMaxErrors(t *testing.T) {
+	const src = `package p
+
+func f() {
+	x := 0
+	_ = a
+	_ = b
+}
+
+func g() {
+	_ = c
+	_ = d
+}
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, test := range []struct {
+		conf Config
+		want string // last words of the reported errors
+	}{
+		{Config{}, "a b used c d"},
+		{Config{MaxErrors: 1}, "a"},
+		{Config{MaxErrors: 3}, "a b used"},
+		{Config{MaxErrors: 3, TolerateUnused: true}, "a b used c"},
+		{Config{MaxErrors: 3, SortErrors: true}, "used a b"},
+		{Config{MaxErrors: 10}, "a b used c d"},
+		{Config{MaxErrors: 3, ConcurrentFuncBodies: true}, "a b used"},
+	} {
+		var list []string
+		conf := test.conf
+		conf.Error = func(err error) {
+			msg := err.(Error).Msg
+			list = append(list, msg[strings.LastIndex(msg, " ")+1:])
+		}
+		_, err := conf.Check("p", []*syntax.File{f}, nil)
+		if got := strings.Join(list, " "); got != test.want {
+			t.Errorf("%d: got errors for %s; want %s", i, got, test.want)
+		}
+		if err == nil || !strings.HasSuffix(err.Error(), list[0]) {
+			t.Errorf("%d: got error %v; want error for %s", i, err, list[0])
+		}
+	}
+}
+
+func TestIgnoreUnexportedFuncBodies(t *testing.T) {
+	const src = `package p
+
+import "math"
+
+type T struct{}
+type t struct{}
+
+func F() int  { return undefF }
+func f() int  { return undeff + int(math.Pi) }
+func (T) M()  { undefTM() }
+func (T) m()  { undefTm() }
+func (*t) M() { undeftM() }
+
+var V = func() { undefV() }
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	conf := Config{
+		IgnoreUnexportedFuncBodies: true,
+		Importer:                   defaultImporter(),
+		Error: func(err error) {
+			got = append(got, err.(Error).Msg)
+		},
+	}
+	pkg, _ := conf.Check("p", []*syntax.File{f}, nil)
+
+	// Only exported bodies and function literals are checked,
+	// and unused imports are not reported.
+	sort.Strings(got)
+	want := []string{
+		"undeclared name: undefF",
+		"undeclared name: undefTM",
+		"undeclared name: undefV",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got errors %q; want %q", got, want)
+	}
+
+	// The signatures of all functions are still known.
+	if obj := pkg.Scope().Lookup("f"); obj == nil || obj.Type().String() != "func() int" {
+		t.Errorf("got f = %v; want func f() int", obj)
+	}
+}
+
+func TestStableOrder(t *testing.T) {
+	const src1 = genericPkg + `p
+
+type T int
+
+func (T) c() {}
+func (T) B() {}
+
+type I interface{ c(); B(); a() }
+
+type U interface{ ~string | int | ~float64 }
+`
+	const src2 = genericPkg + `p
+
+func (T) a() {}
+func (*T) A() {}
+`
+	var files []*syntax.File
+	for _, src := range []string{src1, src2} {
+		f, err := parseSrc("", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+
+	// Check the package repeatedly and with the files in either
+	// order to give map iteration order a chance to matter.
+	for i := 0; i < 10; i++ {
+		files := files
+		wantMethods := "c B a A"
+		if i%2 == 1 {
+			files = []*syntax.File{files[1], files[0]}
+			wantMethods = "a A c B"
+		}
+		var conf Config
+		pkg, err := conf.Check("p", files, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scope := pkg.Scope()
+
+		if got, want := strings.Join(scope.Names(), " "), "I T U"; got != want {
+			t.Errorf("Scope.Names: got %s; want %s", got, want)
+		}
+
+		T := scope.Lookup("T").Type().(*Named)
+		var names []string
+		for i := 0; i < T.NumMethods(); i++ {
+			names = append(names, T.Method(i).Name())
+		}
+		if got := strings.Join(names, " "); got != wantMethods {
+			t.Errorf("Named.Method: got %s; want %s", got, wantMethods)
+		}
+		names = names[:0]
+		for _, m := range T.SortedMethods() {
+			names = append(names, m.Name())
+		}
+		if got, want := strings.Join(names, " "), "A B a c"; got != want {
+			t.Errorf("Named.SortedMethods: got %s; want %s", got, want)
+		}
+
+		I := scope.Lookup("I").Type().Underlying().(*Interface)
+		names = names[:0]
+		for i := 0; i < I.NumMethods(); i++ {
+			names = append(names, I.Method(i).Name())
+		}
+		if got, want := strings.Join(names, " "), "B a c"; got != want {
+			t.Errorf("Interface.Method: got %s; want %s", got, want)
+		}
+
+		U := scope.Lookup("U").Type().Underlying().(*Interface).EmbeddedType(0).(*Union)
+		var terms []string
+		for i := 0; i < U.Len(); i++ {
+			terms = append(terms, U.Term(i).String())
+		}
+		if got, want := strings.Join(terms, " "), "~string int ~float64"; got != want {
+			t.Errorf("Union.Term: got %s; want %s", got, want)
+		}
+	}
+}
+
+func TestPartialInterface(t *testing.T) {
+	const src = genericPkg + `p
+
+type E interface {
+	m()
+	Undefined
+}
+
+type I interface {
+	E
+	n()
+	int | Missing | string
+}
+
+type C interface{ ~int | Missing }
+
+func f[P C]() {}
+
+var _ = f[string]
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []string
+	conf := Config{Error: func(err error) { errs = append(errs, err.(Error).Msg) }}
+	pkg, _ := conf.Check("p", []*syntax.File{f}, nil)
+
+	// Only the undeclared names are reported; in particular
+	// string is not reported as not satisfying C.
+	sort.Strings(errs)
+	if want := []string{"undeclared name: Missing", "undeclared name: Missing", "undeclared name: Undefined"}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("got errors %q; want %q", errs, want)
+	}
+
+	for _, test := range []struct {
+		name    string
+		methods string
+	}{
+		{"E", "m"},
+		{"I", "m n"},
+		{"C", ""},
+	} {
+		iface := pkg.Scope().Lookup(test.name).Type().Underlying().(*Interface)
+		if !iface.IsPartial() {
+			t.Errorf("%s: interface is not partial", test.name)
+		}
+		var methods []string
+		for i := 0; i < iface.NumMethods(); i++ {
+			methods = append(methods, iface.Method(i).Name())
+		}
+		if got := strings.Join(methods, " "); got != test.methods {
+			t.Errorf("%s: got methods %q; want %q", test.name, got, test.methods)
+		}
+		obj, _, _ := LookupFieldOrMethod(iface, false, pkg, "m")
+		if got := obj != nil; got != strings.Contains(test.methods, "m") {
+			t.Errorf("%s: lookup of m succeeded = %v", test.name, got)
+		}
+	}
+
+	// Valid interfaces are not partial.
+	if Universe.Lookup("error").Type().Underlying().(*Interface).IsPartial() {
+		t.Errorf("error interface is partial")
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	const src = genericPkg + `p
+
+type T[P any] struct{ f P }
+
+func (T[P]) m(P) {}
+
+type I[P any] interface{ m(P) }
+
+var x T[int]
+var _ = x.f
+var _ = x.m
+var y I[int]
+`
+	info := &Info{Selections: make(map[*syntax.SelectorExpr]*Selection)}
+	pkg, err := pkgFor("p", src, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	T := pkg.Scope().Lookup("T").Type().(*Named)
+	f := T.Underlying().(*Struct).Field(0)
+	m := T.Method(0)
+	for _, sel := range info.Selections {
+		var orig Object
+		switch obj := sel.Obj().(type) {
+		case *Var:
+			orig = obj.Origin()
+			if orig != f {
+				t.Errorf("%s: got origin %v; want %v", sel, orig, f)
+			}
+		case *Func:
+			orig = obj.Origin()
+			if orig != m {
+				t.Errorf("%s: got origin %v; want %v", sel, orig, m)
+			}
+		}
+		if orig == sel.Obj() {
+			t.Errorf("%s: selected object is not instantiated", sel)
+		}
+	}
+	if f.Origin() != f || m.Origin() != m {
+		t.Errorf("origin of generic declaration was not the object itself")
+	}
+
+	im := pkg.Scope().Lookup("I").Type().Underlying().(*Interface).Method(0)
+	yim := pkg.Scope().Lookup("y").Type().Underlying().(*Interface).Method(0)
+	if yim == im || yim.Origin() != im {
+		t.Errorf("got method %v with origin %v; want origin %v", yim, yim.Origin(), im)
+	}
+}
+
+func TestInstantiatedMethods(t *testing.T) {
+	const src = genericPkg + `p
+
+type T[P any] struct{ E[P] }
+
+func (T[P]) m(P) P { var p P; return p }
+func (*T[P]) n() {}
+
+type E[Q any] struct{}
+
+func (E[Q]) e(Q) {}
+
+var x T[int]
+var y T[string]
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	T := pkg.Scope().Lookup("T").Type().(*Named)
+	x := pkg.Scope().Lookup("x").Type().(*Named)
+	for _, test := range []struct {
+		name, want string
+	}{
+		{"m", "func (generic_p.T[int]).m(int) int"},
+		{"n", "func (*generic_p.T[int]).n()"},
+		{"e", "func (generic_p.E[int]).e(int)"},
+	} {
+		obj, _, _ := LookupFieldOrMethod(x, true, pkg, test.name)
+		m, _ := obj.(*Func)
+		if m == nil {
+			t.Errorf("%s: method not found", test.name)
+			continue
+		}
+		if got := m.String(); got != test.want {
+			t.Errorf("%s: got %s; want %s", test.name, got, test.want)
+		}
+		if m.Origin() == m || m.Origin().Type() == m.Type() {
+			t.Errorf("%s: method is not instantiated", test.name)
+		}
+	}
+
+	// methods are instantiated once, and only for instances
+	y := pkg.Scope().Lookup("y").Type().(*Named)
+	ms := make([]*Func, y.NumMethods())
+	var wg sync.WaitGroup
+	for i := range ms {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ms[i] = y.Method(i)
+		}(i)
+	}
+	wg.Wait()
+	for i, m := range ms {
+		if y.Method(i) != m {
+			t.Errorf("%s: method instantiated twice", m.Name())
+		}
+		if m.Origin() != T.Method(i) || T.Method(i).Origin() != T.Method(i) {
+			t.Errorf("%s: got origin %v; want %v", m.Name(), m.Origin(), T.Method(i))
+		}
+	}
+}
+
+func TestRepresentable(t *testing.T) {
+	const src = `package p
+
+type I interface{ m() }
+type E interface{}
+type MyInt int8
+
+const (
+	c1 = 1 << 10
+	c2 = 1.5
+	c3 = 'a'
+	c4 = "foo"
+	c5 = 1e1000
+)
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &Info{Types: make(map[syntax.Expr]TypeAndValue)}
+	var conf Config
+	pkg, err := conf.Check("p", []*syntax.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the recorded untyped constant values
+	consts := make(map[string]TypeAndValue)
+	for _, decl := range f.DeclList {
+		if d, _ := decl.(*syntax.ConstDecl); d != nil {
+			consts[d.NameList[0].Value] = info.Types[d.Values]
+		}
+	}
+	lookup := func(name string) Type {
+		if obj := Universe.Lookup(name); obj != nil {
+			return obj.Type()
+		}
+		return pkg.Scope().Lookup(name).Type()
+	}
+
+	for _, test := range []struct {
+		x, T          string
+		typ, val, err string
+	}{
+		{"c1", "int", "int", "1024", ""},
+		{"c1", "MyInt", "", "", "1024 (untyped int constant) overflows int8"},
+		{"c1", "float32", "float32", "1024", ""},
+		{"c1", "E", "int", "1024", ""},
+		{"c1", "I", "", "", "cannot use 1024 (untyped int constant) as p.I value: int does not implement p.I"},
+		{"c2", "int", "", "", "1.5 (untyped float constant) truncated to int"},
+		{"c2", "float32", "float32", "1.5", ""},
+		{"c3", "string", "", "", "cannot convert 97 (untyped rune constant) to string"},
+		{"c3", "E", "rune", "97", ""},
+		{"c4", "string", "string", `"foo"`, ""},
+		{"c5", "float64", "", "", "1e+1000 (untyped float constant) overflows float64"},
+		{"c5", "complex128", "", "", "1e+1000 (untyped float constant) overflows complex128"},
+	} {
+		typ, val, err := conf.Representable(consts[test.x], lookup(test.T))
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s as %s: got error %v; want %s", test.x, test.T, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s as %s: %v", test.x, test.T, err)
+			continue
+		}
+		if got := typ.String(); got != test.typ {
+			t.Errorf("%s as %s: got type %s; want %s", test.x, test.T, got, test.typ)
+		}
+		if got := val.String(); got != test.val {
+			t.Errorf("%s as %s: got value %s; want %s", test.x, test.T, got, test.val)
+		}
+	}
+
+	// sizes are taken into account
+	conf32 := Config{Sizes: SizesFor("gc", "386")}
+	if _, _, err := conf32.Representable(consts["c1"], Typ[Int]); err != nil {
+		t.Errorf("1024 as 32-bit int: %v", err)
+	}
+	big := TypeAndValue{Type: Typ[UntypedInt], Value: constant.MakeUint64(1 << 40)}
+	if _, _, err := conf32.Representable(big, Typ[Int]); err == nil {
+		t.Errorf("1 << 40 as 32-bit int: got no error")
+	}
+	if _, _, err := (*Config)(nil).Representable(big, Typ[Int]); err != nil {
+		t.Errorf("1 << 40 as 64-bit int: %v", err)
+	}
+
+	// typed values are not representable
+	if _, _, err := conf.Representable(TypeAndValue{Type: Typ[Int], Value: constant.MakeInt64(1)}, Typ[Int]); err == nil {
+		t.Errorf("typed constant: got no error")
+	}
+}
+
+func TestConvertConst(t *testing.T) {
+	conf32 := &Config{Sizes: SizesFor("gc", "386")}
+	for _, test := range []struct {
+		conf *Config
+		x    constant.Value
+		typ  BasicKind
+		want string // rounded value
+		res  ConstConversion
+	}{
+		{nil, constant.MakeInt64(127), Int8, "127", ConstOK},
+		{nil, constant.MakeInt64(128), Int8, "", ConstOverflow},
+		{nil, constant.MakeInt64(-1), Uint, "", ConstOverflow},
+		{nil, constant.MakeInt64(1 << 40), Int, "1099511627776", ConstOK},
+		{conf32, constant.MakeInt64(1 << 40), Int, "", ConstOverflow},
+		{conf32, constant.MakeInt64(1 << 40), Int64, "1099511627776", ConstOK},
+		{nil, constant.MakeFloat64(2.0), Int, "2", ConstOK},
+		{nil, constant.MakeFloat64(2.5), Int, "", ConstTruncated},
+		{nil, constant.MakeFloat64(1e100), Int64, "", ConstOverflow},
+		{nil, constant.MakeFloat64(0.1), Float32, "0.1", ConstOK},
+		{nil, constant.MakeFloat64(1e300), Float32, "", ConstOverflow},
+		{nil, constant.MakeImag(constant.MakeInt64(1)), Float64, "", ConstTruncated},
+		{nil, constant.MakeImag(constant.MakeInt64(1)), Complex64, "(0 + 1i)", ConstOK},
+		{nil, constant.MakeString("foo"), Int, "", ConstMismatch},
+		{nil, constant.MakeInt64(1), String, "", ConstMismatch},
+		{nil, constant.MakeBool(true), Bool, "true", ConstOK},
+		{nil, constant.MakeUnknown(), Int8, "unknown", ConstOK},
+	} {
+		v, res := test.conf.ConvertConst(test.x, Typ[test.typ])
+		if res != test.res {
+			t.Errorf("%s to %s: got result %d; want %d", test.x, Typ[test.typ], res, test.res)
+			continue
+		}
+		got := ""
+		if v != nil {
+			got = v.String()
+		}
+		if got != test.want {
+			t.Errorf("%s to %s: got value %s; want %s", test.x, Typ[test.typ], got, test.want)
+		}
+	}
+
+	// integer results are Int values
+	if v, _ := (*Config)(nil).ConvertConst(constant.MakeFloat64(3), Typ[Uint8]); v.Kind() != constant.Int {
+		t.Errorf("got %s value; want Int value", v.Kind())
+	}
+}
+
+func TestTParamScopes(t *testing.T) {
+	const src = genericPkg + `p
+
+type T[P any, Q interface{ m(P) }] struct{ f P }
+
+func F[A any, B interface{ *A }](x A) B { var b B; return b }
+
+func (T[X, Y]) m(y X) Y { var r Y; return r }
+`
+	info := &Info{
+		Scopes: make(map[syntax.Node]*Scope),
+		Uses:   make(map[*syntax.Name]Object),
+	}
+	pkg, err := pkgFor("p", src, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each use of a type parameter resolves, by position, to the type parameter.
+	n := 0
+	for id, obj := range info.Uses {
+		if _, ok := obj.(*TypeName); !ok {
+			continue
+		}
+		if _, ok := obj.Type().(*TypeParam); !ok {
+			continue
+		}
+		n++
+		inner := pkg.Scope().Innermost(id.Pos())
+		if _, found := inner.LookupParent(id.Value, id.Pos()); found != obj {
+			t.Errorf("%s at %s: found %v; want %v", id.Value, id.Pos(), found, obj)
+		}
+	}
+	if n != 11 {
+		t.Errorf("found %d type parameter uses; want 11", n)
+	}
+
+	// Type parameters and parameters are declared in the same block.
+	for _, src := range []string{
+		genericPkg + `p; func _[P any](P int) {}`,
+		genericPkg + `p; func _[P any]() (P int) { return }`,
+		genericPkg + `p; type T[P any] int; func (P T[P]) m() {}`,
+	} {
+		f, err := parseSrc("p", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var conf Config
+		if _, err := conf.Check("p", []*syntax.File{f}, nil); err == nil || !strings.Contains(err.Error(), "P redeclared in this block") {
+			t.Errorf("%s: got error %v; want P redeclared", src, err)
+		}
+	}
+}
+
+func TestImplicitInterfaces(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface{ ~int }
+
+type T[P ~int, Q int | ~string, R I, S interface{ ~int }] struct{}
+
+func F[A, B ~[]byte | string]() {}
+`
+	info := &Info{
+		Types:              make(map[syntax.Expr]TypeAndValue),
+		ImplicitInterfaces: make(map[syntax.Expr]*Interface),
+	}
+	pkg, err := pkgFor("p", src, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// collect the constraint literals by source position
+	got := make(map[string]string)
+	for x, iface := range info.ImplicitInterfaces {
+		if !iface.IsImplicit() {
+			t.Errorf("%s: interface is not implicit", x.Pos())
+		}
+		if tv := info.Types[x]; tv.Type != iface {
+			t.Errorf("%s: got type %v; want %v", x.Pos(), tv.Type, iface)
+		}
+		got[syntax.StartPos(x).String()] = iface.String()
+	}
+	want := map[string]string{
+		"p:5:10": "interface{~int}",
+		"p:5:18": "interface{int|~string}",
+		"p:7:13": "interface{~[]byte|string}",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d implicit interfaces; want %d", len(got), len(want))
+	}
+	for pos, w := range want {
+		if g := got[pos]; g != w {
+			t.Errorf("%s: got %q; want %q", pos, g, w)
+		}
+	}
+
+	// For type parameters declared together, the implicit interface is shared.
+	sig := pkg.Scope().Lookup("F").Type().(*Signature)
+	if a, b := sig.TParams().At(0).Constraint(), sig.TParams().At(1).Constraint(); a != b {
+		t.Errorf("got different constraints %v and %v", a, b)
+	}
+
+	// Explicitly written interfaces are not implicit.
+	typ := pkg.Scope().Lookup("T").Type().(*Named)
+	for _, i := range []int{2, 3} {
+		if iface := typ.TParams().At(i).Constraint().Underlying().(*Interface); iface.IsImplicit() {
+			t.Errorf("constraint %v is implicit", iface)
+		}
+	}
+}
+
+func TestDeclaredTypes(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface{ m() }
+
+type List[T any] struct{ next *List[T] }
+
+type A = List[int]
+
+type C interface{ ~int }
+
+func f[P interface{ ~int | C }]() {
+	type local struct{}
+	var _ interface{ n() }
+}
+
+func g() {
+	type local2 int
+}
+`
+	for _, concurrent := range []bool{false, true} {
+		f, err := parseSrc("p", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info := &Info{DeclaredTypes: make(map[syntax.Node]Type)}
+		conf := Config{ConcurrentFuncBodies: concurrent}
+		pkg, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, info)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for n, typ := range info.DeclaredTypes {
+			var s string
+			switch n := n.(type) {
+			case *syntax.TypeDecl:
+				named := typ.(*Named)
+				if named.Obj().Pos() != n.Name.Pos() {
+					t.Errorf("%s: got type %s declared at %s", n.Pos(), named, named.Obj().Pos())
+				}
+				s = named.Obj().Name()
+			case *syntax.InterfaceType:
+				s = TypeString(typ.(*Interface), RelativeTo(pkg))
+			default:
+				t.Errorf("%s: unexpected node %T", n.Pos(), n)
+			}
+			got = append(got, fmt.Sprintf("%s: %s", n.Pos(), s))
+		}
+		sort.Strings(got)
+
+		want := []string{
+			"p:11:10: interface{~int|C}",
+			"p:12:7: local",
+			"p:13:8: interface{n()}",
+			"p:17:7: local2",
+			"p:3:6: I",
+			"p:3:8: interface{m()}",
+			"p:5:6: List",
+			"p:9:6: C",
+			"p:9:8: interface{~int}",
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("concurrent = %v: got\n%s\nwant\n%s", concurrent, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
+
+func TestNonNil(t *testing.T) {
+	const src = `package p
+
+type T struct{ x int }
+
+var p *T
+
+func f() *T { return nil }
+
+var (
+	_ = new(int)
+	_ = (new(int))
+	_ = make([]int, 1)
+	_ = make(map[int]int)
+	_ = []int{1}
+	_ = &T{}
+	_ = &p.x
+	_ = func() {}
+	_ = p
+	_ = f()
+	_ = (*T)(nil)
+	_ = p.x
+)
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &Info{NonNil: make(map[syntax.Expr]bool)}
+	var conf Config
+	if _, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for x := range info.NonNil {
+		// only consider the values of the var declaration
+		if x.Pos().Line() >= 10 && syntax.StartPos(x).Col() == 6 {
+			got = append(got, syntax.String(x))
+		}
+	}
+	sort.Strings(got)
+	want := []string{"&T{}", "&p.x", "(new(int))", "[]int{…}", "func() {}", "make([]int, 1)", "make(map[int]int)", "new(int)"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	const src = genericPkg + `p
+
+import "strconv"
+
+const c = 1
+
+type (
+	T    struct{ f S }
+	S    [c]int
+	G[P any] struct{ p P }
+	I interface{ m() }
+)
+
+func (T) m()               {}
+func (G[P]) n() G[P]       { return G[P]{} }
+func (t T) String() string { t.m(); return strconv.Itoa(c) }
+
+var (
+	v = f
+	w = T.m
+	x G[int]
+	y = x.n
+)
+
+func f() I {
+	type L struct{ S }
+	var _ L
+	return T{}
+}
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &Info{Dependencies: make(map[Object][]Object)}
+	var conf Config
+	conf.Importer = defaultImporter()
+	if _, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for obj, deps := range info.Dependencies {
+		var names []string
+		for _, dep := range deps {
+			names = append(names, dep.Name())
+		}
+		got = append(got, obj.Name()+": "+strings.Join(names, " "))
+	}
+	sort.Strings(got)
+	want := []string{
+		"G: ",
+		"I: ",
+		"S: c",
+		"String: c T m",
+		"T: S",
+		"c: ",
+		"f: T S I",
+		"m: T",
+		"n: G",
+		"v: f",
+		"w: T m",
+		"x: G",
+		"y: n x",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n\t%s\nwant\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}
+
+func TestBadStmts(t *testing.T) {
+	const src = `package p
+
+func f() int {
+	x := 1
+	)
+	return x + y
+}
+
+func g(b bool) {
+	if b {
+	} else )
+}
+`
+	var syntaxErrs int
+	f, _ := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), func(error) { syntaxErrs++ }, nil, 0)
+	if syntaxErrs != 2 {
+		t.Fatalf("got %d syntax errors; want 2", syntaxErrs)
+	}
+
+	var errs []string
+	conf := Config{Error: func(err error) { errs = append(errs, err.Error()) }}
+	conf.Check(f.PkgName.Value, []*syntax.File{f}, nil)
+	want := []string{"p.go:6:13: undeclared name: y"}
+	if strings.Join(errs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got errors %q; want %q", errs, want)
+	}
+}
+
+func TestInterfacePredicates(t *testing.T) {
+	const src = genericPkg + `p
+
+type (
+	Empty      interface{}
+	Stringer   interface{ String() string }
+	Comparable interface{ comparable }
+	Ints       interface{ ~int | ~int8 }
+	Mixed      interface{ Stringer; ~int }
+	Embedding  interface{ Ints }
+	Funcs      interface{ ~func() }
+)
+
+func F[P ~int]() {}
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name                            string
+		comparable, methodSet, implicit bool
+	}{
+		{"Empty", false, true, false},
+		{"Stringer", false, true, false},
+		{"Comparable", true, false, false},
+		{"Ints", true, false, false},
+		{"Mixed", true, false, false},
+		{"Embedding", true, false, false},
+		{"Funcs", false, false, false},
+	} {
+		iface := pkg.Scope().Lookup(test.name).Type().Underlying().(*Interface)
+		if got := iface.IsComparable(); got != test.comparable {
+			t.Errorf("%s: IsComparable() = %v; want %v", test.name, got, test.comparable)
+		}
+		if got := iface.IsMethodSet(); got != test.methodSet {
+			t.Errorf("%s: IsMethodSet() = %v; want %v", test.name, got, test.methodSet)
+		}
+		if got := iface.IsConstraint(); got == test.methodSet {
+			t.Errorf("%s: IsConstraint() = %v; want %v", test.name, got, !test.methodSet)
+		}
+		if got := iface.IsImplicit(); got != test.implicit {
+			t.Errorf("%s: IsImplicit() = %v; want %v", test.name, got, test.implicit)
+		}
+	}
+
+	iface := pkg.Scope().Lookup("F").Type().(*Signature).TParams().At(0).Constraint().(*Interface)
+	if !iface.IsImplicit() || !iface.IsComparable() || iface.IsMethodSet() {
+		t.Errorf("%v: got implicit %v, comparable %v, method set %v", iface, iface.IsImplicit(), iface.IsComparable(), iface.IsMethodSet())
+	}
+}
+
+func TestUnusedTypeParams(t *testing.T) {
+	const src = genericPkg + `p
+
+type T1[P any] struct{ next *T1[int] }
+type T2[P, Q any] struct{ q Q }
+type T3[P interface{ m() P }] struct{}
+type T4[_ any] int
+
+func F1[P any]() {}
+func F2[P, Q any](P) {}
+func F3[P any]() { var _ P }
+func F4[P any, Q ~[]P]() {}
+
+func (T2[A, B]) m() {}
+`
+
+	for _, concurrent := range []bool{false, true} {
+		var errs []string
+		conf := Config{
+			ReportUnused:         true,
+			ConcurrentFuncBodies: concurrent,
+			Error:                func(err error) { errs = append(errs, err.(Error).Msg) },
+		}
+		f, err := parseSrc("p", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conf.Check(f.PkgName.Value, []*syntax.File{f}, nil)
+
+		want := []string{
+			"type parameter P declared but not used", // T1
+			"type parameter P declared but not used", // T2
+			"type parameter P declared but not used", // F1
+			"type parameter Q declared but not used", // F2
+			"type parameter Q declared but not used", // F4
+		}
+		if strings.Join(errs, "\n") != strings.Join(want, "\n") {
+			t.Errorf("concurrent = %v: got errors %q; want %q", concurrent, errs, want)
+		}
+	}
+
+	// Unused type parameters are reported for replaced files, too.
+	var errs []string
+	conf := Config{
+		ReportUnused: true,
+		Error:        func(err error) { errs = append(errs, err.(Error).Msg) },
+	}
+	old, err := parseSrc("p", genericPkg+"p; func f[T any]() {}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := parseSrc("p", genericPkg+"p; func g[U any]() {}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := NewChecker(&conf, NewPackage("p", old.PkgName.Value), nil)
+	check.Files([]*syntax.File{old})
+	check.ReplaceFile(old, new)
+	want := []string{
+		"type parameter T declared but not used",
+		"type parameter U declared but not used",
+	}
+	if strings.Join(errs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ReplaceFile: got errors %q; want %q", errs, want)
+	}
+}
+
+func TestGenericAlias(t *testing.T) {
+	const asrc = genericPkg + `a
+
+type L[P any] = []P
+
+type M[K comparable, V any] = map[K]V
+`
+	f, err := parseSrc("a", asrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := Config{AliasTypeParams: true}
+	a, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without AliasTypeParams, parameterized aliases are not permitted
+	if _, err := new(Config).Check(f.PkgName.Value, []*syntax.File{f}, nil); err == nil || !strings.Contains(err.Error(), "requires GOEXPERIMENT=aliastypeparams") {
+		t.Errorf("got error %v; want GOEXPERIMENT error", err)
+	}
+	L := a.Scope().Lookup("L").(*TypeName)
+	if !L.IsAlias() || L.TParams().Len() != 1 {
+		t.Fatalf("L: got alias %v with %d type parameters", L.IsAlias(), L.TParams().Len())
+	}
+	P := TypeString(L.TParams().At(0), RelativeTo(a))
+	if got, want := ObjectString(L, RelativeTo(a)), fmt.Sprintf("type L[%s interface{}] = []%s", P, P); got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+
+	const bsrc = genericPkg + `b
+
+import a "generic_a"
+
+var x a.L[int] = []int{1}
+var y = a.M[string, bool]{"x": true}
+var _ a.L
+var _ a.M[func(), int]
+`
+	f, err = parseSrc("b", bsrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []string
+	conf = Config{
+		Importer: importHelper{pkg: a},
+		Error:    func(err error) { errs = append(errs, err.Error()) },
+	}
+	info := &Info{Uses: make(map[*syntax.Name]Object)}
+	b, _ := conf.Check("b", []*syntax.File{f}, info)
+
+	for name, want := range map[string]string{"x": "[]int", "y": "map[string]bool"} {
+		if got := b.Scope().Lookup(name).Type().String(); got != want {
+			t.Errorf("%s: got type %s; want %s", name, got, want)
+		}
+	}
+	uses := 0
+	for id, obj := range info.Uses {
+		if id.Value == "L" {
+			if obj != L {
+				t.Errorf("%s: got use of %v; want %v", id.Pos(), obj, L)
+			}
+			uses++
+		}
+	}
+	if uses != 2 {
+		t.Errorf("got %d uses of a.L; want 2", uses)
+	}
+	want := []string{
+		"cannot use generic type generic_a.L without instantiation",
+		"func() does not satisfy comparable",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got errors %q; want %q", errs, want)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err, want[i]) {
+			t.Errorf("got error %q; want %q", err, want[i])
+		}
+	}
+
+	// Parameterized aliases may also be created directly.
+	tpar := (*Checker)(nil).NewTypeParam(NewTypeName(nopos, a, "P", nil), NewInterfaceType(nil, nil))
+	S := NewGenericAlias(nopos, a, "S", []*TypeParam{tpar}, NewSlice(tpar))
+	P = TypeString(tpar, RelativeTo(a))
+	if got, want := ObjectString(S, RelativeTo(a)), fmt.Sprintf("type S[%s interface{}] = []%s", P, P); got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+}
+
+func TestModuleGoVersion(t *testing.T) {
+	// underscores in numeric literals require go1.13
+	const src = `package p; const _ = 1_000`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		goVersion, modVersion string
+		ok                    bool
+	}{
+		{"", "", true},
+		{"go1.12", "", false},
+		{"go1.13", "", true},
+		{"", "1.12", false},
+		{"", "go1.12", false},
+		{"go1.12", "1.13", true},
+		{"go1.13", "1.12", false},
+		{"go1.13", "go1.12", false},
+	} {
+		var paths []string
+		conf := Config{
+			GoVersion: test.goVersion,
+			ModuleGoVersion: func(path string) string {
+				paths = append(paths, path)
+				return test.modVersion
+			},
+		}
+		_, err := conf.Check("example.com/p", []*syntax.File{f}, nil)
+		if ok := err == nil; ok != test.ok {
+			t.Errorf("GoVersion = %q, module version %q: got error %v", test.goVersion, test.modVersion, err)
+		}
+		if len(paths) != 1 || paths[0] != "example.com/p" {
+			t.Errorf("ModuleGoVersion called with %q; want [example.com/p]", paths)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), `invalid Go version "go1.x"`) {
+			t.Errorf("got panic %v; want invalid Go version", r)
+		}
+	}()
+	conf := Config{ModuleGoVersion: func(string) string { return "1.x" }}
+	conf.Check("p", []*syntax.File{f}, nil)
+}
+
+func TestFileVersions(t *testing.T) {
+	for _, test := range []struct {
+		goBuild string // //go:build constraint of the file, or ""
+		ok      bool   // whether the file may use go1.13 features
+	}{
+		{"", false},
+		{"go1.12", false},
+		{"go1.13", true},
+		{"go1.18", true},
+		{"linux", false},
+		{"go1.13 && linux", true},
+		{"linux && go1.11 && go1.13", true},
+		{"go1.13 || linux", false},
+		{"go1.13 || go1.14", true},
+		{"!go1.13", false},
+	} {
+		// underscores in numeric literals require go1.13
+		src := "package p; const _ = 1_000"
+		if test.goBuild != "" {
+			src = "//go:build " + test.goBuild + "\n\n" + src
+		}
+		f1, err := parseSrc("p1.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f2, err := parseSrc("p2.go", "package p; const _ = 0b10")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var errs []string
+		conf := Config{
+			GoVersion: "go1.12",
+			Error:     func(err error) { errs = append(errs, err.(Error).Pos.String()) },
+		}
+		conf.Check("p", []*syntax.File{f1, f2}, nil)
+
+		want := []string{"p2.go:1:22"} // go1.12 applies to the file without //go:build line
+		if !test.ok {
+			want = append([]string{fmt.Sprintf("p1.go:%d:22", f1.PkgName.Pos().Line())}, want...)
+		}
+		if strings.Join(errs, " ") != strings.Join(want, " ") {
+			t.Errorf("//go:build %s: got errors at %s; want %s", test.goBuild, errs, want)
+		}
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	const src = `package p
+
+type I interface{ m() }
+type J interface{ m() }
+type _ interface{ I; J } // duplicate method m requires go1.14
+
+var _ int = "foo" // hard error
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		dup  = "duplicate method m"
+		hard = `cannot use "foo" (untyped string constant) as int value in variable declaration`
+	)
+	for _, test := range []struct {
+		severity Severity
+		want     []string // reported errors; warnings are prefixed by "warning: "
+	}{
+		{SeverityError, []string{hard, dup}}, // duplicate methods are checked later
+		{SeverityWarning, []string{hard, "warning: " + dup}},
+		{SeverityIgnore, []string{hard}},
+	} {
+		var got []string
+		conf := Config{
+			GoVersion: "go1.13",
+			// The severity doesn't apply to the hard error.
+			Severity: map[ErrorCode]Severity{errors.DuplicateDecl: test.severity, errors.IncompatibleAssign: test.severity},
+			Error: func(err error) {
+				e := err.(Error)
+				if e.Warning {
+					got = append(got, "warning: "+e.Primary)
+				} else {
+					got = append(got, e.Primary)
+				}
+			},
+		}
+		_, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, nil)
+		if err == nil || err.(Error).Msg != hard {
+			t.Errorf("severity %d: got error %v; want %s", test.severity, err, hard)
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("severity %d: got errors %q; want %q", test.severity, got, test.want)
+		}
+	}
+
+	// Without an error handler, warnings don't stop type-checking.
+	f, err = parseSrc("p", "package p; type I interface{ m() }; type J interface{ m() }; type _ interface{ I; J }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := Config{GoVersion: "go1.13", Severity: map[ErrorCode]Severity{errors.DuplicateDecl: SeverityWarning}}
+	if _, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, nil); err != nil {
+		t.Errorf("got error %v; want none", err)
+	}
+}
+
+func TestTildeFix(t *testing.T) {
+	for _, test := range []struct {
+		src, want string // want is the fixed source
+	}{
+		{`type _ interface{ ~MyInt | ~string }`, `type _ interface{ ~int | ~string }`},
+		{`type _ interface{ ~Point }`, `type _ interface{ ~struct{x int; y int} }`},
+		{`type _ interface{ ~MyList }`, `type _ interface{ ~[]int }`},
+		{`func _[P ~MyInt]() {}`, `func _[P ~int]() {}`},
+		{`func _[P string | ~(MyInt)]() {}`, `func _[P string | ~(int)]() {}`},
+
+		// no fix if the end of the type expression is not known exactly
+		{`type _ interface{ ~List[int] }`, ""},
+	} {
+		const decls = "type MyInt int; type Point struct{ x, y int }; type MyList []int; type List[E any] []E\n"
+		src := genericPkg + "p; " + decls + test.src
+		f, err := parseSrc("p", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fixes []*SuggestedFix
+		conf := Config{
+			Error: func(err error) {
+				if fix := err.(Error).Fix; fix != nil {
+					fixes = append(fixes, fix)
+				}
+			},
+		}
+		conf.Check("p", []*syntax.File{f}, nil)
+		if test.want == "" {
+			if len(fixes) != 0 {
+				t.Errorf("%s: got unexpected fix %v", test.src, fixes[0])
+			}
+			continue
+		}
+		if len(fixes) != 1 {
+			t.Errorf("%s: got %d fixes; want 1", test.src, len(fixes))
+			continue
+		}
+
+		// apply the fix to the last line
+		fix := fixes[0]
+		if fix.Pos.Line() != 2 || fix.End.Line() != 2 {
+			t.Errorf("%s: fix at %s..%s; want line 2", test.src, fix.Pos, fix.End)
+			continue
+		}
+		got := test.src[:fix.Pos.Col()-1] + fix.NewText + test.src[fix.End.Col()-1:]
+		if got != test.want {
+			t.Errorf("got %s; want %s", got, test.want)
+			continue
+		}
+
+		// the fixed source must be valid
+		if _, err := pkgFor("p", genericPkg+"p; "+decls+got, nil); err != nil {
+			t.Errorf("%s: %v", got, err)
+		}
+	}
+}
+
+func TestOperatorConstraints(t *testing.T) {
+	for _, test := range []struct {
+		src string
+		err string // expected error substring, or ""
+	}{
+		{`func _[T ordered](x, y T) bool { return x < y }`, ""},
+		{`func _[T ordered](x, y T) bool { return x >= y || x == y }`, ""},
+		{`func _[T interface{ ordered; ~int|~string }](x, y T) bool { return x <= y }`, ""},
+		{`func f[T ordered](x, y T) bool { return x < y }; func _[P ordered](x P) { f(x, x) }`, ""},
+		{`func f[T ordered](x, y T) bool { return x < y }; var _ = f(1, 2) || f("a", "b") || f(1.0, 2.0)`, ""},
+		{`func _[T ordered](x, y T) T { return x + y }`, "operator + not defined"},
+		{`func _[T comparable](x, y T) bool { return x < y }`, "operator < not defined"},
+		{`func f[T ordered](x, y T) bool { return x < y }; var _ = f(1i, 2i)`, "complex128 does not satisfy ordered"},
+		{`func f[T ordered](x, y T) bool { return x < y }; var _ = f([]int(nil), nil)`, "[]int does not satisfy ordered"},
+		{`func f[T ordered](x, y T) bool { return x < y }; func _[P any](x P) { f(x, x) }`, "P has no constraints"},
+		{`func f[T ordered](x, y T) bool { return x < y }; func _[P comparable](x P) { f(x, x) }`, "P does not satisfy ordered"},
+		{`type _ interface{ int | ordered }`, "cannot use ordered in union"},
+		{`var _ ordered`, "interface is (or embeds) ordered"},
+	} {
+		src := genericPkg + "p; " + test.src
+		f, err := parseSrc("p", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conf := Config{OperatorConstraints: true}
+		_, err = conf.Check("p", []*syntax.File{f}, nil)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.src, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v; want %q", test.src, err, test.err)
+		}
+
+		// without OperatorConstraints, ordered is not declared
+		if !strings.Contains(test.src, "ordered") {
+			continue
+		}
+		conf = Config{}
+		if _, err := conf.Check("p", []*syntax.File{f}, nil); err == nil || !strings.Contains(err.Error(), "undeclared name: ordered") {
+			t.Errorf("%s: got error %v; want undeclared name: ordered", test.src, err)
+		}
+	}
+}
+
+func TestDefaultTypeArgs(t *testing.T) {
+	for _, test := range []struct {
+		src string
+		typ string // expected type of x, if any
+		err string // expected error substring, or ""
+	}{
+		{`type M[K comparable, V any = int] map[K]V; var x M[string]`, "p.M[string, int]", ""},
+		{`type M[K comparable, V any = []K] map[K]V; var x M[string]`, "p.M[string, []string]", ""},
+		{`type M[K comparable, V any = int] map[K]V; var x M[string, bool]`, "p.M[string, bool]", ""},
+		{`type M[A, B any = int] struct{}; var x M[int]`, "p.M[int, int]", ""},
+		{`type M[A any, B, C any = int] struct{}; var x M[bool]`, "p.M[bool, int, int]", ""},
+		{`type M[A any, B comparable = []int] struct{}; var x M[int]`, "", "[]int does not satisfy comparable"},
+
+		// defaults are only used for type arguments that cannot be inferred
+		{`func f[A any, B any = []A](A) B { panic(0) }; var x = f(1)`, "[]int", ""},
+		{`func f[A any, B any = string](A, B) B { panic(0) }; var x = f(1, 2.0)`, "float64", ""},
+		{`func f[A any, B any = string](A) {}; var x = f[int]`, "func(int)", ""},
+		{`func f[A any, B any = string](A) {}; var x = f[int, bool]`, "func(int)", ""},
+		{`func f[A any, S ~[]E, E any = int](A) S { panic(0) }; var x = f(1)`, "", "cannot infer S"},
+		{`func f[A any, E any = int, S ~[]E = []E](A) S { panic(0) }; var x = f(1)`, "[]int", ""},
+
+		{`type M[A any = int, B any] struct{}`, "", "missing default type for type parameter B"},
+		{`type M[A any = B, B any = int] struct{}`, "", "default type B must only refer to preceding type parameters"},
+		{`type M[A any = A] struct{}`, "", "default type A must only refer to preceding type parameters"},
+		{`type M[A any = comparable] struct{}`, "", "interface is (or embeds) comparable"},
+	} {
+		src := genericPkg + "p; " + test.src
+		f, err := parseSrc("p", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conf := Config{DefaultTypeArgs: true}
+		pkg, err := conf.Check("p", []*syntax.File{f}, nil)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.src, err)
+				continue
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v; want %q", test.src, err, test.err)
+		}
+		if test.typ != "" {
+			if got := pkg.Scope().Lookup("x").Type().String(); got != test.typ {
+				t.Errorf("%s: got type %s; want %s", test.src, got, test.typ)
+			}
+		}
+
+		// without DefaultTypeArgs, default types are not permitted
+		conf = Config{}
+		if _, err := conf.Check("p", []*syntax.File{f}, nil); err == nil || !strings.Contains(err.Error(), "type parameter defaults require GOEXPERIMENT=defaulttypeargs") {
+			t.Errorf("%s: got error %v; want type parameter defaults error", test.src, err)
+		}
+	}
+}
+
+func TestPhases(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface{ m() }
+
+func f[T I](x T) {
+	x.m()
+	var _ interface{ n() } = nil
+}
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var phases []string
+	conf := Config{Phase: func(phase string) { phases = append(phases, phase) }}
+	if _, err := conf.Check("p", []*syntax.File{f}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A typeSets phase must resume the phase it interrupted.
+	var main []string
+	typeSets := 0
+	for i, phase := range phases {
+		if phase == "typeSets" {
+			typeSets++
+			if i == 0 || i+1 == len(phases) || phases[i-1] != phases[i+1] {
+				t.Errorf("typeSets at index %d does not resume the interrupted phase: %v", i, phases)
+			}
+			continue
+		}
+		if n := len(main); n == 0 || main[n-1] != phase {
+			main = append(main, phase)
+		}
+	}
+	if typeSets == 0 {
+		t.Errorf("no typeSets phase reported: %v", phases)
+	}
+
+	want := []string{"initFiles", "collectObjects", "packageObjects", "processDelayed", "initOrder", "unusedImports", "recordUntyped"}
+	if !reflect.DeepEqual(main, want) {
+		t.Errorf("got phases %v; want %v", main, want)
+	}
+}
+
+func TestCounters(t *testing.T) {
+	const src = genericPkg + `p
+
+type List[T any] []T
+
+var (
+	_ List[int]
+	_ List[int] // the instance is reused
+	_ List[string]
+)
+
+type C interface{ ~int | ~string }
+
+func f[T C](x T) T { return x }
+
+var _ = f(1)
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var conf Config
+	check := NewChecker(&conf, NewPackage("p", ""), nil)
+	if err := check.Files([]*syntax.File{f}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := check.Counters()
+	if got.Instances != 3 {
+		t.Errorf("got %d instances; want 3", got.Instances)
+	}
+	if got.Interfaces == 0 || got.Unions == 0 || got.Delayed == 0 {
+		t.Errorf("got %+v; want non-zero counters", got)
+	}
+}
+
+func TestTraceWriter(t *testing.T) {
+	const src = genericPkg + `p
+
+type List[T any] []T
+
+type C interface{ ~int | ~string }
+
+func f[T C](x T) T { return x }
+
+var _ = f(1)
+
+var _ List[int]
+
+func g() { _ = undefined }
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	conf := Config{
+		TraceWriter: &buf,
+		Error:       func(error) {},
+	}
+	conf.Check(f.PkgName.Value, []*syntax.File{f}, nil)
+
+	var stack []TraceEvent
+	seen := make(map[string]bool) // kinds and steps seen
+	var last int64
+	for dec := json.NewDecoder(&buf); ; {
+		var ev TraceEvent
+		if err := dec.Decode(&ev); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Time < last {
+			t.Errorf("%+v: time goes backwards", ev)
+		}
+		last = ev.Time
+		seen[ev.Kind] = true
+		switch ev.Kind {
+		case "enter":
+			if ev.Depth != len(stack) {
+				t.Errorf("%+v: got depth %d; want %d", ev, ev.Depth, len(stack))
+			}
+			stack = append(stack, ev)
+			seen[ev.Step] = true
+		case "exit":
+			if len(stack) == 0 {
+				t.Fatalf("%+v: exit without enter", ev)
+			}
+			enter := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if ev.Step != enter.Step || ev.Pos != enter.Pos || ev.Depth != len(stack) {
+				t.Errorf("%+v: exit does not match %+v", ev, enter)
+			}
+			if ev.Time-ev.Dur != enter.Time {
+				t.Errorf("%+v: duration does not match %+v", ev, enter)
+			}
+		case "error":
+			if want := "undeclared name: undefined"; ev.Desc != want || ev.Pos != "p:13:16" {
+				t.Errorf("got error %q at %s; want %q at p:13:16", ev.Desc, ev.Pos, want)
+			}
+		}
+	}
+	if len(stack) != 0 {
+		t.Errorf("%d steps not exited", len(stack))
+	}
+	for _, want := range []string{"phase", "later", "error", "objDecl", "typeSet", "instantiate", "infer", "funcBody", "delayed"} {
+		if !seen[want] {
+			t.Errorf("no %q event", want)
+		}
+	}
+}
+
+func TestInterfaceLimits(t *testing.T) {
+	for _, test := range []struct {
+		conf Config
+		src  string
+		err  string // error at position of "@"
+	}{
+		{Config{MaxUnionTerms: 2}, `type _ interface{ int | string | @bool }`, "constraint too complex: cannot handle more than 2 union terms"},
+		{Config{MaxUnionTerms: 2}, `type _ interface{ int | string }`, ""},
+		{Config{MaxEmbeddingDepth: 2}, `type (
+	I0 interface{ @I1 }
+	I1 interface{ I2 }
+	I2 interface{ I3 }
+	I3 interface{ m() }
+)`, "interface too complex: cannot handle interfaces embedded more than 2 levels deep"},
+		{Config{MaxEmbeddingDepth: 3}, `type (
+	I0 interface{ I1 }
+	I1 interface{ I2 }
+	I2 interface{ m() }
+)`, ""},
+		{Config{MaxInterfaceMethods: 2}, `type _ interface{ a(); b(); @c(); d() }`, "interface too complex: cannot handle more than 2 methods"},
+		{Config{MaxInterfaceMethods: 2}, `type I interface{ a(); b() }; type _ interface{ I; a() }`, ""},
+	} {
+		src := genericPkg + "p; " + test.src
+		f, err := parseSrc("p", strings.Replace(src, "@", "", 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []Error
+		conf := test.conf
+		conf.Error = func(err error) { got = append(got, err.(Error)) }
+		conf.Check("p", []*syntax.File{f}, nil)
+
+		if test.err == "" {
+			if len(got) > 0 {
+				t.Errorf("%s: unexpected error: %s", test.src, got[0].Msg)
+			}
+			continue
+		}
+		if len(got) != 1 {
+			t.Errorf("%s: got %d errors (%v); want 1", test.src, len(got), got)
+			continue
+		}
+		offs := strings.Index(src, "@")
+		line := uint(strings.Count(src[:offs], "\n") + 1)
+		col := uint(offs - strings.LastIndex(src[:offs], "\n"))
+		if got[0].Msg != test.err || got[0].Pos.Line() != line || got[0].Pos.Col() != col {
+			t.Errorf("%s: got error %s at %d:%d; want %s at %d:%d", test.src, got[0].Msg, got[0].Pos.Line(), got[0].Pos.Col(), test.err, line, col)
+		}
+	}
+}
+
+func TestCompleteTypes(t *testing.T) {
+	const src = genericPkg + `p
+
+import "strings"
+
+const c = 1
+
+type T[P any, _ interface{ ~int | ~string }] struct{ f P }
+
+func (t *T[P, _]) m() P { return t.f }
+
+type C interface {
+	~int | string | ~float64
+	m()
+}
+
+func f[P C, Q ~[]P](x P, q Q) (r int) {
+	var v T[int, int]
+	_ = v.m()
+	_, r = strings.Index("a", "b"), c
+	v.f = len(q)
+	return
+}
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := Config{Importer: defaultImporter(), CompleteTypes: true}
+	info := Info{Types: make(map[syntax.Expr]TypeAndValue)}
+	if _, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	// All expressions except for the documented exceptions are recorded.
+	for _, decl := range f.DeclList {
+		if _, ok := decl.(*syntax.ImportDecl); ok {
+			continue
+		}
+		syntax.Inspect(decl, func(n syntax.Node) bool {
+			switch n := n.(type) {
+			case *syntax.ListExpr:
+				return true
+			case *syntax.Name:
+				if n.Value == "strings" {
+					return true // package name
+				}
+			}
+			if x, _ := n.(syntax.Expr); x != nil {
+				if _, found := info.Types[x]; !found {
+					t.Errorf("%s: %s: no type recorded", x.Pos(), syntax.String(x))
+				}
+			}
+			return true
+		})
+	}
+
+	// The receiver type expressions denote the receiver type.
+	m := f.DeclList[len(f.DeclList)-3].(*syntax.FuncDecl)
+	recv := info.Types[m.Recv.Type].Type
+	if ptr, _ := recv.(*Pointer); ptr == nil || !Identical(info.Types[m.Recv.Type.(*syntax.Operation).X].Type, ptr.Elem()) {
+		t.Errorf("%s: got receiver type %s", syntax.String(m.Recv.Type), recv)
+	}
+
+	// Spot-check some of the expressions that are recorded only with
+	// Config.CompleteTypes.
+	exprs := make(map[string]syntax.Expr)
+	syntax.Inspect(f, func(n syntax.Node) bool {
+		if x, _ := n.(syntax.Expr); x != nil {
+			key := fmt.Sprintf("%s@%d", syntax.String(x), x.Pos().Line())
+			if _, found := exprs[key]; !found {
+				exprs[key] = x
+			}
+		}
+		return true
+	})
+	for _, test := range []struct {
+		expr, typ string
+	}{
+		{"_@19", "int"}, // blank identifier in _, r = ...
+		{"~int | string | ~float64@12", "~int|string|~float64"},
+		{"~int | string@12", "~int|string"},
+		{"~float64@12", "~float64"},
+		{"Index@19", "func(s string, substr string) int"},
+		{"v@17", "generic_p.T[int, int]"},
+		{"any@7", "interface{}"},
+	} {
+		x := exprs[test.expr]
+		if x == nil {
+			t.Errorf("%s: expression not found", test.expr)
+			continue
+		}
+		if got := info.Types[x].Type.String(); got != test.typ {
+			t.Errorf("%s: got type %s; want %s", test.expr, got, test.typ)
+		}
+	}
+}
+
+func TestInterfaceComplete(t *testing.T) {
+	// type I interface{ m() }
+	// type J interface{ I; n() }
+	newMethod := func(name string) *Func {
+		return NewFunc(nopos, nil, name, NewSignature(nil, nil, nil, false))
+	}
+	I := NewInterfaceType([]*Func{newMethod("m")}, nil)
+	J := NewInterfaceType([]*Func{newMethod("n")}, []Type{I})
+
+	if I.IsComplete() || J.IsComplete() {
+		t.Fatalf("interfaces are complete before use")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := J.Complete(); got != J {
+				t.Errorf("Complete returned %v; want the receiver", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !J.IsComplete() || !I.IsComplete() {
+		t.Errorf("interfaces not complete after Complete: I %v, J %v", I.IsComplete(), J.IsComplete())
+	}
+	if got := J.NumMethods(); got != 2 {
+		t.Errorf("got %d methods; want 2", got)
+	}
+	if m := J.Complete().Method(0); m.Name() != "m" {
+		t.Errorf("got method %s; want m", m.Name())
+	}
+}
+
+func TestInterfaceLazy(t *testing.T) {
+	// Type sets are computed lazily and concurrently.
+	newMethod := func(name string) *Func {
+		return NewFunc(nopos, nil, name, NewSignature(nil, nil, nil, false))
+	}
+	I := NewInterfaceType([]*Func{newMethod("m")}, nil)
+	J := NewInterfaceType([]*Func{newMethod("n")}, []Type{I, NewUnion([]*Term{NewTerm(true, Typ[Int])})})
+	K := NewInterfaceType(nil, []Type{J})
+
+	var wg sync.WaitGroup
+	sets := make([]*TypeSet, 8)
+	for i := range sets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				sets[i] = K.TypeSet()
+			} else {
+				sets[i] = J.TypeSet()
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, s := range sets {
+		if want := sets[i%2]; s != want {
+			t.Errorf("got different type sets %s and %s", s, want)
+		}
+	}
+	if got := K.TypeSet().String(); got != "{func (interface).m(); func (interface).n(); ~int}" {
+		t.Errorf("got type set %s", got)
+	}
+
+	// Interfaces of a package whose type-checking stopped at the first
+	// error can be used without the checker.
+	const src = `package p
+
+type I interface {
+	m()
+	m()
+}
+
+type J interface {
+	I
+	m(int)
+}
+
+var _ = undefined
+`
+	f, err := parseSrc("p", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(Config).Check("p", []*syntax.File{f}, nil)
+	if err == nil {
+		t.Fatal("no error reported")
+	}
+	for _, name := range []string{"I", "J"} {
+		iface := pkg.Scope().Lookup(name).Type().Underlying().(*Interface)
+		if iface.IsComplete() {
+			t.Errorf("type set of %s computed by checker", name)
+		}
+		if n := iface.NumMethods(); n != 1 || !iface.IsPartial() {
+			t.Errorf("%s: got %d methods, partial %v; want 1 method, partial", name, n, iface.IsPartial())
+		}
+	}
+}
+
+func TestEmbeddedPos(t *testing.T) {
+	const src = genericPkg + `p
+
+import "fmt"
+
+type I interface {
+	m()
+	fmt.Stringer
+	~int |
+		~string
+	comparable
+}
+
+type C[P interface{ ~int | ~float64 }] interface {
+	*P
+}
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	posString := func(pos syntax.Pos) string {
+		if !pos.IsKnown() {
+			return "-"
+		}
+		return fmt.Sprintf("%d:%d", pos.Line(), pos.Col())
+	}
+	embeddings := func(iface *Interface) string {
+		var list []string
+		for i := 0; i < iface.NumEmbeddeds(); i++ {
+			list = append(list, posString(iface.EmbeddedPos(i)))
+		}
+		return strings.Join(list, " ")
+	}
+
+	I := pkg.Scope().Lookup("I").Type().Underlying().(*Interface)
+	if got, want := embeddings(I), "7:2 8:2 10:2"; got != want {
+		t.Errorf("I: got %s, want %s", got, want)
+	}
+
+	// the implicit interface of a constraint literal has the position of the literal
+	C := pkg.Scope().Lookup("C").Type().(*Named)
+	if got, want := embeddings(C.TParams().At(0).Interface()), "13:21"; got != want {
+		t.Errorf("constraint of P: got %s, want %s", got, want)
+	}
+
+	// the positions are retained for instances
+	inst, err := Instantiate(nil, C, []Type{Typ[Int]}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := embeddings(inst.Underlying().(*Interface)), "14:2"; got != want {
+		t.Errorf("C[int]: got %s, want %s", got, want)
+	}
+
+	// interfaces created by the API have no positions
+	iface := NewInterfaceType(nil, []Type{I, Typ[Int]})
+	if got, want := embeddings(iface), "- -"; got != want {
+		t.Errorf("NewInterfaceType: got %s, want %s", got, want)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	const src = genericPkg + `p
+
+type Number interface{ ~int | ~float64 }
+
+type List[P Number] struct {
+	next *List[P]
+	val  P
+}
+
+func (l *List[P]) Push(v P) *List[P] { return &List[P]{l, v} }
+
+func (l *List[P]) Sum() (s P) {
+	for ; l != nil; l = l.next {
+		s += l.val
+	}
+	return
+}
+
+type Pair[K comparable, V Number] struct {
+	key K
+	val List[V]
+}
+
+var _ Pair[string, int]
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	List := pkg.Scope().Lookup("List").Type()
+	Pair := pkg.Scope().Lookup("Pair").Type().(*Named)
+	inst, err := Instantiate(nil, List, []Type{Typ[Float64]}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst2, err := Instantiate(nil, List, []Type{Typ[Float64]}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair, err := Instantiate(nil, Pair, []Type{Typ[Int], Typ[Float64]}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Use the types concurrently; the lazily computed parts of the
+	// instances and the type sets of the constraints are computed
+	// concurrently (as run with -race).
+	var wg sync.WaitGroup
+	res := make([]string, 8)
+	for i := range res {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var buf strings.Builder
+			obj, index, indirect := LookupFieldOrMethod(NewPointer(inst), false, pkg, "Push")
+			fmt.Fprintln(&buf, obj, index, indirect)
+			obj, index, _ = LookupFieldOrMethod(pair, false, pkg, "val")
+			fmt.Fprintln(&buf, obj, index, pair.Underlying())
+			fmt.Fprintln(&buf, Identical(inst, inst2), Identical(pair.Underlying(), pair.Underlying()))
+			tpar := Pair.TParams().At(0)
+			fmt.Fprintln(&buf, Satisfies(Typ[Int], tpar.Interface()), tpar.Interface().IsComparable())
+			res[i] = buf.String()
+		}(i)
+	}
+	wg.Wait()
+	for _, r := range res {
+		if r != res[0] {
+			t.Errorf("got different results:\n%s\n%s", r, res[0])
+		}
+	}
+	if !strings.Contains(res[0], "func (*generic_p.List[float64]).Push(v float64) *generic_p.List[float64]") {
+		t.Errorf("unexpected results:\n%s", res[0])
+	}
+}
+
+func TestPredeclared(t *testing.T) {
+	const src = `package p
+
+var h Handle = Handle(Version)
+var n = emit("x") + len("ab")
+
+const v = Version * 2
+
+func _() { println(h) }
+`
+	handle := NewNamed(NewTypeName(nopos, nil, "Handle", nil), Typ[Int], nil)
+	funcType := func(params ...Type) *Signature {
+		var vars []*Var
+		for _, typ := range params {
+			vars = append(vars, NewParam(nopos, nil, "", typ))
+		}
+		return NewSignature(nil, NewTuple(vars...), NewTuple(NewParam(nopos, nil, "", Typ[Int])), false)
+	}
+	emit := NewFunc(nopos, nil, "emit", funcType(Typ[String]))
+	println := NewFunc(nopos, nil, "println", funcType(handle)) // shadows the built-in println
+	predeclared := []Object{
+		handle.Obj(),
+		NewConst(nopos, nil, "Version", Typ[UntypedInt], constant.MakeInt64(3)),
+		emit,
+		println,
+	}
+
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := Config{Predeclared: predeclared}
+	info := Info{
+		Types: make(map[syntax.Expr]TypeAndValue),
+		Uses:  make(map[*syntax.Name]Object),
+	}
+	pkg, err := conf.Check("p", []*syntax.File{f}, &info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := pkg.Scope().Lookup("v").(*Const).Val().String(); got != "6" {
+		t.Errorf("got v = %s; want 6", got)
+	}
+	if got := pkg.Scope().Lookup("h").Type(); got != handle {
+		t.Errorf("got type %s for h; want Handle", got)
+	}
+	uses := make(map[string]Object)
+	for id, obj := range info.Uses {
+		uses[id.Value] = obj
+	}
+	if uses["emit"] != emit || uses["println"] != println {
+		t.Errorf("got emit %v, println %v; want predeclared functions", uses["emit"], uses["println"])
+	}
+	if uses["len"] != Universe.Lookup("len") {
+		t.Errorf("got len %v; want built-in", uses["len"])
+	}
+	if pkg.Scope().Parent() != Universe || Universe.Lookup("Handle") != nil {
+		t.Errorf("predeclared objects leaked into Universe scope")
+	}
+
+	// Without Config.Predeclared, the identifiers are undeclared.
+	conf = Config{Error: func(error) {}}
+	if _, err := conf.Check("p", []*syntax.File{f}, nil); err == nil || !strings.Contains(err.Error(), "undeclared name: Handle") {
+		t.Errorf("got error %v; want undeclared name Handle", err)
+	}
+}
+
+func TestTargets(t *testing.T) {
+	const src = `package p
+
+import "unsafe"
+
+type T struct {
+	a int
+	b *int
+}
+
+const size = unsafe.Sizeof(T{})
+const is64 = size == 16
+const same = unsafe.Sizeof(int32(0)) + 1
+
+var a [size / 8]int
+
+var (
+	_ [int(size) - 16]byte
+	_ = 1 / (size - 8)
+	_ = size << 29
+	_ = int(size << 28)
+)
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []string
+	conf := Config{
+		Importer: defaultImporter(),
+		Targets:  []Target{{"linux/386", SizesFor("gc", "386")}},
+		Error:    func(err error) { errs = append(errs, err.(Error).Msg) },
+	}
+	info := Info{
+		Types:        make(map[syntax.Expr]TypeAndValue),
+		TargetValues: make(map[syntax.Expr][]constant.Value),
+	}
+	pkg, _ := conf.Check("p", []*syntax.File{f}, &info)
+
+	// Size-dependent values that are valid for all targets but differ
+	// are recorded with the values for the targets.
+	values := make(map[string]string)
+	for x, vals := range info.TargetValues {
+		values[syntax.String(x)] = fmt.Sprintf("%s -> %s", info.Types[x].Value, vals)
+	}
+	for _, test := range []struct{ expr, want string }{
+		{"unsafe.Sizeof(T{})", "16 -> [8]"},
+		{"size == 16", "true -> [false]"},
+		{"size / 8", "2 -> [1]"},
+	} {
+		if got := values[test.expr]; got != test.want {
+			t.Errorf("%s: got %q; want %q", test.expr, got, test.want)
+		}
+	}
+	if got := values["unsafe.Sizeof(int32(0)) + 1"]; got != "" {
+		t.Errorf("got target values %s for size-independent expression", got)
+	}
+	if got := pkg.Scope().Lookup("a").Type().String(); got != "[2]int" {
+		t.Errorf("got type %s for a; want [2]int", got)
+	}
+
+	// Invalid values for some target are errors.
+	want := []string{
+		"invalid array length int(size) - 16 (value -8 for linux/386)",
+		"invalid operation: division by zero for linux/386",
+		"size << 29 (value 4294967296 for linux/386) overflows uintptr",
+		"size << 28 (value 2147483648 for linux/386) overflows int",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got errors %q; want %d errors", errs, len(want))
+	}
+	for i, msg := range errs {
+		if !strings.HasPrefix(msg, want[i]) {
+			t.Errorf("got error %q; want %q", msg, want[i])
+		}
+	}
+}
+
+func TestConstructGenericNamed(t *testing.T) {
+	// Construct
+	//
+	//	package gen
+	//	type List[P any] struct{ head P }
+	//	func (l *List[P]) Head() P
+	pkg := NewPackage("gen", "gen")
+	P := (*Checker)(nil).NewTypeParam(NewTypeName(nopos, pkg, "P", nil), NewInterfaceType(nil, nil))
+	list := NewNamed(NewTypeName(nopos, pkg, "List", nil), nil, nil)
+	list.SetTParams([]*TypeParam{P})
+	list.SetUnderlying(NewStruct([]*Var{NewField(nopos, pkg, "head", P, false)}, nil))
+
+	env := NewEnvironment(nil)
+	self, err := Instantiate(env, list, []Type{P}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recv := NewVar(nopos, pkg, "l", NewPointer(self))
+	list.AddMethod(NewFunc(nopos, pkg, "Head", NewSignature(recv, nil, NewTuple(NewVar(nopos, pkg, "", P)), false)))
+	pkg.Scope().Insert(list.Obj())
+	pkg.MarkComplete()
+
+	// Instances are de-duplicated within the environment, and their
+	// methods are instantiated.
+	inst, err := Instantiate(env, list, []Type{Typ[Int]}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst2, _ := Instantiate(env, list, []Type{Typ[Int]}, true); inst2 != inst {
+		t.Errorf("got different instances %s and %s", inst, inst2)
+	}
+	named := inst.(*Named)
+	if named.Orig() != list {
+		t.Errorf("got origin %s; want %s", named.Orig(), list)
+	}
+	if got := named.Underlying().(*Struct).Field(0).Type(); got != Typ[Int] {
+		t.Errorf("got field type %s; want int", got)
+	}
+	if got, want := named.Method(0).String(), "func (*gen.List[int]).Head() int"; got != want {
+		t.Errorf("got method %s; want %s", got, want)
+	}
+	if got := named.Method(0).Origin(); got != list.Method(0) {
+		t.Errorf("got method origin %s; want generic method", got)
+	}
+
+	// The constructed package can be imported.
+	const src = `package generic_p
+
+import "gen"
+
+var l gen.List[string]
+var s string = l.Head()
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := Config{Importer: testImporter{"gen": pkg}}
+	if _, err := conf.Check("p", []*syntax.File{f}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Instances cannot be modified.
+	defer func() {
+		if recover() == nil {
+			t.Errorf("AddMethod on instance did not panic")
+		}
+	}()
+	named.AddMethod(NewFunc(nopos, pkg, "Tail", NewSignature(nil, nil, nil, false)))
+}
+
+func TestTildeErrorPos(t *testing.T) {
+	const src = `package p
+
+type MyInt int
+
+type _ interface{ ~MyInt | string }
+type _ interface{ type MyInt }
+`
+	f, err := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), nil, nil, syntax.AllowGenerics|syntax.AllowTypeLists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	conf := Config{
+		AllowTypeLists: true,
+		Error: func(err error) {
+			got = append(got, err.(Error).Pos.String())
+		},
+	}
+	conf.Check(f.PkgName.Value, []*syntax.File{f}, nil)
+
+	// The errors are reported at the ~ operator, which is the position
+	// of the type list entry for type lists.
+	want := []string{"p.go:5:19", "p.go:6:24"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got errors at %v; want %v", got, want)
+	}
+}
+
+func TestInterfaceTypeSet(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface {
+	~int | ~int8 | ~string
+	m()
+}
+
+type J interface {
+	I
+	~int | ~string
+	n()
+}
+
+type T interface{ ~int | string }
+
+type Empty interface {
+	int
+	string
+}
+
+type EmbedsEmpty interface {
+	EmptyMethods
+	n()
+}
+
+type NoMethods int
+
+type EmptyMethods interface {
+	NoMethods
+	m()
+}
+
+type PtrNoMethods interface {
+	*NoMethods | struct{ NoMethods }
+	m()
+}
+
+type Any interface{}
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeSet := func(name string) *TypeSet {
+		return pkg.Scope().Lookup(name).Type().Underlying().(*Interface).TypeSet()
+	}
+
+	s := typeSet("J")
+	var methods, terms []string
+	for i := 0; i < s.NumMethods(); i++ {
+		methods = append(methods, s.Method(i).Name())
+	}
+	for i := 0; i < s.NumTerms(); i++ {
+		terms = append(terms, s.Term(i).String())
+	}
+	if got, want := strings.Join(methods, " "), "m n"; got != want {
+		t.Errorf("got methods %s; want %s", got, want)
+	}
+	if got, want := strings.Join(terms, " "), "~int ~string"; got != want {
+		t.Errorf("got terms %s; want %s", got, want)
+	}
+
+	if s := typeSet("T"); s.NumTerms() != 2 || !s.Term(0).Tilde() || s.Term(1).Tilde() {
+		t.Errorf("got type set %s; want ~ only for the first term", s)
+	}
+
+	for _, name := range []string{"Empty", "EmbedsEmpty", "EmptyMethods", "PtrNoMethods"} {
+		if s := typeSet(name); !s.IsEmpty() || s.NumTerms() != 0 {
+			t.Errorf("%s: got type set %s with %d terms; want empty type set", name, s, s.NumTerms())
+		}
+	}
+	if s := typeSet("Any"); !s.IsAll() || s.NumTerms() != 0 || s.NumMethods() != 0 {
+		t.Errorf("got type set %s; want set of all types", s)
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	const src = genericPkg + `p
+
+type Number interface{ ~int | ~float64 }
+
+type Stringer interface{ String() string }
+
+type MyInt int
+
+func (MyInt) String() string { return "" }
+
+type W int
+
+func (W) String() int { return 0 }
+
+type S struct{}
+
+type C interface{ comparable }
+
+func f[P ~int | ~string, Q ~int]() {}
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(name string) Type { return pkg.Scope().Lookup(name).Type() }
+	iface := func(name string) *Interface { return lookup(name).Underlying().(*Interface) }
+	tparams := lookup("f").(*Signature).TParams()
+
+	for _, test := range []struct {
+		T      Type
+		C      string
+		want   bool
+		method string // name of failing method, if any
+		wrong  bool   // whether the type's method has the wrong signature
+		term   string // failing term, if any
+	}{
+		{lookup("MyInt"), "Number", true, "", false, ""},
+		{lookup("MyInt"), "Stringer", true, "", false, ""},
+		{Typ[String], "Number", false, "", false, ""},
+		{lookup("S"), "Stringer", false, "String", false, ""},
+		{lookup("W"), "Stringer", false, "String", true, ""},
+		{NewSlice(Typ[Int]), "C", false, "", false, ""},
+		{lookup("S"), "C", true, "", false, ""},
+		{tparams.At(0), "Number", false, "", false, "~string"},
+		{tparams.At(1), "Number", true, "", false, ""},
+	} {
+		C := iface(test.C)
+		if got := Satisfies(test.T, C); got != test.want {
+			t.Errorf("Satisfies(%s, %s) = %v; want %v", test.T, test.C, got, test.want)
+		}
+		err := Unsatisfied(test.T, C)
+		if (err == nil) != test.want {
+			t.Errorf("Unsatisfied(%s, %s) = %v", test.T, test.C, err)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+		if got := err.Method; got == nil && test.method != "" || got != nil && got.Name() != test.method {
+			t.Errorf("%s, %s: got method %v; want %s", test.T, test.C, got, test.method)
+		}
+		if got := err.Wrong != nil; got != test.wrong {
+			t.Errorf("%s, %s: got wrong method %v", test.T, test.C, err.Wrong)
+		}
+		if got := err.Term; got == nil && test.term != "" || got != nil && got.String() != test.term {
+			t.Errorf("%s, %s: got term %v; want %s", test.T, test.C, got, test.term)
+		}
+	}
+}
+
+func TestExplainImplements(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface {
+	m()
+	n(int)
+	p()
+}
+
+type T struct{}
+
+func (T) n(string) {}
+func (*T) p()      {}
+
+type Ok struct{}
+
+func (Ok) m()    {}
+func (Ok) n(int) {}
+func (Ok) p()    {}
+
+type C interface {
+	comparable
+	~int | ~string
+}
+
+func f[P ~int | ~float64]() {}
+`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(name string) Type { return pkg.Scope().Lookup(name).Type() }
+	iface := func(name string) *Interface { return lookup(name).Underlying().(*Interface) }
+	qf := RelativeTo(pkg)
+
+	if r := ExplainImplements(lookup("Ok"), iface("I")); r != nil {
+		t.Errorf("got %s for Ok", r)
+	}
+
+	for _, test := range []struct {
+		V     Type
+		T     string
+		kinds []ReasonKind
+		want  string
+	}{
+		{lookup("T"), "I", []ReasonKind{MethodMissing, MethodWrongSignature, MethodPtrRecv}, `T does not implement interface{m(); n(int); p()}
+	missing method m
+	wrong type for method n
+		have func (T).n(string)
+		want func (I).n(int)
+	method p has pointer receiver`},
+		{NewSlice(Typ[Int]), "C", []ReasonKind{NotComparable, TermExcluded}, `[]int does not implement interface{comparable; ~int|~string}
+	[]int is not comparable
+	[]int is not in the type set of interface{comparable; ~int|~string}`},
+		{lookup("f").(*Signature).TParams().At(0), "C", []ReasonKind{TermExcluded}, `P does not implement interface{comparable; ~int|~string}
+	term ~float64 of P is not in the type set of interface{comparable; ~int|~string}`},
+	} {
+		r := ExplainImplements(test.V, iface(test.T))
+		if r == nil {
+			t.Errorf("%s implements %s", test.V, test.T)
+			continue
+		}
+		var kinds []ReasonKind
+		for _, c := range r.Children {
+			kinds = append(kinds, c.Kind)
+		}
+		if fmt.Sprint(kinds) != fmt.Sprint(test.kinds) {
+			t.Errorf("%s, %s: got reasons %v; want %v", test.V, test.T, kinds, test.kinds)
+		}
+		// ignore type parameter subscripts
+		got := strings.Map(func(r rune) rune {
+			if '₀' <= r && r <= '₉' {
+				return -1
+			}
+			return r
+		}, r.Message(qf))
+		if got != test.want {
+			t.Errorf("%s, %s: got message\n%s\nwant\n%s", test.V, test.T, got, test.want)
+		}
+	}
+}
@@ -23,7 +23,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		// ok
 	default:
 		// we may get here because of other problems (issue #39634, crash 12)
-		check.errorf(x, "cannot assign %s to %s in %s", x, T, context)
+		check.errorf(x, _IncompatibleAssign, "cannot assign %s to %s in %s", x, T, context)
 		return
 	}
 
@@ -36,7 +36,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		// complex, or string constant."
 		if x.isNil() {
 			if T == nil {
-				check.errorf(x, "use of untyped nil in %s", context)
+				check.errorf(x, _UntypedNil, "use of untyped nil in %s", context)
 				x.mode = invalid
 				return
 			}
@@ -51,8 +51,10 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 				msg += " (truncated)"
 			case _NumericOverflow:
 				msg += " (overflows)"
+			default:
+				code = _IncompatibleAssign
 			}
-			check.error(x, msg)
+			check.error(x, code, msg)
 			x.mode = invalid
 			return
 		}
@@ -69,7 +71,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 
 	// A generic (non-instantiated) function value cannot be assigned to a variable.
 	if sig := asSignature(x.typ); sig != nil && sig.TParams().Len() > 0 {
-		check.errorf(x, "cannot use generic function %s without instantiation in %s", x, context)
+		check.errorf(x, _Todo, "cannot use generic function %s without instantiation in %s", x, context)
 	}
 
 	// spec: "If a left-hand side is the blank identifier, any typed or
@@ -80,18 +82,32 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 	}
 
 	reason := ""
-	if ok, _ := x.assignableTo(check, T, &reason); !ok {
-		if check.conf.CompilerErrorMessages {
-			check.errorf(x, "incompatible type: cannot use %s as %s value", x, T)
+	if ok, code := x.assignableTo(check, T, &reason); !ok {
+		if isUnknown(x.typ) || isUnknown(T) {
+			// error reported before
+		} else if check.conf.CompilerErrorMessages {
+			check.errorf(x, code, "incompatible type: cannot use %s as %s value", x, T)
 		} else {
 			if reason != "" {
-				check.errorf(x, "cannot use %s as %s value in %s: %s", x, T, context, reason)
+				check.errorf(x, code, "cannot use %s as %s value in %s: %s", x, T, context, reason)
 			} else {
-				check.errorf(x, "cannot use %s as %s value in %s", x, T, context)
+				check.errorf(x, code, "cannot use %s as %s value in %s", x, T, context)
 			}
 		}
 		x.mode = invalid
+		return
+	}
+
+	check.implicitConversion(x, T)
+}
+
+// implicitConversion records the implicit conversion of x to T if T is
+// an interface type and x is a non-nil value of a different type.
+func (check *Checker) implicitConversion(x *operand, T Type) {
+	if x.mode == invalid || x.isNil() || x.expr == nil || !IsInterface(T) || Identical(x.typ, T) {
+		return
 	}
+	check.recordConversion(x.expr, Default(x.typ), T)
 }
 
 func (check *Checker) initConst(lhs *Const, x *operand) {
@@ -104,7 +120,7 @@ func (check *Checker) initConst(lhs *Const, x *operand) {
 
 	// rhs must be a constant
 	if x.mode != constant_ {
-		check.errorf(x, "%s is not constant", x)
+		check.errorf(x, _InvalidConstInit, "%s is not constant", x)
 		if lhs.typ == nil {
 			lhs.typ = Typ[Invalid]
 		}
@@ -123,6 +139,9 @@ func (check *Checker) initConst(lhs *Const, x *operand) {
 	}
 
 	lhs.val = x.val
+	if vals := check.targetValues(x); vals != nil {
+		check.targetConsts[lhs] = vals
+	}
 }
 
 func (check *Checker) initVar(lhs *Var, x *operand, context string) Type {
@@ -142,7 +161,7 @@ func (check *Checker) initVar(lhs *Var, x *operand, context string) Type {
 		if isUntyped(typ) {
 			// convert untyped types to default types
 			if typ == Typ[UntypedNil] {
-				check.errorf(x, "use of untyped nil in %s", context)
+				check.errorf(x, _UntypedNil, "use of untyped nil in %s", context)
 				lhs.typ = Typ[Invalid]
 				return nil
 			}
@@ -175,6 +194,9 @@ func (check *Checker) assignVar(lhs syntax.Expr, x *operand) Type {
 		if x.mode == invalid {
 			return nil
 		}
+		if check.conf.CompleteTypes {
+			check.recordTypeAndValue(lhs, variable, x.typ, nil)
+		}
 		return x.typ
 	}
 
@@ -185,10 +207,9 @@ func (check *Checker) assignVar(lhs syntax.Expr, x *operand) Type {
 	var v_used bool
 	if ident != nil {
 		if obj := check.lookup(ident.Value); obj != nil {
-			// It's ok to mark non-local variables, but ignore variables
-			// from other packages to avoid potential race conditions with
-			// dot-imported variables.
-			if w, _ := obj.(*Var); w != nil && w.pkg == check.pkg {
+			// Ignore package-level variables and variables from other
+			// packages, which are not marked (see Checker.ident).
+			if w, _ := obj.(*Var); w != nil && w.pkg == check.pkg && w.parent != check.pkg.scope {
 				v = w
 				v_used = v.used
 			}
@@ -213,18 +234,18 @@ func (check *Checker) assignVar(lhs syntax.Expr, x *operand) Type {
 	case variable, mapindex:
 		// ok
 	case nilvalue:
-		check.error(&z, "cannot assign to nil") // default would print "untyped nil"
+		check.error(&z, _UnassignableOperand, "cannot assign to nil") // default would print "untyped nil"
 		return nil
 	default:
 		if sel, ok := z.expr.(*syntax.SelectorExpr); ok {
 			var op operand
 			check.expr(&op, sel.X)
 			if op.mode == mapindex {
-				check.errorf(&z, "cannot assign to struct field %s in map", syntax.String(z.expr))
+				check.errorf(&z, _UnaddressableFieldAssign, "cannot assign to struct field %s in map", syntax.String(z.expr))
 				return nil
 			}
 		}
-		check.errorf(&z, "cannot assign to %s", &z)
+		check.errorf(&z, _UnassignableOperand, "cannot assign to %s", &z)
 		return nil
 	}
 
@@ -255,10 +276,10 @@ func (check *Checker) initVars(lhs []*Var, orig_rhs []syntax.Expr, returnPos syn
 			}
 		}
 		if returnPos.IsKnown() {
-			check.errorf(returnPos, "wrong number of return values (want %d, got %d)", len(lhs), len(rhs))
+			check.errorf(returnPos, _WrongResultCount, "wrong number of return values (want %d, got %d)", len(lhs), len(rhs))
 			return
 		}
-		check.errorf(rhs[0], "cannot initialize %d variables with %d values", len(lhs), len(rhs))
+		check.errorf(rhs[0], _WrongAssignCount, "cannot initialize %d variables with %d values", len(lhs), len(rhs))
 		return
 	}
 
@@ -292,7 +313,7 @@ func (check *Checker) assignVars(lhs, orig_rhs []syntax.Expr) {
 				return
 			}
 		}
-		check.errorf(rhs[0], "cannot assign %d values to %d variables", len(rhs), len(lhs))
+		check.errorf(rhs[0], _WrongAssignCount, "cannot assign %d values to %d variables", len(rhs), len(lhs))
 		return
 	}
 
@@ -338,7 +359,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 		ident, _ := lhs.(*syntax.Name)
 		if ident == nil {
 			check.useLHS(lhs)
-			check.errorf(lhs, "non-name %s on left side of :=", lhs)
+			check.errorf(lhs, _BadDecl, "non-name %s on left side of :=", lhs)
 			hasErr = true
 			continue
 		}
@@ -346,7 +367,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 		name := ident.Value
 		if name != "_" {
 			if seen[name] {
-				check.errorf(lhs, "%s repeated on left side of :=", lhs)
+				check.errorf(lhs, _RepeatedDecl, "%s repeated on left side of :=", lhs)
 				hasErr = true
 				continue
 			}
@@ -363,7 +384,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 			if obj, _ := alt.(*Var); obj != nil {
 				lhsVars[i] = obj
 			} else {
-				check.errorf(lhs, "cannot assign to %s", lhs)
+				check.errorf(lhs, _UnassignableOperand, "cannot assign to %s", lhs)
 				hasErr = true
 			}
 			continue
@@ -391,7 +412,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 	check.processDelayed(top)
 
 	if len(newVars) == 0 && !hasErr {
-		check.softErrorf(pos, "no new variables on left side of :=")
+		check.softErrorf(pos, _NoNewVar, "no new variables on left side of :=")
 		return
 	}
 
@@ -0,0 +1,378 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements CheckBatch, which type-checks a set of
+// interdependent packages concurrently.
+
+package types2
+
+import (
+	"cmd/compile/internal/syntax"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A BatchPackage describes a package to be checked by CheckBatch.
+type BatchPackage struct {
+	Path  string         // package path; must be unique within a batch
+	Files []*syntax.File // package files
+	Info  *Info          // if non-nil, populated as by Config.Check
+}
+
+// CheckBatch type-checks the packages pkgs and returns the resulting
+// package objects, in the same order as pkgs, and the first error if any.
+//
+// The import declarations of the package files define the dependency
+// graph: an import of a path that is the Path of another package in the
+// batch denotes that package, all other imports are resolved through
+// conf.Importer. Each package is checked after the packages it imports,
+// and packages which do not depend on each other are checked concurrently.
+// Instances of generic types are shared across packages: a package re-uses
+// the instances created while checking the packages it depends on.
+//
+// Import cycles among the batch packages are reported as errors. The
+// packages in a cycle, and the packages depending on them, are not checked;
+// their entries in the result are nil.
+//
+// Errors are reported through conf.Error as by Config.Check; calls of
+// conf.Error are serialized. conf.Importer calls are serialized as well.
+// The returned error is the first error of the first package (in the order
+// of pkgs) that has errors, with import cycle errors reported first.
+func (conf *Config) CheckBatch(pkgs []*BatchPackage) ([]*Package, error) {
+	b := &batch{
+		conf:  conf,
+		pkgs:  pkgs,
+		index: make(map[string]int, len(pkgs)),
+		nodes: make([]batchNode, len(pkgs)),
+		sem:   make(chan struct{}, runtime.GOMAXPROCS(0)),
+	}
+	for i, p := range pkgs {
+		if _, found := b.index[p.Path]; found {
+			return nil, fmt.Errorf("duplicate package path %q", p.Path)
+		}
+		b.index[p.Path] = i
+	}
+	b.collectImports()
+
+	// report import cycles
+	var firstErr error
+	for _, cycle := range b.findCycles() {
+		err := b.cycleError(cycle)
+		if firstErr == nil {
+			firstErr = err
+		}
+		b.report(err)
+	}
+
+	// check the packages, each one as soon as its dependencies are done
+	var wg sync.WaitGroup
+	for i := range b.nodes {
+		b.nodes[i].done = make(chan struct{})
+	}
+	for i := range b.nodes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b.check(i)
+		}(i)
+	}
+	wg.Wait()
+
+	res := make([]*Package, len(pkgs))
+	for i := range b.nodes {
+		n := &b.nodes[i]
+		res[i] = n.pkg
+		if firstErr == nil {
+			firstErr = n.err
+		}
+	}
+	return res, firstErr
+}
+
+// A batch holds the state of a CheckBatch invocation.
+type batch struct {
+	conf  *Config
+	pkgs  []*BatchPackage
+	index map[string]int // package path -> index in pkgs and nodes
+	nodes []batchNode
+	sem   chan struct{} // limits the number of concurrently checked packages
+
+	errMu sync.Mutex // serializes conf.Error calls
+	impMu sync.Mutex // serializes conf.Importer calls
+}
+
+// A batchNode holds the per-package state of a batch.
+type batchNode struct {
+	deps  []batchDep
+	comp  int  // strongly connected component of the import graph
+	cycle bool // package is part of an import cycle, or depends on one
+
+	// set once done is closed
+	done   chan struct{}
+	pkg    *Package          // nil if the package was not checked
+	err    error             // first error, if any
+	typMap map[string]*Named // instances created while checking the package and its dependencies
+}
+
+// A batchDep describes an import of a batch package by another one.
+type batchDep struct {
+	index int        // index of the imported package
+	pos   syntax.Pos // position of the import path
+}
+
+// collectImports records the imports of batch packages for each package.
+func (b *batch) collectImports() {
+	for i, p := range b.pkgs {
+		n := &b.nodes[i]
+		seen := make(map[int]bool)
+		for _, file := range p.Files {
+			for _, decl := range file.DeclList {
+				d, _ := decl.(*syntax.ImportDecl)
+				if d == nil || d.Path == nil {
+					continue
+				}
+				path, err := strconv.Unquote(d.Path.Value)
+				if err != nil {
+					continue // reported when the package is checked
+				}
+				if j, found := b.index[path]; found && !seen[j] {
+					seen[j] = true
+					n.deps = append(n.deps, batchDep{j, d.Path.Pos()})
+				}
+			}
+		}
+	}
+}
+
+// findCycles marks the packages that are part of import cycles and returns
+// one cycle for each set of mutually dependent packages, in batch order.
+// Each cycle is a list of package indices, starting with the first package
+// of the set; each package imports the next one and the last package imports
+// the first.
+func (b *batch) findCycles() (cycles [][]int) {
+	// Compute the strongly connected components of the import graph
+	// (Tarjan's algorithm). Packages of the same component are never
+	// waited on by each other.
+	const unvisited = -1
+	num := make([]int, len(b.nodes)) // visit order
+	low := make([]int, len(b.nodes))
+	onStack := make([]bool, len(b.nodes))
+	for i := range num {
+		num[i] = unvisited
+	}
+	var stack []int
+	var next, comp int
+
+	var visit func(i int)
+	visit = func(i int) {
+		num[i] = next
+		low[i] = next
+		next++
+		stack = append(stack, i)
+		onStack[i] = true
+		for _, d := range b.nodes[i].deps {
+			j := d.index
+			if num[j] == unvisited {
+				visit(j)
+				if low[j] < low[i] {
+					low[i] = low[j]
+				}
+			} else if onStack[j] && num[j] < low[i] {
+				low[i] = num[j]
+			}
+		}
+		if low[i] == num[i] {
+			for {
+				j := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[j] = false
+				b.nodes[j].comp = comp
+				if j == i {
+					break
+				}
+			}
+			comp++
+		}
+	}
+	for i := range b.nodes {
+		if num[i] == unvisited {
+			visit(i)
+		}
+	}
+
+	// A component is cyclic if it has more than one package
+	// or its package imports itself.
+	size := make([]int, comp)
+	for i := range b.nodes {
+		size[b.nodes[i].comp]++
+	}
+	reported := make([]bool, comp)
+	for i := range b.nodes {
+		n := &b.nodes[i]
+		n.cycle = size[n.comp] > 1
+		for _, d := range n.deps {
+			if d.index == i {
+				n.cycle = true
+			}
+		}
+		if n.cycle && !reported[n.comp] {
+			reported[n.comp] = true
+			cycles = append(cycles, b.cycleFrom(i))
+		}
+	}
+	return
+}
+
+// cycleFrom returns a shortest import cycle starting and ending at the
+// i'th package, which must be part of an import cycle.
+func (b *batch) cycleFrom(i int) []int {
+	// breadth-first search within the component of i
+	prev := make(map[int]int)
+	queue := []int{i}
+	for len(queue) > 0 {
+		j := queue[0]
+		queue = queue[1:]
+		for _, d := range b.nodes[j].deps {
+			k := d.index
+			if b.nodes[k].comp != b.nodes[i].comp {
+				continue
+			}
+			if k == i {
+				// found the cycle; collect it backwards
+				cycle := []int{j}
+				for j != i {
+					j = prev[j]
+					cycle = append(cycle, j)
+				}
+				for l, r := 0, len(cycle)-1; l < r; l, r = l+1, r-1 {
+					cycle[l], cycle[r] = cycle[r], cycle[l]
+				}
+				return cycle
+			}
+			if _, found := prev[k]; !found {
+				prev[k] = j
+				queue = append(queue, k)
+			}
+		}
+	}
+	unreachable()
+	return nil
+}
+
+// cycleError returns the error for the given import cycle, reported at the
+// import that closes the cycle.
+func (b *batch) cycleError(cycle []int) error {
+	var buf strings.Builder
+	for _, i := range cycle {
+		fmt.Fprintf(&buf, "%s -> ", b.pkgs[i].Path)
+	}
+	first, last := cycle[0], cycle[len(cycle)-1]
+	buf.WriteString(b.pkgs[first].Path)
+
+	var pos syntax.Pos
+	for _, d := range b.nodes[last].deps {
+		if d.index == first {
+			pos = d.pos
+			break
+		}
+	}
+	msg := "import cycle not allowed: " + buf.String()
+	return Error{Pos: pos, Msg: msg, Full: msg, Primary: msg}
+}
+
+// report passes err to conf.Error, if any.
+func (b *batch) report(err error) {
+	if f := b.conf.Error; f != nil {
+		b.errMu.Lock()
+		f(err)
+		b.errMu.Unlock()
+	}
+}
+
+// check type-checks the i'th batch package once its dependencies are
+// done, and marks it as done.
+func (b *batch) check(i int) {
+	n := &b.nodes[i]
+	defer close(n.done)
+
+	typMap := make(map[string]*Named)
+	for _, d := range n.deps {
+		dep := &b.nodes[d.index]
+		if dep.comp == n.comp {
+			continue // don't wait for packages of the same cycle
+		}
+		<-dep.done
+		if dep.pkg == nil {
+			n.cycle = true // depends on an unchecked package
+			continue
+		}
+		for h, inst := range dep.typMap {
+			typMap[h] = inst
+		}
+	}
+	if n.cycle {
+		return
+	}
+
+	b.sem <- struct{}{}
+	defer func() { <-b.sem }()
+
+	conf := *b.conf
+	conf.Importer = &batchImporter{b, b.conf.Importer}
+	if b.conf.Error != nil {
+		conf.Error = b.report
+	}
+
+	p := b.pkgs[i]
+	pkg := NewPackage(p.Path, "")
+	check := NewChecker(&conf, pkg, p.Info)
+	check.typMap = typMap
+	n.err = check.Files(p.Files)
+
+	// Expand all instances before they become visible to other packages,
+	// which may be checked concurrently. Expansion may create further
+	// instances, so repeat until there are no unexpanded ones left.
+	for again := true; again; {
+		again = false
+		for _, inst := range typMap {
+			if inst.instPos != nil {
+				inst.expand(typMap)
+				again = true
+			}
+		}
+	}
+	n.typMap = typMap
+	n.pkg = pkg
+}
+
+// A batchImporter resolves imports of batch packages to the checked
+// packages, and all other imports through the configured importer.
+type batchImporter struct {
+	b   *batch
+	imp Importer // configured importer, or nil
+}
+
+func (imp *batchImporter) Import(path string) (*Package, error) {
+	return imp.ImportFrom(path, "", 0)
+}
+
+func (imp *batchImporter) ImportFrom(path, dir string, mode ImportMode) (*Package, error) {
+	if i, found := imp.b.index[path]; found {
+		// The importing package is only checked once all its dependencies
+		// are done and have been checked.
+		return imp.b.nodes[i].pkg, nil
+	}
+	if imp.imp == nil {
+		return nil, fmt.Errorf("Config.Importer not installed")
+	}
+	imp.b.impMu.Lock()
+	defer imp.b.impMu.Unlock()
+	if from, ok := imp.imp.(ImporterFrom); ok {
+		return from.ImportFrom(path, dir, mode)
+	}
+	return imp.imp.Import(path)
+}
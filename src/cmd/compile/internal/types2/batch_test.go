@@ -0,0 +1,112 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"cmd/compile/internal/syntax"
+	. "cmd/compile/internal/types2"
+)
+
+func batchPkgs(t *testing.T, sources ...string) []*BatchPackage {
+	var pkgs []*BatchPackage
+	for _, src := range sources {
+		f, err := syntax.Parse(syntax.NewFileBase(""), strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkgs = append(pkgs, &BatchPackage{Path: f.PkgName.Value, Files: []*syntax.File{f}})
+	}
+	return pkgs
+}
+
+func TestCheckBatch(t *testing.T) {
+	pkgs := batchPkgs(t,
+		`package d; import ("a"; "b"; "c"; "fmt"); var X a.List[int] = b.V; var _ = c.V; var _ = fmt.Sprint`,
+		`package b; import "a"; var V a.List[int]`,
+		`package c; import "a"; var V a.List[string]; var W = a.Sum(1, 2)`,
+		`package a; type List[E any] struct{ next *List[E]; val E }; func Sum[T interface{ ~int }](x ...T) (s T) { return }`,
+	)
+	pkgs[1].Info = &Info{Types: make(map[syntax.Expr]TypeAndValue)}
+
+	conf := Config{Importer: defaultImporter()}
+	res, err := conf.CheckBatch(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, pkg := range res {
+		if pkg == nil || pkg.Path() != pkgs[i].Path || !pkg.Complete() {
+			t.Fatalf("package %s: got %v", pkgs[i].Path, pkg)
+		}
+	}
+	if len(pkgs[1].Info.Types) == 0 {
+		t.Errorf("package b: no types recorded")
+	}
+
+	// the packages refer to the same imported package objects
+	a := res[3]
+	for _, pkg := range res[:3] {
+		if imp := pkg.Imports()[0]; imp != a {
+			t.Errorf("package %s imports %p; want %p", pkg.Path(), imp, a)
+		}
+	}
+
+	// d re-uses the instance of a.List[int] created when checking b
+	X := res[0].Scope().Lookup("X").Type()
+	V := res[1].Scope().Lookup("V").Type()
+	if X != V {
+		t.Errorf("got different instances %s and %s", X, V)
+	}
+}
+
+func TestCheckBatchCycles(t *testing.T) {
+	pkgs := batchPkgs(t,
+		`package w`,
+		`package z; import "x"`,
+		`package x; import "y"`,
+		`package y; import ("w"; "x")`,
+		`package s; import "s"`,
+		`package v; import _ "w"`,
+	)
+
+	var mu sync.Mutex
+	var errs []string
+	conf := Config{Error: func(err error) {
+		mu.Lock()
+		errs = append(errs, err.Error())
+		mu.Unlock()
+	}}
+	res, err := conf.CheckBatch(pkgs)
+	if err == nil || !strings.Contains(err.Error(), "import cycle not allowed: x -> y -> x") {
+		t.Errorf("got error %v; want import cycle x -> y -> x", err)
+	}
+	want := []string{
+		"import cycle not allowed: x -> y -> x",
+		"import cycle not allowed: s -> s",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors %q; want %d", len(errs), errs, len(want))
+	}
+	for i, e := range errs {
+		if !strings.Contains(e, want[i]) {
+			t.Errorf("error %d: got %s; want %s", i, e, want[i])
+		}
+	}
+
+	// only the packages not depending on a cycle are checked
+	for i, checked := range []bool{true, false, false, false, false, true} {
+		if got := res[i] != nil; got != checked {
+			t.Errorf("package %s: got checked = %v; want %v", pkgs[i].Path, got, checked)
+		}
+	}
+
+	// duplicate paths are rejected
+	if _, err := conf.CheckBatch(append(pkgs[:1:1], pkgs[0])); err == nil || !strings.Contains(err.Error(), "duplicate package path") {
+		t.Errorf("got error %v; want duplicate package path", err)
+	}
+}
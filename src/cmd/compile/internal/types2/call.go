@@ -15,8 +15,8 @@ import (
 // funcInst type-checks a function instantiation inst and returns the result in x.
 // The operand x must be the evaluation of inst.X and its type must be a signature.
 func (check *Checker) funcInst(x *operand, inst *syntax.IndexExpr) {
-	if !check.allowVersion(check.pkg, 1, 18) {
-		check.softErrorf(inst.Pos(), "function instantiation requires go1.18 or later")
+	if !check.allowVersion(check.pkg, inst, 1, 18) {
+		check.softErrorf(inst.Pos(), _Todo, "function instantiation requires go1.18 or later")
 	}
 
 	xlist := unpackExpr(inst.Index)
@@ -32,7 +32,7 @@ func (check *Checker) funcInst(x *operand, inst *syntax.IndexExpr) {
 	sig := x.typ.(*Signature)
 	got, want := len(targs), sig.TParams().Len()
 	if !useConstraintTypeInference && got != want || got > want {
-		check.errorf(xlist[got-1], "got %d type arguments but want %d", got, want)
+		check.errorf(xlist[got-1], _Todo, "got %d type arguments but want %d", got, want)
 		x.mode = invalid
 		x.expr = inst
 		return
@@ -103,25 +103,28 @@ func (check *Checker) callExpr(x *operand, call *syntax.CallExpr) exprKind {
 		x.mode = invalid
 		switch n := len(call.ArgList); n {
 		case 0:
-			check.errorf(call, "missing argument in conversion to %s", T)
+			check.errorf(call, _WrongArgCount, "missing argument in conversion to %s", T)
 		case 1:
 			check.expr(x, call.ArgList[0])
 			if x.mode != invalid {
 				if t := asInterface(T); t != nil {
 					if t.IsConstraint() {
-						check.errorf(call, "cannot use interface %s in conversion (contains type list or is comparable)", T)
+						check.errorf(call, _Todo, "cannot use interface %s in conversion (contains type list or is comparable)", T)
 						break
 					}
 				}
 				if call.HasDots {
-					check.errorf(call.ArgList[0], "invalid use of ... in type conversion to %s", T)
+					check.errorf(call.ArgList[0], _BadDotDotDotSyntax, "invalid use of ... in type conversion to %s", T)
 					break
 				}
 				check.conversion(x, T)
+				if x.mode == constant_ {
+					check.copyTargetValues(call.ArgList[0], call)
+				}
 			}
 		default:
 			check.use(call.ArgList...)
-			check.errorf(call.ArgList[n-1], "too many arguments in conversion to %s", T)
+			check.errorf(call.ArgList[n-1], _WrongArgCount, "too many arguments in conversion to %s", T)
 		}
 		x.expr = call
 		return conversion
@@ -146,7 +149,9 @@ func (check *Checker) callExpr(x *operand, call *syntax.CallExpr) exprKind {
 
 	sig := asSignature(x.typ)
 	if sig == nil {
-		check.errorf(x, invalidOp+"cannot call non-function %s", x)
+		if !isUnknown(x.typ) {
+			check.errorf(x, _InvalidCall, invalidOp+"cannot call non-function %s", x)
+		}
 		x.mode = invalid
 		x.expr = call
 		return statement
@@ -168,7 +173,7 @@ func (check *Checker) callExpr(x *operand, call *syntax.CallExpr) exprKind {
 		// check number of type arguments (got) vs number of type parameters (want)
 		got, want := len(targs), sig.TParams().Len()
 		if got > want {
-			check.errorf(xlist[want], "got %d type arguments but want %d", got, want)
+			check.errorf(xlist[want], _Todo, "got %d type arguments but want %d", got, want)
 			check.use(call.ArgList...)
 			x.mode = invalid
 			x.expr = call
@@ -254,7 +259,7 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 	for _, a := range args {
 		switch a.mode {
 		case typexpr:
-			check.errorf(a, "%s used as value", a)
+			check.errorf(a, _NotAnExpr, "%s used as value", a)
 			return
 		case invalid:
 			return
@@ -282,8 +287,8 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 			// variadic_func(a, b, c...)
 			if len(call.ArgList) == 1 && nargs > 1 {
 				// f()... is not permitted if f() is multi-valued
-				//check.errorf(call.Ellipsis, "cannot use ... with %d-valued %s", nargs, call.ArgList[0])
-				check.errorf(call, "cannot use ... with %d-valued %s", nargs, call.ArgList[0])
+				//check.errorf(call.Ellipsis, _InvalidDotDotDot, "cannot use ... with %d-valued %s", nargs, call.ArgList[0])
+				check.errorf(call, _InvalidDotDotDot, "cannot use ... with %d-valued %s", nargs, call.ArgList[0])
 				return
 			}
 		} else {
@@ -310,8 +315,8 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 	} else {
 		if ddd {
 			// standard_func(a, b, c...)
-			//check.errorf(call.Ellipsis, "cannot use ... in call to non-variadic %s", call.Fun)
-			check.errorf(call, "cannot use ... in call to non-variadic %s", call.Fun)
+			//check.errorf(call.Ellipsis, _NonVariadicDotDotDot, "cannot use ... in call to non-variadic %s", call.Fun)
+			check.errorf(call, _NonVariadicDotDotDot, "cannot use ... in call to non-variadic %s", call.Fun)
 			return
 		}
 		// standard_func(a, b, c)
@@ -320,20 +325,20 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 	// check argument count
 	switch {
 	case nargs < npars:
-		check.errorf(call, "not enough arguments in call to %s", call.Fun)
+		check.errorf(call, _WrongArgCount, "not enough arguments in call to %s", call.Fun)
 		return
 	case nargs > npars:
-		check.errorf(args[npars], "too many arguments in call to %s", call.Fun) // report at first extra argument
+		check.errorf(args[npars], _WrongArgCount, "too many arguments in call to %s", call.Fun) // report at first extra argument
 		return
 	}
 
 	// infer type arguments and instantiate signature if necessary
 	if sig.TParams().Len() > 0 {
-		if !check.allowVersion(check.pkg, 1, 18) {
+		if !check.allowVersion(check.pkg, call, 1, 18) {
 			if iexpr, _ := call.Fun.(*syntax.IndexExpr); iexpr != nil {
-				check.softErrorf(iexpr.Pos(), "function instantiation requires go1.18 or later")
+				check.softErrorf(iexpr.Pos(), _Todo, "function instantiation requires go1.18 or later")
 			} else {
-				check.softErrorf(call.Pos(), "implicit function instantiation requires go1.18 or later")
+				check.softErrorf(call.Pos(), _Todo, "implicit function instantiation requires go1.18 or later")
 			}
 		}
 		// TODO(gri) provide position information for targs so we can feed
@@ -398,7 +403,7 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 		if pname, _ := obj.(*PkgName); pname != nil {
 			assert(pname.pkg == check.pkg)
 			check.recordUse(ident, pname)
-			pname.used = true
+			check.usePkgName(pname)
 			pkg := pname.imported
 
 			var exp Object
@@ -421,7 +426,7 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 					}
 				}
 				if exp == nil {
-					check.errorf(e.Sel, "%s not declared by package C", sel)
+					check.errorf(e.Sel, _UndeclaredImportedName, "%s not declared by package C", sel)
 					goto Error
 				}
 				check.objDecl(exp, nil)
@@ -430,15 +435,15 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 				if exp == nil {
 					if !pkg.fake {
 						if check.conf.CompilerErrorMessages {
-							check.errorf(e.Sel, "undefined: %s.%s", pkg.name, sel)
+							check.errorf(e.Sel, _UndeclaredImportedName, "undefined: %s.%s", pkg.name, sel)
 						} else {
-							check.errorf(e.Sel, "%s not declared by package %s", sel, pkg.name)
+							check.errorf(e.Sel, _UndeclaredImportedName, "%s not declared by package %s", sel, pkg.name)
 						}
 					}
 					goto Error
 				}
 				if !exp.Exported() {
-					check.errorf(e.Sel, "%s not exported by package %s", sel, pkg.name)
+					check.errorf(e.Sel, _UnexportedName, "%s not exported by package %s", sel, pkg.name)
 					// ok to continue
 				}
 			}
@@ -453,6 +458,10 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 				x.typ = exp.typ
 				x.val = exp.val
 			case *TypeName:
+				if exp.tparams != nil {
+					check.errorf(e.Sel, _Todo, "cannot use generic type %s.%s without instantiation", pkg.name, sel)
+					goto Error
+				}
 				x.mode = typexpr
 				x.typ = exp.typ
 			case *Var:
@@ -486,14 +495,14 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 		goto Error
 	}
 
-	obj, index, indirect = LookupFieldOrMethod(x.typ, x.mode == variable, check.pkg, sel)
+	obj, index, indirect = lookupSelection(x.typ, x.mode == variable, check.pkg, sel)
 	if obj == nil {
 		switch {
 		case index != nil:
 			// TODO(gri) should provide actual type where the conflict happens
-			check.errorf(e.Sel, "ambiguous selector %s.%s", x.expr, sel)
+			check.errorf(e.Sel, _AmbiguousSelector, "ambiguous selector %s.%s", x.expr, sel)
 		case indirect:
-			check.errorf(e.Sel, "cannot call pointer method %s on %s", sel, x.typ)
+			check.errorf(e.Sel, _InvalidMethodExpr, "cannot call pointer method %s on %s", sel, x.typ)
 		default:
 			var why string
 			if tpar := asTypeParam(x.typ); tpar != nil {
@@ -515,12 +524,12 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 				} else {
 					changeCase = string(unicode.ToUpper(r)) + sel[1:]
 				}
-				if obj, _, _ = LookupFieldOrMethod(x.typ, x.mode == variable, check.pkg, changeCase); obj != nil {
+				if obj, _, _ = lookupSelection(x.typ, x.mode == variable, check.pkg, changeCase); obj != nil {
 					why += ", but does have " + changeCase
 				}
 			}
 
-			check.errorf(e.Sel, "%s.%s undefined (%s)", x.expr, sel, why)
+			check.errorf(e.Sel, _MissingFieldOrMethod, "%s.%s undefined (%s)", x.expr, sel, why)
 
 		}
 		goto Error
@@ -575,6 +584,7 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 			// TODO(gri) investigate and provide a correct explanation here
 			copy := *m
 			copy.typ = check.subst(e.Pos(), m.typ, makeSubstMap(sig.RParams().list(), targs), nil)
+			copy.origin = m.Origin()
 			obj = &copy
 		}
 		// TODO(gri) we also need to do substitution for parameterized interface methods
@@ -587,15 +597,17 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 		m, _ := obj.(*Func)
 		if m == nil {
 			// TODO(gri) should check if capitalization of sel matters and provide better error message in that case
-			check.errorf(e.Sel, "%s.%s undefined (type %s has no method %s)", x.expr, sel, x.typ, sel)
+			check.errorf(e.Sel, _MissingFieldOrMethod, "%s.%s undefined (type %s has no method %s)", x.expr, sel, x.typ, sel)
 			goto Error
 		}
 
 		check.recordSelection(e, MethodExpr, x.typ, m, index, indirect)
+		check.addDeclUse(m)
+		check.recordImplicitOp(e, selectorOps(x.typ, m, index))
 
 		sig := m.typ.(*Signature)
 		if sig.recv == nil {
-			check.error(e, "illegal cycle in method declaration")
+			check.error(e, _InvalidDeclCycle, "illegal cycle in method declaration")
 			goto Error
 		}
 
@@ -635,6 +647,9 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 		switch obj := obj.(type) {
 		case *Var:
 			check.recordSelection(e, FieldVal, x.typ, obj, index, indirect)
+			if indirect {
+				check.recordImplicitOp(e, ImplicitDeref)
+			}
 			if x.mode == variable || indirect {
 				x.mode = variable
 			} else {
@@ -646,6 +661,8 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 			// TODO(gri) If we needed to take into account the receiver's
 			// addressability, should we report the type &(x.typ) instead?
 			check.recordSelection(e, MethodVal, x.typ, obj, index, indirect)
+			check.addDeclUse(obj)
+			check.recordImplicitOp(e, selectorOps(x.typ, obj, index))
 
 			x.mode = value
 
@@ -670,6 +687,36 @@ Error:
 	x.expr = e
 }
 
+// selectorOps returns the implicit operations applied when selecting
+// method m of a value of type T via the embedding path index.
+func selectorOps(T Type, m *Func, index []int) (op ImplicitOp) {
+	recv := T
+	for i := 0; i < len(index)-1; i++ {
+		// The embedded type is either a struct or a pointer to
+		// a struct except for the last one (see also selector).
+		if p := asPointer(recv); p != nil {
+			op |= ImplicitDeref
+			recv = p.base
+		}
+		recv = asStruct(recv).Field(index[i]).typ
+	}
+	sig, _ := m.typ.(*Signature)
+	if sig == nil || sig.recv == nil {
+		return // invalid method
+	}
+	switch under(recv).(type) {
+	case *Interface, *TypeParam:
+		return // interface methods don't have (explicit) receivers
+	}
+	switch ptrRecv := isPointer(sig.recv.typ); {
+	case ptrRecv && !isPointer(recv):
+		op |= ImplicitAddr
+	case !ptrRecv && isPointer(recv):
+		op |= ImplicitDeref
+	}
+	return
+}
+
 // use type-checks each argument.
 // Useful to make sure expressions are evaluated
 // (and variables are "used") in the presence of other errors.
@@ -708,10 +755,9 @@ func (check *Checker) useLHS(arg ...syntax.Expr) {
 				continue
 			}
 			if _, obj := check.scope.LookupParent(ident.Value, nopos); obj != nil {
-				// It's ok to mark non-local variables, but ignore variables
-				// from other packages to avoid potential race conditions with
-				// dot-imported variables.
-				if w, _ := obj.(*Var); w != nil && w.pkg == check.pkg {
+				// Ignore package-level variables and variables from other
+				// packages, which are not marked (see Checker.ident).
+				if w, _ := obj.(*Var); w != nil && w.pkg == check.pkg && w.parent != check.pkg.scope {
 					v = w
 					v_used = v.used
 				}
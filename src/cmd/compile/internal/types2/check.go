@@ -11,6 +11,8 @@ import (
 	"errors"
 	"fmt"
 	"go/constant"
+	"strings"
+	"time"
 )
 
 var nopos syntax.Pos
@@ -42,6 +44,7 @@ type exprInfo struct {
 // A context represents the context within which an object is type-checked.
 type context struct {
 	decl          *declInfo                 // package-level declaration whose init expression/function body is checked
+	user          *declInfo                 // package-level declaration whose uses are recorded (see Info.Dependencies)
 	scope         *Scope                    // top-most scope for lookups
 	pos           syntax.Pos                // if valid, identifiers are looked up as if at position pos (used by Eval)
 	iota          constant.Value            // value of iota in a constant declaration; nil otherwise
@@ -53,11 +56,24 @@ type context struct {
 }
 
 // lookup looks up name in the current context and returns the matching object, or nil.
-func (ctxt *context) lookup(name string) Object {
-	_, obj := ctxt.scope.LookupParent(name, ctxt.pos)
+func (check *Checker) lookup(name string) Object {
+	_, obj := check.lookupParent(name, check.pos)
 	return obj
 }
 
+// lookupParent is like check.scope.LookupParent, but also considers the
+// objects of Config.Predeclared, which take precedence over the objects
+// of the Universe scope.
+func (check *Checker) lookupParent(name string, pos syntax.Pos) (*Scope, Object) {
+	scope, obj := check.scope.LookupParent(name, pos)
+	if (obj == nil || scope == Universe) && check.predecl != nil {
+		if alt := check.predecl.Lookup(name); alt != nil {
+			return check.predecl, alt
+		}
+	}
+	return scope, obj
+}
+
 // An importKey identifies an imported package by import path and source directory
 // (directory containing the file containing the import). In practice, the directory
 // may always be the same, or may not matter. Given an (import path, directory), an
@@ -87,6 +103,18 @@ type Checker struct {
 	objMap  map[Object]*declInfo   // maps package-level objects and (non-interface) methods to declaration info
 	impMap  map[importKey]*Package // maps (import path, source directory) to (complete or fake) package
 	typMap  map[string]*Named      // maps an instantiated named type hash to a *Named type
+	predecl *Scope                 // scope of Config.Predeclared objects, or nil
+
+	// accepted language versions of files which are later than version,
+	// keyed by the files' position bases (see Checker.recordFileVersion)
+	fileVersions map[*syntax.PosBase]version
+
+	// configurations for Config.Targets, with the respective sizes, and
+	// values of size-dependent constant expressions for the targets
+	// (nil if there are no targets)
+	targets      []*Config
+	targetVals   map[syntax.Expr][]constant.Value
+	targetConsts map[*Const][]constant.Value
 
 	// pkgPathMap maps package names to the set of distinct import paths we've
 	// seen for that name, anywhere in the import graph. It is used for
@@ -98,6 +126,11 @@ type Checker struct {
 	pkgPathMap map[string]map[string]bool
 	seenPkgMap map[*Package]bool
 
+	// files checked so far, in order, and the declarations of those
+	// files to collect again (set only by Checker.ReplaceFile)
+	checked []*checkedFile
+	redecl  *redeclSet
+
 	// information collected during type-checking of a set of package files
 	// (initialized by Files, valid only for the duration of check.Files;
 	// maps and lists are allocated on demand)
@@ -110,13 +143,65 @@ type Checker struct {
 	untyped  map[syntax.Expr]exprInfo // map of expressions without final type
 	delayed  []func()                 // stack of delayed action segments; segments are processed in FIFO order
 	objPath  []Object                 // path of object dependencies during type inference (for cycle reporting)
+	names    map[*syntax.Name]Object  // objects of defined and used identifiers (for Config.CompleteTypes)
+	ifaces   []*Interface             // interfaces created, whose type sets may not have been computed yet
+	nameds   []*Named                 // named types created, which may not have been set up completely yet
+	bodies   []*funcBody              // function bodies to check concurrently (see Config.ConcurrentFuncBodies)
+	worker   *bodyWorker              // set if check is checking function bodies concurrently with other Checkers
+	pending  []pendingError           // errors to be reported sorted by position (see Config.SortErrors)
+	nerrors  int                      // number of errors handled which are not tolerated (for Config.MaxErrors)
+
+	// type sets computed before the methods of all package-level types
+	// were known (see Checker.dropMethodTerms)
+	methodsKnown bool
+	early        []*TypeSet
+
+	// structured tracing (for Config.TraceWriter)
+	traceStart time.Time // time of the first trace event
+	traceDepth int       // number of steps entered but not exited
+
+	identCache map[typePair]bool // results of type identity tests (see Checker.identical)
+
+	// type parameters declared and used (for Config.ReportUnused)
+	declTParams []*TypeParam
+	usedTParams map[*TypeParam]bool
 
 	// context within which the current object is type-checked
 	// (valid only for the duration of type-checking a specific object)
 	context
 
 	// debugging
-	indent int // indentation for tracing
+	indent   int      // indentation for tracing
+	phase    string   // current phase (for Config.Phase), or ""
+	counters Counters // work done so far
+}
+
+// Counters records the amount of work done by a Checker.
+type Counters struct {
+	Instances  int // type and function instances created
+	Interfaces int // interface type sets computed
+	Unions     int // union type sets computed
+	Delayed    int // delayed actions executed
+}
+
+// Counters returns the amount of work done by check so far, for all
+// the files checked with it. Work done outside of type-checking, such as
+// by calls of Instantiate without a Checker, is not counted.
+func (check *Checker) Counters() Counters { return check.counters }
+
+// startPhase records the start of the named phase and reports
+// it via Config.Phase, if set.
+func (check *Checker) startPhase(phase string) {
+	if check.conf.Trace {
+		fmt.Printf("== %s ==\n", phase)
+	}
+	check.phase = phase
+	if check.conf.TraceWriter != nil && phase != "" {
+		check.traceEvent("phase", phase, nopos, "")
+	}
+	if check.conf.Phase != nil {
+		check.conf.Phase(phase)
+	}
 }
 
 // addDeclDep adds the dependency edge (check.decl -> to) if check.decl exists
@@ -131,6 +216,27 @@ func (check *Checker) addDeclDep(to Object) {
 	from.addDep(to)
 }
 
+// addDeclUse records that the package-level declaration being checked
+// refers to the object to, if Info.Dependencies is set.
+func (check *Checker) addDeclUse(to Object) {
+	from := check.user
+	if from == nil || check.Dependencies == nil {
+		return
+	}
+	if f, _ := to.(*Func); f != nil {
+		to = f.Origin()
+	}
+	if _, found := check.objMap[to]; !found {
+		return // to is not a package-level object
+	}
+	m := from.uses
+	if m == nil {
+		m = make(map[Object]bool)
+		from.uses = m
+	}
+	m[to] = true
+}
+
 func (check *Checker) rememberUntyped(e syntax.Expr, lhs bool, mode operandMode, typ *Basic, val constant.Value) {
 	m := check.untyped
 	if m == nil {
@@ -146,6 +252,9 @@ func (check *Checker) rememberUntyped(e syntax.Expr, lhs bool, mode operandMode,
 // (so that f still sees the scope before any new declarations).
 func (check *Checker) later(f func()) {
 	check.delayed = append(check.delayed, f)
+	if check.conf.TraceWriter != nil {
+		check.traceEvent("later", "", nopos, check.sprintf("%d delayed actions", len(check.delayed)))
+	}
 }
 
 // push pushes obj onto the object path and returns its index in the path.
@@ -176,12 +285,30 @@ func NewChecker(conf *Config, pkg *Package, info *Info) *Checker {
 		info = new(Info)
 	}
 
-	version, err := parseGoVersion(conf.GoVersion)
+	goVersion := conf.GoVersion
+	if conf.ModuleGoVersion != nil && pkg != nil {
+		if v := conf.ModuleGoVersion(pkg.path); v != "" {
+			goVersion = v
+			if !strings.HasPrefix(v, "go") {
+				goVersion = "go" + v // go.mod format
+			}
+		}
+	}
+	version, err := parseGoVersion(goVersion)
 	if err != nil {
-		panic(fmt.Sprintf("invalid Go version %q (%v)", conf.GoVersion, err))
+		panic(fmt.Sprintf("invalid Go version %q (%v)", goVersion, err))
 	}
 
-	return &Checker{
+	var predecl *Scope
+	if len(conf.Predeclared) > 0 {
+		// NewScope doesn't add children to the Universe scope.
+		predecl = NewScope(Universe, nopos, nopos, "predeclared")
+		for _, obj := range conf.Predeclared {
+			predecl.Insert(obj)
+		}
+	}
+
+	check := &Checker{
 		conf:    conf,
 		pkg:     pkg,
 		Info:    info,
@@ -189,7 +316,20 @@ func NewChecker(conf *Config, pkg *Package, info *Info) *Checker {
 		objMap:  make(map[Object]*declInfo),
 		impMap:  make(map[importKey]*Package),
 		typMap:  make(map[string]*Named),
+		predecl: predecl,
+	}
+
+	if len(conf.Targets) > 0 {
+		for _, t := range conf.Targets {
+			tconf := *conf
+			tconf.Sizes = t.Sizes
+			check.targets = append(check.targets, &tconf)
+		}
+		check.targetVals = make(map[syntax.Expr][]constant.Value)
+		check.targetConsts = make(map[*Const][]constant.Value)
 	}
+
+	return check
 }
 
 // initFiles initializes the files-specific portion of checker.
@@ -204,6 +344,11 @@ func (check *Checker) initFiles(files []*syntax.File) {
 	check.methods = nil
 	check.untyped = nil
 	check.delayed = nil
+	check.names = nil
+	check.bodies = nil
+	check.pending = nil
+	check.nerrors = 0
+	check.methodsKnown = false
 
 	// determine package name and collect valid files
 	pkg := check.pkg
@@ -213,15 +358,16 @@ func (check *Checker) initFiles(files []*syntax.File) {
 			if name != "_" {
 				pkg.name = name
 			} else {
-				check.error(file.PkgName, "invalid package name _")
+				check.error(file.PkgName, _BlankPkgName, "invalid package name _")
 			}
 			fallthrough
 
 		case name:
 			check.files = append(check.files, file)
+			check.recordFileVersion(file)
 
 		default:
-			check.errorf(file, "package %s; expected %s", name, pkg.name)
+			check.errorf(file, _MismatchedPkgName, "package %s; expected %s", name, pkg.name)
 			// ignore this file
 		}
 	}
@@ -252,36 +398,64 @@ func (check *Checker) checkFiles(files []*syntax.File) (err error) {
 	}
 
 	defer check.handleBailout(&err)
-
-	print := func(msg string) {
-		if check.conf.Trace {
-			fmt.Println(msg)
+	defer func() {
+		check.phase = ""
+		check.releaseTypes()
+		if check.pending != nil {
+			check.reportPending()
 		}
-	}
+	}()
 
-	print("== initFiles ==")
+	check.startPhase("initFiles")
 	check.initFiles(files)
 
-	print("== collectObjects ==")
+	check.startPhase("collectObjects")
 	check.collectObjects()
 
-	print("== packageObjects ==")
+	check.checkObjects()
+
+	// TODO(gri) There's more memory we should release at this point.
+
+	return
+}
+
+// checkObjects checks the collected package objects and the function
+// bodies, and runs the remaining phases shared by Files and ReplaceFile.
+func (check *Checker) checkObjects() {
+	check.startPhase("packageObjects")
 	check.packageObjects()
+	check.dropMethodTerms()
+
+	check.startPhase("processDelayed")
+	check.processDelayed(0) // incl. all functions, unless checked concurrently
 
-	print("== processDelayed ==")
-	check.processDelayed(0) // incl. all functions
+	if check.bodies != nil {
+		check.startPhase("funcBodies")
+		check.checkFuncBodies()
+	}
+
+	if check.conf.ReportUnused {
+		check.startPhase("unusedTParams")
+		check.unusedTParams()
+	}
 
-	print("== initOrder ==")
+	check.startPhase("initOrder")
 	check.initOrder()
+	check.recordDependencies()
 
 	if !check.conf.DisableUnusedImportCheck {
-		print("== unusedImports ==")
+		check.startPhase("unusedImports")
 		check.unusedImports()
 	}
 
-	print("== recordUntyped ==")
+	check.startPhase("recordUntyped")
 	check.recordUntyped()
 
+	if check.conf.CompleteTypes {
+		check.startPhase("completeTypes")
+		check.completeTypes()
+	}
+
 	check.pkg.complete = true
 
 	// no longer needed - release memory
@@ -289,10 +463,23 @@ func (check *Checker) checkFiles(files []*syntax.File) (err error) {
 	check.dotImportMap = nil
 	check.pkgPathMap = nil
 	check.seenPkgMap = nil
+}
 
-	// TODO(gri) There's more memory we should release at this point.
-
-	return
+// releaseTypes detaches the interfaces and named types created by check
+// from check once type-checking is done, even if it was stopped early.
+// The type sets of interfaces and the underlying types of instances that
+// are not computed yet are computed later, if needed, without the checker
+// (see lazyTypeSet and Named.expand). Thus types may be used concurrently
+// after type-checking.
+func (check *Checker) releaseTypes() {
+	for _, ityp := range check.ifaces {
+		ityp.check = nil
+	}
+	for _, n := range check.nameds {
+		n.check = nil
+	}
+	check.ifaces = nil
+	check.nameds = nil
 }
 
 // processDelayed processes all delayed actions pushed after top.
@@ -304,7 +491,14 @@ func (check *Checker) processDelayed(top int) {
 	// add more actions (such as nested functions), so
 	// this is a sufficiently bounded process.
 	for i := top; i < len(check.delayed); i++ {
-		check.delayed[i]() // may append to check.delayed
+		if check.conf.TraceWriter != nil {
+			s := check.traceEnter(nopos, "delayed", "action %d", i)
+			check.delayed[i]() // may append to check.delayed
+			check.traceExit(s, "")
+		} else {
+			check.delayed[i]() // may append to check.delayed
+		}
+		check.counters.Delayed++
 	}
 	assert(top <= len(check.delayed)) // stack must not have shrunk
 	check.delayed = check.delayed[:top]
@@ -366,6 +560,9 @@ func (check *Checker) recordTypeAndValue(x syntax.Expr, mode operandMode, typ Ty
 	if m := check.Types; m != nil {
 		m[x] = TypeAndValue{mode, typ, val}
 	}
+	if mode == constant_ {
+		check.recordTargetValues(x, val)
+	}
 }
 
 func (check *Checker) recordBuiltinType(f syntax.Expr, sig *Signature) {
@@ -425,6 +622,7 @@ func (check *Checker) recordDef(id *syntax.Name, obj Object) {
 	if m := check.Defs; m != nil {
 		m[id] = obj
 	}
+	check.recordName(id, obj)
 }
 
 func (check *Checker) recordUse(id *syntax.Name, obj Object) {
@@ -433,6 +631,23 @@ func (check *Checker) recordUse(id *syntax.Name, obj Object) {
 	if m := check.Uses; m != nil {
 		m[id] = obj
 	}
+	if m := check.Deprecated; m != nil {
+		if obj.Deprecated() != "" {
+			m[id] = obj
+		}
+	}
+	check.recordName(id, obj)
+}
+
+// recordName remembers the object obj of identifier id for
+// Config.CompleteTypes, which cannot rely on the Defs and Uses maps.
+func (check *Checker) recordName(id *syntax.Name, obj Object) {
+	if check.conf.CompleteTypes && obj != nil {
+		if check.names == nil {
+			check.names = make(map[*syntax.Name]Object)
+		}
+		check.names[id] = obj
+	}
 }
 
 func (check *Checker) recordImplicit(node syntax.Node, obj Object) {
@@ -451,6 +666,47 @@ func (check *Checker) recordSelection(x *syntax.SelectorExpr, kind SelectionKind
 	}
 }
 
+func (check *Checker) recordConversion(x syntax.Expr, from, to Type) {
+	assert(x != nil)
+	assert(from != nil && to != nil)
+	if m := check.Conversions; m != nil {
+		m[x] = Conversion{from, to}
+	}
+}
+
+func (check *Checker) recordImplicitOp(x syntax.Expr, op ImplicitOp) {
+	assert(x != nil)
+	if op == 0 {
+		return
+	}
+	if m := check.ImplicitOps; m != nil {
+		m[x] = op
+	}
+}
+
+func (check *Checker) recordImplicitInterface(x syntax.Expr, ityp *Interface) {
+	assert(x != nil)
+	assert(ityp != nil)
+	if m := check.ImplicitInterfaces; m != nil {
+		m[x] = ityp
+	}
+}
+
+func (check *Checker) recordNonNil(x syntax.Expr) {
+	assert(x != nil)
+	if m := check.NonNil; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordDeclaredType(node syntax.Node, typ Type) {
+	assert(node != nil)
+	assert(typ != nil)
+	if m := check.DeclaredTypes; m != nil {
+		m[node] = typ
+	}
+}
+
 func (check *Checker) recordScope(node syntax.Node, scope *Scope) {
 	assert(node != nil)
 	assert(scope != nil)
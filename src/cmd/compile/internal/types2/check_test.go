@@ -2,34 +2,18 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// This file implements a typechecker test harness. The packages specified
-// in tests are typechecked. Error messages reported by the typechecker are
-// compared against the error messages expected in the test files.
-//
-// Expected errors are indicated in the test files by putting a comment
-// of the form /* ERROR "rx" */ immediately following an offending token.
-// The harness will verify that an error matching the regular expression
-// rx is reported at that source position. Consecutive comments may be
-// used to indicate multiple errors for the same token position.
-//
-// For instance, the following test file indicates that a "not declared"
-// error should be reported for the undeclared variable x:
-//
-//	package p
-//	func f() {
-//		_ = x /* ERROR "not declared" */ + 1
-//	}
+// This file runs the typechecker tests in testdata using the harness
+// implemented by package checktest; see there for the format of the
+// test files.
 
 package types2_test
 
 import (
-	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2/checktest"
 	"flag"
 	"internal/testenv"
 	"os"
 	"path/filepath"
-	"regexp"
-	"sort"
 	"strings"
 	"testing"
 
@@ -42,199 +26,23 @@ var (
 	goVersion    = flag.String("lang", "", "Go language version (e.g. \"go1.12\")")
 )
 
-func parseFiles(t *testing.T, filenames []string, mode syntax.Mode) ([]*syntax.File, []error) {
-	var files []*syntax.File
-	var errlist []error
-	errh := func(err error) { errlist = append(errlist, err) }
-	for _, filename := range filenames {
-		file, err := syntax.ParseFile(filename, errh, nil, mode)
-		if file == nil {
-			t.Fatalf("%s: %s", filename, err)
-		}
-		files = append(files, file)
-	}
-	return files, errlist
-}
-
-func unpackError(err error) syntax.Error {
-	switch err := err.(type) {
-	case syntax.Error:
-		return err
-	case Error:
-		return syntax.Error{Pos: err.Pos, Msg: err.Msg}
-	default:
-		return syntax.Error{Msg: err.Error()}
-	}
-}
-
-// delta returns the absolute difference between x and y.
-func delta(x, y uint) uint {
-	switch {
-	case x < y:
-		return y - x
-	case x > y:
-		return x - y
-	default:
-		return 0
-	}
-}
-
-// goVersionRx matches a Go version string using '_', e.g. "go1_12".
-var goVersionRx = regexp.MustCompile(`^go[1-9][0-9]*_(0|[1-9][0-9]*)$`)
-
-// asGoVersion returns a regular Go language version string
-// if s is a Go version string using '_' rather than '.' to
-// separate the major and minor version numbers (e.g. "go1_12").
-// Otherwise it returns the empty string.
-func asGoVersion(s string) string {
-	if goVersionRx.MatchString(s) {
-		return strings.Replace(s, "_", ".", 1)
+func testOptions(colDelta uint, manual bool) checktest.Options {
+	return checktest.Options{
+		Lang:     *goVersion,
+		ColDelta: colDelta,
+		List:     manual && !*verifyErrors,
+		Halt:     *haltOnError,
+		Trace:    manual && testing.Verbose(),
+		Importer: defaultImporter(),
 	}
-	return ""
 }
 
 func testFiles(t *testing.T, filenames []string, colDelta uint, manual bool) {
-	if len(filenames) == 0 {
-		t.Fatal("no source files")
-	}
-
-	var mode syntax.Mode
-	if strings.HasSuffix(filenames[0], ".go2") {
-		mode |= syntax.AllowGenerics | syntax.AllowTypeLists
-	}
-	// parse files and collect parser errors
-	files, errlist := parseFiles(t, filenames, mode)
-
-	pkgName := "<no package>"
-	if len(files) > 0 {
-		pkgName = files[0].PkgName.Value
-	}
-
-	// if no Go version is given, consider the package name
-	goVersion := *goVersion
-	if goVersion == "" {
-		goVersion = asGoVersion(pkgName)
-	}
-
-	listErrors := manual && !*verifyErrors
-	if listErrors && len(errlist) > 0 {
-		t.Errorf("--- %s:", pkgName)
-		for _, err := range errlist {
-			t.Error(err)
-		}
-	}
-
-	// typecheck and collect typechecker errors
-	var conf Config
-	conf.GoVersion = goVersion
-	// special case for importC.src
-	if len(filenames) == 1 && strings.HasSuffix(filenames[0], "importC.src") {
-		conf.FakeImportC = true
-	}
-	conf.Trace = manual && testing.Verbose()
-	conf.Importer = defaultImporter()
-	conf.Error = func(err error) {
-		if *haltOnError {
-			defer panic(err)
-		}
-		if listErrors {
-			t.Error(err)
-			return
-		}
-		errlist = append(errlist, err)
-	}
-	conf.Check(pkgName, files, nil)
-
-	if listErrors {
-		return
-	}
-
-	// sort errlist in source order
-	sort.Slice(errlist, func(i, j int) bool {
-		pi := unpackError(errlist[i]).Pos
-		pj := unpackError(errlist[j]).Pos
-		return pi.Cmp(pj) < 0
-	})
-
-	// collect expected errors
-	errmap := make(map[string]map[uint][]syntax.Error)
-	for _, filename := range filenames {
-		f, err := os.Open(filename)
-		if err != nil {
-			t.Error(err)
-			continue
-		}
-		if m := syntax.ErrorMap(f); len(m) > 0 {
-			errmap[filename] = m
-		}
-		f.Close()
-	}
-
-	// match against found errors
-	for _, err := range errlist {
-		got := unpackError(err)
-
-		// find list of errors for the respective error line
-		filename := got.Pos.Base().Filename()
-		filemap := errmap[filename]
-		line := got.Pos.Line()
-		var list []syntax.Error
-		if filemap != nil {
-			list = filemap[line]
-		}
-		// list may be nil
-
-		// one of errors in list should match the current error
-		index := -1 // list index of matching message, if any
-		for i, want := range list {
-			rx, err := regexp.Compile(want.Msg)
-			if err != nil {
-				t.Errorf("%s:%d:%d: %v", filename, line, want.Pos.Col(), err)
-				continue
-			}
-			if rx.MatchString(got.Msg) {
-				index = i
-				break
-			}
-		}
-		if index < 0 {
-			t.Errorf("%s: no error expected: %q", got.Pos, got.Msg)
-			continue
-		}
-
-		// column position must be within expected colDelta
-		want := list[index]
-		if delta(got.Pos.Col(), want.Pos.Col()) > colDelta {
-			t.Errorf("%s: got col = %d; want %d", got.Pos, got.Pos.Col(), want.Pos.Col())
-		}
-
-		// eliminate from list
-		if n := len(list) - 1; n > 0 {
-			// not the last entry - slide entries down (don't reorder)
-			copy(list[index:], list[index+1:])
-			filemap[line] = list[:n]
-		} else {
-			// last entry - remove list from filemap
-			delete(filemap, line)
-		}
-
-		// if filemap is empty, eliminate from errmap
-		if len(filemap) == 0 {
-			delete(errmap, filename)
-		}
-	}
+	checktest.Run(t, filenames, testOptions(colDelta, manual))
+}
 
-	// there should be no expected errors left
-	if len(errmap) > 0 {
-		t.Errorf("--- %s: unreported errors:", pkgName)
-		for filename, filemap := range errmap {
-			for line, list := range filemap {
-				for _, err := range list {
-					t.Errorf("%s:%d:%d: %s", filename, line, err.Pos.Col(), err.Msg)
-				}
-			}
-		}
-	}
+func testArchive(t *testing.T, filename string, colDelta uint, manual bool) {
+	checktest.RunArchive(t, filename, testOptions(colDelta, manual))
 }
 
 // TestManual is for manual testing of a package - either provided
@@ -245,12 +53,15 @@ func testFiles(t *testing.T, filenames []string, colDelta uint, manual bool) {
 //
 // 	go test -run Manual -- foo.go bar.go
 //
+// A single txtar archive (with suffix .txtar) containing the package
+// files may be provided instead; see checktest.RunArchive.
+//
 // If no source arguments are provided, the file testdata/manual.go2
 // is used instead.
 // Provide the -verify flag to verify errors against ERROR comments
 // in the input files rather than having a list of errors reported.
 // The accepted Go language version can be controlled with the -lang
-// flag.
+// flag; test flags at the start of the first file take precedence.
 func TestManual(t *testing.T) {
 	testenv.MustHaveGoBuild(t)
 
@@ -270,6 +81,11 @@ func TestManual(t *testing.T) {
 			t.Fatal("TestManual: must have only one directory argument")
 		}
 		testDir(t, filenames[0], 0, true)
+	} else if strings.HasSuffix(filenames[0], ".txtar") {
+		if len(filenames) > 1 {
+			t.Fatal("TestManual: must have only one archive argument")
+		}
+		testArchive(t, filenames[0], 0, true)
 	} else {
 		testFiles(t, filenames, 0, true)
 	}
@@ -280,6 +96,7 @@ func TestManual(t *testing.T) {
 func TestCheck(t *testing.T)     { DefPredeclaredTestFuncs(); testDirFiles(t, "testdata/check", 75, false) } // TODO(gri) narrow column tolerance
 func TestExamples(t *testing.T)  { testDirFiles(t, "testdata/examples", 0, false) }
 func TestFixedbugs(t *testing.T) { testDirFiles(t, "testdata/fixedbugs", 0, false) }
+func TestArchives(t *testing.T)  { testDirFiles(t, "testdata/archives", 0, false) }
 
 func testDirFiles(t *testing.T, dir string, colDelta uint, manual bool) {
 	testenv.MustHaveGoBuild(t)
@@ -294,9 +111,14 @@ func testDirFiles(t *testing.T, dir string, colDelta uint, manual bool) {
 	for _, fi := range fis {
 		path := filepath.Join(dir, fi.Name())
 
-		// If fi is a directory, its files make up a single package.
+		// If fi is a directory or an archive, its files make up
+		// a single package.
 		if fi.IsDir() {
 			testDir(t, path, colDelta, manual)
+		} else if strings.HasSuffix(path, ".txtar") {
+			t.Run(filepath.Base(path), func(t *testing.T) {
+				testArchive(t, path, colDelta, manual)
+			})
 		} else {
 			t.Run(filepath.Base(path), func(t *testing.T) {
 				testFiles(t, []string{path}, colDelta, manual)
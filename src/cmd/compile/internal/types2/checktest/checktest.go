@@ -0,0 +1,449 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package checktest implements the test harness used by the types2
+// tests. The packages specified by a test are typechecked, and the
+// error messages reported by the typechecker are compared against the
+// error messages expected in the test files.
+//
+// Expected errors are indicated in the test files by putting a comment
+// of the form /* ERROR "rx" */ immediately following an offending token.
+// The harness will verify that an error matching the regular expression
+// rx is reported at that source position. Consecutive comments may be
+// used to indicate multiple errors for the same token position.
+//
+// For instance, the following test file indicates that a "not declared"
+// error should be reported for the undeclared variable x:
+//
+//	package p
+//	func f() {
+//		_ = x /* ERROR "not declared" */ + 1
+//	}
+//
+// A test may adjust the configuration used to typecheck it with flags
+// in a line comment at the start of its first file, before the package
+// clause. For instance, a test that must be typechecked for Go 1.17
+// with generics enabled starts with:
+//
+//	// -lang=go1.17 -G=3
+//
+// The accepted flags are
+//
+//	-lang=version     Go language version (e.g. "go1.12"); see Options.Lang
+//	-G=n              generics level; see Options.G
+//	-aliastypeparams  permit parameterized aliases; see Options.AliasTypeParams
+//
+// A test may also be provided as a txtar archive (see RunArchive), which
+// makes it easy to share self-contained tests of several files.
+package checktest
+
+import (
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/txtar"
+)
+
+// Options control how the files of a test are typechecked and
+// how the reported errors are matched against the expected ones.
+type Options struct {
+	// Lang is the accepted Go language version (e.g. "go1.12").
+	// If Lang is empty, the version is derived from the package
+	// name if it is a Go version string using '_' rather than '.'
+	// (e.g. package go1_12); otherwise the most recent version
+	// is accepted.
+	Lang string
+
+	// G is the generics level, as for the compiler's -G flag.
+	// If G > 0, type parameters and type lists are accepted in
+	// all files; otherwise they are only accepted in files with
+	// the .go2 suffix.
+	G int
+
+	// If AliasTypeParams is set, alias declarations may declare
+	// type parameters (see types2.Config.AliasTypeParams).
+	AliasTypeParams bool
+
+	// ColDelta is the maximum difference between the column of a
+	// reported error and the column of the ERROR comment matching it.
+	ColDelta uint
+
+	// If List is set, the reported errors are listed via t.Error
+	// rather than matched against ERROR comments.
+	List bool
+
+	// If Halt is set, the harness panics with the first reported error.
+	Halt bool
+
+	// If Trace is set, the typechecker's trace is printed.
+	Trace bool
+
+	// If LineErrors is set, an error expected by an ERROR line comment
+	// (// ERROR "rx") matches an error reported anywhere on the line of
+	// the comment, as for the errorcheck tests of the test directory.
+	// Errors expected by ERROR block comments must still be reported
+	// at the preceding token.
+	LineErrors bool
+
+	// Importer is used to import the packages referred to by
+	// the test files. It may be nil if the files have no imports.
+	Importer types2.Importer
+}
+
+// Run typechecks the package made of the given files and verifies
+// the reported errors as described in the package documentation.
+// Any test flags present in the first file override the respective
+// fields of opts.
+func Run(t testing.TB, filenames []string, opts Options) {
+	t.Helper()
+
+	if len(filenames) == 0 {
+		t.Fatal("no source files")
+	}
+
+	src, err := os.ReadFile(filenames[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parseFlags(filenames[0], leadingFlags(string(src)), &opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var mode syntax.Mode
+	if opts.G > 0 || strings.HasSuffix(filenames[0], ".go2") {
+		mode |= syntax.AllowGenerics | syntax.AllowTypeLists
+	}
+	// parse files and collect parser errors
+	files, errlist := parseFiles(t, filenames, mode)
+
+	pkgName := "<no package>"
+	if len(files) > 0 {
+		pkgName = files[0].PkgName.Value
+	}
+
+	// if no Go version is given, consider the package name
+	goVersion := opts.Lang
+	if goVersion == "" {
+		goVersion = asGoVersion(pkgName)
+	}
+
+	if opts.List && len(errlist) > 0 {
+		t.Errorf("--- %s:", pkgName)
+		for _, err := range errlist {
+			t.Error(err)
+		}
+	}
+
+	// typecheck and collect typechecker errors
+	var conf types2.Config
+	conf.GoVersion = goVersion
+	// special case for importC.src
+	if len(filenames) == 1 && strings.HasSuffix(filenames[0], "importC.src") {
+		conf.FakeImportC = true
+	}
+	conf.AliasTypeParams = opts.AliasTypeParams
+	conf.Trace = opts.Trace
+	conf.Importer = opts.Importer
+	conf.Error = func(err error) {
+		if opts.Halt {
+			defer panic(err)
+		}
+		if opts.List {
+			t.Error(err)
+			return
+		}
+		errlist = append(errlist, err)
+	}
+	conf.Check(pkgName, files, nil)
+
+	if opts.List {
+		return
+	}
+
+	for _, err := range errlist {
+		err, ok := err.(types2.Error)
+		if !ok {
+			continue
+		}
+		if err.Code == 0 {
+			t.Errorf("missing error code: %v", err)
+		}
+	}
+
+	// sort errlist in source order
+	sort.Slice(errlist, func(i, j int) bool {
+		pi := unpackError(errlist[i]).Pos
+		pj := unpackError(errlist[j]).Pos
+		return pi.Cmp(pj) < 0
+	})
+
+	// collect expected errors
+	errmap := make(map[string]map[uint][]syntax.Error)
+	anyCol := make(map[syntax.Error]bool) // errors expected by line comments, if opts.LineErrors is set
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		m := syntax.ErrorMap(strings.NewReader(string(src)))
+		if len(m) == 0 {
+			continue
+		}
+		errmap[filename] = m
+		if opts.LineErrors {
+			for line, isLine := range lineComments(string(src)) {
+				for i, list := 0, m[line]; i < len(list) && i < len(isLine); i++ {
+					if isLine[i] {
+						anyCol[list[i]] = true
+					}
+				}
+			}
+		}
+	}
+
+	// match against found errors
+	for _, err := range errlist {
+		got := unpackError(err)
+
+		// find list of errors for the respective error line
+		filename := got.Pos.Base().Filename()
+		filemap := errmap[filename]
+		line := got.Pos.Line()
+		var list []syntax.Error
+		if filemap != nil {
+			list = filemap[line]
+		}
+		// list may be nil
+
+		// one of errors in list should match the current error
+		index := -1 // list index of matching message, if any
+		for i, want := range list {
+			rx, err := regexp.Compile(want.Msg)
+			if err != nil {
+				t.Errorf("%s:%d:%d: %v", filename, line, want.Pos.Col(), err)
+				continue
+			}
+			if rx.MatchString(got.Msg) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			t.Errorf("%s: no error expected: %q", got.Pos, got.Msg)
+			continue
+		}
+
+		// column position must be within expected colDelta
+		want := list[index]
+		if !anyCol[want] && delta(got.Pos.Col(), want.Pos.Col()) > opts.ColDelta {
+			t.Errorf("%s: got col = %d; want %d", got.Pos, got.Pos.Col(), want.Pos.Col())
+		}
+
+		// eliminate from list
+		if n := len(list) - 1; n > 0 {
+			// not the last entry - slide entries down (don't reorder)
+			copy(list[index:], list[index+1:])
+			filemap[line] = list[:n]
+		} else {
+			// last entry - remove list from filemap
+			delete(filemap, line)
+		}
+
+		// if filemap is empty, eliminate from errmap
+		if len(filemap) == 0 {
+			delete(errmap, filename)
+		}
+	}
+
+	// there should be no expected errors left
+	if len(errmap) > 0 {
+		t.Errorf("--- %s: unreported errors:", pkgName)
+		for filename, filemap := range errmap {
+			for line, list := range filemap {
+				for _, err := range list {
+					t.Errorf("%s:%d:%d: %s", filename, line, err.Pos.Col(), err.Msg)
+				}
+			}
+		}
+	}
+}
+
+// RunArchive typechecks the package made of the files in the txtar
+// archive with the given file name, and verifies the reported errors as
+// described in the package documentation. The archive's files are
+// written to a temporary directory, so their names may include
+// directories. Lines of the archive's comment that start with '-'
+// contain test flags, which override the respective fields of opts; any
+// other text in the comment is ignored. For instance:
+//
+//	Test flags and a description.
+//	-lang=go1.17 -G=3
+//	-- a.go --
+//	package p
+//	var x int = "s" // ERROR "cannot use"
+//	-- b.go --
+//	package p
+//	var _ = x
+//
+// LineErrors is always set for archives, so that ERROR line comments
+// may be written as in the errorcheck tests of the test directory.
+func RunArchive(t testing.TB, filename string, opts Options) {
+	t.Helper()
+
+	a, err := txtar.ParseFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var args []string
+	for _, line := range strings.Split(string(a.Comment), "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "-") {
+			args = append(args, strings.Fields(line)...)
+		}
+	}
+	if err := parseFlags(filename, args, &opts); err != nil {
+		t.Fatal(err)
+	}
+	opts.LineErrors = true
+
+	dir, err := os.MkdirTemp("", "checktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var filenames []string
+	for _, f := range a.Files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, f.Data, 0666); err != nil {
+			t.Fatal(err)
+		}
+		filenames = append(filenames, path)
+	}
+
+	Run(t, filenames, opts)
+}
+
+// leadingFlags returns the test flags in the leading line comments
+// of src, if any.
+func leadingFlags(src string) []string {
+	var args []string
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		if text := strings.TrimSpace(line[2:]); strings.HasPrefix(text, "-") {
+			args = append(args, strings.Fields(text)...)
+		}
+	}
+	return args
+}
+
+// lineComments reports, for each line of src with ERROR comments, in
+// source order, whether each of these comments is a line comment.
+func lineComments(src string) map[uint][]bool {
+	m := make(map[uint][]bool)
+	syntax.CommentsDo(strings.NewReader(src), func(line, _ uint, text string) {
+		if text[0] != '/' {
+			return // error, ignore
+		}
+		isLine := text[1] == '/'
+		if !isLine {
+			text = text[:len(text)-2] // strip trailing */
+		}
+		if strings.HasPrefix(strings.TrimLeft(text[2:], " "), "ERROR") { // see syntax.ErrorMap
+			m[line] = append(m[line], isLine)
+		}
+	})
+	return m
+}
+
+// parseFlags sets the fields of opts specified by the test flags args,
+// which were found in the given file.
+func parseFlags(filename string, args []string, opts *Options) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	flags := flag.NewFlagSet(filename, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.Lang, "lang", opts.Lang, "")
+	flags.IntVar(&opts.G, "G", opts.G, "")
+	flags.BoolVar(&opts.AliasTypeParams, "aliastypeparams", opts.AliasTypeParams, "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("%s: unexpected test flag arguments: %v", filename, flags.Args())
+	}
+	return nil
+}
+
+func parseFiles(t testing.TB, filenames []string, mode syntax.Mode) ([]*syntax.File, []error) {
+	var files []*syntax.File
+	var errlist []error
+	errh := func(err error) { errlist = append(errlist, err) }
+	for _, filename := range filenames {
+		file, err := syntax.ParseFile(filename, errh, nil, mode)
+		if file == nil {
+			t.Fatalf("%s: %s", filename, err)
+		}
+		files = append(files, file)
+	}
+	return files, errlist
+}
+
+func unpackError(err error) syntax.Error {
+	switch err := err.(type) {
+	case syntax.Error:
+		return err
+	case types2.Error:
+		return syntax.Error{Pos: err.Pos, Msg: err.Msg}
+	default:
+		return syntax.Error{Msg: err.Error()}
+	}
+}
+
+// delta returns the absolute difference between x and y.
+func delta(x, y uint) uint {
+	switch {
+	case x < y:
+		return y - x
+	case x > y:
+		return x - y
+	default:
+		return 0
+	}
+}
+
+// goVersionRx matches a Go version string using '_', e.g. "go1_12".
+var goVersionRx = regexp.MustCompile(`^go[1-9][0-9]*_(0|[1-9][0-9]*)$`)
+
+// asGoVersion returns a regular Go language version string
+// if s is a Go version string using '_' rather than '.' to
+// separate the major and minor version numbers (e.g. "go1_12").
+// Otherwise it returns the empty string.
+func asGoVersion(s string) string {
+	if goVersionRx.MatchString(s) {
+		return strings.Replace(s, "_", ".", 1)
+	}
+	return ""
+}
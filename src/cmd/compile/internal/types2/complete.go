@@ -0,0 +1,144 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements Config.CompleteTypes: the recording of type
+// information for the expressions that are not otherwise recorded in
+// Info.Types.
+
+package types2
+
+import "cmd/compile/internal/syntax"
+
+// completeTypes records type information for the expressions in the
+// checked files that have no Info.Types entry yet, as described for
+// Config.CompleteTypes.
+func (check *Checker) completeTypes() {
+	complete := func(n syntax.Node) {
+		syntax.Inspect(n, func(n syntax.Node) bool {
+			switch n := n.(type) {
+			case *syntax.FuncDecl:
+				check.completeFunc(n)
+			case *syntax.Name:
+				check.completeName(n)
+			}
+			return true
+		})
+	}
+	for _, file := range check.files {
+		if check.redecl.scope(file) == nil {
+			complete(file)
+			continue
+		}
+		// only the declarations checked again by Checker.ReplaceFile
+		for _, decl := range file.DeclList {
+			if !check.redecl.skip(file, decl) {
+				complete(decl)
+			}
+		}
+	}
+}
+
+// completeName records the identifier x as an operand denoting the
+// object it declares or refers to, if there is no entry for x yet.
+// Identifiers denoting packages and labels are not recorded.
+func (check *Checker) completeName(x *syntax.Name) {
+	if _, found := check.Types[x]; found {
+		return
+	}
+	var mode operandMode
+	switch obj := check.names[x].(type) {
+	case *Var:
+		mode = variable
+	case *Const:
+		if obj.val != nil {
+			check.recordTypeAndValue(x, constant_, obj.typ, obj.val)
+		}
+		return
+	case *TypeName:
+		mode = typexpr
+	case *Func:
+		mode = value
+	default:
+		return // no object, or a package name, label, built-in, or nil
+	}
+	if typ := check.names[x].Type(); typ != nil {
+		check.recordTypeAndValue(x, mode, typ, nil)
+	}
+}
+
+// completeFunc records the function type and the receiver type
+// expressions of the function declaration decl, which are not
+// type-checked as ordinary type expressions.
+func (check *Checker) completeFunc(decl *syntax.FuncDecl) {
+	obj, _ := check.names[decl.Name].(*Func)
+	if obj == nil {
+		return
+	}
+	sig, _ := obj.typ.(*Signature)
+	if sig == nil {
+		return
+	}
+	if _, found := check.Types[decl.Type]; !found {
+		check.recordTypeAndValue(decl.Type, typexpr, sig, nil)
+	}
+	if decl.Recv == nil || sig.recv == nil {
+		return
+	}
+
+	// The receiver type expression has the form [*]T or [*]T[P, ...].
+	rtyp := sig.recv.typ
+	x := unparen(decl.Recv.Type)
+	if op, _ := x.(*syntax.Operation); op != nil && op.Op == syntax.Mul && op.Y == nil {
+		if _, found := check.Types[x]; !found {
+			check.recordTypeAndValue(x, typexpr, rtyp, nil)
+		}
+		x = unparen(op.X)
+		if p, _ := rtyp.(*Pointer); p != nil {
+			rtyp = p.base
+		}
+	}
+	if _, found := check.Types[x]; !found {
+		check.recordTypeAndValue(x, typexpr, rtyp, nil)
+	}
+	if index, _ := x.(*syntax.IndexExpr); index != nil {
+		for i, e := range unpackExpr(index.Index) {
+			if i < sig.RParams().Len() {
+				if _, found := check.Types[e]; !found {
+					check.recordTypeAndValue(e, typexpr, sig.RParams().At(i), nil)
+				}
+			}
+		}
+	}
+}
+
+// recordUnion records the union expression x, which denotes the type
+// typ, and its union and tilde subexpressions, if Config.CompleteTypes
+// is set. The subexpressions denote unions of the respective terms of
+// typ.
+func (check *Checker) recordUnion(x syntax.Expr, typ Type) {
+	if !check.conf.CompleteTypes {
+		return
+	}
+	u, _ := typ.(*Union)
+	if u == nil {
+		return // single type, recorded with the type expression
+	}
+	var record func(x syntax.Expr, terms []*Term) int
+	record = func(x syntax.Expr, terms []*Term) int {
+		n := 1
+		if op, _ := x.(*syntax.Operation); op != nil && op.Op == syntax.Or {
+			n = record(op.X, terms)
+			n += record(op.Y, terms[n:])
+		} else if !(op != nil && op.Op == syntax.Tilde) {
+			return n // term type, recorded with the type expression
+		}
+		if _, found := check.Types[x]; !found {
+			check.recordTypeAndValue(x, typexpr, NewUnion(terms[:n]), nil)
+		}
+		return n
+	}
+	if len(flattenUnion(nil, x)) == len(u.terms) {
+		record(x, u.terms)
+	}
+}
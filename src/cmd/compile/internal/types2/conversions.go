@@ -22,6 +22,10 @@ func (check *Checker) conversion(x *operand, T Type) {
 		// constant conversion
 		switch t := asBasic(T); {
 		case representableConst(x.val, check, t, &x.val):
+			if !check.targetRepresentable(x, t) {
+				x.mode = invalid
+				return
+			}
 			ok = true
 		case isInteger(x.typ) && isString(t):
 			codepoint := unicode.ReplacementChar
@@ -38,8 +42,8 @@ func (check *Checker) conversion(x *operand, T Type) {
 	}
 
 	if !ok {
-		if x.mode != invalid {
-			check.errorf(x, "cannot convert %s to %s", x, T)
+		if x.mode != invalid && !isUnknown(x.typ) && !isUnknown(T) {
+			check.errorf(x, _InvalidConversion, "cannot convert %s to %s", x, T)
 			x.mode = invalid
 		}
 		return
@@ -143,14 +147,14 @@ func (x *operand) convertibleTo(check *Checker, T Type) bool {
 		if p := asPointer(T); p != nil {
 			if a := asArray(p.Elem()); a != nil {
 				if Identical(s.Elem(), a.Elem()) {
-					if check == nil || check.allowVersion(check.pkg, 1, 17) {
+					if check == nil || check.allowVersion(check.pkg, x, 1, 17) {
 						return true
 					}
 					// check != nil
 					if check.conf.CompilerErrorMessages {
-						check.error(x, "conversion of slices to array pointers only supported as of -lang=go1.17")
+						check.error(x, _InvalidConversion, "conversion of slices to array pointers only supported as of -lang=go1.17")
 					} else {
-						check.error(x, "conversion of slices to array pointers requires go1.17 or later")
+						check.error(x, _InvalidConversion, "conversion of slices to array pointers requires go1.17 or later")
 					}
 					x.mode = invalid // avoid follow-up error
 				}
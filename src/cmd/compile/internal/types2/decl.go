@@ -27,6 +27,7 @@ func (check *Checker) declare(scope *Scope, id *syntax.Name, obj Object, pos syn
 	if obj.Name() != "_" {
 		if alt := scope.Insert(obj); alt != nil {
 			var err error_
+			err.code = _DuplicateDecl
 			err.errorf(obj, "%s redeclared in this block", obj.Name())
 			err.recordAltDecl(alt)
 			check.report(&err)
@@ -65,6 +66,12 @@ func (check *Checker) objDecl(obj Object, def *Named) {
 			check.trace(obj.Pos(), "=> %s (%s)", obj, obj.color())
 		}()
 	}
+	if check.conf.TraceWriter != nil && obj.Type() == nil {
+		s := check.traceEnter(obj.Pos(), "objDecl", "%s", obj)
+		defer func() {
+			check.traceExit(s, "%s", obj.Type())
+		}()
+	}
 
 	// Checking the declaration of obj means inferring its type
 	// (and possibly its value, for constants).
@@ -178,6 +185,7 @@ func (check *Checker) objDecl(obj Object, def *Named) {
 	}(check.context)
 	check.context = context{
 		scope: d.file,
+		user:  d,
 	}
 
 	// Const and var declarations must not have initialization
@@ -365,6 +373,7 @@ func (check *Checker) cycleError(cycle []Object) {
 	i := firstInSrc(cycle)
 	obj := cycle[i]
 	var err error_
+	err.code = _InvalidDeclCycle
 	if check.conf.CompilerErrorMessages {
 		err.errorf(obj, "invalid recursive type %s", obj.Name())
 	} else {
@@ -415,7 +424,7 @@ func (check *Checker) constDecl(obj *Const, typ, init syntax.Expr, inherited boo
 			// don't report an error if the type is an invalid C (defined) type
 			// (issue #22090)
 			if under(t) != Typ[Invalid] {
-				check.errorf(typ, "invalid constant type %s", t)
+				check.errorf(typ, _InvalidConstType, "invalid constant type %s", t)
 			}
 			obj.typ = Typ[Invalid]
 			return
@@ -531,31 +540,49 @@ func (check *Checker) typeDecl(obj *TypeName, tdecl *syntax.TypeDecl, def *Named
 	check.later(func() {
 		check.validType(obj.typ, nil)
 		// If typ is local, an error was already reported where typ is specified/defined.
-		if check.isImportedConstraint(rhs) && !check.allowVersion(check.pkg, 1, 18) {
-			check.errorf(tdecl.Type.Pos(), "using type constraint %s requires go1.18 or later", rhs)
+		if check.isImportedConstraint(rhs) && !check.allowVersion(check.pkg, tdecl, 1, 18) {
+			check.errorf(tdecl.Type.Pos(), _Todo, "using type constraint %s requires go1.18 or later", rhs)
 		}
 	})
 
-	alias := tdecl.Alias
-	if alias && tdecl.TParamList != nil {
-		// The parser will ensure this but we may still get an invalid AST.
-		// Complain and continue as regular type definition.
-		check.error(tdecl, "generic type cannot be alias")
-		alias = false
-	}
-
 	// alias declaration
-	if alias {
-		if !check.allowVersion(check.pkg, 1, 9) {
+	if tdecl.Alias {
+		if !check.allowVersion(check.pkg, tdecl, 1, 9) {
 			if check.conf.CompilerErrorMessages {
-				check.error(tdecl, "type aliases only supported as of -lang=go1.9")
+				check.error(tdecl, _BadDecl, "type aliases only supported as of -lang=go1.9")
 			} else {
-				check.error(tdecl, "type aliases requires go1.9 or later")
+				check.error(tdecl, _BadDecl, "type aliases requires go1.9 or later")
 			}
 		}
 
 		obj.typ = Typ[Invalid]
+		var tparams *TParamList
+		if tdecl.TParamList != nil {
+			// parameterized alias
+			check.openScope(tdecl, "type parameters")
+			defer check.closeScope()
+			tparams = check.collectTypeParams(tdecl.TParamList)
+		}
 		rhs = check.varType(tdecl.Type)
+
+		if tparams != nil && !check.conf.AliasTypeParams {
+			// Continue with a plain alias. The type parameters are only
+			// declared to avoid follow-on errors for their uses in the
+			// RHS, which is invalid if it refers to them.
+			check.error(tdecl, _Todo, "generic type cannot be alias (requires GOEXPERIMENT=aliastypeparams)")
+			if isParameterized(tparams.list(), rhs) {
+				rhs = Typ[Invalid]
+			}
+			tparams = nil
+		}
+		obj.tparams = tparams
+
+		// The RHS must not be a type parameter of the alias itself: the
+		// alias would not denote a type (it would stand for its type argument).
+		if tpar, _ := rhs.(*TypeParam); tpar != nil && tparams != nil && tparamIndex(tparams.list(), tpar) >= 0 {
+			check.errorf(tdecl.Type, _Todo, "cannot use type parameter %s as RHS in alias declaration", tpar)
+			rhs = Typ[Invalid]
+		}
 		obj.typ = rhs
 		return
 	}
@@ -563,6 +590,7 @@ func (check *Checker) typeDecl(obj *TypeName, tdecl *syntax.TypeDecl, def *Named
 	// type definition or generic type declaration
 	named := check.newNamed(obj, nil, nil, nil, nil)
 	def.setUnderlying(named)
+	check.recordDeclaredType(tdecl, named)
 
 	if tdecl.TParamList != nil {
 		check.openScope(tdecl, "type parameters")
@@ -593,7 +621,7 @@ func (check *Checker) typeDecl(obj *TypeName, tdecl *syntax.TypeDecl, def *Named
 
 	// If the RHS is a type parameter, it must be from this type declaration.
 	if tpar, _ := named.underlying.(*TypeParam); tpar != nil && tparamIndex(named.TParams().list(), tpar) < 0 {
-		check.errorf(tdecl.Type, "cannot use function type parameter %s as RHS in type declaration", tpar)
+		check.errorf(tdecl.Type, _Todo, "cannot use function type parameter %s as RHS in type declaration", tpar)
 		named.underlying = Typ[Invalid]
 	}
 }
@@ -608,7 +636,7 @@ func (check *Checker) collectTypeParams(list []*syntax.Field) *TParamList {
 		tparams[i] = check.declareTypeParam(f.Name)
 	}
 
-	var bound Type
+	var bound, deflt Type
 	for i, f := range list {
 		// Optimization: Re-use the previous type bound if it hasn't changed.
 		// This also preserves the grouped output of type parameter lists
@@ -617,11 +645,56 @@ func (check *Checker) collectTypeParams(list []*syntax.Field) *TParamList {
 			bound = check.boundType(f.Type)
 		}
 		tparams[i].bound = bound
+
+		// Default types are shared like bounds.
+		if f.Default == nil {
+			if deflt != nil {
+				check.errorf(f.Name, _Todo, "missing default type for type parameter %s", f.Name.Value)
+			}
+			continue
+		}
+		if i == 0 || f.Default != list[i-1].Default {
+			deflt = check.defaultType(f.Default, tparams[i:])
+		}
+		tparams[i].deflt = deflt
+	}
+
+	if check.conf.ReportUnused {
+		check.declTParams = append(check.declTParams, tparams...)
 	}
 
 	return bindTParams(tparams)
 }
 
+// unusedTParams reports the non-blank type parameters declared by generic
+// declarations which are not referenced (see Config.ReportUnused).
+func (check *Checker) unusedTParams() {
+	for _, tpar := range check.declTParams {
+		if tpar.obj.name != "_" && !check.usedTParams[tpar] {
+			check.softErrorf(tpar.obj, _Todo, "type parameter %s declared but not used", tpar.obj.name)
+		}
+	}
+	check.declTParams = nil
+	check.usedTParams = nil
+}
+
+// defaultType type-checks the default type e of the first type parameter
+// in tparams and returns its type, or nil. The default type must not refer
+// to any of the type parameters in tparams.
+func (check *Checker) defaultType(e syntax.Expr, tparams []*TypeParam) Type {
+	if !check.conf.DefaultTypeArgs {
+		check.error(e, _Todo, "type parameter defaults require GOEXPERIMENT=defaulttypeargs")
+		return nil
+	}
+	typ := check.varType(e)
+	check.later(func() {
+		if isParameterized(tparams, typ) {
+			check.errorf(e, _Todo, "default type %s must only refer to preceding type parameters", typ)
+		}
+	})
+	return typ
+}
+
 func (check *Checker) declareTypeParam(name *syntax.Name) *TypeParam {
 	tname := NewTypeName(name.Pos(), check.pkg, name.Value, nil)
 	tpar := check.NewTypeParam(tname, nil)                   // assigns type to tname as a side-effect
@@ -635,14 +708,23 @@ func (check *Checker) boundType(e syntax.Expr) Type {
 	// The predeclared identifier "any" is visible only as a type bound in a type parameter list.
 	// If we allow "any" for general use, this if-statement can be removed (issue #33232).
 	if name, _ := unparen(e).(*syntax.Name); name != nil && name.Value == "any" && check.lookup("any") == universeAny {
+		if check.conf.CompleteTypes {
+			check.recordTypeAndValue(e, typexpr, universeAny.Type(), nil)
+		}
 		return universeAny.Type()
 	}
 
+	// A constraint literal such as ~int or int | string stands for the
+	// implicit interface interface{ ~int } or interface{ int | string }.
+	if op, _ := e.(*syntax.Operation); op != nil && (op.Op == syntax.Tilde || op.Op == syntax.Or) {
+		return check.implicitInterface(e)
+	}
+
 	bound := check.typ(e)
 	check.later(func() {
 		u := under(bound)
 		if _, ok := u.(*Interface); !ok && u != Typ[Invalid] {
-			check.errorf(e, "%s is not an interface", bound)
+			check.errorf(e, _Todo, "%s is not an interface", bound)
 		}
 	})
 	return bound
@@ -692,6 +774,7 @@ func (check *Checker) collectMethods(obj *TypeName) {
 		assert(m.name != "_")
 		if alt := mset.insert(m); alt != nil {
 			var err error_
+			err.code = _DuplicateFieldAndMethod
 			switch alt.(type) {
 			case *Var:
 				err.errorf(m.pos, "field and method with the same name %s", m.name)
@@ -734,20 +817,35 @@ func (check *Checker) funcDecl(obj *Func, decl *declInfo) {
 	saved := obj.color_
 	obj.color_ = black
 	fdecl := decl.fdecl
-	check.funcType(sig, fdecl.Recv, fdecl.TParamList, fdecl.Type)
+	check.funcType(sig, fdecl, fdecl.Type)
 	obj.color_ = saved
 
 	if len(fdecl.TParamList) > 0 && fdecl.Body == nil {
-		check.softErrorf(fdecl, "parameterized function is missing function body")
+		check.softErrorf(fdecl, _Todo, "parameterized function is missing function body")
 	}
 
 	// function body must be type-checked after global declarations
 	// (functions implemented elsewhere have no body)
-	if !check.conf.IgnoreFuncBodies && fdecl.Body != nil {
-		check.later(func() {
-			check.funcBody(decl, obj.name, sig, fdecl.Body, nil)
-		})
+	if !check.conf.IgnoreFuncBodies && fdecl.Body != nil && (!check.conf.IgnoreUnexportedFuncBodies || isExportedFunc(obj)) {
+		check.laterFuncBody(decl, obj.name, sig, fdecl.Body)
+	}
+}
+
+// isExportedFunc reports whether obj is an exported function,
+// or an exported method of an exported type.
+func isExportedFunc(obj *Func) bool {
+	if !obj.Exported() {
+		return false
 	}
+	if recv := obj.typ.(*Signature).recv; recv != nil {
+		if base, _ := deref(recv.typ); base != nil {
+			if named := asNamed(base); named != nil {
+				return named.obj.Exported()
+			}
+		}
+		return false
+	}
+	return true
 }
 
 func (check *Checker) declStmt(list []syntax.Decl) {
@@ -787,6 +885,7 @@ func (check *Checker) declStmt(list []syntax.Decl) {
 			values := unpackExpr(last.Values)
 			for i, name := range s.NameList {
 				obj := NewConst(name.Pos(), pkg, name.Value, nil, iota)
+				obj.doc = declDoc(list, index)
 				lhs[i] = obj
 
 				var init syntax.Expr
@@ -818,6 +917,7 @@ func (check *Checker) declStmt(list []syntax.Decl) {
 			lhs0 := make([]*Var, len(s.NameList))
 			for i, name := range s.NameList {
 				lhs0[i] = NewVar(name.Pos(), pkg, name.Value, nil)
+				lhs0[i].doc = declDoc(list, index)
 			}
 
 			// initialize all variables
@@ -872,6 +972,7 @@ func (check *Checker) declStmt(list []syntax.Decl) {
 
 		case *syntax.TypeDecl:
 			obj := NewTypeName(s.Name.Pos(), pkg, s.Name.Value, nil)
+			obj.doc = declDoc(list, index)
 			// spec: "The scope of a type identifier declared inside a function
 			// begins at the identifier in the TypeSpec and ends at the end of
 			// the innermost containing block."
@@ -883,7 +984,7 @@ func (check *Checker) declStmt(list []syntax.Decl) {
 			check.pop().setColor(black)
 
 		default:
-			check.errorf(s, invalidAST+"unknown syntax.Decl node %T", s)
+			check.errorf(s, _InvalidSyntaxTree, invalidAST+"unknown syntax.Decl node %T", s)
 		}
 	}
 }
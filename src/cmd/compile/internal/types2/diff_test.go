@@ -0,0 +1,330 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements a differential test that type-checks the same
+// sources with types2 and go/types and reports where the results of
+// the two type checkers diverge.
+//
+// TestDiff checks the packages provided with the -diff flag, a
+// comma-separated list of files and directories. Each file or
+// directory is checked as a separate package, except that for a
+// directory argument of the form dir/..., each entry of dir is checked
+// as a separate package. For instance
+//
+//	go test -run Diff -diff=testdata/check/...,testdata/fixedbugs/...
+//	go test -run Diff -diff=$GOROOT/src/fmt,$GOROOT/src/go/types
+//
+// Divergences are reported in four categories: crashes of one of the
+// type checkers, programs accepted by one type checker but not the
+// other, errors reported at different positions, and identifiers whose
+// objects have different types. Use -diff.errors=false to ignore error
+// positions, which differ in many cases.
+//
+// Crashes are recovered, but the packages are checked in the test
+// process: a type checker that overflows the stack terminates the test,
+// and -v shows the package being checked.
+
+package types2_test
+
+import (
+	"cmd/compile/internal/syntax"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/types2"
+)
+
+var (
+	diffFiles  = flag.String("diff", "", "comma-separated list of files or directories for TestDiff")
+	diffErrors = flag.Bool("diff.errors", true, "report divergent error positions in TestDiff")
+)
+
+// goDisallowGenerics is the go/parser mode that disallows type
+// parameters (go/internal/typeparams.DisallowParsing).
+const goDisallowGenerics = 1 << 30
+
+func TestDiff(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	if *diffFiles == "" {
+		t.Skip("no -diff files provided")
+	}
+
+	types2.DefPredeclaredTestFuncs()
+	types.DefPredeclaredTestFuncs()
+
+	for _, path := range strings.Split(*diffFiles, ",") {
+		dir := strings.TrimSuffix(path, "/...")
+		if dir == path {
+			diffPackage(t, filepath.FromSlash(path))
+			continue
+		}
+		dir = filepath.FromSlash(dir)
+		fis, err := os.ReadDir(dir)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		for _, fi := range fis {
+			diffPackage(t, filepath.Join(dir, fi.Name()))
+		}
+	}
+}
+
+// diffPackage checks the package consisting of the file path, or of
+// the .go and .go2 files in directory path, with both type checkers.
+func diffPackage(t *testing.T, path string) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	filenames := []string{path}
+	if fi.IsDir() {
+		filenames = nil
+		fis, err := os.ReadDir(path)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, fi := range fis {
+			if name := fi.Name(); strings.HasSuffix(name, ".go") || strings.HasSuffix(name, ".go2") {
+				filenames = append(filenames, filepath.Join(path, name))
+			}
+		}
+		if len(filenames) == 0 {
+			return
+		}
+	}
+
+	t.Run(filepath.Base(path), func(t *testing.T) {
+		generic := strings.HasSuffix(filenames[0], ".go2")
+		r2, err2 := checkTypes2(filenames, generic)
+		if err2 != nil {
+			t.Error(err2)
+		}
+		r, err := checkGoTypes(filenames, generic)
+		if err != nil {
+			t.Error(err)
+		}
+		if err2 == nil && err == nil {
+			diffResults(t, r2, r)
+		}
+	})
+}
+
+// A diffResult summarizes the result of type-checking a package.
+type diffResult struct {
+	syntaxErrors bool              // if set, the package was not type-checked
+	errors       []string          // error positions, sorted
+	types        map[string]string // identifier position -> type of the identifier's object
+}
+
+func (r *diffResult) accepted() bool {
+	return !r.syntaxErrors && len(r.errors) == 0
+}
+
+// diffResults reports the divergences between the results r2 of
+// types2 and r of go/types.
+func diffResults(t *testing.T, r2, r *diffResult) {
+	if r2.syntaxErrors != r.syntaxErrors {
+		t.Errorf("syntax errors: types2 %v, go/types %v", r2.syntaxErrors, r.syntaxErrors)
+		return
+	}
+	if r2.syntaxErrors {
+		return // nothing else to compare
+	}
+
+	if r2.accepted() != r.accepted() {
+		t.Errorf("accepted: types2 %v, go/types %v", r2.accepted(), r.accepted())
+	}
+
+	if *diffErrors {
+		only2, only := diffSorted(r2.errors, r.errors)
+		for _, pos := range only2 {
+			t.Errorf("%s: error reported by types2 only", pos)
+		}
+		for _, pos := range only {
+			t.Errorf("%s: error reported by go/types only", pos)
+		}
+	}
+
+	// Only compare identifiers recorded by both type checkers; the
+	// error cases that lead to unrecorded identifiers are reported
+	// above.
+	var keys []string
+	for pos := range r2.types {
+		if _, ok := r.types[pos]; ok {
+			keys = append(keys, pos)
+		}
+	}
+	sort.Strings(keys)
+	for _, pos := range keys {
+		if typ2, typ := r2.types[pos], r.types[pos]; typ2 != typ {
+			t.Errorf("%s: types2 type %s, go/types type %s", pos, typ2, typ)
+		}
+	}
+}
+
+// diffSorted returns the elements that occur only in the sorted list
+// a and only in the sorted list b, respectively.
+func diffSorted(a, b []string) (onlyA, onlyB []string) {
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			onlyA = append(onlyA, a[0])
+			a = a[1:]
+		case a[0] > b[0]:
+			onlyB = append(onlyB, b[0])
+			b = b[1:]
+		default:
+			a, b = a[1:], b[1:]
+		}
+	}
+	return append(onlyA, a...), append(onlyB, b...)
+}
+
+// diffPos returns the position string used to match up positions
+// reported by the two type checkers.
+func diffPos(filename string, line, col uint) string {
+	return fmt.Sprintf("%s:%d:%d", filename, line, col)
+}
+
+// canonicalType returns the string for typ with type parameter
+// subscripts removed, since the subscripts depend on the order in
+// which each type checker creates type parameters.
+func canonicalType(typ string) string {
+	return strings.Map(func(r rune) rune {
+		if '₀' <= r && r <= '₉' {
+			return -1
+		}
+		return r
+	}, typ)
+}
+
+// diffRecover recovers from a type checker panic and reports it as an
+// error in *err.
+func diffRecover(checker string, err *error) {
+	if p := recover(); p != nil {
+		*err = fmt.Errorf("%s crashed: %v", checker, p)
+	}
+}
+
+func checkTypes2(filenames []string, generic bool) (_ *diffResult, err error) {
+	defer diffRecover("types2", &err)
+
+	var mode syntax.Mode
+	if generic {
+		mode |= syntax.AllowGenerics | syntax.AllowTypeLists
+	}
+	r := new(diffResult)
+	var files []*syntax.File
+	for _, filename := range filenames {
+		file, err := syntax.ParseFile(filename, func(error) {}, nil, mode)
+		if file == nil {
+			return nil, err
+		}
+		if err != nil {
+			r.syntaxErrors = true
+		}
+		files = append(files, file)
+	}
+	if r.syntaxErrors {
+		return r, nil
+	}
+
+	conf := types2.Config{
+		Importer: defaultImporter(),
+		Error: func(err error) {
+			pos := err.(types2.Error).Pos
+			r.errors = append(r.errors, diffPos(pos.RelFilename(), pos.Line(), pos.Col()))
+		},
+	}
+	info := &types2.Info{
+		Defs: make(map[*syntax.Name]types2.Object),
+		Uses: make(map[*syntax.Name]types2.Object),
+	}
+	conf.Check(files[0].PkgName.Value, files, info)
+	sort.Strings(r.errors)
+
+	r.types = make(map[string]string)
+	record := func(id *syntax.Name, obj types2.Object) {
+		if obj != nil && obj.Type() != nil {
+			pos := id.Pos()
+			r.types[diffPos(pos.RelFilename(), pos.Line(), pos.Col())] = canonicalType(obj.Type().String())
+		}
+	}
+	for id, obj := range info.Defs {
+		record(id, obj)
+	}
+	for id, obj := range info.Uses {
+		record(id, obj)
+	}
+	return r, nil
+}
+
+func checkGoTypes(filenames []string, generic bool) (_ *diffResult, err error) {
+	defer diffRecover("go/types", &err)
+
+	mode := parser.AllErrors
+	if !generic {
+		mode |= goDisallowGenerics
+	}
+	r := new(diffResult)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(fset, filename, nil, mode)
+		if file == nil {
+			return nil, err
+		}
+		if err != nil {
+			r.syntaxErrors = true
+		}
+		files = append(files, file)
+	}
+	if r.syntaxErrors {
+		return r, nil
+	}
+
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error: func(err error) {
+			pos := fset.Position(err.(types.Error).Pos)
+			r.errors = append(r.errors, diffPos(pos.Filename, uint(pos.Line), uint(pos.Column)))
+		},
+	}
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf.Check(files[0].Name.Name, fset, files, info)
+	sort.Strings(r.errors)
+
+	r.types = make(map[string]string)
+	record := func(id *ast.Ident, obj types.Object) {
+		if obj != nil && obj.Type() != nil {
+			pos := fset.Position(id.Pos())
+			r.types[diffPos(pos.Filename, uint(pos.Line), uint(pos.Column))] = canonicalType(obj.Type().String())
+		}
+	}
+	for id, obj := range info.Defs {
+		record(id, obj)
+	}
+	for id, obj := range info.Uses {
+		record(id, obj)
+	}
+	return r, nil
+}
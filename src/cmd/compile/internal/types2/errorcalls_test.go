@@ -10,7 +10,7 @@ import (
 )
 
 // TestErrorCalls makes sure that check.errorf calls have at
-// least 3 arguments (otherwise we should be using check.error).
+// least 4 arguments (otherwise we should be using check.error).
 func TestErrorCalls(t *testing.T) {
 	files, err := pkgFiles(".")
 	if err != nil {
@@ -30,10 +30,10 @@ func TestErrorCalls(t *testing.T) {
 			if !(isName(selx.X, "check") && isName(selx.Sel, "errorf")) {
 				return false
 			}
-			// check.errorf calls should have more than 2 arguments:
-			// position, format string, and arguments to format
-			if n := len(call.ArgList); n <= 2 {
-				t.Errorf("%s: got %d arguments, want > 2", call.Pos(), n)
+			// check.errorf calls should have more than 3 arguments:
+			// position, error code, format string, and arguments to format
+			if n := len(call.ArgList); n <= 3 {
+				t.Errorf("%s: got %d arguments, want > 3", call.Pos(), n)
 				return true
 			}
 			return false
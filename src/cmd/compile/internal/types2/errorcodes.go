@@ -0,0 +1,153 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2
+
+import "internal/types/errors"
+
+// An ErrorCode is a (constant) value uniquely identifying a specific error.
+// The error codes are defined in package internal/types/errors, which is
+// shared with go/types so that the codes reported by the two type checkers
+// remain aligned; see there for their documentation. Error code values are
+// stable: new codes are added at the end.
+type ErrorCode = errors.Code
+
+type errorCode = ErrorCode
+
+const (
+	_InvalidSyntaxTree        = errors.InvalidSyntaxTree
+	_Test                     = errors.Test
+	_BlankPkgName             = errors.BlankPkgName
+	_MismatchedPkgName        = errors.MismatchedPkgName
+	_InvalidPkgUse            = errors.InvalidPkgUse
+	_BadImportPath            = errors.BadImportPath
+	_BrokenImport             = errors.BrokenImport
+	_ImportCRenamed           = errors.ImportCRenamed
+	_UnusedImport             = errors.UnusedImport
+	_InvalidInitCycle         = errors.InvalidInitCycle
+	_DuplicateDecl            = errors.DuplicateDecl
+	_InvalidDeclCycle         = errors.InvalidDeclCycle
+	_InvalidTypeCycle         = errors.InvalidTypeCycle
+	_InvalidConstInit         = errors.InvalidConstInit
+	_InvalidConstVal          = errors.InvalidConstVal
+	_InvalidConstType         = errors.InvalidConstType
+	_UntypedNil               = errors.UntypedNil
+	_WrongAssignCount         = errors.WrongAssignCount
+	_UnassignableOperand      = errors.UnassignableOperand
+	_NoNewVar                 = errors.NoNewVar
+	_MultiValAssignOp         = errors.MultiValAssignOp
+	_InvalidIfaceAssign       = errors.InvalidIfaceAssign
+	_InvalidChanAssign        = errors.InvalidChanAssign
+	_IncompatibleAssign       = errors.IncompatibleAssign
+	_UnaddressableFieldAssign = errors.UnaddressableFieldAssign
+	_NotAType                 = errors.NotAType
+	_InvalidArrayLen          = errors.InvalidArrayLen
+	_BlankIfaceMethod         = errors.BlankIfaceMethod
+	_IncomparableMapKey       = errors.IncomparableMapKey
+	_InvalidIfaceEmbed        = errors.InvalidIfaceEmbed
+	_InvalidPtrEmbed          = errors.InvalidPtrEmbed
+	_BadRecv                  = errors.BadRecv
+	_InvalidRecv              = errors.InvalidRecv
+	_DuplicateFieldAndMethod  = errors.DuplicateFieldAndMethod
+	_DuplicateMethod          = errors.DuplicateMethod
+	_InvalidBlank             = errors.InvalidBlank
+	_InvalidIota              = errors.InvalidIota
+	_MissingInitBody          = errors.MissingInitBody
+	_InvalidInitSig           = errors.InvalidInitSig
+	_InvalidInitDecl          = errors.InvalidInitDecl
+	_InvalidMainDecl          = errors.InvalidMainDecl
+	_TooManyValues            = errors.TooManyValues
+	_NotAnExpr                = errors.NotAnExpr
+	_TruncatedFloat           = errors.TruncatedFloat
+	_NumericOverflow          = errors.NumericOverflow
+	_UndefinedOp              = errors.UndefinedOp
+	_MismatchedTypes          = errors.MismatchedTypes
+	_DivByZero                = errors.DivByZero
+	_NonNumericIncDec         = errors.NonNumericIncDec
+	_UnaddressableOperand     = errors.UnaddressableOperand
+	_InvalidIndirection       = errors.InvalidIndirection
+	_NonIndexableOperand      = errors.NonIndexableOperand
+	_InvalidIndex             = errors.InvalidIndex
+	_SwappedSliceIndices      = errors.SwappedSliceIndices
+	_NonSliceableOperand      = errors.NonSliceableOperand
+	_InvalidSliceExpr         = errors.InvalidSliceExpr
+	_InvalidShiftCount        = errors.InvalidShiftCount
+	_InvalidShiftOperand      = errors.InvalidShiftOperand
+	_InvalidReceive           = errors.InvalidReceive
+	_InvalidSend              = errors.InvalidSend
+	_DuplicateLitKey          = errors.DuplicateLitKey
+	_MissingLitKey            = errors.MissingLitKey
+	_InvalidLitIndex          = errors.InvalidLitIndex
+	_OversizeArrayLit         = errors.OversizeArrayLit
+	_MixedStructLit           = errors.MixedStructLit
+	_InvalidStructLit         = errors.InvalidStructLit
+	_MissingLitField          = errors.MissingLitField
+	_DuplicateLitField        = errors.DuplicateLitField
+	_UnexportedLitField       = errors.UnexportedLitField
+	_InvalidLitField          = errors.InvalidLitField
+	_UntypedLit               = errors.UntypedLit
+	_InvalidLit               = errors.InvalidLit
+	_AmbiguousSelector        = errors.AmbiguousSelector
+	_UndeclaredImportedName   = errors.UndeclaredImportedName
+	_UnexportedName           = errors.UnexportedName
+	_UndeclaredName           = errors.UndeclaredName
+	_MissingFieldOrMethod     = errors.MissingFieldOrMethod
+	_BadDotDotDotSyntax       = errors.BadDotDotDotSyntax
+	_NonVariadicDotDotDot     = errors.NonVariadicDotDotDot
+	_MisplacedDotDotDot       = errors.MisplacedDotDotDot
+	_InvalidDotDotDot         = errors.InvalidDotDotDot
+	_UncalledBuiltin          = errors.UncalledBuiltin
+	_InvalidAppend            = errors.InvalidAppend
+	_InvalidCap               = errors.InvalidCap
+	_InvalidClose             = errors.InvalidClose
+	_InvalidCopy              = errors.InvalidCopy
+	_InvalidComplex           = errors.InvalidComplex
+	_InvalidDelete            = errors.InvalidDelete
+	_InvalidImag              = errors.InvalidImag
+	_InvalidLen               = errors.InvalidLen
+	_SwappedMakeArgs          = errors.SwappedMakeArgs
+	_InvalidMake              = errors.InvalidMake
+	_InvalidReal              = errors.InvalidReal
+	_InvalidAssert            = errors.InvalidAssert
+	_ImpossibleAssert         = errors.ImpossibleAssert
+	_InvalidConversion        = errors.InvalidConversion
+	_InvalidUntypedConversion = errors.InvalidUntypedConversion
+	_BadOffsetofSyntax        = errors.BadOffsetofSyntax
+	_InvalidOffsetof          = errors.InvalidOffsetof
+	_UnusedExpr               = errors.UnusedExpr
+	_UnusedVar                = errors.UnusedVar
+	_MissingReturn            = errors.MissingReturn
+	_WrongResultCount         = errors.WrongResultCount
+	_OutOfScopeResult         = errors.OutOfScopeResult
+	_InvalidCond              = errors.InvalidCond
+	_InvalidPostDecl          = errors.InvalidPostDecl
+	_InvalidIterVar           = errors.InvalidIterVar
+	_InvalidRangeExpr         = errors.InvalidRangeExpr
+	_MisplacedBreak           = errors.MisplacedBreak
+	_MisplacedContinue        = errors.MisplacedContinue
+	_MisplacedFallthrough     = errors.MisplacedFallthrough
+	_DuplicateCase            = errors.DuplicateCase
+	_DuplicateDefault         = errors.DuplicateDefault
+	_BadTypeKeyword           = errors.BadTypeKeyword
+	_InvalidTypeSwitch        = errors.InvalidTypeSwitch
+	_InvalidExprSwitch        = errors.InvalidExprSwitch
+	_InvalidSelectCase        = errors.InvalidSelectCase
+	_UndeclaredLabel          = errors.UndeclaredLabel
+	_DuplicateLabel           = errors.DuplicateLabel
+	_MisplacedLabel           = errors.MisplacedLabel
+	_UnusedLabel              = errors.UnusedLabel
+	_JumpOverDecl             = errors.JumpOverDecl
+	_JumpIntoBlock            = errors.JumpIntoBlock
+	_InvalidMethodExpr        = errors.InvalidMethodExpr
+	_WrongArgCount            = errors.WrongArgCount
+	_InvalidCall              = errors.InvalidCall
+	_UnusedResults            = errors.UnusedResults
+	_InvalidDefer             = errors.InvalidDefer
+	_InvalidGo                = errors.InvalidGo
+	_BadDecl                  = errors.BadDecl
+	_RepeatedDecl             = errors.RepeatedDecl
+	_InvalidUnsafeAdd         = errors.InvalidUnsafeAdd
+	_InvalidUnsafeSlice       = errors.InvalidUnsafeSlice
+	_Todo                     = errors.Todo
+)
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"cmd/compile/internal/syntax"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -32,12 +33,15 @@ func unreachable() {
 // To report an error_, call Checker.report.
 type error_ struct {
 	desc []errorDesc
-	soft bool // TODO(gri) eventually determine this from an error code
+	code errorCode
+	soft bool          // TODO(gri) eventually determine this from an error code
+	fix  *SuggestedFix // suggested fix, or nil
 }
 
 // An errorDesc describes part of a type-checking error.
 type errorDesc struct {
 	pos    syntax.Pos
+	end    syntax.Pos // end of the source range, or nopos
 	format string
 	args   []interface{}
 }
@@ -53,6 +57,14 @@ func (err *error_) pos() syntax.Pos {
 	return err.desc[0].pos
 }
 
+// span returns the source range of err's primary position.
+func (err *error_) span() posSpan {
+	if err.empty() {
+		return posSpan{nopos, nopos}
+	}
+	return posSpan{err.desc[0].pos, err.desc[0].end}
+}
+
 func (err *error_) msg(qf Qualifier) string {
 	if err.empty() {
 		return "no error"
@@ -68,6 +80,26 @@ func (err *error_) msg(qf Qualifier) string {
 	return buf.String()
 }
 
+// primaryMsg returns the message msg of an error with the secondary
+// positions related without their descriptions, which error_.msg
+// appends to the primary message on separate lines.
+func primaryMsg(msg string, related []RelatedPos) string {
+	var suffix strings.Builder
+	for _, r := range related {
+		fmt.Fprintf(&suffix, "\n\t%s: %s", r.Pos, r.Msg)
+	}
+	return strings.TrimSuffix(msg, suffix.String())
+}
+
+// related returns the secondary positions of err, if any.
+func (err *error_) related(qf Qualifier) []RelatedPos {
+	var list []RelatedPos
+	for _, p := range err.desc[1:] {
+		list = append(list, RelatedPos{p.pos, stripAnnotations(sprintf(qf, p.format, p.args...))})
+	}
+	return list
+}
+
 // String is for testing.
 func (err *error_) String() string {
 	if err.empty() {
@@ -79,7 +111,7 @@ func (err *error_) String() string {
 // errorf adds formatted error information to err.
 // It may be called multiple times to provide additional information.
 func (err *error_) errorf(at poser, format string, args ...interface{}) {
-	err.desc = append(err.desc, errorDesc{posFor(at), format, args})
+	err.desc = append(err.desc, errorDesc{posFor(at), endPosFor(at), format, args})
 }
 
 func sprintf(qf Qualifier, format string, args ...interface{}) string {
@@ -99,6 +131,11 @@ func sprintf(qf Qualifier, format string, args ...interface{}) string {
 			arg = ObjectString(a, qf)
 		case Type:
 			arg = TypeString(a, qf)
+		case *Term:
+			arg = TypeString(a.typ, qf)
+			if a.tilde {
+				arg = "~" + arg.(string)
+			}
 		}
 		args[i] = arg
 	}
@@ -150,7 +187,7 @@ func (check *Checker) report(err *error_) {
 	if err.empty() {
 		panic("no error to report")
 	}
-	check.err(err.pos(), err.msg(check.qualifier), err.soft)
+	check.err(err.span(), err.code, err.msg(check.qualifier), err.soft, err.fix, err.related(check.qualifier))
 }
 
 func (check *Checker) trace(pos syntax.Pos, format string, args ...interface{}) {
@@ -166,7 +203,27 @@ func (check *Checker) dump(format string, args ...interface{}) {
 	fmt.Println(check.sprintf(format, args...))
 }
 
-func (check *Checker) err(at poser, msg string, soft bool) {
+// unusedf reports an unused variable or import. If Config.TolerateUnused
+// is set, the error is reported to Config.Error only; it doesn't count as
+// a type checking error.
+func (check *Checker) unusedf(at poser, code errorCode, format string, args ...interface{}) {
+	if !check.conf.TolerateUnused {
+		check.softErrorf(at, code, format, args...)
+		return
+	}
+
+	msg := check.sprintf(format, args...)
+	pos := posFor(at)
+	if check.conf.Trace {
+		check.trace(pos, "UNUSED: %s", msg)
+	}
+	if check.conf.Error != nil {
+		msg0 := stripAnnotations(msg)
+		check.handleError(Error{pos, endPosFor(at), msg0, msg, true, true, nil, code, nil, msg0}, true)
+	}
+}
+
+func (check *Checker) err(at poser, code errorCode, msg string, soft bool, fix *SuggestedFix, related []RelatedPos) {
 	// Cheap trick: Don't report errors with messages containing
 	// "invalid operand" or "invalid type" as those tend to be
 	// follow-on errors which don't add useful information. Only
@@ -176,7 +233,7 @@ func (check *Checker) err(at poser, msg string, soft bool) {
 		return
 	}
 
-	pos := posFor(at)
+	pos, end := posFor(at), endPosFor(at)
 
 	// If we are encountering an error while evaluating an inherited
 	// constant initialization expression, pos is the position of in
@@ -186,23 +243,98 @@ func (check *Checker) err(at poser, msg string, soft bool) {
 	// refer to the position (pos) in the original expression.
 	if check.errpos.IsKnown() {
 		assert(check.iota != nil)
-		pos = check.errpos
+		pos, end = check.errpos, nopos
 	}
 
-	err := Error{pos, stripAnnotations(msg), msg, soft}
-	if check.firstErr == nil {
+	msg0 := stripAnnotations(msg)
+	err := Error{pos, end, msg0, msg, soft, false, fix, code, related, primaryMsg(msg0, related)}
+	if soft {
+		switch check.conf.Severity[code] {
+		case SeverityWarning:
+			err.Warning = true
+		case SeverityIgnore:
+			return
+		}
+	}
+	if check.firstErr == nil && !err.Warning {
 		check.firstErr = err
 	}
 
 	if check.conf.Trace {
 		check.trace(pos, "ERROR: %s", msg)
 	}
+	if check.conf.TraceWriter != nil {
+		check.traceEvent("error", "", pos, msg0)
+	}
 
-	f := check.conf.Error
-	if f == nil {
+	if check.conf.Error == nil {
+		if err.Warning {
+			return
+		}
 		panic(bailout{}) // report only first error
 	}
-	f(err)
+	check.handleError(err, err.Warning)
+}
+
+// A pendingError is an error to be passed to Config.Error later.
+type pendingError struct {
+	err       Error
+	tolerated bool // err doesn't count as a type checking error (see Error.Warning)
+}
+
+// handleError passes err to Config.Error, which must not be nil, unless
+// err is to be reported later: if function bodies are checked concurrently
+// (see Checker.checkFuncBodies), or if Config.SortErrors is set. Checking
+// stops once Config.MaxErrors errors that are not tolerated are handled.
+func (check *Checker) handleError(err Error, tolerated bool) {
+	switch {
+	case check.worker != nil:
+		b := check.worker.body
+		b.errors = append(b.errors, pendingError{err, tolerated})
+	case check.conf.SortErrors:
+		check.pending = append(check.pending, pendingError{err, tolerated})
+	default:
+		check.conf.Error(err)
+	}
+
+	if !tolerated {
+		check.nerrors++
+		if max := check.conf.MaxErrors; max > 0 && check.nerrors >= max {
+			panic(bailout{}) // report only the first max errors
+		}
+	}
+}
+
+// reportPending passes the errors collected for Config.SortErrors to
+// Config.Error, sorted by position. An error whose message starts with
+// a tab character continues the preceding error and stays with it.
+// The first sorted error that is not tolerated becomes the first error
+// of check.
+func (check *Checker) reportPending() {
+	var groups [][]pendingError
+	for _, e := range check.pending {
+		if n := len(groups); n > 0 && strings.HasPrefix(e.err.Msg, "\t") {
+			groups[n-1] = append(groups[n-1], e)
+		} else {
+			groups = append(groups, []pendingError{e})
+		}
+	}
+	check.pending = nil
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i][0].err.Pos.Cmp(groups[j][0].err.Pos) < 0
+	})
+
+	first := check.firstErr != nil
+	for _, g := range groups {
+		for _, e := range g {
+			if first && !e.tolerated {
+				check.firstErr = e.err
+				first = false
+			}
+			check.conf.Error(e.err)
+		}
+	}
 }
 
 const (
@@ -215,16 +347,30 @@ type poser interface {
 	Pos() syntax.Pos
 }
 
-func (check *Checker) error(at poser, msg string) {
-	check.err(at, msg, false)
+func (check *Checker) error(at poser, code errorCode, msg string) {
+	check.err(at, code, msg, false, nil, nil)
 }
 
-func (check *Checker) errorf(at poser, format string, args ...interface{}) {
-	check.err(at, check.sprintf(format, args...), false)
+func (check *Checker) errorf(at poser, code errorCode, format string, args ...interface{}) {
+	check.err(at, code, check.sprintf(format, args...), false, nil, nil)
 }
 
-func (check *Checker) softErrorf(at poser, format string, args ...interface{}) {
-	check.err(at, check.sprintf(format, args...), true)
+func (check *Checker) softErrorf(at poser, code errorCode, format string, args ...interface{}) {
+	check.err(at, code, check.sprintf(format, args...), true, nil, nil)
+}
+
+// A posSpan is a poser for the source range from start to end, used to
+// report an error for a range which doesn't correspond to a single node.
+type posSpan struct {
+	start, end syntax.Pos
+}
+
+func (s posSpan) Pos() syntax.Pos { return s.start }
+
+// spanOf returns the source range of the node n, starting at start rather
+// than at the start of n.
+func spanOf(start syntax.Pos, n syntax.Node) posSpan {
+	return posSpan{start, syntax.EndPos(n)}
 }
 
 // posFor reports the left (= start) position of at.
@@ -240,6 +386,22 @@ func posFor(at poser) syntax.Pos {
 	return at.Pos()
 }
 
+// endPosFor reports the approximate end position of at (see syntax.EndPos),
+// or nopos if at is a plain position.
+func endPosFor(at poser) syntax.Pos {
+	switch x := at.(type) {
+	case posSpan:
+		return x.end
+	case *operand:
+		if x.expr != nil {
+			return syntax.EndPos(x.expr)
+		}
+	case syntax.Node:
+		return syntax.EndPos(x)
+	}
+	return nopos
+}
+
 // stripAnnotations removes internal (type) annotations from s.
 func stripAnnotations(s string) string {
 	// Would like to use strings.Builder but it's not available in Go 1.4.
@@ -4,7 +4,11 @@
 
 package types2
 
-import "testing"
+import (
+	"cmd/compile/internal/syntax"
+	"strings"
+	"testing"
+)
 
 func TestError(t *testing.T) {
 	var err error_
@@ -43,3 +47,78 @@ func TestStripAnnotations(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorCodeAndRelated(t *testing.T) {
+	const src = `package p
+
+type I interface {
+	m()
+	m()
+}
+`
+	f, err := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []Error
+	conf := Config{Error: func(err error) { errs = append(errs, err.(Error)) }}
+	conf.Check("p", []*syntax.File{f}, nil)
+
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors; want 1", len(errs))
+	}
+	e := errs[0]
+	if e.Code != _DuplicateDecl {
+		t.Errorf("got error code %d; want %d", e.Code, _DuplicateDecl)
+	}
+	if got, want := e.Pos.String(), "p.go:5:2"; got != want {
+		t.Errorf("got error position %s; want %s", got, want)
+	}
+	if len(e.Related) != 1 {
+		t.Fatalf("got %d related positions; want 1", len(e.Related))
+	}
+	r := e.Related[0]
+	if got, want := r.Pos.String()+": "+r.Msg, "p.go:4:2: other declaration of m"; got != want {
+		t.Errorf("got related position %q; want %q", got, want)
+	}
+	if got, want := e.Primary, "duplicate method m"; got != want {
+		t.Errorf("got primary message %q; want %q", got, want)
+	}
+	if got, want := e.Msg, "duplicate method m\n\tp.go:4:2: other declaration of m"; got != want {
+		t.Errorf("got message %q; want %q", got, want)
+	}
+}
+
+func TestErrorEnd(t *testing.T) {
+	const src = `package p
+
+type myInt int
+
+type _ interface{ ~string | ~myInt }
+
+var _ int = "foo" + "bar"
+
+var _ = undefined
+`
+	f, err := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []Error
+	conf := Config{Error: func(err error) { errs = append(errs, err.(Error)) }}
+	conf.Check("p", []*syntax.File{f}, nil)
+
+	want := []string{
+		"p.go:7:13-p.go:7:26: cannot use",
+		"p.go:9:9-p.go:9:18: undeclared name",
+		"p.go:5:29-p.go:5:35: invalid use of ~", // entire term, reported later
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors; want %d", len(errs), len(want))
+	}
+	for i, e := range errs {
+		if got := e.Pos.String() + "-" + e.End.String() + ": " + e.Msg; !strings.HasPrefix(got, want[i]) {
+			t.Errorf("got %q; want prefix %q", got, want[i])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements ExplainImplements.
+
+package types2
+
+import "strings"
+
+// A ReasonKind describes the kind of an ImplementsReason.
+type ReasonKind int
+
+const (
+	// NotImplemented is the kind of the root of a reason tree; the
+	// individual reasons are its children.
+	NotImplemented ReasonKind = iota
+
+	// MethodMissing: V has no method with the Id of Method. Impl is a
+	// method of V (or *V) with a similar name, if there is one.
+	MethodMissing
+
+	// MethodWrongSignature: V's method Impl has a different signature
+	// than Method.
+	MethodWrongSignature
+
+	// MethodPtrRecv: the method Impl has a pointer receiver and is
+	// therefore in the method set of *V but not of V.
+	MethodPtrRecv
+
+	// TermExcluded: V is not in the type set described by the type
+	// terms of T. If V is a type parameter, Term is a term of V's type
+	// set that is not in the type set of T.
+	TermExcluded
+
+	// NotComparable: T is or embeds comparable, and V is not comparable.
+	NotComparable
+)
+
+// An ImplementsReason describes why a type V does not implement an
+// interface T, as computed by ExplainImplements. The reasons form a tree:
+// the root, of kind NotImplemented, has one child for each requirement
+// of T that V doesn't meet.
+type ImplementsReason struct {
+	Kind     ReasonKind
+	V        Type
+	T        *Interface
+	Method   *Func // method of T, for method reasons
+	Impl     *Func // method of V or *V, for method reasons; or nil
+	Term     *Term // for TermExcluded, or nil
+	Children []*ImplementsReason
+}
+
+// ExplainImplements returns nil if type V implements interface T, and
+// the reasons why V does not implement T otherwise. If T is a constraint
+// interface, V must also be in the type set of T, as for Satisfies
+// (without the requirement of ordered types).
+func ExplainImplements(V Type, T *Interface) *ImplementsReason {
+	root := &ImplementsReason{Kind: NotImplemented, V: V, T: T}
+	add := func(kind ReasonKind) *ImplementsReason {
+		r := &ImplementsReason{Kind: kind, V: V, T: T}
+		root.Children = append(root.Children, r)
+		return r
+	}
+
+	tset := T.typeSet()
+	if tset.comparable && !Comparable(V) {
+		add(NotComparable)
+	}
+
+	for _, w := range ImplementsWitness(V, T) {
+		switch {
+		case w.Impl == nil:
+			r := add(MethodMissing)
+			r.Method = w.Method
+			r.Impl = w.Similar
+		case w.WrongType:
+			r := add(MethodWrongSignature)
+			r.Method = w.Method
+			r.Impl = w.Impl
+		case w.PtrRecv:
+			r := add(MethodPtrRecv)
+			r.Method = w.Method
+			r.Impl = w.Impl
+		}
+	}
+
+	if tset.hasTerms() && !tset.partial {
+		if tpar := asTypeParam(V); tpar != nil {
+			vset := tpar.iface().typeSet()
+			if !vset.hasTerms() {
+				add(TermExcluded)
+			} else {
+				for _, t := range vset.terms {
+					if !(termlist{t}).subsetOf(tset.terms) {
+						add(TermExcluded).Term = (*Term)(t)
+						break
+					}
+				}
+			}
+		} else if !tset.includes(V) {
+			add(TermExcluded)
+		}
+	}
+
+	if len(root.Children) == 0 {
+		return nil
+	}
+	return root
+}
+
+// String returns the message for r, as for r.Message(nil).
+func (r *ImplementsReason) String() string { return r.Message(nil) }
+
+// Message returns a multi-line description of r and its children, with
+// one line per reason (and signature, for MethodWrongSignature). Each line
+// of a child is indented by a tab. Types and objects are qualified by qf.
+func (r *ImplementsReason) Message(qf Qualifier) string {
+	var buf strings.Builder
+	r.write(&buf, qf, "")
+	return buf.String()
+}
+
+func (r *ImplementsReason) write(buf *strings.Builder, qf Qualifier, indent string) {
+	if buf.Len() > 0 {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(indent)
+	switch r.Kind {
+	case NotImplemented:
+		buf.WriteString(sprintf(qf, "%s does not implement %s", r.V, r.T))
+	case MethodMissing:
+		buf.WriteString(sprintf(qf, "missing method %s", r.Method.name))
+		if r.Impl != nil {
+			buf.WriteString(sprintf(qf, "; did you mean %s?", r.Impl.name))
+		}
+	case MethodWrongSignature:
+		buf.WriteString(sprintf(qf, "wrong type for method %s\n%s\thave %s\n%s\twant %s",
+			r.Method.name, indent, r.Impl, indent, r.Method))
+	case MethodPtrRecv:
+		buf.WriteString(sprintf(qf, "method %s has pointer receiver", r.Method.name))
+	case TermExcluded:
+		if r.Term != nil {
+			buf.WriteString(sprintf(qf, "term %s of %s is not in the type set of %s", r.Term, r.V, r.T))
+		} else {
+			buf.WriteString(sprintf(qf, "%s is not in the type set of %s", r.V, r.T))
+		}
+	case NotComparable:
+		buf.WriteString(sprintf(qf, "%s is not comparable", r.V))
+	}
+	for _, c := range r.Children {
+		c.write(buf, qf, indent+"\t")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements MinimalInterface.
+
+package types2
+
+import (
+	"cmd/compile/internal/syntax"
+	"fmt"
+)
+
+// MinimalInterface returns the smallest interface covering the uses of
+// type T at the selector expressions sels, as recorded in info.Selections.
+// The result contains exactly the methods selected, directly or through
+// embedded fields, on operands of type T or *T; selector expressions that
+// are not recorded in info.Selections or select on an operand of another
+// type are ignored. The method signatures are those of the selected
+// methods, with any receiver type parameters substituted.
+//
+// If the uses include methods with pointer receivers (selected on operands
+// of type *T or on addressable operands of type T), the result is
+// implemented by *T but not by T.
+//
+// If one of the selections is a field selection, the usage cannot be
+// covered by an interface and the result is an error.
+func MinimalInterface(info *Info, T Type, sels []*syntax.SelectorExpr) (*Interface, error) {
+	base, _ := deref(T)
+	var methods []*Func
+	seen := make(map[string]bool)
+	for _, e := range sels {
+		sel := info.Selections[e]
+		if sel == nil {
+			continue
+		}
+		if recv, _ := deref(sel.recv); !Identical(recv, base) {
+			continue
+		}
+		switch sel.kind {
+		case FieldVal:
+			return nil, fmt.Errorf("%s: %s selects field %s of %s", e.Pos(), syntax.String(e), sel.obj.Name(), T)
+		case MethodVal, MethodExpr:
+			m := sel.obj.(*Func)
+			if id := m.Id(); !seen[id] {
+				seen[id] = true
+				sig := m.typ.(*Signature)
+				methods = append(methods, NewFunc(m.pos, m.pkg, m.name, NewSignature(nil, sig.params, sig.results, sig.variadic)))
+			}
+		}
+	}
+	return NewInterfaceType(methods, nil), nil
+}
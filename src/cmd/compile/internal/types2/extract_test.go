@@ -0,0 +1,106 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/syntax"
+	. "cmd/compile/internal/types2"
+)
+
+func TestMinimalInterface(t *testing.T) {
+	const src = genericPkg + `p
+
+type E struct{}
+
+func (E) Close() error { return nil }
+
+type T struct {
+	E
+	x int
+}
+
+func (T) Read(p []byte) (int, error) { return 0, nil }
+func (*T) Write(p []byte) (int, error) { return 0, nil }
+func (T) Unused() {}
+
+type G[P any] struct{}
+
+func (G[P]) Get() P { var p P; return p }
+
+type U struct{}
+
+func (U) Other() {}
+
+func _(t T, p *T, g G[int], u U) {
+	t.Read(nil)
+	p.Write(nil)
+	t.Close()
+	_ = T.Read
+	u.Other()
+	_ = g.Get()
+}
+
+func _(t T) int {
+	return t.x
+}
+`
+	info := &Info{Selections: make(map[*syntax.SelectorExpr]*Selection)}
+	pkg, err := pkgFor("extract", src, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := pkg.Scope()
+
+	// sels returns the recorded selector expressions, excluding
+	// field selections if skipFields is set.
+	sels := func(skipFields bool) []*syntax.SelectorExpr {
+		var list []*syntax.SelectorExpr
+		for e, sel := range info.Selections {
+			if skipFields && sel.Kind() == FieldVal {
+				continue
+			}
+			list = append(list, e)
+		}
+		return list
+	}
+
+	T := scope.Lookup("T").Type()
+	for _, typ := range []Type{T, NewPointer(T)} {
+		iface, err := MinimalInterface(info, typ, sels(true))
+		if err != nil {
+			t.Fatalf("%s: %v", typ, err)
+		}
+		const want = "interface{Close() error; Read(p []byte) (int, error); Write(p []byte) (int, error)}"
+		if got := iface.String(); got != want {
+			t.Errorf("%s: got %s; want %s", typ, got, want)
+		}
+		if Implements(T, iface) {
+			t.Errorf("%s: T must not implement %s", typ, iface)
+		}
+		if !Implements(NewPointer(T), iface) {
+			t.Errorf("%s: *T must implement %s", typ, iface)
+		}
+	}
+
+	// Generic types are matched by their instantiations.
+	G, err := Instantiate(nil, scope.Lookup("G").Type(), []Type{Typ[Int]}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := MinimalInterface(info, G, sels(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := iface.String(), "interface{Get() int}"; got != want {
+		t.Errorf("G[int]: got %s; want %s", got, want)
+	}
+
+	// Field selections cannot be covered by an interface.
+	if _, err := MinimalInterface(info, T, sels(false)); err == nil {
+		t.Errorf("expected error for field selection")
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements the concurrent type-checking of function
+// bodies (see Config.ConcurrentFuncBodies).
+
+package types2
+
+import (
+	"cmd/compile/internal/syntax"
+	"go/constant"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// A funcBody describes the body of a function declaration that is
+// type-checked concurrently with other function bodies.
+type funcBody struct {
+	decl *declInfo
+	name string
+	sig  *Signature
+	body *syntax.BlockStmt
+
+	// set once the body is checked
+	errors   []pendingError // errors to be passed to Config.Error, in order
+	firstErr error          // first error, if no error was reported before the body was checked
+	stopped  bool           // checking stopped at an error (see Config.Error, Config.MaxErrors)
+	panic    interface{}    // panic other than a bailout, or nil
+}
+
+// A bodyWorker holds the state of a Checker which type-checks function
+// bodies concurrently with other Checkers for the same package.
+type bodyWorker struct {
+	body     *funcBody         // body currently checked
+	usedPkgs map[*PkgName]bool // package names used, marked as used once all bodies are checked
+}
+
+// laterFuncBody arranges for body to be type-checked once all
+// package-level declarations are checked: concurrently with the other
+// function bodies if Config.ConcurrentFuncBodies is set, and as a delayed
+// action otherwise.
+func (check *Checker) laterFuncBody(decl *declInfo, name string, sig *Signature, body *syntax.BlockStmt) {
+	if check.conf.ConcurrentFuncBodies && !check.conf.Trace && check.conf.TraceWriter == nil {
+		check.bodies = append(check.bodies, &funcBody{decl: decl, name: name, sig: sig, body: body})
+		return
+	}
+	check.later(func() {
+		check.funcBody(decl, name, sig, body, nil)
+	})
+}
+
+// usePkgName marks the package name pkgName as used.
+func (check *Checker) usePkgName(pkgName *PkgName) {
+	if w := check.worker; w != nil {
+		w.usedPkgs[pkgName] = true // pkgName is shared with other workers
+		return
+	}
+	pkgName.used = true
+}
+
+// checkFuncBodies type-checks the function bodies collected in
+// check.bodies, using up to GOMAXPROCS goroutines. It must be called
+// once all delayed actions are processed, so that package-level types
+// are complete and not modified any further while the bodies are checked.
+//
+// Each goroutine checks bodies with its own Checker, which records type
+// information in its own Info; the information is added to check.Info
+// once all bodies are checked. Errors are reported in the order of the
+// function declarations, and type-checking stops at the same error as
+// it would if the bodies were checked one after the other (if Config.Error
+// is nil or Config.MaxErrors is set).
+func (check *Checker) checkFuncBodies() {
+	bodies := check.bodies
+	check.bodies = nil
+
+	// The types created so far may be used by all workers. Detach them
+	// from check, so that their lazily computed parts are computed in a
+	// synchronized way (see Checker.releaseTypes).
+	check.releaseTypes()
+
+	n := runtime.GOMAXPROCS(0)
+	if n > len(bodies) {
+		n = len(bodies)
+	}
+	workers := make([]*Checker, n)
+	var next int32    // index of the next body to check
+	var stopped int32 // set once checking stopped for a body
+	var wg sync.WaitGroup
+	for i := range workers {
+		w := check.bodyChecker()
+		workers[i] = w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Bodies are taken in order: once a body stops checking,
+			// all preceding bodies have been started and are completed.
+			for atomic.LoadInt32(&stopped) == 0 {
+				i := int(atomic.AddInt32(&next, 1)) - 1
+				if i >= len(bodies) {
+					break
+				}
+				b := bodies[i]
+				w.checkFuncBody(b, check.firstErr)
+				if b.stopped || b.panic != nil {
+					atomic.StoreInt32(&stopped, 1)
+				}
+			}
+			w.recordUntyped()
+			w.releaseTypes()
+		}()
+	}
+	wg.Wait()
+
+	for _, w := range workers {
+		check.merge(w)
+	}
+	for _, b := range bodies {
+		if b.panic != nil {
+			panic(b.panic)
+		}
+		if check.firstErr == nil {
+			check.firstErr = b.firstErr
+		}
+		for _, e := range b.errors {
+			check.handleError(e.err, e.tolerated)
+		}
+		if b.stopped {
+			panic(bailout{})
+		}
+	}
+}
+
+// bodyChecker returns a new Checker for checking function bodies of
+// check's package concurrently with other body Checkers. It shares the
+// state of check which is not modified while function bodies are checked.
+func (check *Checker) bodyChecker() *Checker {
+	wcheck := &Checker{
+		conf:         check.conf,
+		pkg:          check.pkg,
+		Info:         newInfoFor(check.Info),
+		version:      check.version,
+		fileVersions: check.fileVersions,
+		nextID:       check.nextID,
+		objMap:       check.objMap,
+		impMap:       check.impMap,
+		typMap:       make(map[string]*Named, len(check.typMap)),
+		predecl:      check.predecl,
+		targets:      check.targets,
+		files:        check.files,
+		imports:      check.imports,
+		dotImportMap: check.dotImportMap,
+		methods:      check.methods,
+		methodsKnown: check.methodsKnown,
+		worker:       &bodyWorker{usedPkgs: make(map[*PkgName]bool)},
+	}
+	for h, inst := range check.typMap {
+		wcheck.typMap[h] = inst
+	}
+	if check.targets != nil {
+		wcheck.targetVals = make(map[syntax.Expr][]constant.Value)
+		wcheck.targetConsts = make(map[*Const][]constant.Value, len(check.targetConsts))
+		for obj, vals := range check.targetConsts {
+			wcheck.targetConsts[obj] = vals
+		}
+	}
+	return wcheck
+}
+
+// checkFuncBody type-checks the function body b. The error firstErr is
+// the first error reported before function bodies are checked, if any.
+func (check *Checker) checkFuncBody(b *funcBody, firstErr error) {
+	check.worker.body = b
+	check.firstErr = firstErr
+	check.nerrors = 0
+	check.delayed = nil
+	check.objPath = nil
+
+	defer func() {
+		switch p := recover().(type) {
+		case nil:
+			// done
+		case bailout:
+			b.stopped = true
+		default:
+			b.panic = p
+		}
+		if firstErr == nil {
+			b.firstErr = check.firstErr
+		}
+	}()
+
+	check.funcBody(b.decl, b.name, b.sig, b.body, nil)
+	check.processDelayed(0)
+}
+
+// merge adds the information collected by the body Checker w to check.
+// Instances created by w are not added to check.typMap.
+func (check *Checker) merge(w *Checker) {
+	check.Info.merge(w.Info)
+	for x, vals := range w.targetVals {
+		check.targetVals[x] = vals
+	}
+	for obj, vals := range w.targetConsts {
+		check.targetConsts[obj] = vals
+	}
+	for id, obj := range w.names {
+		if check.names == nil {
+			check.names = make(map[*syntax.Name]Object)
+		}
+		check.names[id] = obj
+	}
+	check.declTParams = append(check.declTParams, w.declTParams...)
+	for tpar := range w.usedTParams {
+		if check.usedTParams == nil {
+			check.usedTParams = make(map[*TypeParam]bool)
+		}
+		check.usedTParams[tpar] = true
+	}
+	for pkgName := range w.worker.usedPkgs {
+		pkgName.used = true
+	}
+	check.counters.Instances += w.counters.Instances
+	check.counters.Interfaces += w.counters.Interfaces
+	check.counters.Unions += w.counters.Unions
+	check.counters.Delayed += w.counters.Delayed
+}
+
+// newInfoFor returns a new Info which records the same kinds of
+// information as info: its maps are non-nil exactly if those of info are.
+// InitOrder is not recorded.
+func newInfoFor(info *Info) *Info {
+	res := new(Info)
+	if info.Types != nil {
+		res.Types = make(map[syntax.Expr]TypeAndValue)
+	}
+	if info.Inferred != nil {
+		res.Inferred = make(map[syntax.Expr]Inferred)
+	}
+	if info.TargetValues != nil {
+		res.TargetValues = make(map[syntax.Expr][]constant.Value)
+	}
+	if info.Defs != nil {
+		res.Defs = make(map[*syntax.Name]Object)
+	}
+	if info.Uses != nil {
+		res.Uses = make(map[*syntax.Name]Object)
+	}
+	if info.Deprecated != nil {
+		res.Deprecated = make(map[*syntax.Name]Object)
+	}
+	if info.Implicits != nil {
+		res.Implicits = make(map[syntax.Node]Object)
+	}
+	if info.Selections != nil {
+		res.Selections = make(map[*syntax.SelectorExpr]*Selection)
+	}
+	if info.Conversions != nil {
+		res.Conversions = make(map[syntax.Expr]Conversion)
+	}
+	if info.ImplicitOps != nil {
+		res.ImplicitOps = make(map[syntax.Expr]ImplicitOp)
+	}
+	if info.ImplicitInterfaces != nil {
+		res.ImplicitInterfaces = make(map[syntax.Expr]*Interface)
+	}
+	if info.NonNil != nil {
+		res.NonNil = make(map[syntax.Expr]bool)
+	}
+	if info.DeclaredTypes != nil {
+		res.DeclaredTypes = make(map[syntax.Node]Type)
+	}
+	if info.Scopes != nil {
+		res.Scopes = make(map[syntax.Node]*Scope)
+	}
+	return res
+}
+
+// merge adds the map entries of other, which must have been created
+// by newInfoFor(info), to info.
+func (info *Info) merge(other *Info) {
+	for x, tv := range other.Types {
+		info.Types[x] = tv
+	}
+	for x, inf := range other.Inferred {
+		info.Inferred[x] = inf
+	}
+	for x, vals := range other.TargetValues {
+		info.TargetValues[x] = vals
+	}
+	for id, obj := range other.Defs {
+		info.Defs[id] = obj
+	}
+	for id, obj := range other.Uses {
+		info.Uses[id] = obj
+	}
+	for id, obj := range other.Deprecated {
+		info.Deprecated[id] = obj
+	}
+	for n, obj := range other.Implicits {
+		info.Implicits[n] = obj
+	}
+	for x, sel := range other.Selections {
+		info.Selections[x] = sel
+	}
+	for x, conv := range other.Conversions {
+		info.Conversions[x] = conv
+	}
+	for x, op := range other.ImplicitOps {
+		info.ImplicitOps[x] = op
+	}
+	for x, ityp := range other.ImplicitInterfaces {
+		info.ImplicitInterfaces[x] = ityp
+	}
+	for x := range other.NonNil {
+		info.NonNil[x] = true
+	}
+	for n, typ := range other.DeclaredTypes {
+		info.DeclaredTypes[n] = typ
+	}
+	for n, scope := range other.Scopes {
+		info.Scopes[n] = scope
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"cmd/compile/internal/syntax"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "cmd/compile/internal/types2"
+)
+
+func TestConcurrentFuncBodies(t *testing.T) {
+	files, err := pkgFiles(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(concurrent bool) (string, []string) {
+		conf := Config{Importer: defaultImporter(), ConcurrentFuncBodies: concurrent}
+		info := &Info{
+			Types:      make(map[syntax.Expr]TypeAndValue),
+			Inferred:   make(map[syntax.Expr]Inferred),
+			Defs:       make(map[*syntax.Name]Object),
+			Uses:       make(map[*syntax.Name]Object),
+			Implicits:  make(map[syntax.Node]Object),
+			Selections: make(map[*syntax.SelectorExpr]*Selection),
+			Scopes:     make(map[syntax.Node]*Scope),
+		}
+		if _, err := conf.Check("cmd/compile/internal/types2", files, info); err != nil {
+			t.Fatal(err)
+		}
+		var inits []string
+		for _, init := range info.InitOrder {
+			inits = append(inits, init.String())
+		}
+		return serializeDump(files, info), inits
+	}
+
+	want, wantInits := check(false)
+	got, gotInits := check(true)
+	if got != want {
+		t.Errorf("concurrently checked function bodies: different type information")
+	}
+	if g, w := strings.Join(gotInits, "\n"), strings.Join(wantInits, "\n"); g != w {
+		t.Errorf("got init order\n%s\nwant\n%s", g, w)
+	}
+}
+
+func TestConcurrentFuncBodiesErrors(t *testing.T) {
+	var buf strings.Builder
+	buf.WriteString("package p\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n)\n\nvar x = f0()\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&buf, "\nfunc f%d() int {\n", i)
+		switch i % 5 {
+		case 0:
+			fmt.Fprintf(&buf, "\tvar unused%d int\n", i)
+		case 1:
+			fmt.Fprintf(&buf, "\treturn \"%d\"\n", i)
+		case 2:
+			buf.WriteString("\tfmt.Println(x)\n")
+		case 3:
+			buf.WriteString("\tfunc() { _ = undefined }()\n")
+		}
+		fmt.Fprintf(&buf, "\treturn %d\n}\n", i)
+	}
+	buf.WriteString("\nfunc g() { strings.ToUpper(1) }\n")
+	src := buf.String()
+
+	check := func(concurrent, stop bool) (list []string, first error) {
+		f, err := parseSrc("p.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conf := Config{ConcurrentFuncBodies: concurrent, Importer: defaultImporter()}
+		if !stop {
+			conf.Error = func(err error) { list = append(list, err.Error()) }
+		}
+		_, err = conf.Check("p", []*syntax.File{f}, nil)
+		return list, err
+	}
+
+	want, wantFirst := check(false, false)
+	got, gotFirst := check(true, false)
+	if g, w := strings.Join(got, "\n"), strings.Join(want, "\n"); g != w {
+		t.Errorf("got errors\n%s\nwant\n%s", g, w)
+	}
+	if len(want) != 32 || !strings.Contains(want[len(want)-1], `"os" imported but not used`) {
+		t.Errorf("unexpected errors:\n%s", strings.Join(want, "\n"))
+	}
+	if gotFirst.Error() != wantFirst.Error() {
+		t.Errorf("got first error %s, want %s", gotFirst, wantFirst)
+	}
+
+	// without Config.Error, checking stops at the same error
+	_, wantFirst = check(false, true)
+	_, gotFirst = check(true, true)
+	if gotFirst == nil || gotFirst.Error() != wantFirst.Error() {
+		t.Errorf("got first error %v, want %s", gotFirst, wantFirst)
+	}
+}
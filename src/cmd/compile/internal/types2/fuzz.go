@@ -0,0 +1,53 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build gofuzz
+// +build gofuzz
+
+package types2
+
+import (
+	"bytes"
+	"cmd/compile/internal/syntax"
+	"strings"
+)
+
+// Fuzz parses data as a single Go source file and typechecks it,
+// ignoring all reported errors. The typechecker must not panic,
+// however invalid the input.
+func Fuzz(data []byte) int {
+	errh := func(error) {}
+	file, _ := syntax.Parse(syntax.NewFileBase("fuzz.go"), bytes.NewReader(data), errh, nil, syntax.AllowGenerics|syntax.AllowTypeLists)
+	if file == nil {
+		return 0
+	}
+
+	conf := Config{
+		Error:    errh,
+		Importer: fuzzImporter{},
+	}
+	info := &Info{
+		Types:      make(map[syntax.Expr]TypeAndValue),
+		Defs:       make(map[*syntax.Name]Object),
+		Uses:       make(map[*syntax.Name]Object),
+		Implicits:  make(map[syntax.Node]Object),
+		Selections: make(map[*syntax.SelectorExpr]*Selection),
+		Scopes:     make(map[syntax.Node]*Scope),
+	}
+	if _, err := conf.Check("p", []*syntax.File{file}, info); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// fuzzImporter provides an empty package for each import, so that
+// fuzz inputs exercise the handling of imports without depending
+// on export data.
+type fuzzImporter struct{}
+
+func (fuzzImporter) Import(path string) (*Package, error) {
+	pkg := NewPackage(path, path[strings.LastIndex(path, "/")+1:])
+	pkg.MarkComplete()
+	return pkg, nil
+}
@@ -15,8 +15,13 @@ import (
 // In that case x represents the uninstantiated function value and
 // it is the caller's responsibility to instantiate the function.
 func (check *Checker) indexExpr(x *operand, e *syntax.IndexExpr) (isFuncInst bool) {
-	check.exprOrType(x, e.X, true)
-	// x may be generic
+	if check.genericAlias(e.X) != nil {
+		// instantiation of a parameterized alias
+		x.mode = typexpr
+	} else {
+		check.exprOrType(x, e.X, true)
+		// x may be generic
+	}
 
 	switch x.mode {
 	case invalid:
@@ -77,6 +82,7 @@ func (check *Checker) indexExpr(x *operand, e *syntax.IndexExpr) (isFuncInst boo
 			length = typ.len
 			x.mode = variable
 			x.typ = typ.elem
+			check.recordImplicitOp(e, ImplicitDeref)
 		}
 
 	case *Slice:
@@ -176,7 +182,9 @@ func (check *Checker) indexExpr(x *operand, e *syntax.IndexExpr) (isFuncInst boo
 	}
 
 	if !valid {
-		check.errorf(x, invalidOp+"cannot index %s", x)
+		if !isUnknown(x.typ) {
+			check.errorf(x, _NonIndexableOperand, invalidOp+"cannot index %s", x)
+		}
 		x.mode = invalid
 		return false
 	}
@@ -211,7 +219,7 @@ func (check *Checker) sliceExpr(x *operand, e *syntax.SliceExpr) {
 	case *Basic:
 		if isString(typ) {
 			if e.Full {
-				check.error(x, invalidOp+"3-index slice of string")
+				check.error(x, _InvalidSliceExpr, invalidOp+"3-index slice of string")
 				x.mode = invalid
 				return
 			}
@@ -230,17 +238,19 @@ func (check *Checker) sliceExpr(x *operand, e *syntax.SliceExpr) {
 		valid = true
 		length = typ.len
 		if x.mode != variable {
-			check.errorf(x, invalidOp+"%s (slice of unaddressable value)", x)
+			check.errorf(x, _NonSliceableOperand, invalidOp+"%s (slice of unaddressable value)", x)
 			x.mode = invalid
 			return
 		}
 		x.typ = &Slice{elem: typ.elem}
+		check.recordImplicitOp(e, ImplicitAddr)
 
 	case *Pointer:
 		if typ := asArray(typ.base); typ != nil {
 			valid = true
 			length = typ.len
 			x.typ = &Slice{elem: typ.elem}
+			check.recordImplicitOp(e, ImplicitDeref)
 		}
 
 	case *Slice:
@@ -248,13 +258,15 @@ func (check *Checker) sliceExpr(x *operand, e *syntax.SliceExpr) {
 		// x.typ doesn't change
 
 	case *TypeParam:
-		check.error(x, "generic slice expressions not yet implemented")
+		check.error(x, _Todo, "generic slice expressions not yet implemented")
 		x.mode = invalid
 		return
 	}
 
 	if !valid {
-		check.errorf(x, invalidOp+"cannot slice %s", x)
+		if !isUnknown(x.typ) {
+			check.errorf(x, _NonSliceableOperand, invalidOp+"cannot slice %s", x)
+		}
 		x.mode = invalid
 		return
 	}
@@ -263,7 +275,7 @@ func (check *Checker) sliceExpr(x *operand, e *syntax.SliceExpr) {
 
 	// spec: "Only the first index may be omitted; it defaults to 0."
 	if e.Full && (e.Index[1] == nil || e.Index[2] == nil) {
-		check.error(e, invalidAST+"2nd and 3rd index required in 3-index slice")
+		check.error(e, _InvalidSyntaxTree, invalidAST+"2nd and 3rd index required in 3-index slice")
 		x.mode = invalid
 		return
 	}
@@ -301,7 +313,7 @@ L:
 		if x > 0 {
 			for _, y := range ind[i+1:] {
 				if y >= 0 && x > y {
-					check.errorf(e, "invalid slice indices: %d > %d", x, y)
+					check.errorf(e, _SwappedSliceIndices, "invalid slice indices: %d > %d", x, y)
 					break L // only report one error, ok to continue
 				}
 			}
@@ -315,16 +327,16 @@ L:
 func (check *Checker) singleIndex(e *syntax.IndexExpr) syntax.Expr {
 	index := e.Index
 	if index == nil {
-		check.errorf(e, invalidAST+"missing index for %s", e.X)
+		check.errorf(e, _InvalidSyntaxTree, invalidAST+"missing index for %s", e.X)
 		return nil
 	}
 	if l, _ := index.(*syntax.ListExpr); l != nil {
 		if n := len(l.ElemList); n <= 1 {
-			check.errorf(e, invalidAST+"invalid use of ListExpr for index expression %v with %d indices", e, n)
+			check.errorf(e, _InvalidSyntaxTree, invalidAST+"invalid use of ListExpr for index expression %v with %d indices", e, n)
 			return nil
 		}
 		// len(l.ElemList) > 1
-		check.error(l.ElemList[1], invalidOp+"more than one index")
+		check.error(l.ElemList[1], _InvalidIndex, invalidOp+"more than one index")
 		index = l.ElemList[0] // continue with first index
 	}
 	return index
@@ -340,7 +352,7 @@ func (check *Checker) index(index syntax.Expr, max int64) (typ Type, val int64)
 
 	var x operand
 	check.expr(&x, index)
-	if !check.isValidIndex(&x, "index", false) {
+	if !check.isValidIndex(&x, _InvalidIndex, "index", false) {
 		return
 	}
 
@@ -356,9 +368,9 @@ func (check *Checker) index(index syntax.Expr, max int64) (typ Type, val int64)
 	assert(ok)
 	if max >= 0 && v >= max {
 		if check.conf.CompilerErrorMessages {
-			check.errorf(&x, invalidArg+"array index %s out of bounds [0:%d]", x.val.String(), max)
+			check.errorf(&x, _InvalidIndex, invalidArg+"array index %s out of bounds [0:%d]", x.val.String(), max)
 		} else {
-			check.errorf(&x, invalidArg+"index %s is out of bounds", &x)
+			check.errorf(&x, _InvalidIndex, invalidArg+"index %s is out of bounds", &x)
 		}
 		return
 	}
@@ -371,7 +383,7 @@ func (check *Checker) index(index syntax.Expr, max int64) (typ Type, val int64)
 // index values. If allowNegative is set, a constant operand may be negative.
 // If the operand is not valid, an error is reported (using what as context)
 // and the result is false.
-func (check *Checker) isValidIndex(x *operand, what string, allowNegative bool) bool {
+func (check *Checker) isValidIndex(x *operand, code errorCode, what string, allowNegative bool) bool {
 	if x.mode == invalid {
 		return false
 	}
@@ -384,20 +396,20 @@ func (check *Checker) isValidIndex(x *operand, what string, allowNegative bool)
 
 	// spec: "the index x must be of integer type or an untyped constant"
 	if !isInteger(x.typ) {
-		check.errorf(x, invalidArg+"%s %s must be integer", what, x)
+		check.errorf(x, code, invalidArg+"%s %s must be integer", what, x)
 		return false
 	}
 
 	if x.mode == constant_ {
 		// spec: "a constant index must be non-negative ..."
 		if !allowNegative && constant.Sign(x.val) < 0 {
-			check.errorf(x, invalidArg+"%s %s must not be negative", what, x)
+			check.errorf(x, code, invalidArg+"%s %s must not be negative", what, x)
 			return false
 		}
 
 		// spec: "... and representable by a value of type int"
 		if !representableConst(x.val, check, Typ[Int], &x.val) {
-			check.errorf(x, invalidArg+"%s %s overflows int", what, x)
+			check.errorf(x, code, invalidArg+"%s %s overflows int", what, x)
 			return false
 		}
 	}
@@ -422,12 +434,12 @@ func (check *Checker) indexedElts(elts []syntax.Expr, typ Type, length int64) in
 					index = i
 					validIndex = true
 				} else {
-					check.errorf(e, "index %s must be integer constant", kv.Key)
+					check.errorf(e, _InvalidLitIndex, "index %s must be integer constant", kv.Key)
 				}
 			}
 			eval = kv.Value
 		} else if length >= 0 && index >= length {
-			check.errorf(e, "index %d is out of bounds (>= %d)", index, length)
+			check.errorf(e, _OversizeArrayLit, "index %d is out of bounds (>= %d)", index, length)
 		} else {
 			validIndex = true
 		}
@@ -435,7 +447,7 @@ func (check *Checker) indexedElts(elts []syntax.Expr, typ Type, length int64) in
 		// if we have a valid index, check for duplicate entries
 		if validIndex {
 			if visited[index] {
-				check.errorf(e, "duplicate index %d in array or slice literal", index)
+				check.errorf(e, _DuplicateLitKey, "duplicate index %d in array or slice literal", index)
 			}
 			visited[index] = true
 		}
@@ -39,6 +39,12 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 			//check.dump("### inferred targs = %s", result)
 		}()
 	}
+	if check.conf.TraceWriter != nil {
+		s := check.traceEnter(pos, "infer", "%s from %s", typeParamsString(tparams), NewTypeList(targs))
+		defer func() {
+			check.traceExit(s, "%s", NewTypeList(result))
+		}()
+	}
 
 	// There must be at least one type parameter, and no more type arguments than type parameters.
 	n := len(tparams)
@@ -54,6 +60,14 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 	}
 	// len(targs) < n
 
+	// If the type or function arguments mention the type parameters
+	// themselves (as in a recursive call f(x) of f[P any](x []P)),
+	// unification would bind type parameters to themselves. Rename
+	// the type parameters to avoid such cycles.
+	if check.mentionsTParams(tparams, targs, args) {
+		tparams, params = check.renameTParams(pos, tparams, params)
+	}
+
 	// --- 1 ---
 	// Explicitly provided type arguments take precedence over any inferred types;
 	// and types inferred via constraint type inference take precedence over types
@@ -123,16 +137,16 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 				}
 			}
 			if allFailed {
-				check.errorf(arg, "%s %s of %s does not match %s (cannot infer %s)", kind, targ, arg.expr, tpar, typeParamsString(tparams))
+				check.errorf(arg, _Todo, "%s %s of %s does not match %s (cannot infer %s)", kind, targ, arg.expr, tpar, typeParamsString(tparams))
 				return
 			}
 		}
 		smap := makeSubstMap(tparams, targs)
 		inferred := check.subst(arg.Pos(), tpar, smap, nil)
 		if inferred != tpar {
-			check.errorf(arg, "%s %s of %s does not match inferred type %s for %s", kind, targ, arg.expr, inferred, tpar)
+			check.errorf(arg, _Todo, "%s %s of %s does not match inferred type %s for %s", kind, targ, arg.expr, inferred, tpar)
 		} else {
-			check.errorf(arg, "%s %s of %s does not match %s", kind, targ, arg.expr, tpar)
+			check.errorf(arg, _Todo, "%s %s of %s does not match %s", kind, targ, arg.expr, tpar)
 		}
 	}
 
@@ -219,11 +233,23 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 		}
 	}
 
+	// Use default types for the type arguments that couldn't be inferred,
+	// and follow up with constraint type inference once more.
+	if targs, index = check.defaultTArgs(pos, tparams, targs); index < 0 {
+		return targs
+	}
+	if useConstraintTypeInference {
+		targs, index = check.inferB(tparams, targs, report)
+		if targs == nil || index < 0 {
+			return targs
+		}
+	}
+
 	// At least one type argument couldn't be inferred.
 	assert(targs != nil && index >= 0 && targs[index] == nil)
 	tpar := tparams[index]
 	if report {
-		check.errorf(pos, "cannot infer %s (%s) (%s)", tpar.obj.name, tpar.obj.pos, targs)
+		check.errorf(pos, _Todo, "cannot infer %s (%s) (%s)", tpar.obj.name, tpar.obj.pos, targs)
 	}
 	return nil
 }
@@ -256,6 +282,73 @@ func typeParamsString(list []*TypeParam) string {
 	return b.String()
 }
 
+// mentionsTParams reports whether any of the type arguments targs or the
+// types of the function arguments args refer to a type parameter in tparams.
+func (check *Checker) mentionsTParams(tparams []*TypeParam, targs []Type, args []*operand) bool {
+	for _, targ := range targs {
+		if isParameterized(tparams, targ) {
+			return true
+		}
+	}
+	for _, arg := range args {
+		if isParameterized(tparams, arg.typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// renameTParams returns a fresh copy of the type parameters tparams,
+// with constraints referring to the copies, and the function parameters
+// params expressed in terms of the copies.
+func (check *Checker) renameTParams(pos syntax.Pos, tparams []*TypeParam, params *Tuple) ([]*TypeParam, *Tuple) {
+	tparams2 := make([]*TypeParam, len(tparams))
+	targs := make([]Type, len(tparams))
+	for i, tpar := range tparams {
+		tname := NewTypeName(tpar.obj.pos, tpar.obj.pkg, tpar.obj.name, nil)
+		tparams2[i] = check.NewTypeParam(tname, nil)
+		tparams2[i].index = i
+		targs[i] = tparams2[i]
+	}
+	smap := makeSubstMap(tparams, targs)
+	for i, tpar := range tparams {
+		tparams2[i].bound = check.subst(pos, tpar.bound, smap, nil)
+		if tpar.deflt != nil {
+			tparams2[i].deflt = check.subst(pos, tpar.deflt, smap, nil)
+		}
+	}
+	if params.Len() > 0 {
+		params = check.subst(pos, params, smap, nil).(*Tuple)
+	}
+	return tparams2, params
+}
+
+// defaultTArgs returns the list of type arguments for tparams where each type
+// argument missing in targs is set to the default type of the respective type
+// parameter, if any. targs may be shorter than tparams or contain nil entries;
+// it is not modified. A default type refers to preceding type parameters only
+// and is used if the type arguments for all of them are known. The result
+// index is the index of the first missing type argument, or < 0 if there is
+// none.
+func (check *Checker) defaultTArgs(pos syntax.Pos, tparams []*TypeParam, targs []Type) (result []Type, index int) {
+	result = make([]Type, len(tparams))
+	copy(result, targs)
+	index = -1
+	for i, tpar := range tparams {
+		if result[i] != nil {
+			continue
+		}
+		if tpar.deflt != nil && index < 0 {
+			result[i] = check.subst(pos, tpar.deflt, makeSubstMap(tparams[:i], result[:i]), nil)
+			continue
+		}
+		if index < 0 {
+			index = i
+		}
+	}
+	return
+}
+
 // IsParameterized reports whether typ contains any of the type parameters of tparams.
 func isParameterized(tparams []*TypeParam, typ Type) bool {
 	w := tpWalker{
@@ -368,6 +461,12 @@ func (w *tpWalker) isParameterizedTypeList(list []Type) bool {
 // provided may be less than the number of type parameters, but there must be at least one.
 func (check *Checker) inferB(tparams []*TypeParam, targs []Type, report bool) (types []Type, index int) {
 	assert(len(tparams) >= len(targs) && len(targs) > 0)
+	if check.conf.TraceWriter != nil {
+		s := check.traceEnter(nopos, "inferConstraints", "%s from %s", typeParamsString(tparams), NewTypeList(targs))
+		defer func() {
+			check.traceExit(s, "%s", NewTypeList(types))
+		}()
+	}
 
 	// Setup bidirectional unification between those structural bounds
 	// and the corresponding type arguments (which may be nil!).
@@ -389,7 +488,7 @@ func (check *Checker) inferB(tparams []*TypeParam, targs []Type, report bool) (t
 		if sbound != nil {
 			if !u.unify(typ, sbound) {
 				if report {
-					check.errorf(tpar.obj, "%s does not match %s", tpar.obj, sbound)
+					check.errorf(tpar.obj, _Todo, "%s does not match %s", tpar.obj, sbound)
 				}
 				return nil, 0
 			}
@@ -7,8 +7,35 @@ package types2
 import (
 	"container/heap"
 	"fmt"
+	"sort"
 )
 
+// recordDependencies computes the Info.Dependencies for all package-level
+// objects, if requested.
+func (check *Checker) recordDependencies() {
+	m := check.Dependencies
+	if m == nil {
+		return
+	}
+
+	// Dependencies may already have been computed if a package is
+	// built from several calls to (*Checker).Files. Clear them.
+	for obj := range m {
+		delete(m, obj)
+	}
+
+	for obj, d := range check.objMap {
+		deps := make([]Object, 0, len(d.uses))
+		for dep := range d.uses {
+			deps = append(deps, dep)
+		}
+		sort.Slice(deps, func(i, j int) bool {
+			return deps[i].order() < deps[j].order()
+		})
+		m[obj] = deps
+	}
+}
+
 // initOrder computes the Info.InitOrder for package variables.
 func (check *Checker) initOrder() {
 	// An InitOrder may already have been computed if a package is
@@ -152,6 +179,7 @@ func findPath(objMap map[Object]*declInfo, from, to Object, seen map[Object]bool
 func (check *Checker) reportCycle(cycle []Object) {
 	obj := cycle[0]
 	var err error_
+	err.code = _InvalidInitCycle
 	if check.conf.CompilerErrorMessages {
 		err.errorf(obj, "initialization loop for %s", obj.Name())
 	} else {
@@ -9,7 +9,6 @@ package types2
 
 import (
 	"cmd/compile/internal/syntax"
-	"errors"
 	"fmt"
 )
 
@@ -20,12 +19,21 @@ type Environment struct {
 	// For now, Environment just hides a Checker.
 	// Eventually, we strive to remove the need for a checker.
 	check *Checker
+
+	// typMap maps instance hashes to instances for environments
+	// without Checker (see Checker.typMap).
+	typMap map[string]*Named
 }
 
 // NewEnvironment returns a new Environment, initialized with the given
-// Checker, or nil.
+// Checker, or nil. An environment without Checker is not safe for
+// concurrent use.
 func NewEnvironment(check *Checker) *Environment {
-	return &Environment{check}
+	env := &Environment{check: check}
+	if check == nil {
+		env.typMap = make(map[string]*Named)
+	}
+	return env
 }
 
 // Instantiate instantiates the type typ with the given type arguments targs.
@@ -35,9 +43,10 @@ func NewEnvironment(check *Checker) *Environment {
 // *Signature). Any methods attached to a *Named are simply copied; they are
 // not instantiated.
 //
-// If env is non-nil, it may be used to de-dupe the instance against previous
-// instances with the same identity. This functionality is implemented for
-// environments with non-nil Checkers.
+// If env is non-nil, it is used to de-dupe the instance against previous
+// instances with the same identity: instantiating a *Named type with
+// identical type arguments in the same environment returns the same
+// instance, whose Orig is the generic type.
 //
 // If verify is set and constraint satisfaction fails, the returned error may
 // be of dynamic type ArgumentError indicating which type argument did not
@@ -50,7 +59,19 @@ func Instantiate(env *Environment, typ Type, targs []Type, validate bool) (Type,
 	if env != nil {
 		check = env.check
 	}
-	inst := check.instance(nopos, typ, targs)
+	var inst Type
+	if t, _ := typ.(*Named); t != nil && env != nil && env.typMap != nil {
+		h := typeHash(t, targs)
+		if named := env.typMap[h]; named != nil {
+			inst = named
+		} else {
+			named := check.instance(nopos, typ, targs).(*Named)
+			env.typMap[h] = named
+			inst = named
+		}
+	} else {
+		inst = check.instance(nopos, typ, targs)
+	}
 
 	var err error
 	if validate {
@@ -89,6 +110,12 @@ func (check *Checker) instantiate(pos syntax.Pos, typ Type, targs []Type, posLis
 			check.trace(pos, "=> %s (under = %s)", res, under)
 		}()
 	}
+	if check.conf.TraceWriter != nil {
+		s := check.traceEnter(pos, "instantiate", "%s with %s", typ, NewTypeList(targs))
+		defer func() {
+			check.traceExit(s, "%s", res)
+		}()
+	}
 
 	inst := check.instance(pos, typ, targs)
 
@@ -112,7 +139,7 @@ func (check *Checker) instantiate(pos syntax.Pos, typ Type, targs []Type, posLis
 				if i < len(posList) {
 					pos = posList[i]
 				}
-				check.softErrorf(pos, err.Error())
+				check.softErrorf(pos, _Todo, err.Error())
 			}
 		}
 	})
@@ -139,6 +166,7 @@ func (check *Checker) instance(pos syntax.Pos, typ Type, targs []Type) Type {
 		named.instPos = &pos
 		if check != nil {
 			check.typMap[h] = named
+			check.counters.Instances++
 		}
 		return named
 
@@ -150,6 +178,9 @@ func (check *Checker) instance(pos syntax.Pos, typ Type, targs []Type) Type {
 		if tparams.Len() == 0 {
 			return typ // nothing to do (minor optimization)
 		}
+		if check != nil {
+			check.counters.Instances++
+		}
 		sig := check.subst(pos, typ, makeSubstMap(tparams.list(), targs), nil).(*Signature)
 		// If the signature doesn't use its type parameters, subst
 		// will not make a copy. In that case, make a copy now (so
@@ -175,7 +206,7 @@ func (check *Checker) validateTArgLen(pos syntax.Pos, ntparams, ntargs int) bool
 	if ntargs != ntparams {
 		// TODO(gri) provide better error message
 		if check != nil {
-			check.errorf(pos, "got %d arguments but %d type parameters", ntargs, ntparams)
+			check.errorf(pos, _Todo, "got %d arguments but %d type parameters", ntargs, ntparams)
 			return false
 		}
 		panic(fmt.Sprintf("%v: got %d arguments but %d type parameters", pos, ntargs, ntparams))
@@ -204,6 +235,22 @@ func (check *Checker) satisfies(pos syntax.Pos, targ Type, tpar *TypeParam, smap
 		return nil // no type bound
 	}
 
+	// The type parameter bound is parameterized with the same type parameters
+	// as the instantiated type; before we can use it for bounds checking we
+	// need to instantiate it with the type arguments with which we instantiate
+	// the parameterized type.
+	iface = check.subst(pos, iface, smap, nil).(*Interface)
+
+	if err := check.satisfiesIface(targ, iface, tpar.bound); err != nil {
+		return err
+	}
+	return nil
+}
+
+// satisfiesIface returns nil if targ satisfies the constraint iface, and
+// an error describing why not otherwise. The constraint is shown as bound
+// in error messages.
+func (check *Checker) satisfiesIface(targ Type, iface *Interface, bound Type) *UnsatisfiedError {
 	// TODO(rfindley): it would be great if users could pass in a qualifier here,
 	// rather than falling back to verbose qualification. Maybe this can be part
 	// of a the shared environment.
@@ -211,15 +258,17 @@ func (check *Checker) satisfies(pos syntax.Pos, targ Type, tpar *TypeParam, smap
 	if check != nil {
 		qf = check.qualifier
 	}
-	errorf := func(format string, args ...interface{}) error {
-		return errors.New(sprintf(qf, format, args...))
+	errorf := func(format string, args ...interface{}) *UnsatisfiedError {
+		return &UnsatisfiedError{msg: sprintf(qf, format, args...)}
 	}
 
-	// The type parameter bound is parameterized with the same type parameters
-	// as the instantiated type; before we can use it for bounds checking we
-	// need to instantiate it with the type arguments with which we instantiate
-	// the parameterized type.
-	iface = check.subst(pos, iface, smap, nil).(*Interface)
+	// if iface is ordered, targ must be ordered
+	if iface.typeSet().ordered && !isOrdered(targ) {
+		if tpar := asTypeParam(targ); tpar != nil && tpar.iface().typeSet().IsAll() {
+			return errorf("%s has no constraints", targ)
+		}
+		return errorf("%s does not satisfy ordered", targ)
+	}
 
 	// if iface is comparable, targ must be comparable
 	// TODO(gri) the error messages needs to be better, here
@@ -243,15 +292,20 @@ func (check *Checker) satisfies(pos syntax.Pos, targ Type, tpar *TypeParam, smap
 			// TODO(gri) needs to print updated name to avoid major confusion in error message!
 			//           (print warning for now)
 			// Old warning:
-			// check.softErrorf(pos, "%s does not satisfy %s (warning: name not updated) = %s (missing method %s)", targ, tpar.bound, iface, m)
+			// check.softErrorf(pos, _Todo, "%s does not satisfy %s (warning: name not updated) = %s (missing method %s)", targ, tpar.bound, iface, m)
+			var err *UnsatisfiedError
 			if wrong != nil {
 				// TODO(gri) This can still report uninstantiated types which makes the error message
 				//           more difficult to read then necessary.
-				return errorf("%s does not satisfy %s: wrong method signature\n\tgot  %s\n\twant %s",
-					targ, tpar.bound, wrong, m,
+				err = errorf("%s does not satisfy %s: wrong method signature\n\tgot  %s\n\twant %s",
+					targ, bound, wrong, m,
 				)
+			} else {
+				err = errorf("%s does not satisfy %s (%s)", targ, bound, missingMethodMsg(targ, m))
 			}
-			return errorf("%s does not satisfy %s (missing method %s)", targ, tpar.bound, m.name)
+			err.Method = m
+			err.Wrong = wrong
+			return err
 		}
 	}
 
@@ -260,24 +314,37 @@ func (check *Checker) satisfies(pos syntax.Pos, targ Type, tpar *TypeParam, smap
 		return nil // nothing to do
 	}
 
+	// If some of the interface's type terms are invalid, an error was reported
+	// already and we don't know the actual type set; don't report follow-on errors.
+	if iface.typeSet().partial {
+		return nil
+	}
+
 	// If targ is itself a type parameter, each of its possible types, but at least one, must be in the
 	// list of iface types (i.e., the targ type list must be a non-empty subset of the iface types).
 	if targ := asTypeParam(targ); targ != nil {
 		targBound := targ.iface()
 		if !targBound.typeSet().hasTerms() {
-			return errorf("%s does not satisfy %s (%s has no type constraints)", targ, tpar.bound, targ)
+			return errorf("%s does not satisfy %s (%s has no type constraints)", targ, bound, targ)
 		}
 		if !targBound.typeSet().subsetOf(iface.typeSet()) {
 			// TODO(gri) need better error message
-			return errorf("%s does not satisfy %s", targ, tpar.bound)
+			err := errorf("%s does not satisfy %s", targ, bound)
+			for _, t := range targBound.typeSet().terms {
+				if !(termlist{t}).subsetOf(iface.typeSet().terms) {
+					err.Term = (*Term)(t)
+					break
+				}
+			}
+			return err
 		}
 		return nil
 	}
 
 	// Otherwise, targ's type or underlying type must also be one of the interface types listed, if any.
-	if !iface.typeSet().includes(targ) {
+	if !check.includes(iface.typeSet(), targ) {
 		// TODO(gri) better error message
-		return errorf("%s does not satisfy %s", targ, tpar.bound)
+		return errorf("%s does not satisfy %s", targ, bound)
 	}
 
 	return nil
@@ -4,24 +4,29 @@
 
 package types2
 
-import "cmd/compile/internal/syntax"
+import (
+	"cmd/compile/internal/syntax"
+	"sync"
+)
 
 // ----------------------------------------------------------------------------
 // API
 
 // An Interface represents an interface type.
 type Interface struct {
+	check     *Checker      // checker that created the interface, for error reporting; nil once type set is computed
 	obj       *TypeName     // corresponding declared object; or nil (for better error messages)
 	methods   []*Func       // ordered list of explicitly declared methods
 	embeddeds []Type        // ordered list of explicitly embedded elements
-	embedPos  *[]syntax.Pos // positions of embedded elements; or nil (for error messages) - use pointer to save space
+	embedPos  *[]syntax.Pos // positions of embedded elements; or nil - use pointer to save space
 	complete  bool          // indicates that all fields (except for tset) are set up
+	implicit  bool          // interface is the implicit interface of a constraint literal
 
-	tset *_TypeSet // type set described by this interface, computed lazily
+	tset *TypeSet // type set described by this interface, computed lazily
 }
 
 // typeSet returns the type set for interface t.
-func (t *Interface) typeSet() *_TypeSet { return computeInterfaceTypeSet(nil, nopos, t) }
+func (t *Interface) typeSet() *TypeSet { return computeInterfaceTypeSet(t.check, nopos, t) }
 
 // emptyInterface represents the empty interface
 var emptyInterface = Interface{complete: true, tset: &topTypeSet}
@@ -84,6 +89,19 @@ func (t *Interface) Embedded(i int) *Named { tname, _ := t.embeddeds[i].(*Named)
 // EmbeddedType returns the i'th embedded type of interface t for 0 <= i < t.NumEmbeddeds().
 func (t *Interface) EmbeddedType(i int) Type { return t.embeddeds[i] }
 
+// EmbeddedPos returns the position of the i'th embedded element of interface t
+// for 0 <= i < t.NumEmbeddeds(), as it appears in the source: the position of
+// the embedded type name or of the first term of an embedded union.
+// The result is the zero position if t was not type-checked from source
+// (e.g., because it was created with NewInterfaceType or imported).
+func (t *Interface) EmbeddedPos(i int) syntax.Pos {
+	if t.embedPos == nil {
+		_ = t.embeddeds[i] // check index
+		return nopos
+	}
+	return (*t.embedPos)[i]
+}
+
 // NumMethods returns the total number of methods of interface t.
 func (t *Interface) NumMethods() int { return t.typeSet().NumMethods() }
 
@@ -100,6 +118,70 @@ func (t *Interface) IsComparable() bool { return t.typeSet().IsComparable() }
 // IsConstraint reports whether interface t is not just a method set.
 func (t *Interface) IsConstraint() bool { return t.typeSet().IsConstraint() }
 
+// IsMethodSet reports whether interface t is fully described by its method
+// set, and thus may be used as the type of a variable. It is the opposite of
+// IsConstraint.
+func (t *Interface) IsMethodSet() bool { return t.typeSet().IsMethodSet() }
+
+// Complete computes the interface's type set and returns the receiver.
+// Interfaces compute their type sets lazily, on first use, which is safe
+// for concurrent use; Complete may be used to compute the type set ahead
+// of time. Complete may be called more than once, and concurrently; all
+// calls observe the same type set. Duplicate methods are ignored, and
+// make the interface partial (see IsPartial).
+func (t *Interface) Complete() *Interface {
+	completeMu.Lock()
+	t.complete = true
+	completeMu.Unlock()
+	t.typeSet()
+	return t
+}
+
+// IsComplete reports whether the type set of t has been computed, by
+// Complete or by a use of t during or after type-checking.
+func (t *Interface) IsComplete() bool { return t.loadTypeSet() != nil }
+
+// completeMu guards the recording of lazily computed type sets (see
+// lazyTypeSet).
+var completeMu sync.Mutex
+
+// IsPartial reports whether the type set of t was computed ignoring
+// invalid embedded elements or union terms, or duplicate methods. This
+// can only happen for interfaces of packages with type errors, or for
+// invalid interfaces created with NewInterfaceType. A partial interface retains
+// all its valid methods and type terms and may be used as usual, but
+// its type set may be larger or smaller than intended.
+func (t *Interface) IsPartial() bool { return t.typeSet().partial }
+
+// IsImplicit reports whether t is the implicit interface of a constraint
+// literal such as ~int in [T ~int], rather than an interface written in
+// the source.
+func (t *Interface) IsImplicit() bool { return t.implicit }
+
+// TypeSet returns the type set of t: its methods, including embedded
+// ones, and its type terms in normal form. The type set is computed
+// when TypeSet is first called, as for Complete.
+func (t *Interface) TypeSet() *TypeSet { return t.typeSet() }
+
+// NormalTerms returns the type terms of the type set of t in normal
+// form: the terms are pairwise disjoint and their union is the set of
+// types permitted by t's embedded elements, ignoring methods. The
+// result is nil if the type set of t is not restricted by type terms,
+// and an empty, non-nil slice if the type set of t is empty.
+func (t *Interface) NormalTerms() []*Term {
+	terms := t.typeSet().terms
+	if terms.isAll() {
+		return nil
+	}
+	list := make([]*Term, 0, len(terms))
+	for _, x := range terms {
+		if x != nil {
+			list = append(list, (*Term)(x))
+		}
+	}
+	return list
+}
+
 func (t *Interface) Underlying() Type { return t }
 func (t *Interface) String() string   { return TypeString(t, nil) }
 
@@ -107,6 +189,13 @@ func (t *Interface) String() string   { return TypeString(t, nil) }
 // Implementation
 
 func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType, def *Named) {
+	// The type set of ityp may be needed before it is computed
+	// below (e.g., when ityp is used in a comparison); record the
+	// checker so that any errors are reported rather than causing
+	// a panic.
+	ityp.check = check
+	check.ifaces = append(check.ifaces, ityp)
+
 	var tlist []syntax.Expr // types collected from all type lists
 	var tname *syntax.Name  // most recent "type" name
 
@@ -121,7 +210,9 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 	for _, f := range iface.MethodList {
 		if f.Name == nil {
 			// We have an embedded type; possibly a union of types.
-			addEmbedded(posFor(f.Type), parseUnion(check, flattenUnion(nil, f.Type)))
+			typ := parseUnion(check, flattenUnion(nil, f.Type))
+			check.recordUnion(f.Type, typ)
+			addEmbedded(posFor(f.Type), typ)
 			continue
 		}
 		// f.Name != nil
@@ -130,9 +221,9 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 		name := f.Name.Value
 		if name == "_" {
 			if check.conf.CompilerErrorMessages {
-				check.error(f.Name, "methods must have a unique non-blank name")
+				check.error(f.Name, _BlankIfaceMethod, "methods must have a unique non-blank name")
 			} else {
-				check.error(f.Name, "invalid method name _")
+				check.error(f.Name, _BlankIfaceMethod, "invalid method name _")
 			}
 			continue // ignore
 		}
@@ -142,21 +233,17 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 			// Report an error for the first type list per interface
 			// if we don't allow type lists, but continue.
 			if !check.conf.AllowTypeLists && tlist == nil {
-				check.softErrorf(f.Name, "use generalized embedding syntax instead of a type list")
+				check.softErrorf(f.Name, _Todo, "use generalized embedding syntax instead of a type list")
 			}
 			// For now, collect all type list entries as if it
 			// were a single union, where each union element is
-			// of the form ~T.
-			op := new(syntax.Operation)
-			// We should also set the position (but there is no setter);
-			// we don't care because this code will eventually go away.
-			op.Op = syntax.Tilde
-			op.X = f.Type
-			tlist = append(tlist, op)
+			// of the form ~T. There is no ~ in the source, so the
+			// operation is positioned at T.
+			tlist = append(tlist, syntax.NewOperation(syntax.StartPos(f.Type), syntax.Tilde, f.Type, nil))
 			// Report an error if we have multiple type lists in an
 			// interface, but only if they are permitted in the first place.
 			if check.conf.AllowTypeLists && tname != nil && tname != f.Name {
-				check.error(f.Name, "cannot have multiple type lists in an interface")
+				check.error(f.Name, _Todo, "cannot have multiple type lists in an interface")
 			}
 			tname = f.Name
 			continue
@@ -166,7 +253,7 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 		sig, _ := typ.(*Signature)
 		if sig == nil {
 			if typ != Typ[Invalid] {
-				check.errorf(f.Type, invalidAST+"%s is not a method signature", typ)
+				check.errorf(f.Type, _InvalidSyntaxTree, invalidAST+"%s is not a method signature", typ)
 			}
 			continue // ignore
 		}
@@ -174,8 +261,8 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 		// Always type-check method type parameters but complain if they are not enabled.
 		// (This extra check is needed here because interface method signatures don't have
 		// a receiver specification.)
-		if sig.tparams != nil && !acceptMethodTypeParams {
-			check.error(f.Type, "methods cannot have type parameters")
+		if sig.tparams != nil && !check.methodTypeParams() {
+			check.error(f.Type, _Todo, "methods cannot have type parameters")
 		}
 
 		// use named receiver type if available (for better error messages)
@@ -204,6 +291,7 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 	if len(ityp.methods) == 0 && len(ityp.embeddeds) == 0 {
 		// empty interface
 		ityp.tset = &topTypeSet
+		ityp.check = nil
 		return
 	}
 
@@ -214,7 +302,39 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 	// Compute type set with a non-nil *Checker as soon as possible
 	// to report any errors. Subsequent uses of type sets will use
 	// this computed type set and won't need to pass in a *Checker.
-	check.later(func() { computeInterfaceTypeSet(check, iface.Pos(), ityp) })
+	check.later(func() {
+		computeInterfaceTypeSet(check, iface.Pos(), ityp)
+		if check.conf.ReportEmptyTypeSets {
+			check.reportEmptyTypeSet(iface.Pos(), ityp)
+		}
+		if check.conf.ReportDroppedTerms && ityp.embedPos != nil {
+			check.reportDroppedTerms(ityp, *ityp.embedPos)
+		}
+		if check.conf.ReportUnused && ityp.embedPos != nil {
+			check.reportUnusedEmbeddeds(ityp, *ityp.embedPos)
+		}
+		ityp.check = nil
+	})
+}
+
+// implicitInterface type-checks the constraint literal e and returns
+// the implicit interface which embeds e as its only element.
+func (check *Checker) implicitInterface(e syntax.Expr) *Interface {
+	ityp := &Interface{check: check, implicit: true}
+	check.ifaces = append(check.ifaces, ityp)
+	typ := parseUnion(check, flattenUnion(nil, e))
+	check.recordUnion(e, typ)
+	ityp.embeddeds = []Type{typ}
+	ityp.embedPos = &[]syntax.Pos{posFor(e)}
+	ityp.complete = true
+	check.recordTypeAndValue(e, typexpr, ityp, nil)
+	check.recordImplicitInterface(e, ityp)
+
+	check.later(func() {
+		computeInterfaceTypeSet(check, syntax.StartPos(e), ityp)
+		ityp.check = nil
+	})
+	return ityp
 }
 
 func flattenUnion(list []syntax.Expr, x syntax.Expr) []syntax.Expr {
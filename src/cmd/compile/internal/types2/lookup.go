@@ -6,6 +6,12 @@
 
 package types2
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Internal use of LookupFieldOrMethod: If the obj result is a method
 // associated with a concrete (non-interface) type, the method's signature
 // may not be fully set up. Call Checker.objDecl(obj, nil) before accessing
@@ -15,7 +21,8 @@ package types2
 // in T and returns the corresponding *Var or *Func, an index sequence, and a
 // bool indicating if there were any pointer indirections on the path to the
 // field or method. If addressable is set, T is the type of an addressable
-// variable (only matters for method lookups).
+// variable (only matters for method lookups). Methods of instantiated
+// types are returned instantiated, as by Named.Method.
 //
 // The last index entry is the field or method index in the (possibly embedded)
 // type where the entry was found, either:
@@ -38,6 +45,18 @@ package types2
 //	the method's formal receiver base type, nor was the receiver addressable.
 //
 func LookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (obj Object, index []int, indirect bool) {
+	obj, index, indirect = lookupSelection(T, addressable, pkg, name)
+	// Methods of instantiated types are instantiated lazily, upon lookup.
+	if m, _ := obj.(*Func); m != nil {
+		obj = instantiatedMethod(T, m, index)
+	}
+	return
+}
+
+// lookupSelection is like LookupFieldOrMethod but returns the methods of
+// instantiated types as declared, with parameterized receivers. It is used
+// by the type checker, which infers receiver type arguments itself.
+func lookupSelection(T Type, addressable bool, pkg *Package, name string) (obj Object, index []int, indirect bool) {
 	// Methods cannot be associated to a named pointer type
 	// (spec: "The type denoted by T is called the receiver base type;
 	// it must not be a pointer or interface type and it must be declared
@@ -47,7 +66,7 @@ func LookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (o
 	// not have found it for T (see also issue 8590).
 	if t := asNamed(T); t != nil {
 		if p, _ := safeUnderlying(t).(*Pointer); p != nil {
-			obj, index, indirect = lookupFieldOrMethod(p, false, pkg, name)
+			obj, index, indirect = lookupFieldOrMethod(p, false, pkg, name, nil)
 			if _, ok := obj.(*Func); ok {
 				return nil, nil, false
 			}
@@ -55,7 +74,28 @@ func LookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (o
 		}
 	}
 
-	return lookupFieldOrMethod(T, addressable, pkg, name)
+	return lookupFieldOrMethod(T, addressable, pkg, name, nil)
+}
+
+// instantiatedMethod returns the instantiated method m if m, found via
+// the index path from T, is a method of an instantiated type; otherwise
+// the result is m.
+func instantiatedMethod(T Type, m *Func, index []int) *Func {
+	typ := T
+	for _, i := range index[:len(index)-1] {
+		s := asStruct(derefStructPtr(typ))
+		if s == nil {
+			return m
+		}
+		typ = s.Field(i).typ
+	}
+	typ, _ = deref(typ)
+	if named, _ := typ.(*Named); named != nil && named.targs.Len() > 0 {
+		if i := index[len(index)-1]; i < named.NumMethods() && named.methods[i] == m {
+			return named.Method(i)
+		}
+	}
+	return m
 }
 
 // TODO(gri) The named type consolidation and seen maps below must be
@@ -64,7 +104,8 @@ func LookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (o
 //           indirectly via different packages.)
 
 // lookupFieldOrMethod should only be called by LookupFieldOrMethod and missingMethod.
-func lookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (obj Object, index []int, indirect bool) {
+// Methods are looked up with their names as changed by r, if r != nil.
+func lookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string, r *renaming) (obj Object, index []int, indirect bool) {
 	// WARNING: The code in this function is extremely subtle - do not modify casually!
 
 	if name == "_" {
@@ -123,7 +164,7 @@ func lookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (o
 
 				// look for a matching attached method
 				named.load()
-				if i, m := lookupMethod(named.methods, pkg, name); m != nil {
+				if i, m := r.lookup(named.methods, pkg, name); m != nil {
 					// potential match
 					// caution: method may not have a proper signature yet
 					index = concat(e.index, i)
@@ -178,7 +219,7 @@ func lookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (o
 
 			case *Interface:
 				// look for a matching method
-				if i, m := t.typeSet().LookupMethod(pkg, name); m != nil {
+				if i, m := r.lookup(t.typeSet().methods, pkg, name); m != nil {
 					assert(m.typ != nil)
 					index = concat(e.index, i)
 					if obj != nil || e.multiples {
@@ -189,7 +230,7 @@ func lookupFieldOrMethod(T Type, addressable bool, pkg *Package, name string) (o
 				}
 
 			case *TypeParam:
-				if i, m := t.iface().typeSet().LookupMethod(pkg, name); m != nil {
+				if i, m := r.lookup(t.Interface().typeSet().methods, pkg, name); m != nil {
 					assert(m.typ != nil)
 					index = concat(e.index, i)
 					if obj != nil || e.multiples {
@@ -291,6 +332,62 @@ func MissingMethod(V Type, T *Interface, static bool) (method *Func, wrongType b
 	return m, typ != nil
 }
 
+// A MethodWitness describes the method of a type V that corresponds to
+// a method of an interface T, as computed by ImplementsWitness.
+type MethodWitness struct {
+	Method    *Func // method of T
+	Impl      *Func // method of V (or *V) with the same Id as Method; or nil
+	Index     []int // path from V to Impl, as for LookupFieldOrMethod
+	Indirect  bool  // set if there was any pointer indirection on the path
+	PtrRecv   bool  // set if Impl is a method of *V but not of V
+	WrongType bool  // set if Impl's signature doesn't match Method's
+	Similar   *Func // if Impl is nil, a method of V (or *V) with a similar name; or nil
+}
+
+// Promoted reports whether the method Impl is promoted through an
+// embedded field of V.
+func (w *MethodWitness) Promoted() bool { return len(w.Index) > 1 }
+
+// OK reports whether V's method Impl satisfies the requirement of T's
+// method Method.
+func (w *MethodWitness) OK() bool { return w.Impl != nil && !w.PtrRecv && !w.WrongType }
+
+// ImplementsWitness returns a MethodWitness for each method of the
+// interface T, in the order of T's type set, describing the method
+// of V that implements it. If V has no such method but *V does, the
+// method of *V is reported and PtrRecv is set.
+//
+// V implements the methods of T if and only if all witnesses are OK;
+// ImplementsWitness doesn't consider type terms of constraint
+// interfaces.
+func ImplementsWitness(V Type, T *Interface) []MethodWitness {
+	var res []MethodWitness
+	for _, m := range T.typeSet().methods {
+		w := MethodWitness{Method: m}
+		recv := V
+		obj, index, indirect := lookupFieldOrMethod(V, false, m.pkg, m.name, nil)
+		if obj == nil && !IsInterface(V) {
+			recv = NewPointer(V)
+			obj, index, indirect = lookupFieldOrMethod(recv, false, m.pkg, m.name, nil)
+			w.PtrRecv = obj != nil
+		}
+		if f, _ := obj.(*Func); f != nil {
+			w.Impl = f
+			w.Index = index
+			w.Indirect = indirect
+			// Use missingMethod to compare the signatures, for fidelity
+			// with the checker (e.g., for methods of generic types).
+			single := &Interface{methods: []*Func{m}, complete: true}
+			_, wrong := (*Checker)(nil).missingMethod(recv, single, true)
+			w.WrongType = wrong != nil
+		} else {
+			w.Similar = similarMethod(V, m)
+		}
+		res = append(res, w)
+	}
+	return res
+}
+
 // missingMethod is like MissingMethod but accepts a *Checker as
 // receiver and an addressable flag.
 // The receiver may be nil if missingMethod is invoked through
@@ -301,6 +398,12 @@ func MissingMethod(V Type, T *Interface, static bool) (method *Func, wrongType b
 // To improve error messages, also report the wrong signature
 // when the method exists on *V instead of V.
 func (check *Checker) missingMethod(V Type, T *Interface, static bool) (method, wrongType *Func) {
+	return check.missingRenamedMethod(V, T, static, nil)
+}
+
+// missingRenamedMethod is like missingMethod but looks up the methods
+// with their names as changed by r, if r != nil.
+func (check *Checker) missingRenamedMethod(V Type, T *Interface, static bool, r *renaming) (method, wrongType *Func) {
 	// fast path for common case
 	if T.Empty() {
 		return
@@ -309,7 +412,7 @@ func (check *Checker) missingMethod(V Type, T *Interface, static bool) (method,
 	if ityp := asInterface(V); ityp != nil {
 		// TODO(gri) the methods are sorted - could do this more efficiently
 		for _, m := range T.typeSet().methods {
-			_, f := ityp.typeSet().LookupMethod(m.pkg, m.name)
+			_, f := r.lookup(ityp.typeSet().methods, m.pkg, r.name(m))
 
 			if f == nil {
 				if !static {
@@ -324,7 +427,7 @@ func (check *Checker) missingMethod(V Type, T *Interface, static bool) (method,
 			if ftyp.TParams().Len() != mtyp.TParams().Len() {
 				return m, f
 			}
-			if !acceptMethodTypeParams && ftyp.TParams().Len() > 0 {
+			if !check.methodTypeParams() && ftyp.TParams().Len() > 0 {
 				panic("method with type parameters")
 			}
 
@@ -348,12 +451,12 @@ func (check *Checker) missingMethod(V Type, T *Interface, static bool) (method,
 	Vn := asNamed(Vd)
 	for _, m := range T.typeSet().methods {
 		// TODO(gri) should this be calling lookupFieldOrMethod instead (and why not)?
-		obj, _, _ := lookupFieldOrMethod(V, false, m.pkg, m.name)
+		obj, _, _ := lookupFieldOrMethod(V, false, m.pkg, r.name(m), r)
 
 		// Check if *V implements this method of T.
 		if obj == nil {
 			ptr := NewPointer(V)
-			obj, _, _ = lookupFieldOrMethod(ptr, false, m.pkg, m.name)
+			obj, _, _ = lookupFieldOrMethod(ptr, false, m.pkg, r.name(m), r)
 			if obj != nil {
 				return m, obj.(*Func)
 			}
@@ -376,7 +479,7 @@ func (check *Checker) missingMethod(V Type, T *Interface, static bool) (method,
 		if ftyp.TParams().Len() != mtyp.TParams().Len() {
 			return m, f
 		}
-		if !acceptMethodTypeParams && ftyp.TParams().Len() > 0 {
+		if !check.methodTypeParams() && ftyp.TParams().Len() > 0 {
 			panic("method with type parameters")
 		}
 
@@ -410,7 +513,7 @@ func (check *Checker) missingMethod(V Type, T *Interface, static bool) (method,
 			// We reach here only if we accept method type parameters.
 			// In this case, unification must consider any receiver
 			// and method type parameters as "free" type parameters.
-			assert(acceptMethodTypeParams)
+			assert(check.methodTypeParams())
 			// We don't have a test case for this at the moment since
 			// we can't parse method type parameters. Keeping the
 			// unimplemented call so that we test this code if we
@@ -497,6 +600,38 @@ func lookupMethod(methods []*Func, pkg *Package, name string) (int, *Func) {
 	return -1, nil
 }
 
+// A renaming changes the name of method m to the name of id in method
+// lookups, without modifying m (see RenameMethodEffects). A nil renaming
+// doesn't change any names.
+type renaming struct {
+	m  *Func
+	id object // package and new name of m
+}
+
+// name returns the name of f as changed by r.
+func (r *renaming) name(f *Func) string {
+	if r != nil && f == r.m {
+		return r.id.name
+	}
+	return f.name
+}
+
+// lookup is like lookupMethod but matches the methods by their names as
+// changed by r.
+func (r *renaming) lookup(methods []*Func, pkg *Package, name string) (int, *Func) {
+	if r == nil {
+		return lookupMethod(methods, pkg, name)
+	}
+	if name != "_" {
+		for i, f := range methods {
+			if f == r.m && r.id.sameId(pkg, name) || f != r.m && f.sameId(pkg, name) {
+				return i, f
+			}
+		}
+	}
+	return -1, nil
+}
+
 // ptrRecv reports whether the receiver is of the form *T.
 func ptrRecv(f *Func) bool {
 	// If a method's receiver type is set, use that as the source of truth for the receiver.
@@ -515,3 +650,189 @@ func ptrRecv(f *Func) bool {
 	// For case 2) we can use the information gathered by the resolver.
 	return f.hasPtrRecv
 }
+
+// A MethodPath describes a method in the method set of a type T together
+// with the embedded types through which the method is promoted to T.
+type MethodPath struct {
+	Method *Func // the method, instantiated as by LookupFieldOrMethod
+
+	// Path lists the embedded types traversed to get to the declaration
+	// of Method, starting with the type of the embedded field (or the
+	// embedded element of an interface) at depth 0: first the types of
+	// the embedded struct fields, as declared (possibly pointers), then
+	// the embedded interfaces, as declared, down to the interface which
+	// declares Method explicitly. Path is empty if Method is declared by
+	// T (or the base type of T, if T is a pointer) itself.
+	Path []Type
+
+	// Indirect reports whether there was a pointer indirection on the
+	// path to the method, as reported by LookupFieldOrMethod.
+	Indirect bool
+}
+
+// MethodSetWithPaths returns the methods of the method set of T, sorted
+// by their unique Id, each with the path of embedded types through which
+// the method is promoted. As for LookupFieldOrMethod, the methods of
+// interfaces and type parameters are those of their type sets, and T
+// may be a pointer type.
+func MethodSetWithPaths(T Type) []MethodPath {
+	var res []MethodPath
+	for _, m := range methodCandidates(T) {
+		obj, index, indirect := lookupSelection(T, false, m.pkg, m.name)
+		f, _ := obj.(*Func)
+		if f == nil {
+			continue // not in the method set, shadowed by a field, or ambiguous
+		}
+
+		// follow the embedded struct fields
+		var path []Type
+		typ := T
+		for _, i := range index[:len(index)-1] {
+			typ = asStruct(derefStructPtr(typ)).Field(i).typ
+			path = append(path, typ)
+		}
+
+		// follow the embedded interfaces, if the method is declared by one
+		typ, _ = deref(typ)
+		var iface *Interface
+		switch t := under(typ).(type) {
+		case *Interface:
+			iface = t
+		case *TypeParam:
+			iface = t.iface()
+		}
+		if iface != nil {
+			path, _ = embeddingPath(path, iface, f)
+		}
+
+		res = append(res, MethodPath{instantiatedMethod(T, f, index), path, indirect})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Method.less(&res[j].Method.object)
+	})
+	return res
+}
+
+// methodCandidates returns methods with the names (and packages) of all
+// the methods that may be in the method set of T, with one method for
+// each unique Id.
+func methodCandidates(T Type) []*Func {
+	var list []*Func
+	seenIds := make(map[string]bool)
+	add := func(m *Func) {
+		if id := m.Id(); !seenIds[id] {
+			seenIds[id] = true
+			list = append(list, m)
+		}
+	}
+
+	seen := make(map[*Named]bool)
+	var visit func(typ Type)
+	visit = func(typ Type) {
+		typ, _ = deref(typ)
+		if named := asNamed(typ); named != nil {
+			if seen[named] {
+				return
+			}
+			seen[named] = true
+			for i := 0; i < named.NumMethods(); i++ {
+				add(named.methods[i])
+			}
+		}
+		switch t := under(typ).(type) {
+		case *Struct:
+			for _, f := range t.fields {
+				if f.embedded {
+					visit(f.typ)
+				}
+			}
+		case *Interface:
+			for _, m := range t.typeSet().methods {
+				add(m)
+			}
+		case *TypeParam:
+			for _, m := range t.iface().typeSet().methods {
+				add(m)
+			}
+		}
+	}
+	visit(T)
+	return list
+}
+
+// embeddingPath returns path extended by the embedded elements of iface
+// through which iface has the method m, down to the interface which declares
+// m explicitly, and reports whether such an interface was found.
+func embeddingPath(path []Type, iface *Interface, m *Func) ([]Type, bool) {
+	for _, f := range iface.methods {
+		if f.Id() == m.Id() {
+			return path, true
+		}
+	}
+	for _, e := range iface.embeddeds {
+		if t, _ := under(e).(*Interface); t != nil {
+			if res, found := embeddingPath(append(path[:len(path):len(path)], e), t, m); found {
+				return res, true
+			}
+		}
+	}
+	return path, false
+}
+
+// missingMethodMsg returns the description of the method m which is
+// missing in V, with a suggestion of a method of V (or *V) with a
+// similar name, if any.
+func missingMethodMsg(V Type, m *Func) string {
+	if f := similarMethod(V, m); f != nil {
+		return fmt.Sprintf("missing method %s; did you mean %s?", m.name, f.name)
+	}
+	return "missing method " + m.name
+}
+
+// similarMethod returns a method of V (or *V) whose name is similar to
+// the name of the method m which is missing in V, or nil. Names are
+// similar if they differ in case only, or if their edit distance is at
+// most a third of the length of m's name. Of several similar names, the
+// closest one is chosen.
+func similarMethod(V Type, m *Func) *Func {
+	var best *Func
+	bestDist := len(m.name)/3 + 1 // exclusive
+	name := strings.ToLower(m.name)
+	for _, f := range methodCandidates(V) {
+		if f.name == m.name || f.name == "_" {
+			continue // a method of another package is not a better match
+		}
+		if d := editDistance(strings.ToLower(f.name), name); d < bestDist {
+			best, bestDist = f, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between s and t,
+// counted in bytes.
+func editDistance(s, t string) int {
+	// dist[j] is the distance between the current prefix of s and t[:j]
+	dist := make([]int, len(t)+1)
+	for j := range dist {
+		dist[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		prev := dist[0] // distance between s[:i-1] and t[:j-1]
+		dist[0] = i
+		for j := 1; j <= len(t); j++ {
+			d := prev // substitution (or match)
+			if s[i-1] != t[j-1] {
+				d++
+			}
+			if dist[j]+1 < d {
+				d = dist[j] + 1 // deletion
+			}
+			if dist[j-1]+1 < d {
+				d = dist[j-1] + 1 // insertion
+			}
+			prev, dist[j] = dist[j], d
+		}
+	}
+	return dist[len(t)]
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements MatchFiles, which selects the files
+// of a package that are part of a given build.
+
+package types2
+
+import (
+	"cmd/compile/internal/syntax"
+	"fmt"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+)
+
+// A BuildContext describes a build configuration for MatchFiles.
+// The fields have the same meaning as the respective go/build.Context
+// fields.
+type BuildContext struct {
+	GOOS        string   // target operating system
+	GOARCH      string   // target architecture
+	Compiler    string   // compiler name; "gc" if empty
+	CgoEnabled  bool     // whether the "cgo" build tag is satisfied
+	BuildTags   []string // additional build tags that are satisfied
+	ReleaseTags []string // release tags that are satisfied (such as "go1.17")
+}
+
+// MatchFiles returns the subset of files (in the same order) that are
+// part of the build described by ctxt, using the same rules as the go
+// command: a file is excluded if its name ends in _GOOS, _GOARCH, or
+// _GOOS_GOARCH (optionally followed by _test) for a different operating
+// system or architecture, or if its //go:build constraint (see
+// syntax.File.GoBuild) is not satisfied. Legacy // +build lines are
+// ignored; the file names are taken from the files' position bases.
+//
+// An error is reported for files with malformed //go:build constraints;
+// the result then contains the files which could be matched.
+func MatchFiles(files []*syntax.File, ctxt *BuildContext) ([]*syntax.File, error) {
+	var list []*syntax.File
+	var firstErr error
+	for _, f := range files {
+		ok, err := ctxt.match(f)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ok {
+			list = append(list, f)
+		}
+	}
+	return list, firstErr
+}
+
+// match reports whether file f is part of the build described by ctxt.
+func (ctxt *BuildContext) match(f *syntax.File) (bool, error) {
+	if !ctxt.goodOSArchFile(filepath.Base(fileName(f))) {
+		return false, nil
+	}
+	if f.GoBuild == "" {
+		return true, nil
+	}
+	x, err := constraint.Parse("//go:build " + f.GoBuild)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid //go:build line: %v", fileName(f), err)
+	}
+	return x.Eval(ctxt.matchTag), nil
+}
+
+// fileName returns the name of the file of f,
+// ignoring any line directives.
+func fileName(f *syntax.File) string {
+	b := fileBase(f.Pos())
+	if b == nil {
+		return ""
+	}
+	return b.Filename()
+}
+
+// matchTag reports whether the build tag name is satisfied by ctxt.
+func (ctxt *BuildContext) matchTag(name string) bool {
+	compiler := ctxt.Compiler
+	if compiler == "" {
+		compiler = "gc"
+	}
+
+	// special tags
+	if ctxt.CgoEnabled && name == "cgo" {
+		return true
+	}
+	if name == ctxt.GOOS || name == ctxt.GOARCH || name == compiler {
+		return true
+	}
+	if ctxt.GOOS == "android" && name == "linux" {
+		return true
+	}
+	if ctxt.GOOS == "illumos" && name == "solaris" {
+		return true
+	}
+	if ctxt.GOOS == "ios" && name == "darwin" {
+		return true
+	}
+
+	// other tags
+	for _, tag := range ctxt.BuildTags {
+		if tag == name {
+			return true
+		}
+	}
+	for _, tag := range ctxt.ReleaseTags {
+		if tag == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// goodOSArchFile reports whether the file name (without directory)
+// is acceptable for ctxt given its _GOOS and _GOARCH suffixes, if any.
+// See go/build.Context.goodOSArchFile.
+func (ctxt *BuildContext) goodOSArchFile(name string) bool {
+	if dot := strings.Index(name, "."); dot != -1 {
+		name = name[:dot]
+	}
+
+	// Only names with a non-empty prefix are tagged: "linux.go"
+	// is not a Linux-specific file, but "foo_linux.go" is.
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return true
+	}
+	name = name[i:] // ignore everything before first _
+
+	l := strings.Split(name, "_")
+	if n := len(l); n > 0 && l[n-1] == "test" {
+		l = l[:n-1]
+	}
+	n := len(l)
+	if n >= 2 && knownOS[l[n-2]] && knownArch[l[n-1]] {
+		return ctxt.matchTag(l[n-1]) && ctxt.matchTag(l[n-2])
+	}
+	if n >= 1 && (knownOS[l[n-1]] || knownArch[l[n-1]]) {
+		return ctxt.matchTag(l[n-1])
+	}
+	return true
+}
+
+// The lists of known GOOS and GOARCH values must be kept
+// in sync with the lists in go/build/syslist.go.
+const goosList = "aix android darwin dragonfly freebsd hurd illumos ios js linux nacl netbsd openbsd plan9 solaris windows zos "
+const goarchList = "386 amd64 amd64p32 arm armbe arm64 arm64be ppc64 ppc64le loong64 mips mipsle mips64 mips64le mips64p32 mips64p32le ppc riscv riscv64 s390 s390x sparc sparc64 wasm "
+
+var knownOS = make(map[string]bool)
+var knownArch = make(map[string]bool)
+
+func init() {
+	for _, v := range strings.Fields(goosList) {
+		knownOS[v] = true
+	}
+	for _, v := range strings.Fields(goarchList) {
+		knownArch[v] = true
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/syntax"
+	. "cmd/compile/internal/types2"
+)
+
+func TestMatchFiles(t *testing.T) {
+	sources := []struct{ filename, src string }{
+		{"a.go", "package p"},
+		{"dir/a_linux.go", "package p"},
+		{"a_windows.go", "package p"},
+		{"a_linux_amd64.go", "package p"},
+		{"a_linux_arm64_test.go", "package p"},
+		{"a_amd64.go", "package p"},
+		{"linux.go", "package p"}, // no prefix, not tagged
+		{"b.go", "//go:build linux && !cgo\n\npackage p"},
+		{"c.go", "//go:build ignore\n\npackage main"},
+		{"d.go", "//go:build darwin || go1.17\n\npackage p"},
+		{"e.go", "//go:build custom\n\npackage p"},
+		{"f.go", "//line other_windows.go:1\npackage p"}, // line directives are ignored
+		{"g.go", "//go:build gc\n\npackage p"},
+	}
+	var files []*syntax.File
+	filenames := make(map[*syntax.File]string)
+	for _, s := range sources {
+		f, err := syntax.Parse(syntax.NewFileBase(s.filename), strings.NewReader(s.src), nil, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+		filenames[f] = s.filename
+	}
+
+	for _, test := range []struct {
+		ctxt BuildContext
+		want string
+	}{
+		{BuildContext{GOOS: "linux", GOARCH: "amd64"}, "a.go dir/a_linux.go a_linux_amd64.go a_amd64.go linux.go b.go f.go g.go"},
+		{BuildContext{GOOS: "linux", GOARCH: "amd64", CgoEnabled: true, Compiler: "gccgo"}, "a.go dir/a_linux.go a_linux_amd64.go a_amd64.go linux.go f.go"},
+		{BuildContext{GOOS: "android", GOARCH: "arm64", ReleaseTags: []string{"go1.16", "go1.17"}}, "a.go dir/a_linux.go a_linux_arm64_test.go linux.go b.go d.go f.go g.go"},
+		{BuildContext{GOOS: "windows", GOARCH: "386", BuildTags: []string{"custom"}}, "a.go a_windows.go linux.go e.go f.go g.go"},
+	} {
+		list, err := MatchFiles(files, &test.ctxt)
+		if err != nil {
+			t.Errorf("%+v: %v", test.ctxt, err)
+			continue
+		}
+		var names []string
+		for _, f := range list {
+			names = append(names, filenames[f])
+		}
+		if got := strings.Join(names, " "); got != test.want {
+			t.Errorf("%+v:\ngot  %s\nwant %s", test.ctxt, got, test.want)
+		}
+	}
+
+	// malformed constraints are reported
+	f, err := syntax.Parse(syntax.NewFileBase("bad.go"), strings.NewReader("//go:build linux &&\n\npackage p"), nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := MatchFiles(append(files[:1:1], f), &BuildContext{GOOS: "linux", GOARCH: "amd64"})
+	if err == nil || !strings.Contains(err.Error(), "bad.go: invalid //go:build line") {
+		t.Errorf("got error %v; want invalid //go:build line", err)
+	}
+	if len(list) != 1 || list[0] != files[0] {
+		t.Errorf("got %d files; want a.go only", len(list))
+	}
+}
@@ -24,6 +24,11 @@ type Named struct {
 
 	resolve func(*Named) ([]*TypeParam, Type, []*Func)
 	once    sync.Once
+
+	instMu      sync.Mutex // guards instMethods
+	instMethods []*Func    // methods of an instance, instantiated upon first access; or nil
+
+	lazyMu sync.Mutex // guards the loading and expansion of an instance that is not being type-checked
 }
 
 // NewNamed returns a new named type for the given type name, underlying type, and associated methods.
@@ -44,10 +49,19 @@ func (t *Named) load() *Named {
 	// underlying is set when t is expanded.
 	//
 	// By convention, a type instance is loaded iff its tparams are set.
-	if t.targs.Len() > 0 && t.tparams == nil {
-		t.orig.load()
-		t.tparams = t.orig.tparams
-		t.methods = t.orig.methods
+	if t.targs.Len() > 0 {
+		// Instances may be loaded concurrently once they are no longer
+		// being type-checked.
+		if t.check == nil {
+			t.lazyMu.Lock()
+			defer t.lazyMu.Unlock()
+		}
+		if t.tparams == nil {
+			t.orig.load()
+			t.tparams = t.orig.tparams
+			t.methods = t.orig.methods
+		}
+		return t
 	}
 	if t.resolve == nil {
 		return t
@@ -95,6 +109,7 @@ func (check *Checker) newNamed(obj *TypeName, orig *Named, underlying Type, tpar
 	// TODO(gri): clean this up so that under is the only function mutating
 	//            named types.
 	if check != nil {
+		check.nameds = append(check.nameds, typ)
 		check.later(func() {
 			switch typ.under().(type) {
 			case *Named:
@@ -123,8 +138,15 @@ func (t *Named) Orig() *Named { return t.orig }
 // The result is non-nil for an (originally) parameterized type even if it is instantiated.
 func (t *Named) TParams() *TParamList { return t.load().tparams }
 
-// SetTParams sets the type parameters of the named type t.
-func (t *Named) SetTParams(tparams []*TypeParam) { t.load().tparams = bindTParams(tparams) }
+// SetTParams sets the type parameters of the named type t, which makes t
+// a generic type. Instances of t are created with Instantiate. SetTParams
+// panics if t is an instance.
+func (t *Named) SetTParams(tparams []*TypeParam) {
+	if t.orig != t {
+		panic("cannot set type parameters of instantiated type")
+	}
+	t.load().tparams = bindTParams(tparams)
+}
 
 // TArgs returns the type arguments used to instantiate the named type t.
 func (t *Named) TArgs() *TypeList { return t.targs }
@@ -133,7 +155,33 @@ func (t *Named) TArgs() *TypeList { return t.targs }
 func (t *Named) NumMethods() int { return len(t.load().methods) }
 
 // Method returns the i'th method of named type t for 0 <= i < t.NumMethods().
-func (t *Named) Method(i int) *Func { return t.load().methods[i] }
+// The methods of a type-checked named type are in source order: in the order
+// of the files passed to the type checker, and in order of appearance within
+// each file. The methods of an imported type are in the order provided by the
+// importer. Use SortedMethods for an order independent of either.
+//
+// For an instantiated type t, the method signature has its receiver type
+// parameters substituted by the type arguments of t, and the receiver type
+// is t (or *t). Such methods are instantiated upon first access only; their
+// Origin is the method of the generic type.
+func (t *Named) Method(i int) *Func {
+	t.load()
+	if t.targs.Len() > 0 {
+		return t.instantiateMethod(i)
+	}
+	return t.methods[i]
+}
+
+// SortedMethods returns the explicit methods of named type t, ordered by
+// their unique Id (as the methods of an interface are).
+func (t *Named) SortedMethods() []*Func {
+	methods := make([]*Func, t.NumMethods())
+	for i := range methods {
+		methods[i] = t.Method(i)
+	}
+	sortMethods(methods)
+	return methods
+}
 
 // SetUnderlying sets the underlying type and marks t as complete.
 func (t *Named) SetUnderlying(underlying Type) {
@@ -147,8 +195,24 @@ func (t *Named) SetUnderlying(underlying Type) {
 }
 
 // AddMethod adds method m unless it is already in the method list.
+//
+// If t is generic, the receiver type parameters of the signature of m
+// correspond to the type parameters of t, and the methods of instances
+// of t are instantiated with the type arguments of the instance. If the
+// signature has a receiver but no receiver type parameters, as when
+// constructing the methods of a generic type programmatically, it may
+// use the type parameters of t directly: AddMethod sets its receiver
+// type parameters to those of t. Thus the type parameters of t must be
+// set before its methods are added. AddMethod panics if t is an
+// instance.
 func (t *Named) AddMethod(m *Func) {
+	if t.orig != t {
+		panic("cannot add method to instantiated type")
+	}
 	t.load()
+	if sig, _ := m.typ.(*Signature); sig != nil && sig.recv != nil && sig.rparams == nil {
+		sig.rparams = t.tparams
+	}
 	if i, _ := lookupMethod(t.methods, m.pkg, m.name); i < 0 {
 		t.methods = append(t.methods, m)
 	}
@@ -160,6 +224,51 @@ func (t *Named) String() string   { return TypeString(t, nil) }
 // ----------------------------------------------------------------------------
 // Implementation
 
+// instantiateMethod returns the i'th method of the instance t, with the
+// receiver type parameters substituted by the type arguments of t. The
+// result is cached; instantiateMethod may be called concurrently.
+func (t *Named) instantiateMethod(i int) *Func {
+	t.instMu.Lock()
+	defer t.instMu.Unlock()
+
+	if len(t.instMethods) < len(t.methods) {
+		// methods may have been added to the generic type in the meantime
+		list := make([]*Func, len(t.methods))
+		copy(list, t.instMethods)
+		t.instMethods = list
+	}
+	if m := t.instMethods[i]; m != nil {
+		return m
+	}
+
+	origm := t.methods[i]
+	sig, _ := origm.typ.(*Signature)
+	if sig == nil || sig.RParams().Len() != t.targs.Len() {
+		// The signature is not type-checked yet, or the receiver is
+		// invalid (and an error was reported). Don't cache the method.
+		return origm
+	}
+
+	smap := makeSubstMap(sig.RParams().list(), t.targs.list())
+	isig := t.check.subst(origm.pos, sig, smap, nil).(*Signature)
+	if isig == sig {
+		// the signature doesn't mention the receiver type parameters
+		copy := *sig
+		isig = &copy
+	}
+	var rtyp Type = t
+	if _, ok := sig.recv.typ.(*Pointer); ok {
+		rtyp = NewPointer(t)
+	}
+	isig.recv = NewParam(sig.recv.pos, sig.recv.pkg, sig.recv.name, rtyp)
+
+	m := *origm
+	m.typ = isig
+	m.origin = origm.Origin()
+	t.instMethods[i] = &m
+	return &m
+}
+
 // under returns the expanded underlying type of n0; possibly by following
 // forward chains of named types. If an underlying type is found, resolve
 // the chain by setting the underlying type for each defined type in the
@@ -185,7 +294,22 @@ func (n0 *Named) under() Type {
 	}
 
 	if n0.check == nil {
-		panic("Named.check == nil but type is incomplete")
+		// The chain was not resolved because type-checking stopped
+		// early; follow it without resolving it (n0 may be used
+		// concurrently).
+		seen := make(map[*Named]bool)
+		for n := n0; !seen[n]; n = n1 {
+			seen[n] = true
+			switch u1 := n.Underlying().(type) {
+			case nil:
+				return Typ[Invalid]
+			default:
+				return u1
+			case *Named:
+				n1 = u1
+			}
+		}
+		return Typ[Invalid] // cycle
 	}
 
 	// Invariant: after this point n0 as well as any named types in its
@@ -242,43 +366,88 @@ func (n *Named) setUnderlying(typ Type) {
 
 // expand ensures that the underlying type of n is instantiated.
 // The underlying type will be Typ[Invalid] if there was an error.
+//
+// Unless n is being type-checked, expand may be called concurrently:
+// the underlying type is computed without holding a lock, and the first
+// result recorded is used.
 func (n *Named) expand(typMap map[string]*Named) *Named {
-	if n.instPos != nil {
-		// n must be loaded before instantiation, in order to have accurate
-		// tparams. This is done implicitly by the call to n.TParams, but making it
-		// explicit is harmless: load is idempotent.
-		n.load()
-		var u Type
-		if n.check.validateTArgLen(*n.instPos, n.tparams.Len(), n.targs.Len()) {
-			if typMap == nil {
-				if n.check != nil {
-					typMap = n.check.typMap
-				} else {
-					// If we're instantiating lazily, we might be outside the scope of a
-					// type-checking pass. In that case we won't have a pre-existing
-					// typMap, but don't want to create a duplicate of the current instance
-					// in the process of expansion.
-					h := typeHash(n.orig, n.targs.list())
-					typMap = map[string]*Named{h: n}
-				}
-			}
-			u = n.check.subst(*n.instPos, n.orig.underlying, makeSubstMap(n.TParams().list(), n.targs.list()), typMap)
-		} else {
-			u = Typ[Invalid]
+	if n.targs.Len() == 0 {
+		return n // not an instance
+	}
+	if n.check != nil {
+		if n.instPos != nil {
+			n.setExpanded(n.expandUnderlying(*n.instPos, typMap))
 		}
-		n.underlying = u
-		n.fromRHS = u
-		n.instPos = nil
+		return n
+	}
+
+	n.lazyMu.Lock()
+	pos := n.instPos
+	n.lazyMu.Unlock()
+	if pos != nil {
+		u := n.expandUnderlying(*pos, typMap)
+		n.lazyMu.Lock()
+		if n.instPos != nil {
+			n.setExpanded(u)
+		}
+		n.lazyMu.Unlock()
 	}
 	return n
 }
 
+// expandUnderlying returns the instantiated underlying type of the
+// unexpanded instance n, created at pos.
+func (n *Named) expandUnderlying(pos syntax.Pos, typMap map[string]*Named) Type {
+	// n must be loaded before instantiation, in order to have accurate
+	// tparams. This is done implicitly by the call to n.TParams, but making it
+	// explicit is harmless: load is idempotent.
+	n.load()
+	if !n.check.validateTArgLen(pos, n.tparams.Len(), n.targs.Len()) {
+		return Typ[Invalid]
+	}
+	if typMap == nil {
+		if n.check != nil {
+			typMap = n.check.typMap
+		} else {
+			// If we're instantiating lazily, we might be outside the scope of a
+			// type-checking pass. In that case we won't have a pre-existing
+			// typMap, but don't want to create a duplicate of the current instance
+			// in the process of expansion.
+			h := typeHash(n.orig, n.targs.list())
+			typMap = map[string]*Named{h: n}
+		}
+	}
+	return n.check.subst(pos, n.orig.underlying, makeSubstMap(n.TParams().list(), n.targs.list()), typMap)
+}
+
+func (n *Named) setExpanded(u Type) {
+	n.underlying = u
+	n.fromRHS = u
+	n.instPos = nil
+}
+
+// unexpanded reports whether n is an instance whose underlying type has
+// not been instantiated yet.
+func (n *Named) unexpanded() bool {
+	if n.targs.Len() == 0 {
+		return false
+	}
+	if n.check == nil {
+		n.lazyMu.Lock()
+		defer n.lazyMu.Unlock()
+	}
+	return n.instPos != nil
+}
+
 // safeUnderlying returns the underlying of typ without expanding instances, to
 // avoid infinite recursion.
 //
 // TODO(rfindley): eliminate this function or give it a better name.
 func safeUnderlying(typ Type) Type {
 	if t, _ := typ.(*Named); t != nil {
+		if t.unexpanded() {
+			return nil
+		}
 		return t.load().underlying
 	}
 	return typ.Underlying()
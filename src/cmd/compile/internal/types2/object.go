@@ -9,6 +9,7 @@ import (
 	"cmd/compile/internal/syntax"
 	"fmt"
 	"go/constant"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -26,6 +27,33 @@ type Object interface {
 	Exported() bool  // reports whether the name starts with a capital letter
 	Id() string      // object name if exported, qualified name if not exported (see func Id)
 
+	// Doc returns the doc comment of the object's declaration, or nil.
+	// Doc comments are only available for declared constants, variables,
+	// types, functions, methods, and imported package names, and only if
+	// the respective source files were parsed with syntax.DocComments.
+	// The only declaration of a group without doc comment is documented
+	// by the doc comment of the group, as with go/doc.
+	Doc() *syntax.CommentGroup
+
+	// Deprecated returns the paragraph of the object's doc comment that
+	// starts with "Deprecated: ", by convention marking the object as
+	// deprecated, or the empty string if there is no such paragraph.
+	// The result is only meaningful if Doc comments are available.
+	Deprecated() string
+
+	// Data returns the value set with SetData, or nil.
+	Data() interface{}
+
+	// SetData associates the value data with the object, replacing
+	// any previous value. The type checker never uses or modifies the
+	// value; it lets clients attach their own information to objects
+	// without maintaining separate maps. Objects are shared by all
+	// packages that use them, for instance the objects of a package
+	// provided by an importer, and objects of the Universe scope are
+	// shared by all packages. SetData must not be called concurrently
+	// with other uses of the object's data.
+	SetData(data interface{})
+
 	// String returns a human-readable string of the object.
 	String() string
 
@@ -95,6 +123,23 @@ type object struct {
 	order_    uint32
 	color_    color
 	scopePos_ syntax.Pos
+	doc       *syntax.CommentGroup
+	data      interface{}
+}
+
+// deprecation returns the paragraph of doc that starts with "Deprecated: ",
+// or the empty string.
+func deprecation(doc *syntax.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	for _, par := range strings.Split(doc.Text(), "\n\n") {
+		par = strings.TrimLeft(par, "\n")
+		if strings.HasPrefix(par, "Deprecated: ") {
+			return par
+		}
+	}
+	return ""
 }
 
 // color encodes the color of an object (see Checker.objDecl for details).
@@ -153,6 +198,18 @@ func (obj *object) Exported() bool { return isExported(obj.name) }
 // Id is a wrapper for Id(obj.Pkg(), obj.Name()).
 func (obj *object) Id() string { return Id(obj.pkg, obj.name) }
 
+// Doc returns the doc comment of the object's declaration, or nil.
+func (obj *object) Doc() *syntax.CommentGroup { return obj.doc }
+
+// Deprecated returns the deprecation paragraph of the object's doc comment, if any.
+func (obj *object) Deprecated() string { return deprecation(obj.doc) }
+
+// Data returns the value set with SetData, or nil.
+func (obj *object) Data() interface{} { return obj.data }
+
+// SetData associates the value data with the object.
+func (obj *object) SetData(data interface{}) { obj.data = data }
+
 func (obj *object) String() string       { panic("abstract") }
 func (obj *object) order() uint32        { return obj.order_ }
 func (obj *object) color() color         { return obj.color_ }
@@ -236,7 +293,7 @@ type PkgName struct {
 // NewPkgName returns a new PkgName object representing an imported package.
 // The remaining arguments set the attributes found with all Objects.
 func NewPkgName(pos syntax.Pos, pkg *Package, name string, imported *Package) *PkgName {
-	return &PkgName{object{nil, pos, pkg, name, Typ[Invalid], 0, black, nopos}, imported, false}
+	return &PkgName{object{nil, pos, pkg, name, Typ[Invalid], 0, black, nopos, nil, nil}, imported, false}
 }
 
 // Imported returns the package that was imported.
@@ -252,7 +309,7 @@ type Const struct {
 // NewConst returns a new constant with value val.
 // The remaining arguments set the attributes found with all Objects.
 func NewConst(pos syntax.Pos, pkg *Package, name string, typ Type, val constant.Value) *Const {
-	return &Const{object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos}, val}
+	return &Const{object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos, nil, nil}, val}
 }
 
 // Val returns the constant's value.
@@ -263,6 +320,7 @@ func (*Const) isDependency() {} // a constant may be a dependency of an initiali
 // A TypeName represents a name for a (defined or alias) type.
 type TypeName struct {
 	object
+	tparams *TParamList // type parameters of a parameterized alias, or nil
 }
 
 // NewTypeName returns a new type name denoting the given typ.
@@ -273,7 +331,7 @@ type TypeName struct {
 // argument for NewNamed, which will set the TypeName's type as a side-
 // effect.
 func NewTypeName(pos syntax.Pos, pkg *Package, name string, typ Type) *TypeName {
-	return &TypeName{object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos}}
+	return &TypeName{object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos, nil, nil}, nil}
 }
 
 // NewTypeNameLazy returns a new defined type like NewTypeName, but it
@@ -284,6 +342,22 @@ func NewTypeNameLazy(pos syntax.Pos, pkg *Package, name string, resolve func(nam
 	return obj
 }
 
+// NewGenericAlias returns a new type name for a parameterized alias
+// declaration such as type A[P any] = []P: typ is the aliased type and
+// may refer to the type parameters tparams, which must not be bound to
+// another declaration. A parameterized alias denotes a type only once it
+// is instantiated with type arguments replacing its type parameters.
+func NewGenericAlias(pos syntax.Pos, pkg *Package, name string, tparams []*TypeParam, typ Type) *TypeName {
+	obj := NewTypeName(pos, pkg, name, typ)
+	obj.tparams = bindTParams(tparams)
+	return obj
+}
+
+// TParams returns the type parameters of the parameterized alias obj,
+// or nil. The type parameters of defined types are those of their Named
+// type (see Named.TParams).
+func (obj *TypeName) TParams() *TParamList { return obj.tparams }
+
 // IsAlias reports whether obj is an alias name for a type.
 func (obj *TypeName) IsAlias() bool {
 	switch t := obj.typ.(type) {
@@ -314,24 +388,25 @@ type Var struct {
 	embedded bool // if set, the variable is an embedded struct field, and name is the type name
 	isField  bool // var is struct field
 	used     bool // set if the variable was used
+	origin   *Var // if non-nil, the Var from which this one was instantiated
 }
 
 // NewVar returns a new variable.
 // The arguments set the attributes found with all Objects.
 func NewVar(pos syntax.Pos, pkg *Package, name string, typ Type) *Var {
-	return &Var{object: object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos}}
+	return &Var{object: object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos, nil, nil}}
 }
 
 // NewParam returns a new variable representing a function parameter.
 func NewParam(pos syntax.Pos, pkg *Package, name string, typ Type) *Var {
-	return &Var{object: object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos}, used: true} // parameters are always 'used'
+	return &Var{object: object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos, nil, nil}, used: true} // parameters are always 'used'
 }
 
 // NewField returns a new variable representing a struct field.
 // For embedded fields, the name is the unqualified type name
 /// under which the field is accessible.
 func NewField(pos syntax.Pos, pkg *Package, name string, typ Type, embedded bool) *Var {
-	return &Var{object: object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos}, embedded: embedded, isField: true}
+	return &Var{object: object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos, nil, nil}, embedded: embedded, isField: true}
 }
 
 // Anonymous reports whether the variable is an embedded field.
@@ -344,6 +419,18 @@ func (obj *Var) Embedded() bool { return obj.embedded }
 // IsField reports whether the variable is a struct field.
 func (obj *Var) IsField() bool { return obj.isField }
 
+// Origin returns the canonical Var for its receiver, i.e. the Var object
+// recorded in Info.Defs. For variables created during instantiation (such
+// as struct fields or function parameters whose types depend on type
+// arguments), this is the corresponding Var of the generic declaration.
+// For all other variables, Origin returns the receiver.
+func (obj *Var) Origin() *Var {
+	if obj.origin != nil {
+		return obj.origin
+	}
+	return obj
+}
+
 func (*Var) isDependency() {} // a variable may be a dependency of an initialization expression
 
 // A Func represents a declared function, concrete method, or abstract
@@ -351,7 +438,8 @@ func (*Var) isDependency() {} // a variable may be a dependency of an initializa
 // An abstract method may belong to many interfaces due to embedding.
 type Func struct {
 	object
-	hasPtrRecv bool // only valid for methods that don't have a type yet
+	hasPtrRecv bool  // only valid for methods that don't have a type yet
+	origin     *Func // if non-nil, the Func from which this one was instantiated
 }
 
 // NewFunc returns a new function with the given signature, representing
@@ -362,7 +450,7 @@ func NewFunc(pos syntax.Pos, pkg *Package, name string, sig *Signature) *Func {
 	if sig != nil {
 		typ = sig
 	}
-	return &Func{object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos}, false}
+	return &Func{object{nil, pos, pkg, name, typ, 0, colorFor(typ), nopos, nil, nil}, false, nil}
 }
 
 // FullName returns the package- or receiver-type-qualified name of
@@ -376,6 +464,22 @@ func (obj *Func) FullName() string {
 // Scope returns the scope of the function's body block.
 func (obj *Func) Scope() *Scope { return obj.typ.(*Signature).scope }
 
+// Origin returns the canonical Func for its receiver, i.e. the Func object
+// recorded in Info.Defs. For methods created during instantiation (such as
+// the methods of an instantiated type, or of an instantiated interface),
+// this is the corresponding method of the generic declaration. For all other
+// functions, Origin returns the receiver.
+func (obj *Func) Origin() *Func {
+	if obj.origin != nil {
+		return obj.origin
+	}
+	return obj
+}
+
+// Deprecated returns the deprecation paragraph of the doc comment of the
+// function's origin, if any.
+func (obj *Func) Deprecated() string { return deprecation(obj.Origin().doc) }
+
 func (*Func) isDependency() {} // a function may be a dependency of an initialization expression
 
 // A Label represents a declared label.
@@ -475,7 +579,9 @@ func writeObject(buf *bytes.Buffer, obj Object, qf Qualifier) {
 		if _, ok := typ.(*Basic); ok {
 			return
 		}
-		if named, _ := typ.(*Named); named != nil && named.TParams().Len() > 0 {
+		if tname.tparams != nil {
+			newTypeWriter(buf, qf).tParamList(tname.tparams.list())
+		} else if named, _ := typ.(*Named); named != nil && named.TParams().Len() > 0 {
 			newTypeWriter(buf, qf).tParamList(named.TParams().list())
 		}
 		if tname.IsAlias() {
@@ -86,3 +86,39 @@ func TestEmbeddedMethod(t *testing.T) {
 		t.Fatalf("%s (%p) != %s (%p)", orig, orig, embed, embed)
 	}
 }
+
+func TestObjectData(t *testing.T) {
+	const src = `package p; type T struct{ f int }; func (T) m() {}`
+
+	f, err := parseSrc("", src)
+	if err != nil {
+		t.Fatalf("parse failed: %s", err)
+	}
+	var conf Config
+	pkg, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, nil)
+	if err != nil {
+		t.Fatalf("typecheck failed: %s", err)
+	}
+
+	// data attached to an object is found through any path to the object
+	T := pkg.Scope().Lookup("T")
+	if d := T.Data(); d != nil {
+		t.Fatalf("got initial data %v; want nil", d)
+	}
+	for _, name := range []string{"f", "m"} {
+		obj, _, _ := LookupFieldOrMethod(T.Type(), false, pkg, name)
+		obj.SetData(name + " fact")
+	}
+	T.SetData(1)
+	T.SetData(2)
+	if d := T.Data(); d != 2 {
+		t.Errorf("got data %v for T; want 2", d)
+	}
+	named := T.Type().(*Named)
+	if d := named.Method(0).Data(); d != "m fact" {
+		t.Errorf("got data %v for m; want %q", d, "m fact")
+	}
+	if d := named.Underlying().(*Struct).Field(0).Data(); d != "f fact" {
+		t.Errorf("got data %v for f; want %q", d, "f fact")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package objectpath_test
+
+import (
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+	"cmd/compile/internal/types2/objectpath"
+)
+
+const src = `
+package p
+
+type A struct{ X int }
+type B A
+
+type T interface {
+	f() (a string, b struct{ X int })
+}
+
+type List[E any] struct {
+	next *List[E]
+	val  E
+}
+
+func (l *List[E]) Push(v E) *List[E] { return &List[E]{l, v} }
+
+type Number interface{ ~int | ~float64 }
+
+func Sum[N Number](list ...N) (sum N) { return }
+
+type Getter[T any] interface{ Get() T }
+
+var V List[int]
+var G Getter[string]
+
+func F(x int) {
+	var local int
+	_ = local
+	_ = V.Push(x)
+}
+
+const c = 0
+`
+
+func check(t *testing.T) (*types2.Package, *types2.Info) {
+	t.Helper()
+	f, err := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types2.Info{
+		Defs:       make(map[*syntax.Name]types2.Object),
+		Selections: make(map[*syntax.SelectorExpr]*types2.Selection),
+	}
+	var conf types2.Config
+	pkg, err := conf.Check("p", []*syntax.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg, info
+}
+
+func TestPaths(t *testing.T) {
+	pkg, info := check(t)
+
+	lookup := func(name string) types2.Object { return pkg.Scope().Lookup(name) }
+	named := func(name string) *types2.Named { return lookup(name).Type().(*types2.Named) }
+	underStruct := func(typ types2.Type) *types2.Struct { return typ.Underlying().(*types2.Struct) }
+	sig := func(obj types2.Object) *types2.Signature { return obj.Type().(*types2.Signature) }
+
+	var push types2.Object // Push selected on the instantiated type List[int]
+	for _, sel := range info.Selections {
+		if sel.Obj().Name() == "Push" {
+			push = sel.Obj()
+		}
+	}
+
+	for _, test := range []struct {
+		obj  types2.Object
+		path string
+	}{
+		{lookup("A"), "A"},
+		{underStruct(named("A")).Field(0), "A.UF0"},
+		{lookup("T").Type().Underlying().(*types2.Interface).Method(0), "T.UM0"},
+		{sig(lookup("T").Type().Underlying().(*types2.Interface).Method(0)).Results().At(1), "T.UM0.RA1"},
+		{underStruct(sig(lookup("T").Type().Underlying().(*types2.Interface).Method(0)).Results().At(1).Type()).Field(0), "T.UM0.RA1.F0"},
+		{named("List").TParams().At(0).Obj(), "List.T0O"},
+		{underStruct(named("List")).Field(1), "List.UF1"},
+		{named("List").Method(0), "List.M0"},
+		{sig(named("List").Method(0)).RParams().At(0).Obj(), "List.M0.r0O"},
+		{sig(named("List").Method(0)).Params().At(0), "List.M0.PA0"},
+		{push, "List.M0"},
+		{sig(lookup("Sum")).TParams().At(0).Obj(), "Sum.T0O"},
+		{sig(lookup("Sum")).Results().At(0), "Sum.RA0"},
+		{underStruct(lookup("V").Type()).Field(0), "List.UF0"},
+		{lookup("G").Type().Underlying().(*types2.Interface).Method(0), "Getter.UM0"},
+		{sig(lookup("F")).Params().At(0), "F.PA0"},
+	} {
+		path, err := objectpath.For(test.obj)
+		if err != nil {
+			t.Errorf("For(%v): %v", test.obj, err)
+			continue
+		}
+		if string(path) != test.path {
+			t.Errorf("For(%v) = %q; want %q", test.obj, path, test.path)
+			continue
+		}
+		obj, err := objectpath.Object(pkg, path)
+		if err != nil {
+			t.Errorf("Object(%q): %v", path, err)
+			continue
+		}
+		// objects created by instantiation are resolved to their origin
+		want := test.obj
+		switch o := want.(type) {
+		case *types2.Var:
+			want = o.Origin()
+		case *types2.Func:
+			want = o.Origin()
+		}
+		if obj != want {
+			t.Errorf("Object(%q) = %v; want %v", path, obj, want)
+		}
+	}
+
+	// Objects without a path.
+	for _, name := range []string{"c", "local"} {
+		var obj types2.Object
+		for id, def := range info.Defs {
+			if id.Value == name {
+				obj = def
+			}
+		}
+		if path, err := objectpath.For(obj); err == nil {
+			t.Errorf("For(%v) = %q; want error", obj, path)
+		}
+	}
+}
+
+// TestCrossCheck verifies that the paths of the objects of one Check run
+// denote the corresponding objects of another Check run.
+func TestCrossCheck(t *testing.T) {
+	pkg1, info1 := check(t)
+	pkg2, _ := check(t)
+	if pkg1 == pkg2 {
+		t.Fatal("got the same package twice")
+	}
+
+	n := 0
+	for _, obj1 := range info1.Defs {
+		if obj1 == nil {
+			continue
+		}
+		path, err := objectpath.For(obj1)
+		if err != nil {
+			continue // objects without path are tested above
+		}
+		obj2, err := objectpath.Object(pkg2, path)
+		if err != nil {
+			t.Errorf("Object(%q): %v", path, err)
+			continue
+		}
+		if obj1.Name() != obj2.Name() || obj1.Pos().String() != obj2.Pos().String() {
+			t.Errorf("path %q denotes %v in one package and %v in the other", path, obj1, obj2)
+		}
+		n++
+	}
+	if n < 20 {
+		t.Errorf("found paths for only %d objects", n)
+	}
+}
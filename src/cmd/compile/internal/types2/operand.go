@@ -307,7 +307,7 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 					}
 
 				} else {
-					*reason = "missing method " + m.Name()
+					*reason = missingMethodMsg(V, m)
 				}
 			}
 			return false, _InvalidIfaceAssign
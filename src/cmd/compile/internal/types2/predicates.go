@@ -66,7 +66,15 @@ func isUntyped(typ Type) bool {
 	return !isTyped(typ)
 }
 
-func isOrdered(typ Type) bool { return is(typ, IsOrdered) }
+// isOrdered reports whether the ordering operators apply to values of type typ.
+// This is the case for ordered basic types, and for type parameters whose type
+// set contains only such types or which are constrained by ordered.
+func isOrdered(typ Type) bool {
+	if t := asTypeParam(typ); t != nil && t.iface().typeSet().ordered {
+		return true
+	}
+	return is(typ, IsOrdered)
+}
 
 func isConstType(typ Type) bool {
 	// Type parameters are never const types.
@@ -128,6 +136,84 @@ func hasNil(typ Type) bool {
 	return false
 }
 
+// isUnknown reports whether typ is unknown because of an earlier error,
+// i.e., whether typ is or is composed of the invalid type. Typ[Invalid]
+// is only used after an error was reported; operations involving an
+// unknown type should not report follow-on errors. For instance, the
+// (unknown) type func() T where T is not declared is not assignable
+// to func() int, but this is a consequence of the undeclared T.
+func isUnknown(typ Type) bool {
+	return unknown(typ, nil)
+}
+
+func unknown(typ Type, seen map[Type]bool) bool {
+	if seen[typ] {
+		return false
+	}
+	if seen == nil {
+		seen = make(map[Type]bool)
+	}
+	seen[typ] = true
+
+	switch t := typ.(type) {
+	case nil:
+		return false
+	case *Basic:
+		return t == Typ[Invalid]
+	case *Array:
+		return unknown(t.elem, seen)
+	case *Slice:
+		return unknown(t.elem, seen)
+	case *Struct:
+		for _, f := range t.fields {
+			if unknown(f.typ, seen) {
+				return true
+			}
+		}
+	case *Pointer:
+		return unknown(t.base, seen)
+	case *Tuple:
+		if t != nil {
+			for _, v := range t.vars {
+				if unknown(v.typ, seen) {
+					return true
+				}
+			}
+		}
+	case *Signature:
+		return unknown(t.params, seen) || unknown(t.results, seen)
+	case *Interface:
+		for _, m := range t.methods {
+			if unknown(m.typ, seen) {
+				return true
+			}
+		}
+		for _, e := range t.embeddeds {
+			if unknown(e, seen) {
+				return true
+			}
+		}
+	case *Union:
+		for _, term := range t.terms {
+			if unknown(term.typ, seen) {
+				return true
+			}
+		}
+	case *Map:
+		return unknown(t.key, seen) || unknown(t.elem, seen)
+	case *Chan:
+		return unknown(t.elem, seen)
+	case *Named:
+		for _, targ := range t.targs.list() {
+			if unknown(targ, seen) {
+				return true
+			}
+		}
+		return unknown(under(t), seen)
+	}
+	return false
+}
+
 // An ifacePair is a node in a stack of interface type pairs compared for identity.
 type ifacePair struct {
 	x, y *Interface
@@ -138,6 +224,34 @@ func (p *ifacePair) identical(q *ifacePair) bool {
 	return p.x == q.x && p.y == q.y || p.x == q.y && p.y == q.x
 }
 
+// A typePair is a pair of types (a key for Checker.identCache).
+type typePair struct{ x, y Type }
+
+// identical is like Identical but memoizes the results for check: if
+// interfaces embed many other interfaces, the same (possibly large) method
+// signatures may be compared over and over again. It must only be used for
+// types which are set up completely, such as in delayed actions.
+func (check *Checker) identical(x, y Type) bool {
+	if x == y {
+		return true
+	}
+	if check == nil {
+		return Identical(x, y)
+	}
+	if res, found := check.identCache[typePair{x, y}]; found {
+		return res
+	}
+	if res, found := check.identCache[typePair{y, x}]; found {
+		return res
+	}
+	res := Identical(x, y)
+	if check.identCache == nil {
+		check.identCache = make(map[typePair]bool)
+	}
+	check.identCache[typePair{x, y}] = res
+	return res
+}
+
 // For changes to this code the corresponding changes should be made to unifier.nify.
 func identical(x, y Type, cmpTags bool, p *ifacePair) bool {
 	if x == y {
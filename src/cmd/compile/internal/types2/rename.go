@@ -0,0 +1,164 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements RenameMethodEffects.
+
+package types2
+
+import "fmt"
+
+// A Satisfaction describes the fact that type T implements interface I.
+type Satisfaction struct {
+	T Type // implementing type; a concrete type, a pointer to a concrete type, or an interface
+	I Type // implemented interface; a defined interface type or a type parameter constraint
+}
+
+func (s Satisfaction) String() string {
+	return fmt.Sprintf("%s implements %s", s.T, s.I)
+}
+
+// RenameMethodEffects reports which interface satisfactions would break
+// and which would newly appear if method m were renamed to newName. The
+// packages pkgs must have been type-checked completely; the set of types
+// considered consists of the types declared at package level in pkgs and
+// of the constraints of type parameters declared at package level (for
+// generic types, functions, and methods).
+//
+// For each declared (non-interface) type T, both T and *T are considered
+// as implementations. Generic types are considered in their declared
+// form, i.e., instantiated with their own type parameters; a generic
+// interface is considered uninstantiated.
+//
+// If renaming m would lead to a conflict with another field or method
+// of m's receiver type, the result is an error.
+func RenameMethodEffects(m *Func, newName string, pkgs []*Package) (broken, added []Satisfaction, err error) {
+	sig, _ := m.typ.(*Signature)
+	if sig == nil || sig.recv == nil {
+		return nil, nil, fmt.Errorf("%s is not a method", m.name)
+	}
+	if newName == m.name {
+		return // nothing to do
+	}
+	if obj, index, _ := LookupFieldOrMethod(sig.recv.typ, true, m.pkg, newName); obj != nil && len(index) == 1 {
+		return nil, nil, fmt.Errorf("renaming %s to %s conflicts with %s", m.name, newName, obj)
+	}
+
+	types, ifaces := renameCandidates(pkgs)
+
+	// Only interfaces that have a method with the old or the new name
+	// can be affected by the renaming.
+	var relevant []int
+	for i, iface := range ifaces {
+		tset := asInterface(iface).typeSet()
+		if _, f := tset.LookupMethod(m.pkg, m.name); f != nil {
+			relevant = append(relevant, i)
+		} else if _, f := tset.LookupMethod(m.pkg, newName); f != nil {
+			relevant = append(relevant, i)
+		}
+	}
+
+	// Compute all relevant satisfactions, with the methods looked up by
+	// their names as changed by r. m itself is not modified.
+	satisfies := func(r *renaming) map[Satisfaction]bool {
+		res := make(map[Satisfaction]bool)
+		for _, i := range relevant {
+			I := ifaces[i]
+			for _, T := range types {
+				if T == I {
+					continue
+				}
+				if f, _ := (*Checker)(nil).missingRenamedMethod(T, asInterface(I), true, r); f == nil {
+					res[Satisfaction{T, I}] = true
+				}
+			}
+		}
+		return res
+	}
+
+	before := satisfies(nil)
+	after := satisfies(&renaming{m, object{pkg: m.pkg, name: newName}})
+
+	// report results in deterministic order
+	for _, i := range relevant {
+		I := ifaces[i]
+		for _, T := range types {
+			s := Satisfaction{T, I}
+			switch {
+			case before[s] && !after[s]:
+				broken = append(broken, s)
+			case !before[s] && after[s]:
+				added = append(added, s)
+			}
+		}
+	}
+
+	return
+}
+
+// renameCandidates returns the types declared at package level in pkgs
+// (incl. pointers to non-interface types), and the interfaces among them
+// plus all interfaces used as type parameter constraints. Interfaces in
+// the ifaces list are recorded with their defined types if available;
+// they all have an underlying *Interface with at least one method.
+func renameCandidates(pkgs []*Package) (types, ifaces []Type) {
+	seen := make(map[*Interface]bool)
+	addIface := func(T Type) {
+		if iface := asInterface(T); iface != nil && iface.NumMethods() > 0 && !seen[iface] {
+			seen[iface] = true
+			ifaces = append(ifaces, T)
+		}
+	}
+	addTParams := func(list *TParamList) {
+		for i := 0; i < list.Len(); i++ {
+			addIface(list.At(i).Constraint())
+		}
+	}
+
+	for _, pkg := range pkgs {
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			switch obj := scope.Lookup(name).(type) {
+			case *TypeName:
+				if obj.IsAlias() {
+					continue
+				}
+				named, _ := obj.typ.(*Named)
+				if named == nil {
+					continue
+				}
+				tparams := named.TParams()
+				addTParams(tparams)
+				for i := 0; i < named.NumMethods(); i++ {
+					if sig, _ := named.Method(i).typ.(*Signature); sig != nil {
+						addTParams(sig.TParams())
+					}
+				}
+				if iface := asInterface(named); iface != nil {
+					addIface(named)
+					types = append(types, named)
+					continue
+				}
+				// use generic types in their declared form
+				var T Type = named
+				if tparams.Len() > 0 {
+					targs := make([]Type, tparams.Len())
+					for i := range targs {
+						targs[i] = tparams.At(i)
+					}
+					T, _ = Instantiate(nil, named, targs, false)
+				}
+				types = append(types, T)
+				if !isPointer(T) {
+					types = append(types, NewPointer(T))
+				}
+			case *Func:
+				if sig, _ := obj.typ.(*Signature); sig != nil {
+					addTParams(sig.TParams())
+				}
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"strings"
+	"testing"
+
+	. "cmd/compile/internal/types2"
+)
+
+func TestRenameMethodEffects(t *testing.T) {
+	const src = genericPkg + `p
+
+type Stringer interface{ String() string }
+type Namer interface{ Name() string }
+type Both interface{ Stringer; Namer }
+
+type T struct{}
+
+func (T) String() string { return "" }
+func (*T) Title() string  { return "" }
+
+type U struct{}
+
+func (U) Name() string { return "" }
+
+type G[P any] struct{}
+
+func (G[P]) String() string { return "" }
+
+func F[P interface{ Name() string }](P) {}
+`
+	pkg, err := pkgFor("rename", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := pkg.Scope()
+	method := func(typ, name string) *Func {
+		obj, _, _ := LookupFieldOrMethod(scope.Lookup(typ).Type(), true, pkg, name)
+		return obj.(*Func)
+	}
+
+	for _, test := range []struct {
+		typ, method, newName string
+		broken, added        string
+	}{
+		{"T", "String", "Str",
+			"generic_p.T implements generic_p.Stringer, *generic_p.T implements generic_p.Stringer", ""},
+		{"T", "String", "Name",
+			"generic_p.T implements generic_p.Stringer, *generic_p.T implements generic_p.Stringer",
+			"generic_p.T implements interface{Name() string}, *generic_p.T implements interface{Name() string}, generic_p.T implements generic_p.Namer, *generic_p.T implements generic_p.Namer"},
+		{"U", "Name", "String",
+			"generic_p.U implements interface{Name() string}, *generic_p.U implements interface{Name() string}, generic_p.U implements generic_p.Namer, *generic_p.U implements generic_p.Namer",
+			"generic_p.U implements generic_p.Stringer, *generic_p.U implements generic_p.Stringer"},
+		{"G", "String", "Name",
+			"generic_p.G[generic_p.P₁] implements generic_p.Stringer, *generic_p.G[generic_p.P₁] implements generic_p.Stringer",
+			"generic_p.G[generic_p.P₁] implements interface{Name() string}, *generic_p.G[generic_p.P₁] implements interface{Name() string}, generic_p.G[generic_p.P₁] implements generic_p.Namer, *generic_p.G[generic_p.P₁] implements generic_p.Namer"},
+		// Both embeds Stringer and thus continues to implement it;
+		// *T continues to implement Stringer via (*T).Title.
+		{"Stringer", "String", "Title",
+			"generic_p.G[generic_p.P₁] implements generic_p.Stringer, *generic_p.G[generic_p.P₁] implements generic_p.Stringer, generic_p.T implements generic_p.Stringer",
+			""},
+	} {
+		m := method(test.typ, test.method)
+		broken, added, err := RenameMethodEffects(m, test.newName, []*Package{pkg})
+		if err != nil {
+			t.Errorf("%s.%s -> %s: %v", test.typ, test.method, test.newName, err)
+			continue
+		}
+		if got := satisfactionsString(broken); got != test.broken {
+			t.Errorf("%s.%s -> %s: got broken %s; want %s", test.typ, test.method, test.newName, got, test.broken)
+		}
+		if got := satisfactionsString(added); got != test.added {
+			t.Errorf("%s.%s -> %s: got added %s; want %s", test.typ, test.method, test.newName, got, test.added)
+		}
+		if m.Name() != test.method {
+			t.Errorf("%s.%s -> %s: method name changed", test.typ, test.method, test.newName)
+		}
+	}
+
+	// renaming T.String to Title conflicts with (*T).Title
+	if _, _, err := RenameMethodEffects(method("T", "String"), "Title", []*Package{pkg}); err == nil {
+		t.Errorf("T.String -> Title: missing conflict error")
+	}
+}
+
+func satisfactionsString(list []Satisfaction) string {
+	var s []string
+	for _, x := range list {
+		s = append(s, x.String())
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,432 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements Checker.ReplaceFile, which re-checks a package
+// incrementally after one of its files changed.
+
+package types2
+
+import (
+	"cmd/compile/internal/syntax"
+	"errors"
+)
+
+// A checkedFile describes a file checked by Checker.Files.
+type checkedFile struct {
+	file    *syntax.File
+	scope   *Scope     // file scope
+	imports []*Package // packages imported by the file
+}
+
+// A redeclSet describes the declarations of already checked files that
+// Checker.ReplaceFile collects and checks again.
+type redeclSet struct {
+	files map[*syntax.File]*Scope // files with such declarations, and their file scopes
+	decls map[syntax.Decl]bool    // declarations to collect again
+	names map[syntax.Pos]bool     // positions of the names they declare
+}
+
+// scope returns the file scope of file if file is an already checked file
+// whose declarations are collected again; otherwise it returns nil.
+func (r *redeclSet) scope(file *syntax.File) *Scope {
+	if r == nil {
+		return nil
+	}
+	return r.files[file]
+}
+
+// skip reports whether decl is a declaration of an already checked file
+// that is not collected again.
+func (r *redeclSet) skip(file *syntax.File, decl syntax.Decl) bool {
+	return r.scope(file) != nil && !r.decls[decl]
+}
+
+var errNotChecked = errors.New("file was not checked")
+
+// ReplaceFile replaces the file old, which must have been checked with
+// Files, with the file new, and type-checks the package incrementally.
+// If new is nil, old is removed from the package.
+//
+// Only the package-level declarations that may depend on the change are
+// checked again: the declarations of new, and the declarations of other
+// files that refer, directly or indirectly, to a name declared by old or
+// new or to the receiver base type of one of their methods. Whether a
+// declaration refers to a name is determined conservatively, from the
+// identifiers it contains. The objects of all other declarations and
+// their type information are retained.
+//
+// The type information for old and for the declarations checked again is
+// removed from the checker's Info and recorded anew, and Info.InitOrder
+// is recomputed. Errors are reported for the declarations checked again
+// only. As for Files, the result is the first error reported.
+func (check *Checker) ReplaceFile(old, new *syntax.File) (err error) {
+	index := -1
+	for i, cf := range check.checked {
+		if cf.file == old {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return errNotChecked
+	}
+
+	defer check.handleBailout(&err)
+	defer func() {
+		check.phase = ""
+		check.redecl = nil
+		check.releaseTypes()
+		if check.pending != nil {
+			check.reportPending()
+		}
+	}()
+
+	check.startPhase("initFiles")
+	check.files = nil
+	check.imports = nil
+	check.dotImportMap = nil
+	check.firstErr = nil
+	check.methods = nil
+	check.untyped = nil
+	check.delayed = nil
+	check.names = nil
+	check.bodies = nil
+	check.pending = nil
+	check.declTParams = nil
+	check.usedTParams = nil
+	check.nerrors = 0
+	check.methodsKnown = false
+	if new != nil && new.PkgName.Value != check.pkg.name {
+		check.errorf(new, _MismatchedPkgName, "package %s; expected %s", new.PkgName.Value, check.pkg.name)
+		new = nil // ignore this file
+	}
+	if new != nil {
+		check.recordFileVersion(new)
+	}
+
+	check.startPhase("invalidate")
+	redecl := check.dependents(index, new)
+	check.invalidate(index, redecl)
+
+	// Collect the objects of new and the dependent declarations again,
+	// in source order, and move new to the position of old.
+	check.checked = append(check.checked[:index], check.checked[index+1:]...)
+	for i, cf := range check.checked {
+		if i == index && new != nil {
+			check.files = append(check.files, new)
+		}
+		if redecl.files[cf.file] != nil {
+			check.files = append(check.files, cf.file)
+		}
+	}
+	if index == len(check.checked) && new != nil {
+		check.files = append(check.files, new)
+	}
+	check.redecl = redecl
+
+	check.startPhase("collectObjects")
+	check.collectObjects()
+	if new != nil {
+		last := len(check.checked) - 1
+		cf := check.checked[last]
+		copy(check.checked[index+1:], check.checked[index:last])
+		check.checked[index] = cf
+	}
+	check.renumberObjects()
+	check.updateImports()
+
+	check.checkObjects()
+
+	return
+}
+
+// dependents returns the declarations of the checked files other than
+// the file check.checked[index] that are to be checked again if that file
+// is replaced by new (which may be nil).
+func (check *Checker) dependents(index int, new *syntax.File) *redeclSet {
+	r := &redeclSet{
+		files: make(map[*syntax.File]*Scope),
+		decls: make(map[syntax.Decl]bool),
+		names: make(map[syntax.Pos]bool),
+	}
+
+	// names that may refer to changed objects
+	changed := make(map[string]bool)
+	forEachDecl(check.checked[index].file, func(decl syntax.Decl, _ map[string]bool) {
+		referencedNames(decl, changed)
+	})
+	if new != nil {
+		forEachDecl(new, func(decl syntax.Decl, _ map[string]bool) {
+			referencedNames(decl, changed)
+		})
+	}
+
+	// the declarations of the other files, with the names they contain
+	type declRefs struct {
+		cf   *checkedFile
+		decl syntax.Decl
+		refs map[string]bool
+	}
+	var list []declRefs
+	for i, cf := range check.checked {
+		if i != index {
+			forEachDecl(cf.file, func(decl syntax.Decl, refs map[string]bool) {
+				list = append(list, declRefs{cf, decl, refs})
+			})
+		}
+	}
+
+	// Declarations that refer to changed names change themselves;
+	// repeat until no more declarations are affected.
+	for more := true; more; {
+		more = false
+		for _, d := range list {
+			if r.decls[d.decl] {
+				continue
+			}
+			for name := range d.refs {
+				if changed[name] {
+					r.files[d.cf.file] = d.cf.scope
+					r.decls[d.decl] = true
+					for _, name := range declaredNames(d.decl) {
+						r.names[name.Pos()] = true
+					}
+					referencedNames(d.decl, changed)
+					more = true
+					break
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+// forEachDecl calls f for each declaration of file other than imports,
+// with the set of identifiers contained in the declaration. For a
+// constant declaration with an inherited initialization expression, the
+// set includes the identifiers of that expression and its type.
+func forEachDecl(file *syntax.File, f func(decl syntax.Decl, refs map[string]bool)) {
+	var group *syntax.Group
+	var last *syntax.ConstDecl // last ConstDecl with init expressions in group, or nil
+	for _, decl := range file.DeclList {
+		refs := make(map[string]bool)
+		switch s := decl.(type) {
+		case *syntax.ImportDecl:
+			continue
+		case *syntax.ConstDecl:
+			if s.Group == nil || s.Group != group {
+				last = nil
+			}
+			group = s.Group
+			if s.Type != nil || s.Values != nil {
+				last = s
+			} else if last != nil {
+				addNames(refs, last.Type)
+				addNames(refs, last.Values)
+			}
+		}
+		addNames(refs, decl)
+		f(decl, refs)
+	}
+}
+
+// addNames adds the identifiers contained in n, if any, to the set names.
+func addNames(names map[string]bool, n syntax.Node) {
+	if n == nil {
+		return
+	}
+	syntax.Inspect(n, func(n syntax.Node) bool {
+		if name, _ := n.(*syntax.Name); name != nil {
+			names[name.Value] = true
+		}
+		return true
+	})
+}
+
+// declaredNames returns the identifiers of the objects declared by decl.
+func declaredNames(decl syntax.Decl) []*syntax.Name {
+	switch s := decl.(type) {
+	case *syntax.ConstDecl:
+		return s.NameList
+	case *syntax.VarDecl:
+		return s.NameList
+	case *syntax.TypeDecl:
+		return []*syntax.Name{s.Name}
+	case *syntax.FuncDecl:
+		return []*syntax.Name{s.Name}
+	}
+	return nil
+}
+
+// referencedNames adds the names by which other declarations may refer
+// to the objects declared by decl, or observe a change of them, to the
+// set names: the names of the declared objects other than methods, the
+// receiver base type names of methods, and the names in the right-hand
+// side of alias declarations (whose methods are those of the aliased
+// type).
+func referencedNames(decl syntax.Decl, names map[string]bool) {
+	switch s := decl.(type) {
+	case *syntax.FuncDecl:
+		if s.Recv != nil {
+			var check *Checker // unpackRecv doesn't need a checker if type parameters are not unpacked
+			if _, rname, _ := check.unpackRecv(s.Recv.Type, false); rname != nil {
+				names[rname.Value] = true
+			}
+			return
+		}
+	case *syntax.TypeDecl:
+		if s.Alias {
+			addNames(names, s.Type)
+		}
+	}
+	for _, name := range declaredNames(decl) {
+		names[name.Value] = true
+	}
+}
+
+// invalidate removes the objects declared by the file check.checked[index]
+// and by the declarations of r, and the type information recorded for
+// them.
+func (check *Checker) invalidate(index int, r *redeclSet) {
+	cf := check.checked[index]
+	for obj, d := range check.objMap {
+		if d.file != cf.scope && !r.names[obj.Pos()] {
+			continue
+		}
+		delete(check.objMap, obj)
+		if check.pkg.scope.elems[obj.Name()] == obj {
+			delete(check.pkg.scope.elems, obj.Name())
+		}
+		switch obj := obj.(type) {
+		case *Const:
+			delete(check.targetConsts, obj)
+		case *Func:
+			// A method may belong to a type that is not checked again
+			// (if it was declared through an alias).
+			if sig, _ := obj.typ.(*Signature); sig != nil && sig.recv != nil {
+				if base, _ := deref(sig.recv.typ); asNamed(base) != nil {
+					base := asNamed(base).orig
+					if i, _ := lookupMethod(base.methods, obj.pkg, obj.name); i >= 0 && base.methods[i] == obj {
+						base.methods = append(base.methods[:i:i], base.methods[i+1:]...)
+					}
+				}
+			}
+		}
+	}
+
+	check.forget(cf.file)
+	check.pkg.scope.removeChildren(func(s *Scope) bool { return s == cf.scope })
+	for file, scope := range r.files {
+		var decls []syntax.Decl
+		for _, decl := range file.DeclList {
+			if r.decls[decl] {
+				check.forget(decl)
+				decls = append(decls, decl)
+			}
+		}
+		// remove the scopes of declarations (such as function scopes)
+		scope.removeChildren(func(s *Scope) bool {
+			for _, decl := range decls {
+				if syntax.StartPos(decl).Cmp(s.pos) <= 0 && s.pos.Cmp(syntax.EndPos(decl)) <= 0 {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	// Instances may refer to removed types; they are created again as
+	// needed.
+	check.typMap = make(map[string]*Named)
+}
+
+// forget removes the type information recorded for n and the nodes it
+// contains.
+func (check *Checker) forget(n syntax.Node) {
+	syntax.Inspect(n, func(n syntax.Node) bool {
+		if n == nil {
+			return false
+		}
+		if x, _ := n.(syntax.Expr); x != nil {
+			delete(check.Types, x)
+			delete(check.Inferred, x)
+			delete(check.TargetValues, x)
+			delete(check.Conversions, x)
+			delete(check.ImplicitOps, x)
+			delete(check.ImplicitInterfaces, x)
+			delete(check.NonNil, x)
+			delete(check.targetVals, x)
+		}
+		if id, _ := n.(*syntax.Name); id != nil {
+			delete(check.Defs, id)
+			delete(check.Uses, id)
+			delete(check.Deprecated, id)
+		}
+		if sel, _ := n.(*syntax.SelectorExpr); sel != nil {
+			delete(check.Selections, sel)
+		}
+		delete(check.Implicits, n)
+		delete(check.DeclaredTypes, n)
+		delete(check.Scopes, n)
+		return true
+	})
+}
+
+// removeChildren removes the children of s for which remove reports true.
+func (s *Scope) removeChildren(remove func(*Scope) bool) {
+	list := s.children[:0]
+	for _, c := range s.children {
+		if !remove(c) {
+			list = append(list, c)
+			c.number = len(list)
+		}
+	}
+	for i := len(list); i < len(s.children); i++ {
+		s.children[i] = nil
+	}
+	s.children = list
+}
+
+// renumberObjects sets the order of all package-level objects and methods
+// to their source order.
+func (check *Checker) renumberObjects() {
+	byPos := make(map[syntax.Pos]Object, len(check.objMap))
+	for obj := range check.objMap {
+		byPos[obj.Pos()] = obj
+	}
+	var order uint32
+	for _, cf := range check.checked {
+		for _, decl := range cf.file.DeclList {
+			for _, name := range declaredNames(decl) {
+				if obj := byPos[name.Pos()]; obj != nil {
+					order++
+					obj.setOrder(order)
+				}
+			}
+		}
+	}
+}
+
+// updateImports recomputes the list of imported packages and the height
+// of the package from the imports of all checked files.
+func (check *Checker) updateImports() {
+	pkg := check.pkg
+	pkg.imports = nil
+	pkg.height = 0
+	seen := make(map[*Package]bool)
+	for _, cf := range check.checked {
+		for _, imp := range cf.imports {
+			if imp != Unsafe {
+				if h := imp.height + 1; h > pkg.height {
+					pkg.height = h
+				}
+			}
+			if !seen[imp] {
+				seen[imp] = true
+				pkg.imports = append(pkg.imports, imp)
+			}
+		}
+	}
+}
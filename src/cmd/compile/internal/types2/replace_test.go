@@ -0,0 +1,150 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"cmd/compile/internal/syntax"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "cmd/compile/internal/types2"
+)
+
+var replaceSrcs = []string{
+	`package p
+
+import "strings"
+
+type T struct{ s string }
+
+func (t T) Upper() string { return strings.ToUpper(t.s) }
+
+var a = b + 1
+`,
+	`package p
+
+const (
+	c0 = iota * k
+	c1
+)
+
+var b = F(c1)
+`,
+	`package p
+
+const k = 2
+
+func F(x int) int { return x * k }
+`,
+	`package p
+
+import "fmt"
+
+func H() { fmt.Println(V) }
+
+var V = T{"v"}.Upper()
+`,
+}
+
+func TestReplaceFile(t *testing.T) {
+	parse := func(i int, src string) *syntax.File {
+		f, err := syntax.Parse(syntax.NewFileBase(fmt.Sprintf("p%d.go", i)), strings.NewReader(src), nil, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	newInfo := func() *Info {
+		return &Info{
+			Types:      make(map[syntax.Expr]TypeAndValue),
+			Defs:       make(map[*syntax.Name]Object),
+			Uses:       make(map[*syntax.Name]Object),
+			Implicits:  make(map[syntax.Node]Object),
+			Selections: make(map[*syntax.SelectorExpr]*Selection),
+			Scopes:     make(map[syntax.Node]*Scope),
+		}
+	}
+	describe := func(pkg *Package, files []*syntax.File, info *Info) string {
+		var inits []string
+		for _, init := range info.InitOrder {
+			inits = append(inits, init.String())
+		}
+		// the sizes of the maps show stale entries for nodes that are gone
+		return fmt.Sprintf("%s\nscope %s\ninit %s\nfiles %d\nsizes %d %d %d %d %d %d",
+			serializeDump(files, info), strings.Join(pkg.Scope().Names(), " "), strings.Join(inits, "; "), pkg.Scope().NumChildren(),
+			len(info.Types), len(info.Defs), len(info.Uses), len(info.Implicits), len(info.Selections), len(info.Scopes))
+	}
+
+	for _, test := range []struct {
+		index int
+		src   string // "" means the file is removed
+		keepH bool   // whether the object for H is retained
+	}{
+		{0, replaceSrcs[0], false},
+		{1, replaceSrcs[1], true},
+		{2, "package p\n\nconst k = 3\n\nfunc F(x int) int { return x - k }\n", true},
+		{2, "package p\n\nfunc F(x int) int { return x }\n\nconst k = 1\n\nvar X = 1\n", true},
+		{0, "package p\n\ntype T struct{ s string }\n\nfunc (t T) Upper() string { return t.s }\n\nvar a = b\n", false},
+		{3, "package p\n\nvar V = F(k)\n", false},
+		{3, "", false},
+	} {
+		var files []*syntax.File
+		for i, src := range replaceSrcs {
+			files = append(files, parse(i, src))
+		}
+		imp := defaultImporter()
+		conf := Config{Importer: imp}
+		info := newInfo()
+		pkg := NewPackage("p", "p")
+		check := NewChecker(&conf, pkg, info)
+		if err := check.Files(files); err != nil {
+			t.Fatal(err)
+		}
+		H := pkg.Scope().Lookup("H")
+
+		var new *syntax.File
+		if test.src != "" {
+			new = parse(test.index, test.src)
+		}
+		if err := check.ReplaceFile(files[test.index], new); err != nil {
+			t.Errorf("%d: %s", test.index, err)
+			continue
+		}
+
+		// the result must match a check of the changed files from scratch
+		var want []*syntax.File
+		for i, src := range replaceSrcs {
+			if i == test.index {
+				src = test.src
+			}
+			if src != "" {
+				want = append(want, parse(i, src))
+			}
+		}
+		files[test.index] = new
+		if new == nil {
+			files = append(files[:test.index], files[test.index+1:]...)
+		}
+		wantInfo := newInfo()
+		wantPkg, err := conf.Check("p", want, wantInfo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := describe(pkg, files, info), describe(wantPkg, want, wantInfo); got != want {
+			t.Errorf("%d: got:\n%s\nwant:\n%s", test.index, got, want)
+		}
+
+		if test.keepH && pkg.Scope().Lookup("H") != H {
+			t.Errorf("%d: object for H was recreated", test.index)
+		}
+	}
+
+	// a file that was not checked cannot be replaced
+	check := NewChecker(&Config{}, NewPackage("p", "p"), nil)
+	if err := check.ReplaceFile(parse(0, replaceSrcs[2]), nil); err == nil {
+		t.Errorf("replacing an unchecked file succeeded")
+	}
+}
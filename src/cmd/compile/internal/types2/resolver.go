@@ -26,6 +26,10 @@ type declInfo struct {
 
 	// The deps field tracks initialization expression dependencies.
 	deps map[Object]bool // lazily initialized
+
+	// The uses field tracks all package-level objects the declaration
+	// refers to; it is only maintained if Info.Dependencies is set.
+	uses map[Object]bool // lazily initialized
 }
 
 // hasInitializer reports whether the declared object has an initialization
@@ -52,17 +56,18 @@ func (check *Checker) arity(pos syntax.Pos, names []*syntax.Name, inits []syntax
 	l := len(names)
 	r := len(inits)
 
+	const code = _WrongAssignCount
 	switch {
 	case l < r:
 		n := inits[l]
 		if inherited {
-			check.errorf(pos, "extra init expr at %s", n.Pos())
+			check.errorf(pos, code, "extra init expr at %s", n.Pos())
 		} else {
-			check.errorf(n, "extra init expr %s", n)
+			check.errorf(n, code, "extra init expr %s", n)
 		}
 	case l > r && (constDecl || r != 1): // if r == 1 it may be a multi-valued function and we can't say anything yet
 		n := names[r]
-		check.errorf(n, "missing init expr for %s", n.Value)
+		check.errorf(n, code, "missing init expr for %s", n.Value)
 	}
 }
 
@@ -91,14 +96,14 @@ func (check *Checker) declarePkgObj(ident *syntax.Name, obj Object, d *declInfo)
 	// spec: "A package-scope or file-scope identifier with name init
 	// may only be declared to be a function with this (func()) signature."
 	if ident.Value == "init" {
-		check.error(ident, "cannot declare init - must be func")
+		check.error(ident, _InvalidInitDecl, "cannot declare init - must be func")
 		return
 	}
 
 	// spec: "The main package must have package name main and declare
 	// a function main that takes no arguments and returns no value."
 	if ident.Value == "main" && check.pkg.name == "main" {
-		check.error(ident, "cannot declare main - must be func")
+		check.error(ident, _InvalidMainDecl, "cannot declare main - must be func")
 		return
 	}
 
@@ -158,7 +163,7 @@ func (check *Checker) importPackage(pos syntax.Pos, path, dir string) *Package {
 			imp = nil // create fake package below
 		}
 		if err != nil {
-			check.errorf(pos, "could not import %s (%s)", path, err)
+			check.errorf(pos, _BrokenImport, "could not import %s (%s)", path, err)
 			if imp == nil {
 				// create a new fake package
 				// come up with a sensible package name (heuristic)
@@ -216,14 +221,25 @@ func (check *Checker) collectObjects() {
 	}
 	var methods []methodInfo // collected methods with valid receivers and non-blank _ names
 	var fileScopes []*Scope
+	var reused []bool // reused[i] is set if fileScopes[i] is the scope of an already checked file
 	for fileNo, file := range check.files {
-		// The package identifier denotes the current package,
-		// but there is no corresponding package object.
-		check.recordDef(file.PkgName, nil)
-
-		fileScope := NewScope(check.pkg.scope, syntax.StartPos(file), syntax.EndPos(file), check.filename(fileNo))
+		// Declarations of already checked files are collected again
+		// by Checker.ReplaceFile, in their existing file scope; their
+		// imports are not.
+		var cf *checkedFile
+		fileScope := check.redecl.scope(file)
+		reused = append(reused, fileScope != nil)
+		if fileScope == nil {
+			// The package identifier denotes the current package,
+			// but there is no corresponding package object.
+			check.recordDef(file.PkgName, nil)
+
+			fileScope = NewScope(check.pkg.scope, syntax.StartPos(file), syntax.EndPos(file), check.filename(fileNo))
+			check.recordScope(file, fileScope)
+			cf = &checkedFile{file: file, scope: fileScope}
+			check.checked = append(check.checked, cf)
+		}
 		fileScopes = append(fileScopes, fileScope)
-		check.recordScope(file, fileScope)
 
 		// determine file directory, necessary to resolve imports
 		// FileName may be "" (typically for tests) in which case
@@ -237,6 +253,14 @@ func (check *Checker) collectObjects() {
 				first = -1 // we're not in a constant declaration
 			}
 
+			// Constant declarations that are not collected again still
+			// determine the iota and initialization expressions of the
+			// following ones.
+			skip := check.redecl.skip(file, decl)
+			if _, ok := decl.(*syntax.ConstDecl); skip && !ok {
+				continue
+			}
+
 			switch s := decl.(type) {
 			case *syntax.ImportDecl:
 				// import package
@@ -245,7 +269,7 @@ func (check *Checker) collectObjects() {
 				}
 				path, err := validatedImportPath(s.Path.Value)
 				if err != nil {
-					check.errorf(s.Path, "invalid import path (%s)", err)
+					check.errorf(s.Path, _BadImportPath, "invalid import path (%s)", err)
 					continue
 				}
 
@@ -253,6 +277,7 @@ func (check *Checker) collectObjects() {
 				if imp == nil {
 					continue
 				}
+				cf.imports = append(cf.imports, imp)
 
 				if imp == Unsafe {
 					// typecheck ignores imports of package unsafe for
@@ -269,13 +294,13 @@ func (check *Checker) collectObjects() {
 					name = s.LocalPkgName.Value
 					if path == "C" {
 						// match cmd/compile (not prescribed by spec)
-						check.error(s.LocalPkgName, `cannot rename import "C"`)
+						check.error(s.LocalPkgName, _ImportCRenamed, `cannot rename import "C"`)
 						continue
 					}
 				}
 
 				if name == "init" {
-					check.error(s, "cannot import package as init - init must be a func")
+					check.error(s, _InvalidInitDecl, "cannot import package as init - init must be a func")
 					continue
 				}
 
@@ -288,6 +313,7 @@ func (check *Checker) collectObjects() {
 				}
 
 				pkgName := NewPkgName(s.Pos(), pkg, name, imp)
+				pkgName.doc = declDoc(file.DeclList, index)
 				if s.LocalPkgName != nil {
 					// in a dot-import, the dot represents the package
 					check.recordDef(s.LocalPkgName, pkgName)
@@ -322,6 +348,7 @@ func (check *Checker) collectObjects() {
 							// concurrently. See issue #32154.)
 							if alt := fileScope.Lookup(name); alt != nil {
 								var err error_
+								err.code = _DuplicateDecl
 								err.errorf(s.LocalPkgName, "%s redeclared in this block", alt.Name())
 								err.recordAltDecl(alt)
 								check.report(&err)
@@ -355,11 +382,15 @@ func (check *Checker) collectObjects() {
 					last = new(syntax.ConstDecl) // make sure last exists
 					inherited = false
 				}
+				if skip {
+					break
+				}
 
 				// declare all constants
 				values := unpackExpr(last.Values)
 				for i, name := range s.NameList {
 					obj := NewConst(name.Pos(), pkg, name.Value, nil, iota)
+					obj.doc = declDoc(file.DeclList, index)
 
 					var init syntax.Expr
 					if i < len(values) {
@@ -391,6 +422,7 @@ func (check *Checker) collectObjects() {
 				values := unpackExpr(s.Values)
 				for i, name := range s.NameList {
 					obj := NewVar(name.Pos(), pkg, name.Value, nil)
+					obj.doc = declDoc(file.DeclList, index)
 					lhs[i] = obj
 
 					d := d1
@@ -412,25 +444,31 @@ func (check *Checker) collectObjects() {
 				}
 
 			case *syntax.TypeDecl:
-				if len(s.TParamList) != 0 && !check.allowVersion(pkg, 1, 18) {
-					check.softErrorf(s.TParamList[0], "type parameters require go1.18 or later")
+				if len(s.TParamList) != 0 && !check.allowVersion(pkg, s, 1, 18) {
+					check.softErrorf(s.TParamList[0], _Todo, "type parameters require go1.18 or later")
 				}
 				obj := NewTypeName(s.Name.Pos(), pkg, s.Name.Value, nil)
+				obj.doc = declDoc(file.DeclList, index)
 				check.declarePkgObj(s.Name, obj, &declInfo{file: fileScope, tdecl: s})
 
 			case *syntax.FuncDecl:
 				name := s.Name.Value
 				obj := NewFunc(s.Name.Pos(), pkg, name, nil)
+				obj.doc = declDoc(file.DeclList, index)
 				hasTParamError := false // avoid duplicate type parameter errors
 				if s.Recv == nil {
 					// regular function
 					if name == "init" || name == "main" && pkg.name == "main" {
+						code := _InvalidInitDecl
+						if name == "main" {
+							code = _InvalidMainDecl
+						}
 						if len(s.TParamList) != 0 {
-							check.softErrorf(s.TParamList[0], "func %s must have no type parameters", name)
+							check.softErrorf(s.TParamList[0], code, "func %s must have no type parameters", name)
 							hasTParamError = true
 						}
 						if t := s.Type; len(t.ParamList) != 0 || len(t.ResultList) != 0 {
-							check.softErrorf(s, "func %s must have no arguments and no return values", name)
+							check.softErrorf(s, code, "func %s must have no arguments and no return values", name)
 						}
 					}
 					// don't declare init functions in the package scope - they are invisible
@@ -440,7 +478,7 @@ func (check *Checker) collectObjects() {
 						// init functions must have a body
 						if s.Body == nil {
 							// TODO(gri) make this error message consistent with the others above
-							check.softErrorf(obj.pos, "missing function body")
+							check.softErrorf(obj.pos, _MissingInitBody, "missing function body")
 						}
 					} else {
 						check.declare(pkg.scope, s.Name, obj, nopos)
@@ -448,9 +486,9 @@ func (check *Checker) collectObjects() {
 				} else {
 					// method
 					// d.Recv != nil
-					if !acceptMethodTypeParams && len(s.TParamList) != 0 {
-						//check.error(d.TParamList.Pos(), invalidAST + "method must have no type parameters")
-						check.error(s.TParamList[0], invalidAST+"method must have no type parameters")
+					if !check.methodTypeParams() && len(s.TParamList) != 0 {
+						//check.error(d.TParamList.Pos(), _InvalidSyntaxTree, invalidAST + "method must have no type parameters")
+						check.error(s.TParamList[0], _InvalidSyntaxTree, invalidAST+"method must have no type parameters")
 						hasTParamError = true
 					}
 					ptr, recv, _ := check.unpackRecv(s.Recv.Type, false)
@@ -462,8 +500,8 @@ func (check *Checker) collectObjects() {
 					}
 					check.recordDef(s.Name, obj)
 				}
-				if len(s.TParamList) != 0 && !check.allowVersion(pkg, 1, 18) && !hasTParamError {
-					check.softErrorf(s.TParamList[0], "type parameters require go1.18 or later")
+				if len(s.TParamList) != 0 && !check.allowVersion(pkg, s, 1, 18) && !hasTParamError {
+					check.softErrorf(s.TParamList[0], _Todo, "type parameters require go1.18 or later")
 				}
 				info := &declInfo{file: fileScope, fdecl: s}
 				// Methods are not package-level objects but we still track them in the
@@ -474,17 +512,20 @@ func (check *Checker) collectObjects() {
 				obj.setOrder(uint32(len(check.objMap)))
 
 			default:
-				check.errorf(s, invalidAST+"unknown syntax.Decl node %T", s)
+				check.errorf(s, _InvalidSyntaxTree, invalidAST+"unknown syntax.Decl node %T", s)
 			}
 		}
 	}
 
 	// verify that objects in package and file scopes have different names
-	for _, scope := range fileScopes {
+	// (for the scopes of already checked files, only for the objects
+	// declared again)
+	for i, scope := range fileScopes {
 		for name, obj := range scope.elems {
-			if alt := pkg.scope.Lookup(name); alt != nil {
+			if alt := pkg.scope.Lookup(name); alt != nil && (!reused[i] || check.redecl.names[alt.Pos()]) {
 				obj = resolve(name, obj)
 				var err error_
+				err.code = _DuplicateDecl
 				if pkg, ok := obj.(*PkgName); ok {
 					err.errorf(alt, "%s already declared through import of %s", alt.Name(), pkg.Imported())
 					err.recordAltDecl(pkg)
@@ -556,9 +597,9 @@ L: // unpack receiver type
 				case *syntax.BadExpr:
 					// ignore - error already reported by parser
 				case nil:
-					check.error(ptyp, invalidAST+"parameterized receiver contains nil parameters")
+					check.error(ptyp, _InvalidSyntaxTree, invalidAST+"parameterized receiver contains nil parameters")
 				default:
-					check.errorf(arg, "receiver type parameter %s must be an identifier", arg)
+					check.errorf(arg, _Todo, "receiver type parameter %s must be an identifier", arg)
 				}
 				if par == nil {
 					par = syntax.NewName(arg.Pos(), "_")
@@ -643,6 +684,44 @@ func (check *Checker) resolveBaseTypeName(seenPtr bool, typ syntax.Expr) (ptr bo
 	}
 }
 
+// declDoc returns the doc comment of the declaration list[i]. As with
+// go/doc, a declaration without doc comment which is the only one of its
+// group is documented by the doc comment of the group.
+func declDoc(list []syntax.Decl, i int) *syntax.CommentGroup {
+	doc, group := declDocGroup(list[i])
+	if doc != nil || group == nil {
+		return doc
+	}
+	if i > 0 {
+		if _, g := declDocGroup(list[i-1]); g == group {
+			return nil
+		}
+	}
+	if i+1 < len(list) {
+		if _, g := declDocGroup(list[i+1]); g == group {
+			return nil
+		}
+	}
+	return group.Doc
+}
+
+// declDocGroup returns the doc comment and group of the declaration d.
+func declDocGroup(d syntax.Decl) (*syntax.CommentGroup, *syntax.Group) {
+	switch d := d.(type) {
+	case *syntax.ImportDecl:
+		return d.Doc, d.Group
+	case *syntax.ConstDecl:
+		return d.Doc, d.Group
+	case *syntax.TypeDecl:
+		return d.Doc, d.Group
+	case *syntax.VarDecl:
+		return d.Doc, d.Group
+	case *syntax.FuncDecl:
+		return d.Doc, nil
+	}
+	return nil, nil
+}
+
 // packageObjects typechecks all package objects, but not function bodies.
 func (check *Checker) packageObjects() {
 	// process package objects in source order for reproducible results
@@ -700,7 +779,7 @@ func (a inSourceOrder) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 // unusedImports checks for unused imports.
 func (check *Checker) unusedImports() {
 	// if function bodies are not checked, packages' uses are likely missing - don't check
-	if check.conf.IgnoreFuncBodies {
+	if check.conf.IgnoreFuncBodies || check.conf.IgnoreUnexportedFuncBodies {
 		return
 	}
 
@@ -729,15 +808,15 @@ func (check *Checker) errorUnusedPkg(obj *PkgName) {
 	}
 	if obj.name == "" || obj.name == "." || obj.name == elem {
 		if check.conf.CompilerErrorMessages {
-			check.softErrorf(obj, "imported and not used: %q", path)
+			check.unusedf(obj, _UnusedImport, "imported and not used: %q", path)
 		} else {
-			check.softErrorf(obj, "%q imported but not used", path)
+			check.unusedf(obj, _UnusedImport, "%q imported but not used", path)
 		}
 	} else {
 		if check.conf.CompilerErrorMessages {
-			check.softErrorf(obj, "imported and not used: %q as %s", path, obj.name)
+			check.unusedf(obj, _UnusedImport, "imported and not used: %q as %s", path, obj.name)
 		} else {
-			check.softErrorf(obj, "%q imported but not used as %s", path, obj.name)
+			check.unusedf(obj, _UnusedImport, "%q imported but not used as %s", path, obj.name)
 		}
 	}
 }
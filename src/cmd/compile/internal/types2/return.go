@@ -18,7 +18,7 @@ func (check *Checker) isTerminating(s syntax.Stmt, label string) bool {
 	default:
 		unreachable()
 
-	case *syntax.DeclStmt, *syntax.EmptyStmt, *syntax.SendStmt,
+	case *syntax.DeclStmt, *syntax.EmptyStmt, *syntax.BadStmt, *syntax.SendStmt,
 		*syntax.AssignStmt, *syntax.CallStmt:
 		// no chance
 
@@ -105,7 +105,7 @@ func hasBreak(s syntax.Stmt, label string, implicit bool) bool {
 	default:
 		unreachable()
 
-	case *syntax.DeclStmt, *syntax.EmptyStmt, *syntax.ExprStmt,
+	case *syntax.DeclStmt, *syntax.EmptyStmt, *syntax.BadStmt, *syntax.ExprStmt,
 		*syntax.SendStmt, *syntax.AssignStmt, *syntax.CallStmt,
 		*syntax.ReturnStmt:
 		// no chance
@@ -48,7 +48,8 @@ func (s *Scope) Parent() *Scope { return s.parent }
 // Len returns the number of scope elements.
 func (s *Scope) Len() int { return len(s.elems) }
 
-// Names returns the scope's element names in sorted order.
+// Names returns the scope's element names in sorted order,
+// independent of the order in which the elements were inserted.
 func (s *Scope) Names() []string {
 	names := make([]string, len(s.elems))
 	i := 0
@@ -281,6 +282,10 @@ func (*lazyObject) Name() string                          { panic("unreachable")
 func (*lazyObject) Type() Type                            { panic("unreachable") }
 func (*lazyObject) Exported() bool                        { panic("unreachable") }
 func (*lazyObject) Id() string                            { panic("unreachable") }
+func (*lazyObject) Doc() *syntax.CommentGroup             { panic("unreachable") }
+func (*lazyObject) Deprecated() string                    { panic("unreachable") }
+func (*lazyObject) Data() interface{}                     { panic("unreachable") }
+func (*lazyObject) SetData(interface{})                   { panic("unreachable") }
 func (*lazyObject) String() string                        { panic("unreachable") }
 func (*lazyObject) order() uint32                         { panic("unreachable") }
 func (*lazyObject) color() color                          { panic("unreachable") }
@@ -5,10 +5,13 @@
 package types2_test
 
 import (
+	"bytes"
 	"cmd/compile/internal/syntax"
+	"fmt"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -93,6 +96,41 @@ func runbench(b *testing.B, path string, ignoreFuncBodies, writeInfo bool) {
 	b.ReportMetric(float64(lines)*float64(b.N)/time.Since(start).Seconds(), "lines/s")
 }
 
+// BenchmarkEmbeddedInterfaces measures checking a package with many
+// interfaces which embed other interfaces with the same methods.
+func BenchmarkEmbeddedInterfaces(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("package p\n")
+	const nbase = 20
+	for i := 0; i < nbase; i++ {
+		fmt.Fprintf(&buf, "type B%d interface {\n", i)
+		for j := 0; j < 5; j++ {
+			fmt.Fprintf(&buf, "\tm%d(map[string][]struct{ x, y int }, func(chan<- [4]*int) error) (interface{ m() }, error)\n", j)
+		}
+		buf.WriteString("}\n")
+	}
+	for i := 0; i < 600; i++ {
+		fmt.Fprintf(&buf, "type I%d interface {\n", i)
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&buf, "\tB%d\n", (i+j)%nbase)
+		}
+		buf.WriteString("}\n")
+	}
+	src := buf.String()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		file, err := syntax.Parse(nil, strings.NewReader(src), nil, nil, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var conf Config
+		if _, err := conf.Check("p", []*syntax.File{file}, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func pkgFiles(path string) ([]*syntax.File, error) {
 	filenames, err := pkgFilenames(path) // from stdlib_test.go
 	if err != nil {
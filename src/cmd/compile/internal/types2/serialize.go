@@ -0,0 +1,1547 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements Info.Encode and Info.Decode, which serialize the
+// type information recorded for a package so that it can be reused for
+// the same source files without type-checking them again.
+
+package types2
+
+import (
+	"bytes"
+	"cmd/compile/internal/syntax"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"hash/fnv"
+	"io"
+	"sort"
+)
+
+// Encode writes a serialization of the type information in info,
+// recorded by type-checking files as package pkg, to w. Decode
+// reconstructs pkg and info from the serialization and the same files,
+// parsed again, without type-checking them.
+//
+// The serialization contains the Types, Defs, Uses, Implicits,
+// Selections, and Inferred maps of info, keyed by the positions of the
+// respective syntax nodes in files rather than by node identity, and the
+// objects and types they refer to. The objects and types of pkg are
+// recorded in full. Objects and types of imported packages are recorded
+// by reference if possible: by package-level name, or as fields, methods,
+// and type parameters of package-level types and functions. The type
+// sets of interfaces are recorded if they were computed, so that they
+// need not be computed again after decoding. The other maps of info,
+// including Scopes, are not serialized.
+func (info *Info) Encode(w io.Writer, pkg *Package, files []*syntax.File) error {
+	e := newInfoEncoder(pkg, files)
+	body, err := e.body(info)
+	if err != nil {
+		return err
+	}
+
+	// Encoding records may refer to further types and objects.
+	var types, objs [][]byte
+	for len(types) < len(e.types) || len(objs) < len(e.objs) {
+		if i := len(types); i < len(e.types) {
+			types = append(types, e.typeRecord(e.types[i]))
+			continue
+		}
+		objs = append(objs, e.objRecord(e.objs[len(objs)]))
+	}
+
+	var out infoWriter
+	out.buf.WriteString(infoMagic)
+	var strs infoWriter
+	strs.uint(uint64(len(e.strings)))
+	for _, s := range e.strings {
+		strs.uint(uint64(len(s)))
+		strs.buf.WriteString(s)
+	}
+	out.section(strs.buf.Bytes())
+	out.records(types)
+	out.records(objs)
+	out.section(body)
+	_, err = w.Write(out.buf.Bytes())
+	return err
+}
+
+// Decode reads the serialization data written by Encode and records
+// the type information it contains for files in those maps of info
+// that are non-nil. The files must be the files passed to Encode,
+// parsed again in the same way; Decode reports an error if their
+// syntax trees differ in shape or positions. Imported packages are
+// obtained from imp.
+//
+// The result is a new package with the path, name, imports, and
+// package-level objects of the encoded package, and the objects and
+// types recorded in info are those of the new package. Objects declared
+// in function bodies have no parent scope.
+func (info *Info) Decode(data []byte, imp Importer, files []*syntax.File) (_ *Package, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			if e, ok := p.(infoError); ok {
+				err = e.err
+				return
+			}
+			panic(p)
+		}
+	}()
+
+	if !bytes.HasPrefix(data, []byte(infoMagic)) {
+		return nil, errors.New("invalid type information: bad header")
+	}
+	d := &infoDecoder{imp: imp, pkgs: make(map[string]*Package)}
+	r := &infoReader{d: d, data: data[len(infoMagic):]}
+	strs := infoReader{d: d, data: r.section()}
+	d.strings = make([]string, strs.uint())
+	for i := range d.strings {
+		d.strings[i] = string(strs.bytes(strs.uint()))
+	}
+	d.typeRecs = r.records()
+	d.objRecs = r.records()
+	d.types = make([]Type, len(d.typeRecs))
+	d.objs = make([]Object, len(d.objRecs))
+	body := &infoReader{d: d, data: r.section()}
+
+	d.pkg = NewPackage(body.string(), body.string())
+	imports := make([]*Package, body.uint())
+	for i := range imports {
+		imports[i] = d.importPkg(body.string())
+	}
+	d.pkg.SetImports(imports)
+
+	var sum uint64
+	d.nodes, d.bases, sum = infoNodes(files)
+	if n := body.uint(); n != uint64(len(d.nodes)) || body.uint() != sum {
+		return nil, errors.New("invalid type information: syntax trees do not match")
+	}
+
+	for n := body.uint(); n > 0; n-- {
+		obj := body.obj()
+		if alt := d.pkg.scope.Insert(obj); alt != nil {
+			infoErrorf("duplicate package-level object %s", obj.Name())
+		}
+	}
+
+	if info == nil {
+		info = new(Info)
+	}
+	for n := body.uint(); n > 0; n-- {
+		x := body.expr()
+		mode := operandMode(body.uint())
+		tv := TypeAndValue{mode: mode, Type: body.typ()}
+		if mode == constant_ {
+			tv.Value = body.value()
+		}
+		if info.Types != nil {
+			info.Types[x] = tv
+		}
+	}
+	for _, m := range []map[*syntax.Name]Object{info.Defs, info.Uses} {
+		for n := body.uint(); n > 0; n-- {
+			id := body.name()
+			obj := body.obj()
+			if m != nil {
+				m[id] = obj
+			}
+		}
+	}
+	for n := body.uint(); n > 0; n-- {
+		node := body.node()
+		obj := body.obj()
+		if info.Implicits != nil {
+			info.Implicits[node] = obj
+		}
+	}
+	for n := body.uint(); n > 0; n-- {
+		x, ok := body.node().(*syntax.SelectorExpr)
+		if !ok {
+			infoErrorf("selection is not a selector expression")
+		}
+		sel := &Selection{kind: SelectionKind(body.uint()), recv: body.typ(), obj: body.obj()}
+		sel.index = make([]int, body.uint())
+		for i := range sel.index {
+			sel.index[i] = int(body.int())
+		}
+		sel.indirect = body.bool()
+		if info.Selections != nil {
+			info.Selections[x] = sel
+		}
+	}
+	for n := body.uint(); n > 0; n-- {
+		x := body.expr()
+		targs := body.typeList()
+		sig, _ := body.typ().(*Signature)
+		if info.Inferred != nil {
+			info.Inferred[x] = Inferred{NewTypeList(targs), sig}
+		}
+	}
+
+	// Expand the instances now that all generic types are complete,
+	// reusing the decoded instances for identical instantiations.
+	typMap := make(map[string]*Named)
+	var insts []*Named
+	for _, t := range d.types {
+		if t, _ := t.(*Named); t != nil && t.orig != t {
+			h := typeHash(t.orig, t.targs.list())
+			if _, found := typMap[h]; !found {
+				typMap[h] = t
+			}
+			insts = append(insts, t)
+		}
+	}
+	for _, t := range insts {
+		t.expand(typMap)
+	}
+
+	d.pkg.complete = true
+	return d.pkg, nil
+}
+
+// infoMagic starts each serialization written by Info.Encode.
+const infoMagic = "types2 info v4\n"
+
+// infoNodes returns the nodes of files in the order in which
+// syntax.Inspect visits them, the position bases of the nodes in order
+// of their first appearance, and a fingerprint of the nodes' kinds and
+// positions. Serialized type information refers to nodes and position
+// bases by their indices.
+func infoNodes(files []*syntax.File) (nodes []syntax.Node, bases []*syntax.PosBase, sum uint64) {
+	h := fnv.New64a()
+	seen := make(map[*syntax.PosBase]bool)
+	for _, file := range files {
+		syntax.Inspect(file, func(n syntax.Node) bool {
+			if n == nil {
+				return false
+			}
+			pos := n.Pos()
+			if b := pos.Base(); b != nil && !seen[b] {
+				seen[b] = true
+				bases = append(bases, b)
+			}
+			fmt.Fprintf(h, "%T %d:%d\n", n, pos.Line(), pos.Col())
+			nodes = append(nodes, n)
+			return true
+		})
+	}
+	return nodes, bases, h.Sum64()
+}
+
+// Serialized type and object records start with one of the following
+// tags, followed by the data shown.
+const (
+	// types
+	tagBasic     = iota // kind
+	tagUniverse         // name of predeclared type
+	tagObjType          // object of which this is the type
+	tagUnder            // named type of which this is the underlying type
+	tagTParam           // generic type or signature, index of type parameter
+	tagPointer          // elem
+	tagSlice            // elem
+	tagArray            // len, elem
+	tagMap              // key, elem
+	tagChan             // dir, elem
+	tagTuple            // nil, vars
+	tagStruct           // fields, tags
+	tagSignature        // recv, tparams, rparams, params, results, variadic
+	tagInterface        // implicit, methods, embeddeds, embedding positions if known, type set if computed
+	tagUnion            // terms
+	tagTypeParam        // type name, constraint
+	tagNamed            // type name, tparams, underlying, methods
+	tagInstance         // orig, targs
+)
+
+const (
+	// objects
+	tagUniverseObj = iota // name
+	tagPkgObj             // package path, name
+	tagField              // struct, index
+	tagMethod             // named type or interface, index
+	tagParam              // tuple, index
+	tagRecv               // signature
+	tagTypeName           // named type or type parameter
+	tagConst              // object data, type, value
+	tagAlias              // object data, type parameters, type
+	tagVar                // object data, type, embedded, isField
+	tagFunc               // object data, signature
+	tagLabel              // object data
+	tagPkgName            // object data, imported package
+)
+
+// An infoEncoder serializes type information for Info.Encode.
+type infoEncoder struct {
+	pkg    *Package
+	nodes  map[syntax.Node]uint64 // index of first appearance; nodes may be shared
+	nnodes int
+	sum    uint64
+	bases  map[*syntax.PosBase]uint64
+
+	strings   []string
+	stringIdx map[string]uint64
+	types     []Type
+	typeIdx   map[Type]uint64 // index+1 in types; 0 stands for nil
+	objs      []Object
+	objIdx    map[Object]uint64 // index+1 in objs; 0 stands for nil
+
+	// Objects and types that are parts of other types, as found by walk.
+	walked   map[Type]bool
+	universe map[Type]string          // predeclared types
+	owners   map[Object]infoOwner     // fields, methods, parameters, receivers, and type names
+	typeObjs map[Type]Object          // types of objects recorded by reference
+	unders   map[Type]*Named          // underlying types of named types recorded by reference
+	tparams  map[*TypeParam]infoOwner // type parameters of types and functions recorded by reference
+}
+
+// An infoOwner describes the type of which an object or type is a part.
+type infoOwner struct {
+	tag   uint64 // tagField, tagMethod, tagParam, tagRecv, tagTypeName, or tagTParam
+	typ   Type
+	index int
+}
+
+func newInfoEncoder(pkg *Package, files []*syntax.File) *infoEncoder {
+	e := &infoEncoder{
+		pkg:       pkg,
+		nodes:     make(map[syntax.Node]uint64),
+		bases:     make(map[*syntax.PosBase]uint64),
+		stringIdx: make(map[string]uint64),
+		typeIdx:   make(map[Type]uint64),
+		objIdx:    make(map[Object]uint64),
+		walked:    make(map[Type]bool),
+		universe:  make(map[Type]string),
+		owners:    make(map[Object]infoOwner),
+		typeObjs:  make(map[Type]Object),
+		unders:    make(map[Type]*Named),
+		tparams:   make(map[*TypeParam]infoOwner),
+	}
+	nodes, bases, sum := infoNodes(files)
+	for i, n := range nodes {
+		if _, found := e.nodes[n]; !found {
+			e.nodes[n] = uint64(i)
+		}
+	}
+	e.nnodes = len(nodes)
+	for i, b := range bases {
+		e.bases[b] = uint64(i)
+	}
+	e.sum = sum
+	for _, name := range Universe.Names() {
+		if obj, _ := Universe.Lookup(name).(*TypeName); obj != nil {
+			if _, found := e.universe[obj.typ]; !found {
+				e.universe[obj.typ] = name
+			}
+		}
+	}
+	return e
+}
+
+// body encodes the package and the maps of info.
+func (e *infoEncoder) body(info *Info) ([]byte, error) {
+	// Find the parts of all types first, so that objects that are parts
+	// of types can be recorded by reference.
+	scope := e.pkg.scope
+	for _, name := range scope.Names() {
+		e.walkObj(scope.Lookup(name))
+	}
+	for _, tv := range info.Types {
+		e.walk(tv.Type)
+	}
+	for _, m := range []map[*syntax.Name]Object{info.Defs, info.Uses} {
+		for _, obj := range m {
+			e.walkObj(obj)
+		}
+	}
+	for _, obj := range info.Implicits {
+		e.walkObj(obj)
+	}
+	for _, sel := range info.Selections {
+		e.walk(sel.recv)
+		e.walkObj(sel.obj)
+	}
+	for _, inf := range info.Inferred {
+		for _, targ := range inf.TArgs.list() {
+			e.walk(targ)
+		}
+		e.walk(inf.Sig)
+	}
+
+	w := &infoWriter{e: e}
+	w.string(e.pkg.path)
+	w.string(e.pkg.name)
+	w.uint(uint64(len(e.pkg.imports)))
+	for _, imp := range e.pkg.imports {
+		w.string(imp.path)
+	}
+	w.uint(uint64(e.nnodes))
+	w.uint(e.sum)
+	w.uint(uint64(scope.Len()))
+	for _, name := range scope.Names() {
+		w.obj(scope.Lookup(name))
+	}
+
+	// Write map entries in node order, so that the result is deterministic.
+	var err error
+	keys := func(n int, node func(i int) syntax.Node) []int {
+		index := make([]int, 0, n)
+		for i := 0; i < n; i++ {
+			if _, found := e.nodes[node(i)]; found {
+				index = append(index, i)
+			} else if err == nil {
+				err = fmt.Errorf("%s: node not found in files", node(i).Pos())
+			}
+		}
+		sort.Slice(index, func(i, j int) bool { return e.nodes[node(index[i])] < e.nodes[node(index[j])] })
+		w.uint(uint64(len(index)))
+		return index
+	}
+
+	var exprs []syntax.Expr
+	for x := range info.Types {
+		exprs = append(exprs, x)
+	}
+	for _, i := range keys(len(exprs), func(i int) syntax.Node { return exprs[i] }) {
+		tv := info.Types[exprs[i]]
+		w.node(exprs[i])
+		w.uint(uint64(tv.mode))
+		w.typ(tv.Type)
+		if tv.mode == constant_ {
+			w.value(tv.Value)
+		}
+	}
+	for _, m := range []map[*syntax.Name]Object{info.Defs, info.Uses} {
+		var names []*syntax.Name
+		for id := range m {
+			names = append(names, id)
+		}
+		for _, i := range keys(len(names), func(i int) syntax.Node { return names[i] }) {
+			w.node(names[i])
+			w.obj(m[names[i]])
+		}
+	}
+	var nodes []syntax.Node
+	for n := range info.Implicits {
+		nodes = append(nodes, n)
+	}
+	for _, i := range keys(len(nodes), func(i int) syntax.Node { return nodes[i] }) {
+		w.node(nodes[i])
+		w.obj(info.Implicits[nodes[i]])
+	}
+	var sels []*syntax.SelectorExpr
+	for x := range info.Selections {
+		sels = append(sels, x)
+	}
+	for _, i := range keys(len(sels), func(i int) syntax.Node { return sels[i] }) {
+		sel := info.Selections[sels[i]]
+		w.node(sels[i])
+		w.uint(uint64(sel.kind))
+		w.typ(sel.recv)
+		w.obj(sel.obj)
+		w.uint(uint64(len(sel.index)))
+		for _, x := range sel.index {
+			w.int(int64(x))
+		}
+		w.bool(sel.indirect)
+	}
+	exprs = exprs[:0]
+	for x := range info.Inferred {
+		exprs = append(exprs, x)
+	}
+	for _, i := range keys(len(exprs), func(i int) syntax.Node { return exprs[i] }) {
+		inf := info.Inferred[exprs[i]]
+		w.node(exprs[i])
+		w.typeList(inf.TArgs.list())
+		w.typ(inf.Sig)
+	}
+
+	return w.buf.Bytes(), err
+}
+
+// imported reports whether obj is a package-level object of an imported
+// package.
+func (e *infoEncoder) imported(obj Object) bool {
+	pkg := obj.Pkg()
+	return pkg != nil && pkg != e.pkg && pkg.scope.Lookup(obj.Name()) == obj
+}
+
+// byRef reports whether the named type t is recorded by reference: as a
+// predeclared or imported type, or as an instance of its generic type.
+func (e *infoEncoder) byRef(t *Named) bool {
+	return t.orig != t || Universe.Lookup(t.obj.name) == t.obj || e.imported(t.obj)
+}
+
+func (e *infoEncoder) own(obj Object, tag uint64, typ Type, index int) {
+	if _, found := e.owners[obj]; !found {
+		e.owners[obj] = infoOwner{tag, typ, index}
+	}
+}
+
+func (e *infoEncoder) walkObj(obj Object) {
+	if obj == nil || obj.Type() == nil {
+		return
+	}
+	if e.imported(obj) {
+		e.typeObj(obj)
+		if sig, _ := obj.Type().(*Signature); sig != nil {
+			for i, tpar := range sig.TParams().list() {
+				e.tparams[tpar] = infoOwner{tagTParam, sig, i}
+			}
+		}
+	}
+	if tname, _ := obj.(*TypeName); tname != nil {
+		for _, tpar := range tname.tparams.list() {
+			e.walk(tpar)
+		}
+	}
+	e.walk(obj.Type())
+
+	// A method of an instance is recorded as a method of its receiver type.
+	if m, _ := obj.(*Func); m != nil {
+		if recv := m.typ.(*Signature).recv; recv != nil {
+			base, _ := deref(recv.typ)
+			if t, _ := base.(*Named); t != nil && t.orig != t {
+				for i := 0; i < t.NumMethods(); i++ {
+					if t.Method(i) == m {
+						e.own(m, tagMethod, t, i)
+						e.typeObj(m)
+					}
+				}
+			}
+		}
+	}
+}
+
+// walk finds the parts of t.
+func (e *infoEncoder) walk(t Type) {
+	if t == nil || e.walked[t] {
+		return
+	}
+	e.walked[t] = true
+
+	switch t := t.(type) {
+	case *Pointer:
+		e.walk(t.base)
+	case *Slice:
+		e.walk(t.elem)
+	case *Array:
+		e.walk(t.elem)
+	case *Map:
+		e.walk(t.key)
+		e.walk(t.elem)
+	case *Chan:
+		e.walk(t.elem)
+	case *Tuple:
+		if t != nil {
+			for i, v := range t.vars {
+				e.own(v, tagParam, t, i)
+				e.walk(v.typ)
+			}
+		}
+	case *Struct:
+		for i, f := range t.fields {
+			e.own(f, tagField, t, i)
+			e.walk(f.typ)
+		}
+	case *Signature:
+		if t.recv != nil {
+			e.own(t.recv, tagRecv, t, 0)
+			e.walk(t.recv.typ)
+		}
+		for _, tpar := range t.TParams().list() {
+			e.walk(tpar)
+		}
+		for _, tpar := range t.RParams().list() {
+			e.walk(tpar)
+		}
+		if t.params != nil {
+			e.walk(t.params)
+		}
+		if t.results != nil {
+			e.walk(t.results)
+		}
+	case *Interface:
+		for i, m := range t.methods {
+			e.own(m, tagMethod, t, i)
+			e.walk(m.typ)
+		}
+		for _, typ := range t.embeddeds {
+			e.walk(typ)
+		}
+		if tset := t.loadTypeSet(); tset != nil {
+			for _, m := range tset.methods {
+				e.walk(m.typ)
+			}
+			for _, term := range tset.terms {
+				e.walk(term.typ)
+			}
+		}
+	case *Union:
+		for _, term := range t.terms {
+			e.walk(term.typ)
+		}
+	case *TypeParam:
+		e.own(t.obj, tagTypeName, t, 0)
+		e.walk(t.bound)
+	case *Named:
+		if t.orig == t {
+			e.own(t.obj, tagTypeName, t, 0)
+		}
+		if !e.byRef(t) {
+			for _, tpar := range t.TParams().list() {
+				e.walk(tpar)
+			}
+			e.walk(t.Underlying())
+			for i, m := range t.methods {
+				e.own(m, tagMethod, t, i)
+				e.walk(m.typ)
+			}
+			return
+		}
+		// The underlying type of an instance of a generic type of this
+		// package cannot be recorded by reference, since the decoder may
+		// need it before the generic type is complete.
+		under := t.Underlying()
+		if _, found := e.unders[under]; !found && (t.orig == t || e.byRef(t.orig)) {
+			e.unders[under] = t
+		}
+		if t.orig != t {
+			e.walk(t.orig)
+			for _, targ := range t.targs.list() {
+				e.walk(targ)
+			}
+		} else {
+			if e.imported(t.obj) {
+				e.typeObj(t.obj)
+			}
+			for i, tpar := range t.TParams().list() {
+				e.tparams[tpar] = infoOwner{tagTParam, t, i}
+				e.walk(tpar)
+			}
+		}
+		e.walk(under)
+		if t.orig != t {
+			// Methods of instances are instantiated on demand (see walkObj);
+			// instantiating all of them might create more instances.
+			break
+		}
+		for i, m := range t.methods {
+			e.own(m, tagMethod, t, i)
+			e.typeObj(m)
+			e.walk(m.typ)
+		}
+	}
+}
+
+// typeObj records that the type of the object obj, which is recorded
+// by reference, is recorded as the type of obj.
+func (e *infoEncoder) typeObj(obj Object) {
+	if _, found := e.typeObjs[obj.Type()]; !found {
+		e.typeObjs[obj.Type()] = obj
+	}
+}
+
+func (e *infoEncoder) typeRecord(t Type) []byte {
+	w := &infoWriter{e: e}
+	if name, found := e.universe[t]; found {
+		w.uint(tagUniverse)
+		w.string(name)
+		return w.buf.Bytes()
+	}
+	if obj := e.typeObjs[t]; obj != nil {
+		w.uint(tagObjType)
+		w.obj(obj)
+		return w.buf.Bytes()
+	}
+	if n := e.unders[t]; n != nil {
+		w.uint(tagUnder)
+		w.typ(n)
+		return w.buf.Bytes()
+	}
+
+	switch t := t.(type) {
+	case *Basic:
+		w.uint(tagBasic)
+		w.uint(uint64(t.kind))
+	case *Pointer:
+		w.uint(tagPointer)
+		w.typ(t.base)
+	case *Slice:
+		w.uint(tagSlice)
+		w.typ(t.elem)
+	case *Array:
+		w.uint(tagArray)
+		w.int(t.len)
+		w.typ(t.elem)
+	case *Map:
+		w.uint(tagMap)
+		w.typ(t.key)
+		w.typ(t.elem)
+	case *Chan:
+		w.uint(tagChan)
+		w.uint(uint64(t.dir))
+		w.typ(t.elem)
+	case *Tuple:
+		w.uint(tagTuple)
+		w.bool(t == nil)
+		if t != nil {
+			w.uint(uint64(len(t.vars)))
+			for _, v := range t.vars {
+				w.objData(v)
+				w.typ(v.typ)
+			}
+		}
+	case *Struct:
+		w.uint(tagStruct)
+		w.uint(uint64(len(t.fields)))
+		for _, f := range t.fields {
+			w.objData(f)
+			w.typ(f.typ)
+			w.bool(f.embedded)
+		}
+		w.uint(uint64(len(t.tags)))
+		for _, tag := range t.tags {
+			w.string(tag)
+		}
+	case *Signature:
+		w.uint(tagSignature)
+		w.bool(t.recv != nil)
+		if t.recv != nil {
+			w.objData(t.recv)
+			w.typ(t.recv.typ)
+		}
+		w.typeList(tparamTypes(t.TParams().list()))
+		w.typeList(tparamTypes(t.RParams().list()))
+		w.tuple(t.params)
+		w.tuple(t.results)
+		w.bool(t.variadic)
+	case *Interface:
+		w.uint(tagInterface)
+		w.bool(t.implicit)
+		w.uint(uint64(len(t.methods)))
+		for _, m := range t.methods {
+			w.objData(m)
+			w.typ(m.typ)
+		}
+		w.typeList(t.embeddeds)
+		w.bool(t.embedPos != nil)
+		if t.embedPos != nil {
+			for _, pos := range *t.embedPos {
+				w.pos(pos)
+			}
+		}
+		tset := t.loadTypeSet()
+		w.bool(tset != nil)
+		if tset != nil {
+			w.bool(tset.comparable)
+			w.bool(tset.ordered)
+			w.bool(tset.partial)
+			w.uint(uint64(tset.depth))
+			w.uint(uint64(len(tset.methods)))
+			for _, m := range tset.methods {
+				w.obj(m)
+			}
+			w.uint(uint64(len(tset.terms)))
+			for _, term := range tset.terms {
+				w.bool(term.tilde)
+				w.typ(term.typ)
+			}
+		}
+	case *Union:
+		w.uint(tagUnion)
+		w.uint(uint64(len(t.terms)))
+		for _, term := range t.terms {
+			w.bool(term.tilde)
+			w.typ(term.typ)
+		}
+	case *TypeParam:
+		if owner, found := e.tparams[t]; found {
+			w.uint(tagTParam)
+			w.typ(owner.typ)
+			w.int(int64(owner.index))
+			break
+		}
+		w.uint(tagTypeParam)
+		w.objData(t.obj)
+		w.typ(t.bound)
+	case *Named:
+		if t.orig != t {
+			w.uint(tagInstance)
+			w.typ(t.orig)
+			w.typeList(t.targs.list())
+			break
+		}
+		w.uint(tagNamed)
+		w.objData(t.obj)
+		w.typeList(tparamTypes(t.TParams().list()))
+		w.typ(t.Underlying())
+		w.uint(uint64(len(t.methods)))
+		for _, m := range t.methods {
+			w.objData(m)
+			w.typ(m.typ)
+		}
+	default:
+		panic(fmt.Sprintf("unexpected type %T", t))
+	}
+	return w.buf.Bytes()
+}
+
+func (e *infoEncoder) objRecord(obj Object) []byte {
+	w := &infoWriter{e: e}
+	switch {
+	case Universe.Lookup(obj.Name()) == obj:
+		w.uint(tagUniverseObj)
+		w.string(obj.Name())
+		return w.buf.Bytes()
+	case e.imported(obj):
+		w.uint(tagPkgObj)
+		w.string(obj.Pkg().path)
+		w.string(obj.Name())
+		return w.buf.Bytes()
+	}
+	if owner, found := e.owners[obj]; found {
+		w.uint(owner.tag)
+		w.typ(owner.typ)
+		w.int(int64(owner.index))
+		return w.buf.Bytes()
+	}
+
+	switch obj := obj.(type) {
+	case *Const:
+		w.uint(tagConst)
+		w.objData(obj)
+		w.typ(obj.typ)
+		w.value(obj.val)
+	case *TypeName:
+		w.uint(tagAlias)
+		w.objData(obj)
+		w.typeList(tparamTypes(obj.tparams.list()))
+		w.typ(obj.typ)
+	case *Var:
+		w.uint(tagVar)
+		w.objData(obj)
+		w.typ(obj.typ)
+		w.bool(obj.embedded)
+		w.bool(obj.isField)
+	case *Func:
+		w.uint(tagFunc)
+		w.objData(obj)
+		w.typ(obj.typ)
+	case *Label:
+		w.uint(tagLabel)
+		w.objData(obj)
+	case *PkgName:
+		w.uint(tagPkgName)
+		w.objData(obj)
+		w.pkg(obj.imported)
+	default:
+		panic(fmt.Sprintf("unexpected object %T", obj))
+	}
+	return w.buf.Bytes()
+}
+
+func tparamTypes(list []*TypeParam) []Type {
+	types := make([]Type, len(list))
+	for i, tpar := range list {
+		types[i] = tpar
+	}
+	return types
+}
+
+// An infoWriter writes the encoding of a record or section.
+type infoWriter struct {
+	e   *infoEncoder
+	buf bytes.Buffer
+}
+
+func (w *infoWriter) uint(x uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutUvarint(b[:], x)])
+}
+
+func (w *infoWriter) int(x int64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutVarint(b[:], x)])
+}
+
+func (w *infoWriter) bool(b bool) {
+	if b {
+		w.uint(1)
+	} else {
+		w.uint(0)
+	}
+}
+
+func (w *infoWriter) section(data []byte) {
+	w.uint(uint64(len(data)))
+	w.buf.Write(data)
+}
+
+func (w *infoWriter) records(recs [][]byte) {
+	var sec infoWriter
+	sec.uint(uint64(len(recs)))
+	for _, rec := range recs {
+		sec.section(rec)
+	}
+	w.section(sec.buf.Bytes())
+}
+
+func (w *infoWriter) string(s string) {
+	e := w.e
+	i, found := e.stringIdx[s]
+	if !found {
+		i = uint64(len(e.strings))
+		e.strings = append(e.strings, s)
+		e.stringIdx[s] = i
+	}
+	w.uint(i)
+}
+
+func (w *infoWriter) node(n syntax.Node) {
+	w.uint(w.e.nodes[n])
+}
+
+func (w *infoWriter) pos(pos syntax.Pos) {
+	b := pos.Base()
+	if b == nil {
+		w.uint(0)
+		return
+	}
+	if i, found := w.e.bases[b]; found {
+		w.uint(i + 2)
+	} else {
+		w.uint(1)
+		w.string(b.Filename())
+	}
+	w.uint(uint64(pos.Line()))
+	w.uint(uint64(pos.Col()))
+}
+
+func (w *infoWriter) pkg(pkg *Package) {
+	switch pkg {
+	case nil:
+		w.uint(0)
+	case w.e.pkg:
+		w.uint(1)
+	default:
+		w.uint(2)
+		w.string(pkg.path)
+	}
+}
+
+// objData writes the name, package, and position of obj.
+func (w *infoWriter) objData(obj Object) {
+	w.string(obj.Name())
+	w.pkg(obj.Pkg())
+	w.pos(obj.Pos())
+}
+
+func (w *infoWriter) typ(t Type) {
+	if t == nil {
+		w.uint(0)
+		return
+	}
+	e := w.e
+	i, found := e.typeIdx[t]
+	if !found {
+		e.types = append(e.types, t)
+		i = uint64(len(e.types))
+		e.typeIdx[t] = i
+	}
+	w.uint(i)
+}
+
+func (w *infoWriter) tuple(t *Tuple) {
+	if t == nil {
+		w.typ(nil)
+	} else {
+		w.typ(t)
+	}
+}
+
+func (w *infoWriter) typeList(list []Type) {
+	w.uint(uint64(len(list)))
+	for _, t := range list {
+		w.typ(t)
+	}
+}
+
+func (w *infoWriter) obj(obj Object) {
+	if obj == nil {
+		w.uint(0)
+		return
+	}
+	e := w.e
+	i, found := e.objIdx[obj]
+	if !found {
+		e.objs = append(e.objs, obj)
+		i = uint64(len(e.objs))
+		e.objIdx[obj] = i
+	}
+	w.uint(i)
+}
+
+func (w *infoWriter) value(x constant.Value) {
+	w.uint(uint64(x.Kind()))
+	switch x.Kind() {
+	case constant.Bool:
+		w.bool(constant.BoolVal(x))
+	case constant.String:
+		w.string(constant.StringVal(x))
+	case constant.Int:
+		w.string(x.ExactString())
+	case constant.Float:
+		w.string(constant.Num(x).ExactString())
+		w.string(constant.Denom(x).ExactString())
+	case constant.Complex:
+		w.value(constant.Real(x))
+		w.value(constant.Imag(x))
+	}
+}
+
+// An infoDecoder reconstructs type information for Info.Decode. Types and
+// objects are decoded on first use.
+type infoDecoder struct {
+	imp   Importer
+	pkg   *Package
+	pkgs  map[string]*Package
+	nodes []syntax.Node
+	bases []*syntax.PosBase
+	files map[string]*syntax.PosBase // position bases not found in nodes, by file name
+
+	strings  []string
+	typeRecs [][]byte
+	types    []Type
+	objRecs  [][]byte
+	objs     []Object
+}
+
+// An infoError is a decoding error; it is raised with panic and
+// recovered by Info.Decode.
+type infoError struct{ err error }
+
+func infoErrorf(format string, args ...interface{}) {
+	panic(infoError{fmt.Errorf("invalid type information: "+format, args...)})
+}
+
+func (d *infoDecoder) importPkg(path string) *Package {
+	if path == "unsafe" {
+		return Unsafe
+	}
+	if pkg := d.pkgs[path]; pkg != nil {
+		return pkg
+	}
+	if d.imp == nil {
+		infoErrorf("cannot import %q (no importer)", path)
+	}
+	pkg, err := d.imp.Import(path)
+	if err != nil {
+		panic(infoError{err})
+	}
+	d.pkgs[path] = pkg
+	return pkg
+}
+
+func (d *infoDecoder) typ(i uint64) Type {
+	if i == 0 {
+		return nil
+	}
+	if i > uint64(len(d.types)) {
+		infoErrorf("type index %d out of range", i)
+	}
+	if t := d.types[i-1]; t != nil {
+		return t
+	}
+	typeRecs := d.typeRecs[i-1]
+	if typeRecs == nil {
+		infoErrorf("invalid cycle through type %d", i)
+	}
+	d.typeRecs[i-1] = nil // detect cycles
+	r := &infoReader{d: d, data: typeRecs}
+	t := r.typeRecord(func(t Type) { d.types[i-1] = t })
+	d.types[i-1] = t
+	return t
+}
+
+func (d *infoDecoder) obj(i uint64) Object {
+	if i == 0 {
+		return nil
+	}
+	if i > uint64(len(d.objs)) {
+		infoErrorf("object index %d out of range", i)
+	}
+	if obj := d.objs[i-1]; obj != nil {
+		return obj
+	}
+	objRecs := d.objRecs[i-1]
+	if objRecs == nil {
+		infoErrorf("invalid cycle through object %d", i)
+	}
+	d.objRecs[i-1] = nil // detect cycles
+	r := &infoReader{d: d, data: objRecs}
+	obj := r.objRecord(func(obj Object) { d.objs[i-1] = obj })
+	d.objs[i-1] = obj
+	return obj
+}
+
+// An infoReader reads the encoding of a record or section.
+type infoReader struct {
+	d    *infoDecoder
+	data []byte
+}
+
+func (r *infoReader) uint() uint64 {
+	x, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		infoErrorf("truncated data")
+	}
+	r.data = r.data[n:]
+	return x
+}
+
+func (r *infoReader) int() int64 {
+	x, n := binary.Varint(r.data)
+	if n <= 0 {
+		infoErrorf("truncated data")
+	}
+	r.data = r.data[n:]
+	return x
+}
+
+func (r *infoReader) bool() bool { return r.uint() != 0 }
+
+func (r *infoReader) bytes(n uint64) []byte {
+	if n > uint64(len(r.data)) {
+		infoErrorf("truncated data")
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *infoReader) section() []byte { return r.bytes(r.uint()) }
+
+func (r *infoReader) records() [][]byte {
+	sec := &infoReader{d: r.d, data: r.section()}
+	recs := make([][]byte, sec.uint())
+	for i := range recs {
+		recs[i] = sec.section()
+	}
+	return recs
+}
+
+func (r *infoReader) string() string {
+	i := r.uint()
+	if i >= uint64(len(r.d.strings)) {
+		infoErrorf("string index %d out of range", i)
+	}
+	return r.d.strings[i]
+}
+
+func (r *infoReader) node() syntax.Node {
+	i := r.uint()
+	if i >= uint64(len(r.d.nodes)) {
+		infoErrorf("node index %d out of range", i)
+	}
+	return r.d.nodes[i]
+}
+
+func (r *infoReader) expr() syntax.Expr {
+	x, ok := r.node().(syntax.Expr)
+	if !ok {
+		infoErrorf("node is not an expression")
+	}
+	return x
+}
+
+func (r *infoReader) name() *syntax.Name {
+	id, ok := r.node().(*syntax.Name)
+	if !ok {
+		infoErrorf("node is not an identifier")
+	}
+	return id
+}
+
+func (r *infoReader) pos() syntax.Pos {
+	d := r.d
+	var base *syntax.PosBase
+	switch i := r.uint(); i {
+	case 0:
+		return nopos
+	case 1:
+		filename := r.string()
+		base = d.files[filename]
+		if base == nil {
+			base = syntax.NewFileBase(filename)
+			if d.files == nil {
+				d.files = make(map[string]*syntax.PosBase)
+			}
+			d.files[filename] = base
+		}
+	default:
+		if i-2 >= uint64(len(d.bases)) {
+			infoErrorf("position base index %d out of range", i)
+		}
+		base = d.bases[i-2]
+	}
+	line := r.uint()
+	return syntax.MakePos(base, uint(line), uint(r.uint()))
+}
+
+func (r *infoReader) pkg() *Package {
+	switch r.uint() {
+	case 0:
+		return nil
+	case 1:
+		return r.d.pkg
+	}
+	return r.d.importPkg(r.string())
+}
+
+// objData reads the name, package, and position of an object.
+func (r *infoReader) objData() (name string, pkg *Package, pos syntax.Pos) {
+	return r.string(), r.pkg(), r.pos()
+}
+
+func (r *infoReader) typ() Type { return r.d.typ(r.uint()) }
+
+func (r *infoReader) tuple() *Tuple {
+	t, ok := r.typ().(*Tuple)
+	if !ok && t != nil {
+		infoErrorf("tuple expected")
+	}
+	return t
+}
+
+func (r *infoReader) typeList() []Type {
+	var list []Type
+	for n := r.uint(); n > 0; n-- {
+		list = append(list, r.typ())
+	}
+	return list
+}
+
+// tparamList reads a list of type parameters and binds them to their
+// indices. Methods of instances share the receiver type parameters of
+// the generic method; such a list is bound already.
+func (r *infoReader) tparamList() *TParamList {
+	var list []*TypeParam
+	for i, t := range r.typeList() {
+		tpar, _ := t.(*TypeParam)
+		if tpar == nil {
+			infoErrorf("type parameter expected")
+		}
+		if tpar.index >= 0 && tpar.index != i {
+			infoErrorf("type parameter %s bound at index %d", tpar, tpar.index)
+		}
+		list = append(list, tpar)
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	if list[0].index < 0 {
+		return bindTParams(list)
+	}
+	return &TParamList{tparams: list}
+}
+
+func (r *infoReader) obj() Object { return r.d.obj(r.uint()) }
+
+func (r *infoReader) value() constant.Value {
+	switch kind := constant.Kind(r.uint()); kind {
+	case constant.Unknown:
+		return constant.MakeUnknown()
+	case constant.Bool:
+		return constant.MakeBool(r.bool())
+	case constant.String:
+		return constant.MakeString(r.string())
+	case constant.Int:
+		return constant.MakeFromLiteral(r.string(), token.INT, 0)
+	case constant.Float:
+		num := constant.MakeFromLiteral(r.string(), token.INT, 0)
+		denom := constant.MakeFromLiteral(r.string(), token.INT, 0)
+		return constant.ToFloat(constant.BinaryOp(num, token.QUO, denom))
+	case constant.Complex:
+		re := r.value()
+		im := r.value()
+		return constant.BinaryOp(re, token.ADD, constant.MakeImag(im))
+	default:
+		infoErrorf("invalid constant kind %d", kind)
+		panic("unreachable")
+	}
+}
+
+// typeRecord decodes a type record. Types that may be part of a cycle
+// are passed to memo before their parts are decoded.
+func (r *infoReader) typeRecord(memo func(Type)) Type {
+	switch tag := r.uint(); tag {
+	case tagBasic:
+		kind := BasicKind(r.uint())
+		if int(kind) >= len(Typ) {
+			infoErrorf("invalid basic kind %d", kind)
+		}
+		return Typ[kind]
+	case tagUniverse:
+		name := r.string()
+		obj, _ := Universe.Lookup(name).(*TypeName)
+		if obj == nil {
+			infoErrorf("%s is not a predeclared type", name)
+		}
+		return obj.typ
+	case tagObjType:
+		return r.obj().Type()
+	case tagUnder:
+		n, _ := r.typ().(*Named)
+		if n == nil {
+			infoErrorf("named type expected")
+		}
+		return n.Underlying()
+	case tagTParam:
+		owner := r.typ()
+		i := int(r.int())
+		var list *TParamList
+		switch owner := owner.(type) {
+		case *Named:
+			list = owner.TParams()
+		case *Signature:
+			list = owner.TParams()
+		}
+		if i < 0 || i >= list.Len() {
+			infoErrorf("type parameter %d of %s not found", i, owner)
+		}
+		return list.At(i)
+	case tagPointer:
+		return NewPointer(r.typ())
+	case tagSlice:
+		return NewSlice(r.typ())
+	case tagArray:
+		n := r.int()
+		return NewArray(r.typ(), n)
+	case tagMap:
+		key := r.typ()
+		return NewMap(key, r.typ())
+	case tagChan:
+		dir := ChanDir(r.uint())
+		return NewChan(dir, r.typ())
+	case tagTuple:
+		if r.bool() {
+			return (*Tuple)(nil)
+		}
+		vars := make([]*Var, r.uint())
+		for i := range vars {
+			name, pkg, pos := r.objData()
+			vars[i] = NewParam(pos, pkg, name, r.typ())
+		}
+		return NewTuple(vars...)
+	case tagStruct:
+		fields := make([]*Var, r.uint())
+		for i := range fields {
+			name, pkg, pos := r.objData()
+			typ := r.typ()
+			fields[i] = NewField(pos, pkg, name, typ, r.bool())
+		}
+		var tags []string
+		for n := r.uint(); n > 0; n-- {
+			tags = append(tags, r.string())
+		}
+		return &Struct{fields: fields, tags: tags}
+	case tagSignature:
+		sig := new(Signature)
+		memo(sig)
+		if r.bool() {
+			name, pkg, pos := r.objData()
+			sig.recv = NewParam(pos, pkg, name, r.typ())
+		}
+		sig.tparams = r.tparamList()
+		sig.rparams = r.tparamList()
+		sig.params = r.tuple()
+		sig.results = r.tuple()
+		sig.variadic = r.bool()
+		return sig
+	case tagInterface:
+		t := new(Interface)
+		memo(t)
+		t.implicit = r.bool()
+		for n := r.uint(); n > 0; n-- {
+			name, pkg, pos := r.objData()
+			sig, _ := r.typ().(*Signature)
+			if sig == nil {
+				infoErrorf("signature expected")
+			}
+			t.methods = append(t.methods, NewFunc(pos, pkg, name, sig))
+		}
+		t.embeddeds = r.typeList()
+		if r.bool() {
+			embedPos := make([]syntax.Pos, len(t.embeddeds))
+			for i := range embedPos {
+				embedPos[i] = r.pos()
+			}
+			t.embedPos = &embedPos
+		}
+		t.complete = true
+		if r.bool() {
+			// The type set was computed when the information was
+			// encoded; don't compute it again.
+			tset := new(TypeSet)
+			tset.comparable = r.bool()
+			tset.ordered = r.bool()
+			tset.partial = r.bool()
+			tset.depth = int(r.uint())
+			for n := r.uint(); n > 0; n-- {
+				m, _ := r.obj().(*Func)
+				if m == nil {
+					infoErrorf("method expected")
+				}
+				tset.methods = append(tset.methods, m)
+			}
+			for n := r.uint(); n > 0; n-- {
+				tilde := r.bool()
+				tset.terms = append(tset.terms, &term{tilde, r.typ()})
+			}
+			t.tset = tset
+		}
+		return t
+	case tagUnion:
+		terms := make([]*Term, r.uint())
+		for i := range terms {
+			tilde := r.bool()
+			terms[i] = NewTerm(tilde, r.typ())
+		}
+		return NewUnion(terms)
+	case tagTypeParam:
+		name, pkg, pos := r.objData()
+		tpar := (*Checker)(nil).NewTypeParam(NewTypeName(pos, pkg, name, nil), nil)
+		memo(tpar)
+		tpar.bound = r.typ()
+		return tpar
+	case tagNamed:
+		name, pkg, pos := r.objData()
+		t := NewNamed(NewTypeName(pos, pkg, name, nil), nil, nil)
+		memo(t)
+		t.tparams = r.tparamList()
+		under := r.typ()
+		if under == nil {
+			infoErrorf("missing underlying type of %s", name)
+		}
+		t.SetUnderlying(under)
+		t.fromRHS = under
+		for n := r.uint(); n > 0; n-- {
+			name, pkg, pos := r.objData()
+			sig, _ := r.typ().(*Signature)
+			if sig == nil {
+				infoErrorf("signature expected")
+			}
+			t.methods = append(t.methods, NewFunc(pos, pkg, name, sig))
+		}
+		return t
+	case tagInstance:
+		orig := r.typ()
+		inst, err := Instantiate(nil, orig, r.typeList(), false)
+		if err != nil {
+			panic(infoError{err})
+		}
+		return inst
+	default:
+		infoErrorf("invalid type tag %d", tag)
+		panic("unreachable")
+	}
+}
+
+// objRecord decodes an object record. Objects are passed to memo before
+// their types are decoded.
+func (r *infoReader) objRecord(memo func(Object)) Object {
+	switch tag := r.uint(); tag {
+	case tagUniverseObj:
+		name := r.string()
+		obj := Universe.Lookup(name)
+		if obj == nil {
+			infoErrorf("%s is not predeclared", name)
+		}
+		return obj
+	case tagPkgObj:
+		pkg := r.d.importPkg(r.string())
+		name := r.string()
+		obj := pkg.scope.Lookup(name)
+		if obj == nil {
+			infoErrorf("%s.%s not found", pkg.path, name)
+		}
+		return obj
+	case tagField, tagMethod, tagParam, tagRecv, tagTypeName:
+		owner := r.typ()
+		i := int(r.int())
+		var obj Object
+		switch owner := owner.(type) {
+		case *Struct:
+			if tag == tagField && 0 <= i && i < owner.NumFields() {
+				obj = owner.Field(i)
+			}
+		case *Named:
+			if tag == tagMethod && 0 <= i && i < owner.NumMethods() {
+				obj = owner.Method(i)
+			} else if tag == tagTypeName {
+				obj = owner.obj
+			}
+		case *Interface:
+			if tag == tagMethod && 0 <= i && i < owner.NumExplicitMethods() {
+				obj = owner.ExplicitMethod(i)
+			}
+		case *Tuple:
+			if tag == tagParam && 0 <= i && i < owner.Len() {
+				obj = owner.At(i)
+			}
+		case *Signature:
+			if tag == tagRecv && owner.recv != nil {
+				obj = owner.recv
+			}
+		case *TypeParam:
+			if tag == tagTypeName {
+				obj = owner.obj
+			}
+		}
+		if obj == nil {
+			infoErrorf("object %d of %s not found", i, owner)
+		}
+		return obj
+
+	// objects recorded in full
+	case tagConst:
+		name, pkg, pos := r.objData()
+		obj := NewConst(pos, pkg, name, nil, nil)
+		memo(obj)
+		obj.typ = r.typ()
+		obj.val = r.value()
+		return obj
+	case tagAlias:
+		name, pkg, pos := r.objData()
+		obj := NewTypeName(pos, pkg, name, nil)
+		memo(obj)
+		obj.tparams = r.tparamList()
+		obj.typ = r.typ()
+		return obj
+	case tagVar:
+		name, pkg, pos := r.objData()
+		obj := NewVar(pos, pkg, name, nil)
+		memo(obj)
+		obj.typ = r.typ()
+		obj.embedded = r.bool()
+		obj.isField = r.bool()
+		return obj
+	case tagFunc:
+		name, pkg, pos := r.objData()
+		obj := NewFunc(pos, pkg, name, nil)
+		memo(obj)
+		obj.typ = r.typ()
+		return obj
+	case tagLabel:
+		name, pkg, pos := r.objData()
+		return NewLabel(pos, pkg, name)
+	case tagPkgName:
+		name, pkg, pos := r.objData()
+		return NewPkgName(pos, pkg, name, r.pkg())
+	default:
+		infoErrorf("invalid object tag %d", tag)
+		panic("unreachable")
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"bytes"
+	"cmd/compile/internal/syntax"
+	"fmt"
+	"internal/testenv"
+	"sort"
+	"strings"
+	"testing"
+
+	. "cmd/compile/internal/types2"
+)
+
+const serializeSrc1 = `package p
+
+import "fmt"
+
+const (
+	c = 1 << 70
+	f = 1.0 / 3
+	z = 1 + 2i
+	s = "s"
+)
+
+type T struct {
+	x, y int
+	E
+	fmt.Stringer
+}
+
+type E struct{ z int }
+
+func (E) m(int) string { return s }
+
+func (t *T) String() string { return fmt.Sprint(t.x, t.z, t.m(t.y)) }
+
+type List[P any] struct {
+	next *List[P]
+	val  P
+}
+
+func (l *List[P]) Push(v P) *List[P] { return &List[P]{l, v} }
+
+type Stack[P any] = *List[P]
+
+func Map[A, B any](l *List[A], f func(A) B) *List[B] {
+	var r *List[B]
+	for ; l != nil; l = l.next {
+		r = r.Push(f(l.val))
+	}
+	return r
+}
+
+type Number interface{ ~int | ~float64 }
+
+type NamedStringer interface {
+	fmt.Stringer
+	Name() string
+}
+
+func Sum[N Number](list ...N) (sum N) {
+	for _, x := range list {
+		sum += x
+	}
+	return
+}
+`
+
+const serializeSrc2 = `package p
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	_ = Sum(1, 2, 3)
+	_ = Map[int](nil, func(x int) string { return strings.Repeat("x", x) })
+	_ = strings.NewReader("").Len
+	_ Stack[string] = new(List[string]).Push("")
+	t T
+)
+
+func init() {
+	type local struct{ f float64 }
+	var l local
+	_ = l.f
+	switch x := interface{}(t).(type) {
+	case fmt.Stringer:
+		_ = x.String()
+	}
+L:
+	for {
+		break L
+	}
+}
+`
+
+func TestSerializeInfo(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	parse := func() []*syntax.File {
+		var files []*syntax.File
+		for i, src := range []string{serializeSrc1, serializeSrc2} {
+			f, err := syntax.Parse(syntax.NewFileBase(fmt.Sprintf("p%d.go", i)), strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, f)
+		}
+		return files
+	}
+	newInfo := func() *Info {
+		return &Info{
+			Types:      make(map[syntax.Expr]TypeAndValue),
+			Defs:       make(map[*syntax.Name]Object),
+			Uses:       make(map[*syntax.Name]Object),
+			Implicits:  make(map[syntax.Node]Object),
+			Selections: make(map[*syntax.SelectorExpr]*Selection),
+			Inferred:   make(map[syntax.Expr]Inferred),
+		}
+	}
+
+	files := parse()
+	imp := defaultImporter()
+	conf := Config{Importer: imp, AliasTypeParams: true}
+	info := newInfo()
+	pkg, err := conf.Check("p", files, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := info.Encode(&buf, pkg, files); err != nil {
+		t.Fatal(err)
+	}
+
+	// decode for a new parse of the same sources
+	files2 := parse()
+	info2 := newInfo()
+	pkg2, err := info2.Decode(buf.Bytes(), imp, files2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := serializeDump(files2, info2), serializeDump(files, info); got != want {
+		t.Errorf("decoded information differs\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := strings.Join(pkg2.Scope().Names(), " "), strings.Join(pkg.Scope().Names(), " "); got != want {
+		t.Errorf("got package scope %s; want %s", got, want)
+	}
+
+	// Uses that refer to the object of a definition still do, and
+	// imported objects are those of the importer.
+	byPos := func(m map[*syntax.Name]Object) map[string]Object {
+		objs := make(map[string]Object)
+		for id, obj := range m {
+			objs[id.Pos().String()] = obj
+		}
+		return objs
+	}
+	defs, uses := byPos(info.Defs), byPos(info.Uses)
+	defs2, uses2 := byPos(info2.Defs), byPos(info2.Uses)
+	for pos, obj := range uses {
+		if def := defs[obj.Pos().String()]; def != nil && def == obj {
+			if obj2 := uses2[pos]; obj2 == nil || obj2 != defs2[obj2.Pos().String()] {
+				t.Errorf("%s: use of %s does not refer to its definition", pos, obj2)
+			}
+		}
+	}
+	strs, err := imp.Import("strings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, obj := range info2.Uses {
+		if id.Value == "Repeat" && obj != strs.Scope().Lookup("Repeat") {
+			t.Errorf("got %v for strings.Repeat; want object of imported package", obj)
+		}
+	}
+	T := pkg2.Scope().Lookup("T").Type()
+	if obj, index, _ := LookupFieldOrMethod(T, true, pkg2, "z"); obj == nil || len(index) != 2 {
+		t.Errorf("got promoted field %v, index %v", obj, index)
+	}
+	if !Implements(NewPointer(T), fmtStringer(t, imp)) {
+		t.Errorf("*T does not implement fmt.Stringer")
+	}
+
+	// Parameterized aliases keep their type parameters.
+	alias := pkg2.Scope().Lookup("Stack").(*TypeName)
+	if alias.TParams().Len() != 1 || alias.Type().(*Pointer).Elem().(*Named).TArgs().At(0) != alias.TParams().At(0) {
+		t.Errorf("got alias %s", alias)
+	}
+
+	// Type sets are those of the encoded interfaces, with the methods of
+	// imported interfaces.
+	// So are the positions of embedded elements.
+	for _, name := range []string{"Number", "NamedStringer"} {
+		iface := pkg.Scope().Lookup(name).Type().Underlying().(*Interface)
+		iface2 := pkg2.Scope().Lookup(name).Type().Underlying().(*Interface)
+		if got, want := iface2.TypeSet().String(), iface.TypeSet().String(); got != want {
+			t.Errorf("got type set %s for %s; want %s", got, name, want)
+		}
+		for i := 0; i < iface.NumEmbeddeds(); i++ {
+			if got, want := iface2.EmbeddedPos(i), iface.EmbeddedPos(i); got.String() != want.String() || !want.IsKnown() {
+				t.Errorf("got position %s for embedded element %d of %s; want %s", got, i, name, want)
+			}
+		}
+	}
+	tset := pkg2.Scope().Lookup("NamedStringer").Type().Underlying().(*Interface).TypeSet()
+	if m := fmtStringer(t, imp).ExplicitMethod(0); tset.NumMethods() != 2 || tset.Method(1) != m {
+		t.Errorf("got type set %s; want method %s of fmt.Stringer", tset, m)
+	}
+
+	// A different syntax tree cannot be used.
+	files3 := parse()
+	files3[1].DeclList = files3[1].DeclList[1:]
+	if _, err := new(Info).Decode(buf.Bytes(), imp, files3); err == nil {
+		t.Errorf("decoding for different files succeeded")
+	}
+}
+
+func fmtStringer(t *testing.T, imp Importer) *Interface {
+	fmt, err := imp.Import("fmt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Scope().Lookup("Stringer").Type().Underlying().(*Interface)
+}
+
+// serializeDump returns a description of the type information for files,
+// with one line per entry, in source order.
+func serializeDump(files []*syntax.File, info *Info) string {
+	var lines []string
+	add := func(n syntax.Node, format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf("%s %T %s", n.Pos(), n, fmt.Sprintf(format, args...)))
+	}
+	for _, file := range files {
+		syntax.Inspect(file, func(n syntax.Node) bool {
+			if x, _ := n.(syntax.Expr); x != nil {
+				if tv, found := info.Types[x]; found {
+					add(x, "type %s %v", serializeStr(tv.Type), tv.Value)
+				}
+				if inf, found := info.Inferred[x]; found {
+					add(x, "inferred %s %s", serializeStr(inf.TArgs), serializeStr(inf.Sig))
+				}
+			}
+			if id, _ := n.(*syntax.Name); id != nil {
+				if obj, found := info.Defs[id]; found {
+					add(id, "def %s", serializeObj(obj))
+				}
+				if obj, found := info.Uses[id]; found {
+					add(id, "use %s", serializeObj(obj))
+				}
+			}
+			if obj := info.Implicits[n]; obj != nil {
+				add(n, "implicit %s", serializeObj(obj))
+			}
+			if x, _ := n.(*syntax.SelectorExpr); x != nil {
+				if sel := info.Selections[x]; sel != nil {
+					add(x, "selection %s %v", serializeStr(sel), sel.Index())
+				}
+			}
+			return true
+		})
+	}
+	sort.Stable(sort.StringSlice(lines))
+	return strings.Join(lines, "\n")
+}
+
+func serializeObj(obj Object) string {
+	if obj == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s @%s", serializeStr(obj), obj.Pos())
+}
+
+// serializeStr returns the string for x without type parameter
+// subscripts, which depend on the order in which type parameters are
+// created.
+func serializeStr(x fmt.Stringer) string {
+	return strings.Map(func(r rune) rune {
+		if '₀' <= r && r <= '₉' {
+			return -1
+		}
+		return r
+	}, x.String())
+}
@@ -0,0 +1,311 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements Session, which type-checks a package
+// incrementally, one input (such as a REPL line) at a time.
+
+package types2
+
+import (
+	"bytes"
+	"cmd/compile/internal/syntax"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Session type-checks a sequence of inputs, each consisting of either
+// declarations or statements, as part of a single synthetic package. Each
+// input sees the imports, declarations, and variables of all preceding
+// inputs. Sessions are meant for interactive tools (such as a REPL).
+//
+// Declarations are package-level declarations. Redeclaring a package-level
+// object in a later input replaces it for subsequent inputs; uses of the
+// object in earlier inputs continue to refer to the original object.
+//
+// Statements are checked as if they were the body of a function. Objects
+// declared at the top level of a statement input (as in x := 1) are added
+// to the package scope once the input is checked, replacing any objects of
+// the same name. They are not reported as unused.
+type Session struct {
+	check   *Checker
+	info    *Info
+	errh    func(error) // Config.Error of the client; or nil
+	imports []string    // source of all import declarations seen so far
+	ninputs int         // number of inputs checked so far
+
+	// per-input state
+	base   *syntax.PosBase          // position base for reported errors
+	filter map[syntax.Pos]errorCode // errors to suppress
+	err    error                    // first reported error
+}
+
+// NewSession returns a new session for a package with the given path and
+// name. The configuration conf is copied; the session modifies the copy
+// so that unused imports and variables are not considered errors (see
+// Config.TolerateUnused). The type information for all inputs is recorded
+// in info, which may be nil. Its Types map is always populated.
+func NewSession(conf *Config, path, name string, info *Info) *Session {
+	var c Config
+	if conf != nil {
+		c = *conf
+	}
+	c.DisableUnusedImportCheck = true
+	c.TolerateUnused = true
+
+	if info == nil {
+		info = new(Info)
+	}
+	if info.Types == nil {
+		info.Types = make(map[syntax.Expr]TypeAndValue)
+	}
+
+	s := &Session{info: info, errh: c.Error}
+	c.Error = s.report
+	s.check = NewChecker(&c, NewPackage(path, name), info)
+	return s
+}
+
+// Pkg returns the session's package.
+func (s *Session) Pkg() *Package { return s.check.pkg }
+
+// Check type-checks src as the next input of the session. The input is
+// either a list of package-level declarations (including imports) or a
+// list of statements. If src consists of a single expression, the result
+// describes the type and value of that expression; otherwise it is the
+// zero TypeAndValue. The error result is the first error reported for
+// the input, if any.
+//
+// Positions in the input use the file name "inputN", where N is the
+// number of the input, starting at 1.
+func (s *Session) Check(src string) (TypeAndValue, error) {
+	s.ninputs++
+	s.filter = make(map[syntax.Pos]errorCode)
+	s.err = nil
+	filename := fmt.Sprintf("input%d", s.ninputs)
+	s.base = syntax.NewFileBase(filename)
+
+	// Try src as a list of declarations first; if that fails,
+	// check it as a list of statements.
+	if file := s.parse(filename, src, false, false); file != nil {
+		s.checkDecls(filename, src, file)
+		return TypeAndValue{}, s.err
+	}
+
+	file := s.parse(filename, src, true, true)
+	if file == nil {
+		return TypeAndValue{}, s.err
+	}
+	fdecl := file.DeclList[len(file.DeclList)-1].(*syntax.FuncDecl)
+
+	// Don't report the top-level variables of the input as unused,
+	// and don't complain if the input is a single expression.
+	var expr syntax.Expr
+	for _, stmt := range fdecl.Body.List {
+		switch stmt := stmt.(type) {
+		case *syntax.AssignStmt:
+			if stmt.Op == syntax.Def {
+				for _, lhs := range unpackExpr(stmt.Lhs) {
+					if name, _ := lhs.(*syntax.Name); name != nil {
+						s.filter[name.Pos()] = _UnusedVar
+					}
+				}
+			}
+		case *syntax.DeclStmt:
+			for _, decl := range stmt.DeclList {
+				if decl, _ := decl.(*syntax.VarDecl); decl != nil {
+					for _, name := range decl.NameList {
+						s.filter[name.Pos()] = _UnusedVar
+					}
+				}
+			}
+		case *syntax.ExprStmt:
+			if len(fdecl.Body.List) == 1 {
+				expr = stmt.X
+				s.filter[syntax.StartPos(expr)] = _UnusedExpr
+			}
+		}
+	}
+
+	s.check.Files([]*syntax.File{file})
+
+	// Move the top-level objects of the input into the package scope.
+	for obj, d := range s.check.objMap {
+		if d.fdecl == fdecl {
+			if sig, _ := obj.Type().(*Signature); sig != nil && sig.scope != nil {
+				s.promote(sig.scope)
+			}
+			break
+		}
+	}
+
+	var tv TypeAndValue
+	if expr != nil {
+		tv = s.info.Types[expr]
+	}
+	return tv, s.err
+}
+
+// checkDecls checks the declarations of the given input file.
+func (s *Session) checkDecls(filename, src string, file *syntax.File) {
+	// Imports of earlier inputs are in scope unless they are
+	// imported again. Parse the input again with those imports.
+	own := make(map[string]bool)
+	for _, imp := range s.importsOf(file) {
+		own[imp] = true
+	}
+	var imports []string
+	for _, imp := range s.imports {
+		if !own[imp] {
+			imports = append(imports, imp)
+		}
+	}
+	s.imports = append(imports, sortedKeys(own)...)
+
+	file = s.parseWith(imports, filename, src, false, true)
+	if file == nil {
+		return // cannot happen, but be conservative
+	}
+
+	// Objects redeclared by the input replace the earlier ones.
+	scope := s.check.pkg.scope
+	for _, decl := range file.DeclList {
+		switch decl := decl.(type) {
+		case *syntax.ConstDecl:
+			for _, name := range decl.NameList {
+				delete(scope.elems, name.Value)
+			}
+		case *syntax.VarDecl:
+			for _, name := range decl.NameList {
+				delete(scope.elems, name.Value)
+			}
+		case *syntax.TypeDecl:
+			delete(scope.elems, decl.Name.Value)
+		case *syntax.FuncDecl:
+			if decl.Recv == nil {
+				delete(scope.elems, decl.Name.Value)
+			}
+		}
+	}
+
+	s.check.Files([]*syntax.File{file})
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var list []string
+	for key := range m {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// importsOf returns the import declarations of the input in file (which
+// follow the line directive preceding the input), in source form.
+func (s *Session) importsOf(file *syntax.File) []string {
+	var list []string
+	for _, decl := range file.DeclList {
+		if decl, _ := decl.(*syntax.ImportDecl); decl != nil && decl.Path != nil && !decl.Pos().Base().IsFileBase() {
+			imp := "import " + decl.Path.Value
+			if decl.LocalPkgName != nil {
+				imp = "import " + decl.LocalPkgName.Value + " " + decl.Path.Value
+			}
+			list = append(list, imp)
+		}
+	}
+	return list
+}
+
+// promote moves the objects of scope into the package scope.
+func (s *Session) promote(scope *Scope) {
+	pkgScope := s.check.pkg.scope
+	for _, name := range scope.Names() {
+		obj := scope.elems[name]
+		delete(pkgScope.elems, name)
+		obj.setParent(pkgScope)
+		obj.setScopePos(nopos)
+		pkgScope.Insert(obj)
+	}
+}
+
+// parse parses src as the input file filename, with the imports of
+// all earlier inputs. If stmts is set, src is parsed as a list of
+// statements. Syntax errors are reported only if report is set.
+// The result is nil if there were syntax errors.
+func (s *Session) parse(filename, src string, stmts, report bool) *syntax.File {
+	return s.parseWith(s.imports, filename, src, stmts, report)
+}
+
+func (s *Session) parseWith(imports []string, filename, src string, stmts, report bool) *syntax.File {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n", s.check.pkg.name)
+	for _, imp := range imports {
+		fmt.Fprintf(&b, "%s\n", imp)
+	}
+	if stmts {
+		b.WriteString("func _() {\n")
+	}
+	fmt.Fprintf(&b, "//line %s:1:1\n%s\n", filename, src)
+	if stmts {
+		b.WriteString("}\n")
+	}
+
+	failed := false
+	errh := func(err error) {
+		failed = true
+		if report {
+			s.report(err)
+		}
+	}
+	file, _ := syntax.Parse(s.base, strings.NewReader(b.String()), errh, nil, syntax.AllowGenerics)
+	if failed {
+		return nil
+	}
+	return file
+}
+
+// report is the error handler installed by the session.
+// Error positions are reported relative to the input's
+// line directive, i.e., without the synthesized prefix.
+func (s *Session) report(err error) {
+	switch e := err.(type) {
+	case syntax.Error:
+		e.Pos = s.inputPos(e.Pos)
+		err = e
+	case Error:
+		if code, found := s.filter[e.Pos]; found && code == e.Code {
+			return
+		}
+		e.Pos = s.inputPos(e.Pos)
+		e.End = s.inputPos(e.End)
+		if e.Related != nil {
+			related := make([]RelatedPos, len(e.Related))
+			for i, r := range e.Related {
+				related[i] = RelatedPos{s.inputPos(r.Pos), r.Msg}
+			}
+			e.Related = related
+		}
+		err = e
+		if e.Warning {
+			// not an error (see Config.TolerateUnused, Config.Severity)
+			if s.errh != nil {
+				s.errh(err)
+			}
+			return
+		}
+	}
+	if s.err == nil {
+		s.err = err
+	}
+	if s.errh != nil {
+		s.errh(err)
+	}
+}
+
+func (s *Session) inputPos(pos syntax.Pos) syntax.Pos {
+	if pos.IsKnown() && pos.RelFilename() == s.base.Filename() {
+		return syntax.MakePos(s.base, pos.RelLine(), pos.RelCol())
+	}
+	return pos
+}
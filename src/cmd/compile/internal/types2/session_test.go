@@ -0,0 +1,88 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types2_test
+
+import (
+	"strings"
+	"testing"
+
+	. "cmd/compile/internal/types2"
+)
+
+func TestSession(t *testing.T) {
+	s := NewSession(&Config{Importer: defaultImporter()}, "repl", "main", nil)
+
+	for _, test := range []struct {
+		src string
+		typ string // type of the expression, if any
+		val string // constant value of the expression, if any
+		err string // error, if any
+	}{
+		// expressions
+		{src: `1 + 2`, typ: "untyped int", val: "3"},
+		{src: `"a" + "b"`, typ: "untyped string", val: `"ab"`},
+
+		// statements; top-level variables persist and are not unused
+		{src: `x := 1`},
+		{src: `x * 2`, typ: "int"},
+		{src: `var y, z = x, "z"`},
+		{src: `y + len(z)`, typ: "int"},
+		{src: `x := "shadow"`},
+		{src: `x`, typ: "string"},
+		{src: `for i := 0; i < 10; i++ { j := i }`}, // j is unused but that's not an error
+
+		// declarations
+		{src: `import "strings"`},
+		{src: `strings.ToUpper(x)`, typ: "string"},
+		{src: `func f(s string) int { return len(s) }`},
+		{src: `f(x)`, typ: "int"},
+		{src: `func g() int { return y }`}, // y was declared by a statement
+		{src: `g()`, typ: "int"},
+		{src: `type T struct{ f int }
+func (t T) m() int { return t.f }`},
+		{src: `T{}.m()`, typ: "int"},
+		{src: `func f() {}`}, // redeclaration
+		{src: `f()`, typ: "()"},
+
+		// imports remain available and may be repeated
+		{src: `import "strings"; const c = "c"`},
+		{src: `strings.Repeat(c, 2)`, typ: "string"},
+
+		// errors
+		{src: `undefined`, err: "input22:1:1: undeclared name: undefined"},
+		{src: `x +`, err: "input23:2:1: syntax error: unexpected }, expecting expression"},
+	} {
+		tv, err := s.Check(test.src)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%q: got error %v; want %s", test.src, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.src, err)
+			continue
+		}
+		if got := typString(tv.Type); got != test.typ {
+			t.Errorf("%q: got type %s; want %s", test.src, got, test.typ)
+		}
+		if test.val != "" {
+			if got := tv.Value.ExactString(); got != test.val {
+				t.Errorf("%q: got value %s; want %s", test.src, got, test.val)
+			}
+		}
+	}
+
+	if got, want := strings.Join(s.Pkg().Scope().Names(), " "), "T c f g x y z"; got != want {
+		t.Errorf("got package scope %s; want %s", got, want)
+	}
+}
+
+func typString(typ Type) string {
+	if typ == nil {
+		return ""
+	}
+	return typ.String()
+}
@@ -83,20 +83,43 @@ func (s *Signature) String() string   { return TypeString(s, nil) }
 // Disabled by default, but enabled when running tests (via types_test.go).
 var acceptMethodTypeParams bool
 
-// funcType type-checks a function or method type.
-func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []*syntax.Field, ftyp *syntax.FuncType) {
-	check.openScope(ftyp, "function")
-	check.scope.isFunc = true
-	check.recordScope(ftyp, check.scope)
-	sig.scope = check.scope
-	defer check.closeScope()
+// methodTypeParams reports whether methods may have type parameters.
+// If check is nil, type-checking is done and any methods with type
+// parameters were accepted.
+func (check *Checker) methodTypeParams() bool {
+	return acceptMethodTypeParams || check == nil || check.conf.MethodTypeParams
+}
+
+// funcType type-checks a function or method type. For function declarations,
+// decl is the declaration; it is nil for function literals.
+func (check *Checker) funcType(sig *Signature, decl *syntax.FuncDecl, ftyp *syntax.FuncType) {
+	var recvPar *syntax.Field
+	var tparams []*syntax.Field
+	if decl != nil {
+		recvPar = decl.Recv
+		tparams = decl.TParamList
+	}
+
+	var rname *syntax.Name
+	var rparams []*syntax.Name
+	if recvPar != nil {
+		_, rname, rparams = check.unpackRecv(recvPar.Type, true)
+	}
+
+	// Type parameters, including receiver type parameters, are declared in
+	// a scope of their own which encloses the function scope.
+	var tpscope *Scope
+	if len(rparams) > 0 || len(tparams) > 0 {
+		check.openScope(decl, "type parameters")
+		tpscope = check.scope
+		defer check.closeScope()
+	}
 
 	var recvTyp syntax.Expr // rewritten receiver type; valid if != nil
 	if recvPar != nil {
 		// collect generic receiver type parameters, if any
 		// - a receiver type parameter is like any other type parameter, except that it is declared implicitly
 		// - the receiver specification acts as local declaration for its type parameters, which may be blank
-		_, rname, rparams := check.unpackRecv(recvPar.Type, true)
 		if len(rparams) > 0 {
 			// Blank identifiers don't get declared and regular type-checking of the instantiated
 			// parameterized receiver type expression fails in Checker.collectParams of receiver.
@@ -161,11 +184,17 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 		// Always type-check method type parameters but complain if they are not enabled.
 		// (A separate check is needed when type-checking interface method signatures because
 		// they don't have a receiver specification.)
-		if recvPar != nil && !acceptMethodTypeParams {
-			check.error(ftyp, "methods cannot have type parameters")
+		if recvPar != nil && !check.methodTypeParams() {
+			check.error(ftyp, _Todo, "methods cannot have type parameters")
 		}
 	}
 
+	check.openScope(ftyp, "function")
+	check.scope.isFunc = true
+	check.recordScope(ftyp, check.scope)
+	sig.scope = check.scope
+	defer check.closeScope()
+
 	// Value (non-type) parameters' scope starts in the function body. Use a temporary scope for their
 	// declarations and then squash that scope into the parent scope (and report any redeclarations at
 	// that time).
@@ -176,12 +205,25 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 	}
 	params, variadic := check.collectParams(scope, ftyp.ParamList, nil, true)
 	results, _ := check.collectParams(scope, ftyp.ResultList, nil, false)
-	scope.Squash(func(obj, alt Object) {
+	redeclared := func(obj, alt Object) {
 		var err error_
+		err.code = _DuplicateDecl
 		err.errorf(obj, "%s redeclared in this block", obj.Name())
 		err.recordAltDecl(alt)
 		check.report(&err)
-	})
+	}
+	scope.Squash(redeclared)
+	if tpscope != nil {
+		// spec: "Each function has a function block containing all of the
+		// function's type parameters, parameters, and results."
+		for _, list := range [][]*Var{recvList, params, results} {
+			for _, par := range list {
+				if alt := tpscope.Lookup(par.name); alt != nil && sig.scope.Lookup(par.name) == par {
+					redeclared(par, alt)
+				}
+			}
+		}
+	}
 
 	if recvPar != nil {
 		// recv parameter list present (may be empty)
@@ -194,7 +236,7 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 			recv = NewParam(nopos, nil, "", Typ[Invalid]) // ignore recv below
 		default:
 			// more than one receiver
-			check.error(recvList[len(recvList)-1].Pos(), "method must have exactly one receiver")
+			check.error(recvList[len(recvList)-1].Pos(), _BadRecv, "method must have exactly one receiver")
 			fallthrough // continue with first receiver
 		case 1:
 			recv = recvList[0]
@@ -214,7 +256,7 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 				// The receiver type may be an instantiated type referred to
 				// by an alias (which cannot have receiver parameters for now).
 				if T.TArgs() != nil && sig.RParams() == nil {
-					check.errorf(recv.pos, "cannot define methods on instantiated type %s", recv.typ)
+					check.errorf(recv.pos, _Todo, "cannot define methods on instantiated type %s", recv.typ)
 					break
 				}
 				// spec: "The type denoted by T is called the receiver base type; it must not
@@ -223,7 +265,7 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 				if T.obj.pkg != check.pkg {
 					err = "type not defined in this package"
 					if check.conf.CompilerErrorMessages {
-						check.errorf(recv.pos, "cannot define new methods on non-local type %s", recv.typ)
+						check.errorf(recv.pos, _InvalidRecv, "cannot define new methods on non-local type %s", recv.typ)
 						err = ""
 					}
 				} else {
@@ -247,14 +289,14 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 			case *Basic:
 				err = "basic or unnamed type"
 				if check.conf.CompilerErrorMessages {
-					check.errorf(recv.pos, "cannot define new methods on non-local type %s", recv.typ)
+					check.errorf(recv.pos, _InvalidRecv, "cannot define new methods on non-local type %s", recv.typ)
 					err = ""
 				}
 			default:
-				check.errorf(recv.pos, "invalid receiver type %s", recv.typ)
+				check.errorf(recv.pos, _InvalidRecv, "invalid receiver type %s", recv.typ)
 			}
 			if err != "" {
-				check.errorf(recv.pos, "invalid receiver type %s (%s)", recv.typ, err)
+				check.errorf(recv.pos, _InvalidRecv, "invalid receiver type %s (%s)", recv.typ, err)
 				// ok to continue
 			}
 		}
@@ -290,7 +332,7 @@ func (check *Checker) collectParams(scope *Scope, list []*syntax.Field, type0 sy
 				if variadicOk && i == len(list)-1 {
 					variadic = true
 				} else {
-					check.softErrorf(t, "can only use ... with final parameter in list")
+					check.softErrorf(t, _MisplacedDotDotDot, "can only use ... with final parameter in list")
 					// ignore ... and continue
 				}
 			}
@@ -302,7 +344,7 @@ func (check *Checker) collectParams(scope *Scope, list []*syntax.Field, type0 sy
 			// named parameter
 			name := field.Name.Value
 			if name == "" {
-				check.error(field.Name, invalidAST+"anonymous parameter")
+				check.error(field.Name, _InvalidSyntaxTree, invalidAST+"anonymous parameter")
 				// ok to continue
 			}
 			par := NewParam(field.Name.Pos(), check.pkg, name, typ)
@@ -319,7 +361,7 @@ func (check *Checker) collectParams(scope *Scope, list []*syntax.Field, type0 sy
 	}
 
 	if named && anonymous {
-		check.error(list[0], invalidAST+"list contains both named and anonymous parameters")
+		check.error(list[0], _InvalidSyntaxTree, invalidAST+"list contains both named and anonymous parameters")
 		// ok to continue
 	}
 
@@ -28,28 +28,28 @@ func TestSizeof(t *testing.T) {
 		{Tuple{}, 12, 24},
 		{Signature{}, 28, 56},
 		{Union{}, 16, 32},
-		{Interface{}, 40, 80},
+		{Interface{}, 44, 88},
 		{Map{}, 16, 32},
 		{Chan{}, 12, 24},
-		{Named{}, 72, 136},
-		{TypeParam{}, 28, 48},
+		{Named{}, 100, 176},
+		{TypeParam{}, 36, 64},
 		{term{}, 12, 24},
 		{top{}, 0, 0},
 
 		// Objects
-		{PkgName{}, 64, 104},
-		{Const{}, 64, 104},
-		{TypeName{}, 56, 88},
-		{Var{}, 60, 96},
-		{Func{}, 60, 96},
-		{Label{}, 60, 96},
-		{Builtin{}, 60, 96},
-		{Nil{}, 56, 88},
+		{PkgName{}, 76, 128},
+		{Const{}, 76, 128},
+		{TypeName{}, 72, 120},
+		{Var{}, 76, 128},
+		{Func{}, 76, 128},
+		{Label{}, 72, 120},
+		{Builtin{}, 72, 120},
+		{Nil{}, 68, 112},
 
 		// Misc
 		{Scope{}, 60, 104},
 		{Package{}, 40, 80},
-		{_TypeSet{}, 28, 56},
+		{TypeSet{}, 32, 64},
 	}
 
 	for _, test := range tests {
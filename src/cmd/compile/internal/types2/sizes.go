@@ -158,13 +158,13 @@ func (s *StdSizes) Sizeof(T Type) int64 {
 	return s.WordSize // catch-all
 }
 
-// common architecture word sizes and alignments
-var gcArchSizes = map[string]*StdSizes{
+// word sizes and maximum alignments of the architectures supported by gc
+var gcArchSizes = map[string]*gcSizes{
 	"386":      {4, 4},
-	"arm":      {4, 4},
-	"arm64":    {8, 8},
 	"amd64":    {8, 8},
 	"amd64p32": {4, 8},
+	"arm":      {4, 4},
+	"arm64":    {8, 8},
 	"mips":     {4, 4},
 	"mipsle":   {4, 4},
 	"mips64":   {8, 8},
@@ -183,27 +183,32 @@ var gcArchSizes = map[string]*StdSizes{
 // The result is nil if a compiler/architecture pair is not known.
 //
 // Supported architectures for compiler "gc":
-// "386", "arm", "arm64", "amd64", "amd64p32", "mips", "mipsle",
+// "386", "amd64", "amd64p32", "arm", "arm64", "mips", "mipsle",
 // "mips64", "mips64le", "ppc64", "ppc64le", "riscv64", "s390x", "sparc64", "wasm".
+//
+// The Sizes for "gc" compute the same sizes, alignments, and field offsets
+// as cmd/compile. Unlike StdSizes, the size of a struct includes trailing
+// padding up to a multiple of its alignment, and a non-zero-sized struct
+// whose last field has size 0 is padded so that a pointer to that field
+// does not point past the struct. On 32-bit architectures, 64-bit and
+// complex values are aligned to 4 bytes.
 func SizesFor(compiler, arch string) Sizes {
-	var m map[string]*StdSizes
 	switch compiler {
 	case "gc":
-		m = gcArchSizes
+		if s, ok := gcArchSizes[arch]; ok {
+			return s
+		}
 	case "gccgo":
-		m = gccgoArchSizes
-	default:
-		return nil
-	}
-	s, ok := m[arch]
-	if !ok {
-		return nil
+		if s, ok := gccgoArchSizes[arch]; ok {
+			return s
+		}
 	}
-	return s
+	return nil
 }
 
 // stdSizes is used if Config.Sizes == nil.
-var stdSizes = SizesFor("gc", "amd64")
+// Unlike SizesFor("gc", "amd64"), it doesn't pad struct sizes.
+var stdSizes = &StdSizes{WordSize: 8, MaxAlign: 8}
 
 func (conf *Config) alignof(T Type) int64 {
 	if s := conf.Sizes; s != nil {
@@ -260,6 +265,41 @@ func (conf *Config) sizeof(T Type) int64 {
 	return stdSizes.Sizeof(T)
 }
 
+// A StructLayout describes the memory layout of a struct type.
+type StructLayout struct {
+	Offsets []int64 // offset of each field, in bytes
+	Padding []int64 // padding following each field, in bytes; for the last field, the trailing padding
+	Size    int64   // size of the struct, in bytes
+	Align   int64   // alignment of the struct, in bytes
+}
+
+// Layout returns the layout of the struct type T, using the sizes of conf
+// (conf may be nil). The field offsets and the size are those reported by
+// Config.Sizes.Offsetsof and Config.Sizes.Sizeof; the padding after each
+// field is the space up to the next field or, for the last field, up to
+// the end of the struct. With the Sizes of SizesFor("gc", arch), the
+// trailing padding matches the layout of cmd/compile.
+func (conf *Config) Layout(T *Struct) *StructLayout {
+	if conf == nil {
+		conf = new(Config)
+	}
+	n := T.NumFields()
+	l := &StructLayout{
+		Offsets: conf.offsetsof(T),
+		Padding: make([]int64, n),
+		Size:    conf.sizeof(T),
+		Align:   conf.alignof(T),
+	}
+	for i, f := range T.fields {
+		end := l.Size
+		if i+1 < n {
+			end = l.Offsets[i+1]
+		}
+		l.Padding[i] = end - l.Offsets[i] - conf.sizeof(f.typ)
+	}
+	return l
+}
+
 // align returns the smallest y >= x such that y % a == 0.
 func align(x, a int64) int64 {
 	y := x + a - 1
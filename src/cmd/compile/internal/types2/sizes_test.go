@@ -9,6 +9,8 @@ package types2_test
 import (
 	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/types2"
+	"cmd/internal/sys"
+	"fmt"
 	"testing"
 )
 
@@ -105,3 +107,78 @@ const _ = unsafe.Offsetof(struct{ x int64 }{}.x)
 		_ = conf.Sizes.Alignof(tv.Type)
 	}
 }
+
+func TestSizesForGc(t *testing.T) {
+	// architectures supported by cmd/compile
+	for _, arch := range []string{"386", "amd64", "arm", "arm64", "mips", "mipsle", "mips64", "mips64le", "ppc64", "ppc64le", "riscv64", "s390x", "wasm"} {
+		var a *sys.Arch
+		for _, x := range sys.Archs {
+			if x.Name == arch {
+				a = x
+			}
+		}
+		if a == nil {
+			t.Fatalf("unknown architecture %s", arch)
+		}
+		sizes := types2.SizesFor("gc", arch)
+		if sizes == nil {
+			t.Errorf("SizesFor(gc, %s) = nil", arch)
+			continue
+		}
+		if got := sizes.Sizeof(types2.Typ[types2.Uintptr]); got != int64(a.PtrSize) {
+			t.Errorf("%s: Sizeof(uintptr) = %d; want %d", arch, got, a.PtrSize)
+		}
+		for _, kind := range []types2.BasicKind{types2.Int64, types2.Float64, types2.Complex128} {
+			if got := sizes.Alignof(types2.Typ[kind]); got != int64(a.RegSize) {
+				t.Errorf("%s: Alignof(%s) = %d; want %d", arch, types2.Typ[kind], got, a.RegSize)
+			}
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	const src = `
+package main
+
+type S struct {
+	a int64
+	b bool
+	c int16
+	d struct{}
+}
+`
+	f, err := parseSrc("x.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conf types2.Config
+	pkg, err := conf.Check("x", []*syntax.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := pkg.Scope().Lookup("S").Type().Underlying().(*types2.Struct)
+	for _, test := range []struct {
+		sizes types2.Sizes
+		want  types2.StructLayout
+	}{
+		{types2.SizesFor("gc", "amd64"), types2.StructLayout{Offsets: []int64{0, 8, 10, 12}, Padding: []int64{0, 1, 0, 4}, Size: 16, Align: 8}},
+		{types2.SizesFor("gc", "386"), types2.StructLayout{Offsets: []int64{0, 8, 10, 12}, Padding: []int64{0, 1, 0, 4}, Size: 16, Align: 4}},
+		{types2.SizesFor("gc", "arm"), types2.StructLayout{Offsets: []int64{0, 8, 10, 12}, Padding: []int64{0, 1, 0, 4}, Size: 16, Align: 4}},
+		{nil, types2.StructLayout{Offsets: []int64{0, 8, 10, 12}, Padding: []int64{0, 1, 0, 0}, Size: 12, Align: 8}},
+	} {
+		conf := &types2.Config{Sizes: test.sizes}
+		got := conf.Layout(ts)
+		if fmt.Sprint(*got) != fmt.Sprint(test.want) {
+			t.Errorf("Layout(%v) with %v = %v; want %v", ts, test.sizes, *got, test.want)
+		}
+	}
+
+	// the size of arrays includes the trailing padding of their elements
+	arr := types2.NewArray(ts, 3)
+	if got := types2.SizesFor("gc", "386").Sizeof(arr); got != 48 {
+		t.Errorf("Sizeof(%v) = %d; want 48", arr, got)
+	}
+	if got := (*types2.Config)(nil).Layout(ts).Size; got != 12 {
+		t.Errorf("Layout(%v).Size with nil Config = %d; want 12", ts, got)
+	}
+}
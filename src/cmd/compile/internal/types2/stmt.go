@@ -23,6 +23,12 @@ func (check *Checker) funcBody(decl *declInfo, name string, sig *Signature, body
 			check.trace(syntax.EndPos(body), "--- <end>")
 		}()
 	}
+	if check.conf.TraceWriter != nil {
+		s := check.traceEnter(body.Pos(), "funcBody", "%s: %s", name, sig)
+		defer func() {
+			check.traceExit(s, "")
+		}()
+	}
 
 	// set function scope extent
 	sig.scope.pos = body.Pos()
@@ -36,6 +42,7 @@ func (check *Checker) funcBody(decl *declInfo, name string, sig *Signature, body
 	}(check.context, check.indent)
 	check.context = context{
 		decl:  decl,
+		user:  decl,
 		scope: sig.scope,
 		iota:  iota,
 		sig:   sig,
@@ -49,7 +56,7 @@ func (check *Checker) funcBody(decl *declInfo, name string, sig *Signature, body
 	}
 
 	if sig.results.Len() > 0 && !check.isTerminating(body, "") {
-		check.error(body.Rbrace, "missing return")
+		check.error(body.Rbrace, _MissingReturn, "missing return")
 	}
 
 	// spec: "Implementation restriction: A compiler may make it illegal to
@@ -69,7 +76,7 @@ func (check *Checker) usage(scope *Scope) {
 		return unused[i].pos.Cmp(unused[j].pos) < 0
 	})
 	for _, v := range unused {
-		check.softErrorf(v.pos, "%s declared but not used", v.name)
+		check.unusedf(v.pos, _UnusedVar, "%s declared but not used", v.name)
 	}
 
 	for _, scope := range scope.children {
@@ -130,7 +137,7 @@ func (check *Checker) multipleSwitchDefaults(list []*syntax.CaseClause) {
 	for _, c := range list {
 		if c.Cases == nil {
 			if first != nil {
-				check.errorf(c, "multiple defaults (first at %s)", first.Pos())
+				check.errorf(c, _DuplicateDefault, "multiple defaults (first at %s)", first.Pos())
 				// TODO(gri) probably ok to bail out after first error (and simplify this code)
 			} else {
 				first = c
@@ -144,7 +151,7 @@ func (check *Checker) multipleSelectDefaults(list []*syntax.CommClause) {
 	for _, c := range list {
 		if c.Comm == nil {
 			if first != nil {
-				check.errorf(c, "multiple defaults (first at %s)", first.Pos())
+				check.errorf(c, _DuplicateDefault, "multiple defaults (first at %s)", first.Pos())
 				// TODO(gri) probably ok to bail out after first error (and simplify this code)
 			} else {
 				first = c
@@ -170,17 +177,23 @@ func (check *Checker) closeScope() {
 func (check *Checker) suspendedCall(keyword string, call *syntax.CallExpr) {
 	var x operand
 	var msg string
+	var code errorCode
 	switch check.rawExpr(&x, call, nil, false) {
 	case conversion:
 		msg = "requires function call, not conversion"
+		code = _InvalidDefer
+		if keyword == "go" {
+			code = _InvalidGo
+		}
 	case expression:
 		msg = "discards result of"
+		code = _UnusedResults
 	case statement:
 		return
 	default:
 		unreachable()
 	}
-	check.errorf(&x, "%s %s %s", keyword, msg, &x)
+	check.errorf(&x, code, "%s %s %s", keyword, msg, &x)
 }
 
 // goVal returns the Go value for val, or nil.
@@ -253,6 +266,7 @@ L:
 			for _, vt := range seen[val] {
 				if Identical(v.typ, vt.typ) {
 					var err error_
+					err.code = _DuplicateCase
 					err.errorf(&v, "duplicate case %s in expression switch", &v)
 					err.errorf(vt.pos, "previous case")
 					check.report(&err)
@@ -298,6 +312,7 @@ L:
 					Ts = TypeString(T, check.qualifier)
 				}
 				var err error_
+				err.code = _DuplicateCase
 				err.errorf(e, "duplicate case %s in type switch", Ts)
 				err.errorf(other, "previous case")
 				check.report(&err)
@@ -374,6 +389,9 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 	case *syntax.EmptyStmt:
 		// ignore
 
+	case *syntax.BadStmt:
+		// ignore (error was reported by the parser)
+
 	case *syntax.DeclStmt:
 		check.declStmt(s.DeclList)
 
@@ -388,18 +406,22 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 		var x operand
 		kind := check.rawExpr(&x, s.X, nil, false)
 		var msg string
+		var code errorCode
 		switch x.mode {
 		default:
 			if kind == statement {
 				return
 			}
 			msg = "is not used"
+			code = _UnusedExpr
 		case builtin:
 			msg = "must be called"
+			code = _UncalledBuiltin
 		case typexpr:
 			msg = "is not an expression"
+			code = _NotAnExpr
 		}
-		check.errorf(&x, "%s %s", &x, msg)
+		check.errorf(&x, code, "%s %s", &x, msg)
 
 	case *syntax.SendStmt:
 		var ch, val operand
@@ -412,15 +434,17 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 		if !underIs(ch.typ, func(u Type) bool {
 			uch, _ := u.(*Chan)
 			if uch == nil {
-				check.errorf(s, invalidOp+"cannot send to non-channel %s", &ch)
+				if !isUnknown(u) {
+					check.errorf(s, _InvalidSend, invalidOp+"cannot send to non-channel %s", &ch)
+				}
 				return false
 			}
 			if uch.dir == RecvOnly {
-				check.errorf(s, invalidOp+"cannot send to receive-only channel %s", &ch)
+				check.errorf(s, _InvalidSend, invalidOp+"cannot send to receive-only channel %s", &ch)
 				return false
 			}
 			if elem != nil && !Identical(uch.elem, elem) {
-				check.errorf(s, invalidOp+"channels of %s must have the same element type", &ch)
+				check.errorf(s, _Todo, invalidOp+"channels of %s must have the same element type", &ch)
 				return false
 			}
 			elem = uch.elem
@@ -435,7 +459,7 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 		if s.Rhs == nil {
 			// x++ or x--
 			if len(lhs) != 1 {
-				check.errorf(s, invalidAST+"%s%s requires one operand", s.Op, s.Op)
+				check.errorf(s, _InvalidSyntaxTree, invalidAST+"%s%s requires one operand", s.Op, s.Op)
 				return
 			}
 			var x operand
@@ -444,7 +468,7 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 				return
 			}
 			if !isNumeric(x.typ) {
-				check.errorf(lhs[0], invalidOp+"%s%s%s (non-numeric type %s)", lhs[0], s.Op, s.Op, x.typ)
+				check.errorf(lhs[0], _NonNumericIncDec, invalidOp+"%s%s%s (non-numeric type %s)", lhs[0], s.Op, s.Op, x.typ)
 				return
 			}
 			check.assignVar(lhs[0], &x)
@@ -463,7 +487,7 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 
 		// assignment operations
 		if len(lhs) != 1 || len(rhs) != 1 {
-			check.errorf(s, "assignment operation %s requires single-valued expressions", s.Op)
+			check.errorf(s, _MultiValAssignOp, "assignment operation %s requires single-valued expressions", s.Op)
 			return
 		}
 
@@ -491,6 +515,7 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 				for _, obj := range res.vars {
 					if alt := check.lookup(obj.name); alt != nil && alt != obj {
 						var err error_
+						err.code = _OutOfScopeResult
 						err.errorf(s, "result parameter %s not in scope at return", obj.name)
 						err.errorf(alt, "inner declaration of %s", obj)
 						check.report(&err)
@@ -502,7 +527,7 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 				check.initVars(res.vars, results, s.Pos())
 			}
 		} else if len(results) > 0 {
-			check.error(results[0], "no result values expected")
+			check.error(results[0], _WrongResultCount, "no result values expected")
 			check.use(results...)
 		}
 
@@ -515,32 +540,33 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 		case syntax.Break:
 			if ctxt&breakOk == 0 {
 				if check.conf.CompilerErrorMessages {
-					check.error(s, "break is not in a loop, switch, or select statement")
+					check.error(s, _MisplacedBreak, "break is not in a loop, switch, or select statement")
 				} else {
-					check.error(s, "break not in for, switch, or select statement")
+					check.error(s, _MisplacedBreak, "break not in for, switch, or select statement")
 				}
 			}
 		case syntax.Continue:
 			if ctxt&continueOk == 0 {
 				if check.conf.CompilerErrorMessages {
-					check.error(s, "continue is not in a loop")
+					check.error(s, _MisplacedContinue, "continue is not in a loop")
 				} else {
-					check.error(s, "continue not in for statement")
+					check.error(s, _MisplacedContinue, "continue not in for statement")
 				}
 			}
 		case syntax.Fallthrough:
 			if ctxt&fallthroughOk == 0 {
 				msg := "fallthrough statement out of place"
+				code := _MisplacedFallthrough
 				if ctxt&finalSwitchCase != 0 {
 					msg = "cannot fallthrough final case in switch"
 				}
-				check.error(s, msg)
+				check.error(s, code, msg)
 			}
 		case syntax.Goto:
 			// goto's must have labels, should have been caught above
 			fallthrough
 		default:
-			check.errorf(s, invalidAST+"branch statement: %s", s.Tok)
+			check.errorf(s, _InvalidSyntaxTree, invalidAST+"branch statement: %s", s.Tok)
 		}
 
 	case *syntax.BlockStmt:
@@ -556,19 +582,19 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 		check.simpleStmt(s.Init)
 		var x operand
 		check.expr(&x, s.Cond)
-		if x.mode != invalid && !isBoolean(x.typ) {
-			check.error(s.Cond, "non-boolean condition in if statement")
+		if x.mode != invalid && !isBoolean(x.typ) && !isUnknown(x.typ) {
+			check.error(s.Cond, _InvalidCond, "non-boolean condition in if statement")
 		}
 		check.stmt(inner, s.Then)
 		// The parser produces a correct AST but if it was modified
 		// elsewhere the else branch may be invalid. Check again.
 		switch s.Else.(type) {
-		case nil:
+		case nil, *syntax.BadStmt:
 			// valid or error already reported
 		case *syntax.IfStmt, *syntax.BlockStmt:
 			check.stmt(inner, s.Else)
 		default:
-			check.error(s.Else, "invalid else branch in if statement")
+			check.error(s.Else, _InvalidSyntaxTree, "invalid else branch in if statement")
 		}
 
 	case *syntax.SwitchStmt:
@@ -616,7 +642,7 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 			}
 
 			if !valid {
-				check.error(clause.Comm, "select case must be send or receive (possibly with assignment)")
+				check.error(clause.Comm, _InvalidSelectCase, "select case must be send or receive (possibly with assignment)")
 				continue
 			}
 			end := s.Rbrace
@@ -645,8 +671,8 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 		if s.Cond != nil {
 			var x operand
 			check.expr(&x, s.Cond)
-			if x.mode != invalid && !isBoolean(x.typ) {
-				check.error(s.Cond, "non-boolean condition in for statement")
+			if x.mode != invalid && !isBoolean(x.typ) && !isUnknown(x.typ) {
+				check.error(s.Cond, _InvalidCond, "non-boolean condition in for statement")
 			}
 		}
 		check.simpleStmt(s.Post)
@@ -662,7 +688,7 @@ func (check *Checker) stmt(ctxt stmtContext, s syntax.Stmt) {
 		check.stmt(inner, s.Body)
 
 	default:
-		check.error(s, "invalid statement")
+		check.error(s, _InvalidSyntaxTree, "invalid statement")
 	}
 }
 
@@ -676,7 +702,7 @@ func (check *Checker) switchStmt(inner stmtContext, s *syntax.SwitchStmt) {
 		// (as a compiler would), we get all the relevant checks.
 		check.assignment(&x, nil, "switch expression")
 		if x.mode != invalid && !Comparable(x.typ) && !hasNil(x.typ) {
-			check.errorf(&x, "cannot switch on %s (%s is not comparable)", &x, x.typ)
+			check.errorf(&x, _InvalidExprSwitch, "cannot switch on %s (%s is not comparable)", &x, x.typ)
 			x.mode = invalid
 		}
 	} else {
@@ -698,7 +724,7 @@ func (check *Checker) switchStmt(inner stmtContext, s *syntax.SwitchStmt) {
 	seen := make(valueMap) // map of seen case values to positions and types
 	for i, clause := range s.Body {
 		if clause == nil {
-			check.error(clause, invalidAST+"incorrect expression switch case")
+			check.error(clause, _InvalidSyntaxTree, invalidAST+"incorrect expression switch case")
 			continue
 		}
 		end := s.Rbrace
@@ -729,7 +755,7 @@ func (check *Checker) typeSwitchStmt(inner stmtContext, s *syntax.SwitchStmt, gu
 	if lhs != nil {
 		if lhs.Value == "_" {
 			// _ := x.(type) is an invalid short variable declaration
-			check.softErrorf(lhs, "no new variable on left side of :=")
+			check.softErrorf(lhs, _NoNewVar, "no new variable on left side of :=")
 			lhs = nil // avoid declared but not used error below
 		} else {
 			check.recordDef(lhs, nil) // lhs variable is implicitly declared in each cause clause
@@ -748,7 +774,7 @@ func (check *Checker) typeSwitchStmt(inner stmtContext, s *syntax.SwitchStmt, gu
 	// TODO(gri) Need to revisit this.
 	xtyp, _ := under(x.typ).(*Interface)
 	if xtyp == nil {
-		check.errorf(&x, "%s is not an interface type", &x)
+		check.errorf(&x, _InvalidTypeSwitch, "%s is not an interface type", &x)
 		return
 	}
 
@@ -758,7 +784,7 @@ func (check *Checker) typeSwitchStmt(inner stmtContext, s *syntax.SwitchStmt, gu
 	seen := make(map[Type]syntax.Expr) // map of seen types to positions
 	for i, clause := range s.Body {
 		if clause == nil {
-			check.error(s, invalidAST+"incorrect type switch case")
+			check.error(s, _InvalidSyntaxTree, invalidAST+"incorrect type switch case")
 			continue
 		}
 		end := s.Rbrace
@@ -810,7 +836,7 @@ func (check *Checker) typeSwitchStmt(inner stmtContext, s *syntax.SwitchStmt, gu
 			v.used = true // avoid usage error when checking entire function
 		}
 		if !used {
-			check.softErrorf(lhs, "%s declared but not used", lhs.Value)
+			check.unusedf(lhs, _UnusedVar, "%s declared but not used", lhs.Value)
 		}
 	}
 }
@@ -827,7 +853,7 @@ func (check *Checker) rangeStmt(inner stmtContext, s *syntax.ForStmt, rclause *s
 	var sValue syntax.Expr
 	if p, _ := sKey.(*syntax.ListExpr); p != nil {
 		if len(p.ElemList) != 2 {
-			check.error(s, invalidAST+"invalid lhs in range clause")
+			check.error(s, _InvalidSyntaxTree, invalidAST+"invalid lhs in range clause")
 			return
 		}
 		sKey = p.ElemList[0]
@@ -840,16 +866,16 @@ func (check *Checker) rangeStmt(inner stmtContext, s *syntax.ForStmt, rclause *s
 		// Ranging over a type parameter is permitted if it has a structural type.
 		typ := optype(x.typ)
 		if _, ok := typ.(*Chan); ok && sValue != nil {
-			check.softErrorf(sValue, "range over %s permits only one iteration variable", &x)
+			check.softErrorf(sValue, _InvalidIterVar, "range over %s permits only one iteration variable", &x)
 			// ok to continue
 		}
 		var msg string
 		key, val, msg = rangeKeyVal(typ, isVarName(sKey), isVarName(sValue))
-		if key == nil || msg != "" {
+		if (key == nil || msg != "") && !isUnknown(x.typ) {
 			if msg != "" {
 				msg = ": " + msg
 			}
-			check.softErrorf(&x, "cannot range over %s%s", &x, msg)
+			check.softErrorf(&x, _InvalidRangeExpr, "cannot range over %s%s", &x, msg)
 			// ok to continue
 		}
 	}
@@ -883,7 +909,7 @@ func (check *Checker) rangeStmt(inner stmtContext, s *syntax.ForStmt, rclause *s
 					vars = append(vars, obj)
 				}
 			} else {
-				check.errorf(lhs, "cannot declare %s", lhs)
+				check.errorf(lhs, _InvalidSyntaxTree, "cannot declare %s", lhs)
 				obj = NewVar(lhs.Pos(), check.pkg, "_", nil) // dummy variable
 			}
 
@@ -910,7 +936,7 @@ func (check *Checker) rangeStmt(inner stmtContext, s *syntax.ForStmt, rclause *s
 				check.declare(check.scope, nil /* recordDef already called */, obj, scopePos)
 			}
 		} else {
-			check.error(s, "no new variables on left side of :=")
+			check.error(s, _NoNewVar, "no new variables on left side of :=")
 		}
 	} else {
 		// ordinary assignment
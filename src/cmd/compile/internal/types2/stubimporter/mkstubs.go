@@ -0,0 +1,274 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+// +build ignore
+
+// Generate stubs.go from the export data of the installed std packages.
+//
+// For each package, the stub contains the exported constants, variables,
+// functions, types, and exported methods, with function bodies omitted.
+// Unexported types are included as needed to describe the exported ones.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var stdout = flag.Bool("stdout", false, "write to stdout instead of stubs.go")
+
+// packages is the list of packages for which stubs are generated.
+// It must be closed under the imports of the exported API.
+var packages = []string{
+	"bytes",
+	"errors",
+	"fmt",
+	"io",
+	"math",
+	"sort",
+	"strconv",
+	"strings",
+	"sync",
+	"sync/atomic",
+	"unicode",
+	"unicode/utf8",
+}
+
+func main() {
+	flag.Parse()
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by mkstubs.go. DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package stubimporter")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "var stubs = map[string]string{")
+	imp := importer.Default()
+	for _, path := range packages {
+		pkg, err := imp.Import(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(&b, "%q: `%s`,\n", path, mkstub(pkg))
+	}
+	fmt.Fprintln(&b, "}")
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *stdout {
+		_, err = os.Stdout.Write(out)
+	} else {
+		err = ioutil.WriteFile("stubs.go", out, 0666)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// A stubber collects the declarations of a package stub.
+type stubber struct {
+	pkg     *types.Package
+	imports map[string]string // path -> name of imported packages
+	types   map[*types.TypeName]bool
+	queue   []*types.TypeName // types yet to be declared
+}
+
+func mkstub(pkg *types.Package) string {
+	s := &stubber{
+		pkg:     pkg,
+		imports: make(map[string]string),
+		types:   make(map[*types.TypeName]bool),
+	}
+
+	var decls bytes.Buffer
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Const:
+			s.constDecl(&decls, obj)
+		case *types.Var:
+			fmt.Fprintf(&decls, "var %s %s\n", obj.Name(), s.typeString(obj.Type()))
+		case *types.Func:
+			fmt.Fprintf(&decls, "func %s%s\n", obj.Name(), s.signature(obj.Type().(*types.Signature)))
+		case *types.TypeName:
+			s.declareType(obj)
+		default:
+			log.Fatalf("%s: unexpected object %v", pkg.Path(), obj)
+		}
+	}
+
+	// Type declarations may refer to further (unexported) types.
+	for len(s.queue) > 0 {
+		obj := s.queue[0]
+		s.queue = s.queue[1:]
+		s.typeDecl(&decls, obj)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n\n", pkg.Name())
+	var paths []string
+	for path := range s.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&b, "import %q\n", path)
+	}
+	fmt.Fprintln(&b)
+	b.Write(decls.Bytes())
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("%s: %v\n%s", pkg.Path(), err, b.Bytes())
+	}
+	if bytes.IndexByte(out, '`') >= 0 {
+		log.Fatalf("%s: stub contains back quote", pkg.Path())
+	}
+	return string(out)
+}
+
+func (s *stubber) constDecl(w *bytes.Buffer, obj *types.Const) {
+	val := obj.Val()
+	lit := val.ExactString()
+	typ := obj.Type()
+	switch {
+	case typ == types.Typ[types.UntypedRune]:
+		r, _ := constant.Int64Val(val)
+		lit = strconv.QuoteRuneToASCII(rune(r))
+	case val.Kind() == constant.Float:
+		// Exact float values may be integral or written as fractions;
+		// make sure they remain (untyped) floats and fractions are not
+		// evaluated as integer division.
+		if i := strings.IndexByte(lit, '/'); i >= 0 {
+			lit = lit[:i] + ".0" + lit[i:]
+		} else if !strings.ContainsAny(lit, ".ep") {
+			lit += ".0"
+		}
+	}
+	if b, _ := typ.(*types.Basic); b != nil && b.Info()&types.IsUntyped != 0 {
+		fmt.Fprintf(w, "const %s = %s\n", obj.Name(), lit)
+		return
+	}
+	fmt.Fprintf(w, "const %s %s = %s\n", obj.Name(), s.typeString(typ), lit)
+}
+
+// declareType schedules the declaration of the type obj, if it belongs to
+// the stub package and has not been scheduled before.
+func (s *stubber) declareType(obj *types.TypeName) {
+	if obj.Pkg() != s.pkg || s.types[obj] {
+		return
+	}
+	s.types[obj] = true
+	s.queue = append(s.queue, obj)
+}
+
+func (s *stubber) typeDecl(w *bytes.Buffer, obj *types.TypeName) {
+	if obj.IsAlias() {
+		fmt.Fprintf(w, "type %s = %s\n", obj.Name(), s.typeString(obj.Type()))
+		return
+	}
+	named := obj.Type().(*types.Named)
+	fmt.Fprintf(w, "type %s %s\n", obj.Name(), s.typeString(named.Underlying()))
+	for i := 0; i < named.NumMethods(); i++ {
+		m := named.Method(i)
+		if !m.Exported() {
+			continue
+		}
+		sig := m.Type().(*types.Signature)
+		recv := obj.Name()
+		if _, ok := sig.Recv().Type().(*types.Pointer); ok {
+			recv = "*" + recv
+		}
+		fmt.Fprintf(w, "func (%s) %s%s\n", recv, m.Name(), s.signature(sig))
+	}
+}
+
+func (s *stubber) signature(sig *types.Signature) string {
+	return strings.TrimPrefix(s.typeString(sig), "func")
+}
+
+// typeString returns the string for typ in the stub package, and
+// records the packages and types typ refers to.
+func (s *stubber) typeString(typ types.Type) string {
+	s.collect(typ, make(map[types.Type]bool))
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg == s.pkg {
+			return ""
+		}
+		s.imports[pkg.Path()] = pkg.Name()
+		return pkg.Name()
+	})
+}
+
+// collect schedules the declarations of the types of the stub package
+// that typ refers to, and verifies that the types of other packages are
+// exported.
+func (s *stubber) collect(typ types.Type, seen map[types.Type]bool) {
+	if seen[typ] {
+		return
+	}
+	seen[typ] = true
+	switch t := typ.(type) {
+	case *types.Basic:
+		if t.Kind() == types.UnsafePointer {
+			s.imports["unsafe"] = "unsafe"
+		}
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != s.pkg && obj.Pkg() != nil && !obj.Exported() {
+			log.Fatalf("%s: refers to unexported type %s.%s", s.pkg.Path(), obj.Pkg().Path(), obj.Name())
+		}
+		s.declareType(obj)
+	case *types.Pointer:
+		s.collect(t.Elem(), seen)
+	case *types.Slice:
+		s.collect(t.Elem(), seen)
+	case *types.Array:
+		s.collect(t.Elem(), seen)
+	case *types.Map:
+		s.collect(t.Key(), seen)
+		s.collect(t.Elem(), seen)
+	case *types.Chan:
+		s.collect(t.Elem(), seen)
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			s.collect(t.Field(i).Type(), seen)
+		}
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			s.collect(t.At(i).Type(), seen)
+		}
+	case *types.Signature:
+		s.collect(t.Params(), seen)
+		s.collect(t.Results(), seen)
+	case *types.Interface:
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			s.collect(t.ExplicitMethod(i).Type(), seen)
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			s.collect(t.EmbeddedType(i), seen)
+		}
+	default:
+		log.Fatalf("%s: unexpected type %T", s.pkg.Path(), typ)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:generate go run mkstubs.go
+
+// Package stubimporter implements an importer for a curated subset of
+// the standard library (such as fmt, errors, strings, and sync) that
+// needs neither export data nor package sources on disk.
+//
+// The packages are described by stubs which declare the exported API
+// of the respective std package, with function bodies omitted. The stubs
+// are generated from the export data of the installed std packages (see
+// mkstubs.go) and are intended for type checker tests and fuzzers.
+package stubimporter
+
+import (
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Packages returns the sorted list of import paths of the packages
+// provided by the stub importer.
+func Packages() []string {
+	var list []string
+	for path := range stubs {
+		list = append(list, path)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// New returns a new importer for the stub packages. Each package is
+// type-checked at most once per importer, upon its first import; as
+// usual, importers must not be used concurrently.
+func New() types2.Importer {
+	return &importer{make(map[string]*types2.Package)}
+}
+
+type importer struct {
+	packages map[string]*types2.Package
+}
+
+func (imp *importer) Import(path string) (*types2.Package, error) {
+	if path == "unsafe" {
+		return types2.Unsafe, nil
+	}
+	if pkg := imp.packages[path]; pkg != nil {
+		return pkg, nil
+	}
+	src, ok := stubs[path]
+	if !ok {
+		return nil, fmt.Errorf("no stub for package %q", path)
+	}
+
+	filename := path + "/stub.go"
+	file, err := syntax.Parse(syntax.NewFileBase(filename), strings.NewReader(src), nil, nil, 0)
+	if err != nil {
+		return nil, err // cannot happen for generated stubs
+	}
+	conf := types2.Config{Importer: imp}
+	pkg, err := conf.Check(path, []*syntax.File{file}, nil)
+	if err != nil {
+		return nil, err // cannot happen for generated stubs
+	}
+	imp.packages[path] = pkg
+	return pkg, nil
+}
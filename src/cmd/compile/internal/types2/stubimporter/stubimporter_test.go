@@ -0,0 +1,126 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stubimporter_test
+
+import (
+	"bytes"
+	"internal/testenv"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+	"cmd/compile/internal/types2/stubimporter"
+)
+
+func TestImport(t *testing.T) {
+	imp := stubimporter.New()
+	for _, path := range stubimporter.Packages() {
+		pkg, err := imp.Import(path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if pkg.Path() != path || !pkg.Complete() {
+			t.Errorf("%s: got package %s (complete = %v)", path, pkg.Path(), pkg.Complete())
+		}
+		// packages are imported once
+		if pkg2, _ := imp.Import(path); pkg2 != pkg {
+			t.Errorf("%s: imported twice", path)
+		}
+	}
+
+	if _, err := imp.Import("net/http"); err == nil {
+		t.Errorf("net/http: got no error")
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	imp := stubimporter.New()
+	for _, test := range []struct {
+		path, name, want string
+	}{
+		{"fmt", "Sprintf", "func(format string, a ...interface{}) string"},
+		{"fmt", "Stringer", "fmt.Stringer"},
+		{"errors", "New", "func(text string) error"},
+		{"strings", "Builder", "strings.Builder"},
+		{"sync", "Mutex", "sync.Mutex"},
+		{"io", "EOF", "error"},
+		{"math", "MaxInt64", "untyped int"},
+		{"unicode/utf8", "RuneError", "untyped rune"},
+	} {
+		path := test.path
+		pkg, err := imp.Import(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj := pkg.Scope().Lookup(test.name)
+		if obj == nil {
+			t.Errorf("%s.%s not found", path, test.name)
+			continue
+		}
+		if got := obj.Type().String(); got != test.want {
+			t.Errorf("%s.%s: got type %s; want %s", path, test.name, got, test.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	const src = `
+package p
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+type T struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (t *T) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.b.String()
+}
+
+var _ fmt.Stringer = new(T)
+var _ io.Writer = new(strings.Builder)
+var _ = errors.Is(fmt.Errorf("%w", io.EOF), io.EOF)
+`
+	f, err := syntax.Parse(syntax.NewFileBase("p.go"), strings.NewReader(src), nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types2.Config{Importer: stubimporter.New()}
+	if _, err := conf.Check("p", []*syntax.File{f}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStubs(t *testing.T) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	old, err := ioutil.ReadFile("stubs.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	new, err := exec.Command(testenv.GoToolPath(t), "run", "mkstubs.go", "-stdout").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(old, new) {
+		t.Fatal("stubs.go out of date; run mkstubs.go")
+	}
+}
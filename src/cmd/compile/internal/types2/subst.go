@@ -152,7 +152,7 @@ func (subst *subster) typ(typ Type) Type {
 		methods, mcopied := subst.funcList(t.methods)
 		embeddeds, ecopied := subst.typeList(t.embeddeds)
 		if mcopied || ecopied {
-			iface := &Interface{methods: methods, embeddeds: embeddeds, complete: t.complete}
+			iface := &Interface{methods: methods, embeddeds: embeddeds, embedPos: t.embedPos, complete: t.complete}
 			return iface
 		}
 
@@ -188,7 +188,10 @@ func (subst *subster) typ(typ Type) Type {
 		}
 
 		var newTArgs []Type
-		assert(t.targs.Len() == t.TParams().Len())
+		if t.targs.Len() != t.TParams().Len() {
+			// An error is reported when t is expanded.
+			return Typ[Invalid]
+		}
 
 		// already instantiated
 		dump(">>> %s already instantiated", t)
@@ -297,6 +300,7 @@ func (subst *subster) var_(v *Var) *Var {
 		if typ := subst.typ(v.typ); typ != v.typ {
 			copy := *v
 			copy.typ = typ
+			copy.origin = v.Origin()
 			return &copy
 		}
 	}
@@ -335,6 +339,7 @@ func (subst *subster) func_(f *Func) *Func {
 		if typ := subst.typ(f.typ); typ != f.typ {
 			copy := *f
 			copy.typ = typ
+			copy.origin = f.Origin()
 			return &copy
 		}
 	}
@@ -0,0 +1,205 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements the evaluation of size-dependent constant
+// expressions for Config.Targets.
+
+package types2
+
+import (
+	"cmd/compile/internal/syntax"
+	"go/constant"
+	"go/token"
+)
+
+// A Target describes a target platform for Config.Targets.
+type Target struct {
+	Name  string // for error messages, e.g. "linux/386"
+	Sizes Sizes
+}
+
+// The values of a size-dependent constant expression for the targets
+// are recorded in check.targetVals, with one value per target. There
+// is no entry for constant expressions that do not depend on sizes.
+
+// targetValues returns the values of the constant operand x for the
+// targets, or nil if x doesn't depend on sizes.
+func (check *Checker) targetValues(x *operand) []constant.Value {
+	if check.targetVals == nil || x.mode != constant_ || x.expr == nil {
+		return nil
+	}
+	return check.targetVals[x.expr]
+}
+
+// setTargetValues records vals as the values of the constant
+// expression x for the targets.
+func (check *Checker) setTargetValues(x syntax.Expr, vals []constant.Value) {
+	if vals != nil && x != nil {
+		check.targetVals[x] = vals
+	}
+}
+
+// copyTargetValues records the values of the constant expression from
+// for the targets as those of the expression to, which has the same
+// value.
+func (check *Checker) copyTargetValues(from, to syntax.Expr) {
+	if check.targetVals != nil {
+		check.setTargetValues(to, check.targetVals[from])
+	}
+}
+
+// targetSizes records the size, alignment, or offset computed by f for
+// each target as the value of the constant expression x.
+func (check *Checker) targetSizes(x syntax.Expr, f func(conf *Config) int64) {
+	if len(check.targets) == 0 {
+		return
+	}
+	vals := make([]constant.Value, len(check.targets))
+	for i, conf := range check.targets {
+		vals[i] = constant.MakeInt64(f(conf))
+	}
+	check.setTargetValues(x, vals)
+}
+
+// targetValue returns the value for target i of a constant operand with
+// value val and target values vals.
+func targetValue(val constant.Value, vals []constant.Value, i int) constant.Value {
+	if vals == nil {
+		return val
+	}
+	return vals[i]
+}
+
+// targetUnary records the target values of the constant operand x, which
+// is the result of the unary operation op on a constant with target
+// values vals. The caller checks the result for overflow.
+func (check *Checker) targetUnary(x *operand, op syntax.Operator, vals []constant.Value) {
+	if vals == nil {
+		return
+	}
+	res := make([]constant.Value, len(vals))
+	for i, conf := range check.targets {
+		var prec uint
+		if isUnsigned(x.typ) {
+			prec = uint(conf.sizeof(x.typ) * 8)
+		}
+		res[i] = constant.UnaryOp(op2tok[op], vals[i], prec)
+	}
+	check.setTargetValues(x.expr, res)
+}
+
+// targetBinary records the target values of the constant operand x,
+// which is the result of the binary operation tok on constants with
+// values xval and yval and target values xvals and yvals. It reports
+// whether there was no division by zero for any target. The caller
+// checks the result for overflow.
+func (check *Checker) targetBinary(x *operand, y *operand, tok token.Token, xval, yval constant.Value, xvals, yvals []constant.Value) bool {
+	if xvals == nil && yvals == nil {
+		return true
+	}
+	res := make([]constant.Value, len(check.targets))
+	for i, t := range check.conf.Targets {
+		xv, yv := targetValue(xval, xvals, i), targetValue(yval, yvals, i)
+		if (tok == token.QUO || tok == token.QUO_ASSIGN || tok == token.REM) && constant.Sign(yv) == 0 {
+			check.errorf(y, _DivByZero, invalidOp+"division by zero for %s", t.Name)
+			return false
+		}
+		res[i] = constant.BinaryOp(xv, tok, yv)
+	}
+	check.setTargetValues(x.expr, res)
+	return true
+}
+
+// targetShift records the target values of the constant operand x,
+// which is the result of the shift operation tok on constants with
+// values xval and yval and target values xvals and yvals. It reports
+// whether the shift count is valid for all targets. The caller checks
+// the result for overflow.
+func (check *Checker) targetShift(x *operand, y *operand, tok token.Token, xval, yval constant.Value, xvals, yvals []constant.Value) bool {
+	if xvals == nil && yvals == nil {
+		return true
+	}
+	res := make([]constant.Value, len(check.targets))
+	for i, t := range check.conf.Targets {
+		xv, yv := constant.ToInt(targetValue(xval, xvals, i)), targetValue(yval, yvals, i)
+		const shiftBound = 1023 - 1 + 52 // see Checker.shift
+		s, ok := constant.Uint64Val(yv)
+		if !ok || s > shiftBound {
+			check.errorf(y, _InvalidShiftCount, invalidOp+"invalid shift count %s (%s for %s)", y, yv, t.Name)
+			return false
+		}
+		res[i] = constant.Shift(xv, tok, uint(s))
+	}
+	check.setTargetValues(x.expr, res)
+	return true
+}
+
+// targetCompare records the target values of the boolean constant x,
+// which is the result of the comparison tok of constants with values
+// xval and yval and target values xvals and yvals.
+func (check *Checker) targetCompare(x syntax.Expr, tok token.Token, xval, yval constant.Value, xvals, yvals []constant.Value) {
+	if xvals == nil && yvals == nil {
+		return
+	}
+	res := make([]constant.Value, len(check.targets))
+	for i := range res {
+		res[i] = constant.MakeBool(constant.Compare(targetValue(xval, xvals, i), tok, targetValue(yval, yvals, i)))
+	}
+	check.setTargetValues(x, res)
+}
+
+// targetRepresentable is like representable for the target values of
+// the constant operand x. It reports whether all values are
+// representable.
+func (check *Checker) targetRepresentable(x *operand, typ *Basic) bool {
+	vals := check.targetValues(x)
+	if vals == nil || typ == nil {
+		return true
+	}
+	res := make([]constant.Value, len(vals))
+	for i, conf := range check.targets {
+		v := vals[i]
+		if !representableConstFor(v, conf, typ, &v) {
+			check.errorf(x, _NumericOverflow, "%s (value %s for %s) overflows %s", x.expr, vals[i], check.conf.Targets[i].Name, typ)
+			return false
+		}
+		res[i] = v
+	}
+	check.setTargetValues(x.expr, res)
+	return true
+}
+
+// targetArrayLength reports an error if the constant array length x is
+// not a valid array length for some target.
+func (check *Checker) targetArrayLength(x *operand) bool {
+	vals := check.targetValues(x)
+	for i, conf := range check.targets {
+		if vals == nil {
+			break
+		}
+		val := constant.ToInt(vals[i])
+		if val.Kind() == constant.Int && representableConstFor(val, conf, Typ[Int], nil) && constant.Sign(val) >= 0 {
+			continue
+		}
+		check.errorf(x, _InvalidArrayLen, "invalid array length %s (value %s for %s)", x.expr, vals[i], check.conf.Targets[i].Name)
+		return false
+	}
+	return true
+}
+
+// recordTargetValues records the target values of the constant
+// expression x with value val in Info.TargetValues if some of them
+// differ from val.
+func (check *Checker) recordTargetValues(x syntax.Expr, val constant.Value) {
+	m := check.TargetValues
+	if m == nil || check.targetVals == nil {
+		return
+	}
+	for _, v := range check.targetVals[x] {
+		if !constant.Compare(v, token.EQL, val) {
+			m[x] = check.targetVals[x]
+			return
+		}
+	}
+}
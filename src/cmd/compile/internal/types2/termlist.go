@@ -83,8 +83,14 @@ func (xl termlist) norm() termlist {
 				if u1.typ == nil {
 					return allTermlist
 				}
-				xi = u1
 				used[j] = true // xj is now unioned into xi - ignore it in future iterations
+				if u1 != xi {
+					// xi grew and may now subsume terms we have
+					// already skipped (e.g., myInt ∪ int ∪ ~int):
+					// start over with the remaining terms.
+					xi = u1
+					j = i
+				}
 			}
 		}
 		rl = append(rl, xi)
@@ -95,10 +101,45 @@ func (xl termlist) norm() termlist {
 // If the type set represented by xl is specified by a single (non-𝓤) term,
 // structuralType returns that type. Otherwise it returns nil.
 func (xl termlist) structuralType() Type {
-	if nl := xl.norm(); len(nl) == 1 {
+	nl := xl.norm()
+	if len(nl) == 1 {
 		return nl[0].typ // if nl.isAll() then typ is nil, which is ok
 	}
-	return nil
+	return chanStructuralType(nl)
+}
+
+// chanStructuralType returns the structural type of a normalized termlist
+// with more than one term if all its terms are channel types with identical
+// element types, and all directional channels have the same direction. The
+// result is the underlying channel type with the most restrictive direction
+// (a bidirectional channel only if all channels are bidirectional). Otherwise
+// the result is nil.
+func chanStructuralType(nl termlist) Type {
+	var res *Chan
+	for _, x := range nl {
+		if x.typ == nil {
+			return nil
+		}
+		ch, _ := under(x.typ).(*Chan)
+		if ch == nil {
+			return nil
+		}
+		switch {
+		case res == nil:
+			res = ch
+		case !Identical(ch.elem, res.elem):
+			return nil
+		case ch.dir != SendRecv:
+			if res.dir != SendRecv && res.dir != ch.dir {
+				return nil // opposite directions
+			}
+			res = ch
+		}
+	}
+	if res == nil {
+		return nil
+	}
+	return res
 }
 
 // union returns the union xl ∪ yl.
@@ -106,7 +147,8 @@ func (xl termlist) union(yl termlist) termlist {
 	return append(xl, yl...).norm()
 }
 
-// intersect returns the intersection xl ∩ yl.
+// intersect returns the intersection xl ∩ yl in normal form;
+// for instance, (~int ∪ ~string) ∩ (myInt ∪ string) is myInt ∪ string.
 func (xl termlist) intersect(yl termlist) termlist {
 	if xl.isEmpty() || yl.isEmpty() {
 		return nil
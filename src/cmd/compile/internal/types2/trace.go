@@ -0,0 +1,90 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements structured trace events (see Config.TraceWriter).
+
+package types2
+
+import (
+	"cmd/compile/internal/syntax"
+	"encoding/json"
+	"time"
+)
+
+// A TraceEvent describes a step of type-checking. If Config.TraceWriter
+// is set, each event is written to it as a JSON object on a line of its own.
+//
+// Steps which may contain other steps, such as the checking of an object
+// declaration, are described by an "enter" event when the step starts, and
+// a matching "exit" event when it ends. The steps currently traced are:
+//
+//	"objDecl"          the declaration of a (package-level or local) object
+//	"typeSet"          the computation of the type set of an interface
+//	"instantiate"      the instantiation of a generic type or function
+//	"infer"            the inference of type arguments for a function call
+//	"inferConstraints" a round of constraint type inference
+//	"funcBody"         the function body of a declaration or function literal
+//	"delayed"          a delayed action, such as the verification of type arguments
+//
+// Other events are "phase" events when a phase of type-checking starts (see
+// Config.Phase), "later" events when an action is scheduled to be processed
+// later, and "error" events when an error is reported.
+type TraceEvent struct {
+	Kind  string `json:"kind"`           // "enter", "exit", "phase", "later", or "error"
+	Step  string `json:"step,omitempty"` // step, for "enter" and "exit" events; phase, for "phase" events
+	Pos   string `json:"pos,omitempty"`  // source position, if known
+	Desc  string `json:"desc,omitempty"` // description of the step, or of its result for "exit" events
+	Depth int    `json:"depth"`          // number of enclosing steps
+	Time  int64  `json:"time"`           // time since the first event, in nanoseconds
+	Dur   int64  `json:"dur,omitempty"`  // duration of the step, in nanoseconds, for "exit" events
+}
+
+// A traceStep is a step started by Checker.traceEnter.
+type traceStep struct {
+	step  string
+	pos   syntax.Pos
+	start time.Time
+}
+
+// traceEnter writes an "enter" event for the step at pos, described by
+// the formatted message, and returns the step for use with traceExit.
+// It must only be called if Config.TraceWriter is set.
+func (check *Checker) traceEnter(pos syntax.Pos, step string, format string, args ...interface{}) *traceStep {
+	s := &traceStep{step, pos, time.Now()}
+	check.writeTraceEvent(&TraceEvent{Kind: "enter", Step: step, Desc: check.sprintf(format, args...)}, pos, s.start)
+	check.traceDepth++
+	return s
+}
+
+// traceExit writes the "exit" event for the step s, with the result of the
+// step described by the formatted message.
+func (check *Checker) traceExit(s *traceStep, format string, args ...interface{}) {
+	now := time.Now()
+	check.traceDepth--
+	check.writeTraceEvent(&TraceEvent{Kind: "exit", Step: s.step, Desc: check.sprintf(format, args...), Dur: int64(now.Sub(s.start))}, s.pos, now)
+}
+
+// traceEvent writes an event of the given kind (other than "enter" or
+// "exit") at pos. It must only be called if Config.TraceWriter is set.
+func (check *Checker) traceEvent(kind, step string, pos syntax.Pos, desc string) {
+	check.writeTraceEvent(&TraceEvent{Kind: kind, Step: step, Desc: desc}, pos, time.Now())
+}
+
+// writeTraceEvent completes the event ev with the position pos and the
+// time t, and writes it to Config.TraceWriter. Write errors are ignored.
+func (check *Checker) writeTraceEvent(ev *TraceEvent, pos syntax.Pos, t time.Time) {
+	if check.traceStart.IsZero() {
+		check.traceStart = t
+	}
+	if pos.IsKnown() {
+		ev.Pos = pos.String()
+	}
+	ev.Depth = check.traceDepth
+	ev.Time = int64(t.Sub(check.traceStart))
+	b, err := json.Marshal(ev)
+	if err != nil {
+		panic(err) // cannot happen: all fields can be encoded
+	}
+	check.conf.TraceWriter.Write(append(b, '\n'))
+}
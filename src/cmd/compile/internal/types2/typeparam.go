@@ -23,6 +23,7 @@ type TypeParam struct {
 	index int       // type parameter index in source order, starting at 0
 	// TODO(rfindley): this could also be Typ[Invalid]. Verify that this is handled correctly.
 	bound Type // *Named or *Interface; underlying type is always *Interface
+	deflt Type // default type argument, or nil
 }
 
 // Obj returns the type name for the type parameter t.
@@ -47,6 +48,12 @@ func (check *Checker) NewTypeParam(obj *TypeName, bound Type) *TypeParam {
 	return typ
 }
 
+// Default returns the default type argument of t, or nil if t has no
+// default type. Default types are experimental (see Config.DefaultTypeArgs).
+func (t *TypeParam) Default() Type {
+	return t.deflt
+}
+
 // Index returns the index of the type param within its param list.
 func (t *TypeParam) Index() int {
 	return t.index
@@ -80,6 +87,16 @@ func (t *TypeParam) SetConstraint(bound Type) {
 	t.bound = bound
 }
 
+// Interface returns the interface of the type constraint of t, with
+// its type set computed. If the constraint is not an interface, the
+// result is the empty interface. The method set of t is the method
+// set of this interface, including the methods of embedded
+// constraints: LookupFieldOrMethod finds exactly those methods for
+// operands of type t, and none for operands of type *t.
+func (t *TypeParam) Interface() *Interface {
+	return t.iface()
+}
+
 func (t *TypeParam) Underlying() Type { return t }
 func (t *TypeParam) String() string   { return TypeString(t, nil) }
 
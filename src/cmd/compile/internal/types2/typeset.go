@@ -7,36 +7,44 @@ package types2
 import (
 	"bytes"
 	"cmd/compile/internal/syntax"
-	"fmt"
 	"sort"
+	"strings"
 )
 
 // ----------------------------------------------------------------------------
 // API
 
-// A _TypeSet represents the type set of an interface.
-type _TypeSet struct {
+// A TypeSet represents the type set of an interface: the set of types
+// that have all the methods of the interface and are permitted by all
+// its type terms (see Interface.TypeSet).
+type TypeSet struct {
 	comparable bool // if set, the interface is or embeds comparable
+	ordered    bool // if set, the interface is or embeds ordered
+	partial    bool // if set, invalid embedded elements or union terms were ignored
+	depth      int  // nesting depth of embedded interfaces, including those in union terms
 	// TODO(gri) consider using a set for the methods for faster lookup
 	methods []*Func  // all methods of the interface; sorted by unique ID
 	terms   termlist // type terms of the type set
 }
 
 // IsEmpty reports whether type set s is the empty set.
-func (s *_TypeSet) IsEmpty() bool { return s.terms.isEmpty() }
+func (s *TypeSet) IsEmpty() bool { return s.terms.isEmpty() }
 
 // IsAll reports whether type set s is the set of all types (corresponding to the empty interface).
-func (s *_TypeSet) IsAll() bool {
-	return !s.comparable && len(s.methods) == 0 && s.terms.isAll()
+func (s *TypeSet) IsAll() bool {
+	return !s.comparable && !s.ordered && len(s.methods) == 0 && s.terms.isAll()
 }
 
 // IsConstraint reports whether type set s is not just a set of methods.
-func (s *_TypeSet) IsConstraint() bool { return s.comparable || !s.terms.isAll() }
+func (s *TypeSet) IsConstraint() bool { return s.comparable || s.ordered || !s.terms.isAll() }
+
+// IsMethodSet reports whether type set s is fully described by its methods.
+func (s *TypeSet) IsMethodSet() bool { return !s.IsConstraint() }
 
 // IsComparable reports whether each type in the set is comparable.
-func (s *_TypeSet) IsComparable() bool {
+func (s *TypeSet) IsComparable() bool {
 	if s.terms.isAll() {
-		return s.comparable
+		return s.comparable || s.ordered // ordered types are comparable
 	}
 	return s.is(func(t *term) bool {
 		return Comparable(t.typ)
@@ -46,24 +54,54 @@ func (s *_TypeSet) IsComparable() bool {
 // TODO(gri) IsTypeSet is not a great name for this predicate. Find a better one.
 
 // IsTypeSet reports whether the type set s is represented by a finite set of underlying types.
-func (s *_TypeSet) IsTypeSet() bool {
-	return !s.comparable && len(s.methods) == 0
+func (s *TypeSet) IsTypeSet() bool {
+	return !s.comparable && !s.ordered && len(s.methods) == 0
 }
 
 // NumMethods returns the number of methods available.
-func (s *_TypeSet) NumMethods() int { return len(s.methods) }
+func (s *TypeSet) NumMethods() int { return len(s.methods) }
 
 // Method returns the i'th method of type set s for 0 <= i < s.NumMethods().
 // The methods are ordered by their unique ID.
-func (s *_TypeSet) Method(i int) *Func { return s.methods[i] }
+func (s *TypeSet) Method(i int) *Func { return s.methods[i] }
+
+// NumTerms returns the number of type terms of type set s. The terms
+// are in normal form: they are pairwise disjoint. The result is 0 if s
+// is not restricted by type terms, or if s is empty; use IsEmpty to
+// distinguish the two cases.
+func (s *TypeSet) NumTerms() int {
+	if s.terms.isAll() {
+		return 0
+	}
+	n := 0
+	for _, x := range s.terms {
+		if x != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Term returns the i'th type term of type set s for 0 <= i < s.NumTerms().
+func (s *TypeSet) Term(i int) *Term {
+	for _, x := range s.terms {
+		if x != nil {
+			if i == 0 {
+				return (*Term)(x)
+			}
+			i--
+		}
+	}
+	panic("term index out of range")
+}
 
 // LookupMethod returns the index of and method with matching package and name, or (-1, nil).
-func (s *_TypeSet) LookupMethod(pkg *Package, name string) (int, *Func) {
+func (s *TypeSet) LookupMethod(pkg *Package, name string) (int, *Func) {
 	// TODO(gri) s.methods is sorted - consider binary search
 	return lookupMethod(s.methods, pkg, name)
 }
 
-func (s *_TypeSet) String() string {
+func (s *TypeSet) String() string {
 	switch {
 	case s.IsEmpty():
 		return "∅"
@@ -78,6 +116,12 @@ func (s *_TypeSet) String() string {
 	buf.WriteByte('{')
 	if s.comparable {
 		buf.WriteString("comparable")
+		if s.ordered || hasMethods || hasTerms {
+			buf.WriteString("; ")
+		}
+	}
+	if s.ordered {
+		buf.WriteString("ordered")
 		if hasMethods || hasTerms {
 			buf.WriteString("; ")
 		}
@@ -101,16 +145,36 @@ func (s *_TypeSet) String() string {
 // ----------------------------------------------------------------------------
 // Implementation
 
-func (s *_TypeSet) hasTerms() bool              { return !s.terms.isAll() }
-func (s *_TypeSet) structuralType() Type        { return s.terms.structuralType() }
-func (s *_TypeSet) includes(t Type) bool        { return s.terms.includes(t) }
-func (s1 *_TypeSet) subsetOf(s2 *_TypeSet) bool { return s1.terms.subsetOf(s2.terms) }
+func (s *TypeSet) hasTerms() bool             { return !s.terms.isAll() }
+func (s *TypeSet) structuralType() Type       { return s.terms.structuralType() }
+func (s *TypeSet) includes(t Type) bool       { return s.terms.includes(t) }
+func (s1 *TypeSet) subsetOf(s2 *TypeSet) bool { return s1.terms.subsetOf(s2.terms) }
+
+// includes is like s.includes(t) but compares types with check.identical.
+func (check *Checker) includes(s *TypeSet, t Type) bool {
+	for _, x := range s.terms {
+		switch {
+		case x == nil:
+			continue // t ∈ ∅ == false
+		case x.typ == nil:
+			return true // t ∈ 𝓤 == true
+		}
+		u := t
+		if x.tilde {
+			u = under(u)
+		}
+		if check.identical(x.typ, u) {
+			return true
+		}
+	}
+	return false
+}
 
 // TODO(gri) TypeSet.is and TypeSet.underIs should probably also go into termlist.go
 
 var topTerm = term{false, theTop}
 
-func (s *_TypeSet) is(f func(*term) bool) bool {
+func (s *TypeSet) is(f func(*term) bool) bool {
 	if len(s.terms) == 0 {
 		return false
 	}
@@ -129,7 +193,7 @@ func (s *_TypeSet) is(f func(*term) bool) bool {
 	return true
 }
 
-func (s *_TypeSet) underIs(f func(Type) bool) bool {
+func (s *TypeSet) underIs(f func(Type) bool) bool {
 	if len(s.terms) == 0 {
 		return false
 	}
@@ -154,10 +218,28 @@ func (s *_TypeSet) underIs(f func(Type) bool) bool {
 }
 
 // topTypeSet may be used as type set for the empty interface.
-var topTypeSet = _TypeSet{terms: allTermlist}
+var topTypeSet = TypeSet{terms: allTermlist}
+
+// Default limits on the complexity of interfaces (see Config).
+const (
+	maxEmbeddingDepth   = 100
+	maxInterfaceMethods = 10000
+)
 
 // computeInterfaceTypeSet may be called with check == nil.
-func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_TypeSet {
+// The limits on embedding depth and method count are only enforced
+// if check != nil.
+//
+// The checker that created ityp computes its type set while it is
+// type-checking, reporting any errors. The type sets of all other
+// interfaces (such as imported interfaces, interfaces created through
+// the API or by substitution, and interfaces created by a checker that
+// is done) are computed without a checker, as needed, by lazyTypeSet.
+func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *TypeSet {
+	if check == nil || ityp.check != check {
+		return lazyTypeSet(pos, ityp, nil)
+	}
+
 	if ityp.tset != nil {
 		return ityp.tset
 	}
@@ -172,7 +254,14 @@ func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_
 		return &topTypeSet
 	}
 
-	if check != nil && check.conf.Trace {
+	check.counters.Interfaces++
+
+	if check.conf.Phase != nil && check.phase != "" && check.phase != "typeSets" {
+		defer check.startPhase(check.phase)
+		check.startPhase("typeSets")
+	}
+
+	if check.conf.Trace {
 		// Types don't generally have position information.
 		// If we don't have a valid pos provided, try to use
 		// one close enough.
@@ -187,42 +276,125 @@ func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_
 			check.trace(pos, "=> %s ", ityp.typeSet())
 		}()
 	}
+	if check.conf.TraceWriter != nil {
+		s := check.traceEnter(pos, "typeSet", "%s", ityp)
+		defer func() {
+			check.traceExit(s, "%s", ityp.typeSet())
+		}()
+	}
 
 	// An infinitely expanding interface (due to a cycle) is detected
 	// elsewhere (Checker.validType), so here we simply assume we only
 	// have valid interfaces. Mark the interface as complete to avoid
 	// infinite recursion if the validType check occurs later for some
 	// reason.
-	ityp.tset = &_TypeSet{terms: allTermlist} // TODO(gri) is this sufficient?
-
-	// Methods of embedded interfaces are collected unchanged; i.e., the identity
-	// of a method I.m's Func Object of an interface I is the same as that of
-	// the method m in an interface that embeds interface I. On the other hand,
-	// if a method is embedded via multiple overlapping embedded interfaces, we
-	// don't provide a guarantee which "original m" got chosen for the embedding
-	// interface. See also issue #34421.
-	//
-	// If we don't care to provide this identity guarantee anymore, instead of
-	// reusing the original method in embeddings, we can clone the method's Func
-	// Object and give it the position of a corresponding embedded interface. Then
-	// we can get rid of the mpos map below and simply use the cloned method's
-	// position.
+	ityp.tset = &TypeSet{terms: allTermlist} // TODO(gri) is this sufficient?
+	newTypeSet(check, pos, ityp, ityp.tset, nil)
+	return ityp.tset
+}
+
+// lazyTypeSet returns the type set of ityp, which is computed without a
+// checker if it is not known yet. The computation may happen concurrently
+// in multiple goroutines; the type set is recorded only once it is fully
+// computed, and all goroutines use the same type set. The type sets being
+// computed by the current goroutine are kept in active (which may be nil),
+// to avoid infinite recursion.
+func lazyTypeSet(pos syntax.Pos, ityp *Interface, active map[*Interface]*TypeSet) *TypeSet {
+	if check := ityp.check; check != nil {
+		// The type set is needed while ityp's checker is type-checking
+		// (in the current goroutine).
+		return computeInterfaceTypeSet(check, pos, ityp)
+	}
+	completeMu.Lock()
+	tset, complete := ityp.tset, ityp.complete
+	completeMu.Unlock()
+	if tset != nil {
+		return tset
+	}
+	if !complete {
+		return &topTypeSet // see computeInterfaceTypeSet
+	}
+	if tset := active[ityp]; tset != nil {
+		return tset // see computeInterfaceTypeSet
+	}
+
+	tset = &TypeSet{terms: allTermlist}
+	if active == nil {
+		active = make(map[*Interface]*TypeSet)
+	}
+	active[ityp] = tset
+	newTypeSet(nil, pos, ityp, tset, active)
+	delete(active, ityp)
+
+	completeMu.Lock()
+	defer completeMu.Unlock()
+	if ityp.tset == nil {
+		ityp.tset = tset
+	}
+	return ityp.tset
+}
+
+// loadTypeSet returns the type set of t if it has been computed, or nil.
+func (t *Interface) loadTypeSet() *TypeSet {
+	completeMu.Lock()
+	defer completeMu.Unlock()
+	return t.tset
+}
+
+// newTypeSet computes the type set of ityp in res. If active is nil,
+// the type sets of embedded elements are computed with check; otherwise
+// check is nil and they are computed with lazyTypeSet (see there).
+func newTypeSet(check *Checker, pos syntax.Pos, ityp *Interface, res *TypeSet, active map[*Interface]*TypeSet) {
+	maxDepth, maxMethods := maxEmbeddingDepth, maxInterfaceMethods
+	if check != nil && check.conf.MaxEmbeddingDepth > 0 {
+		maxDepth = check.conf.MaxEmbeddingDepth
+	}
+	if check != nil && check.conf.MaxInterfaceMethods > 0 {
+		maxMethods = check.conf.MaxInterfaceMethods
+	}
+
+	// embed records that embedding an element at pos nests interfaces
+	// depth levels deep. It reports whether depth is within limits.
+	embed := func(pos syntax.Pos, depth int) bool {
+		if check != nil && depth > maxDepth {
+			check.errorf(pos, _Todo, "interface too complex: cannot handle interfaces embedded more than %d levels deep", maxDepth)
+			res.partial = true
+			return false
+		}
+		if depth > res.depth {
+			res.depth = depth
+		}
+		return true
+	}
 
 	var todo []*Func
 	var seen objset
 	var methods []*Func
 	mpos := make(map[*Func]syntax.Pos) // method specification or method embedding position, for good error messages
+	tooManyMethods := false
 	addMethod := func(pos syntax.Pos, m *Func, explicit bool) {
 		switch other := seen.insert(m); {
 		case other == nil:
+			if check != nil && len(methods) >= maxMethods {
+				if !tooManyMethods {
+					check.errorf(pos, _Todo, "interface too complex: cannot handle more than %d methods", maxMethods)
+					tooManyMethods = true
+				}
+				res.partial = true
+				return
+			}
 			methods = append(methods, m)
 			mpos[m] = pos
 		case explicit:
 			if check == nil {
-				panic(fmt.Sprintf("%s: duplicate method %s", m.pos, m.name))
+				// The duplicate was reported when the interface was
+				// checked, if it was; ignore it.
+				res.partial = true
+				break
 			}
 			// check != nil
 			var err error_
+			err.code = _DuplicateDecl
 			err.errorf(pos, "duplicate method %s", m.name)
 			err.errorf(mpos[other.(*Func)], "other declaration of %s", m.name)
 			check.report(&err)
@@ -239,8 +411,11 @@ func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_
 			}
 			// check != nil
 			check.later(func() {
-				if !check.allowVersion(m.pkg, 1, 14) || !Identical(m.typ, other.Type()) {
+				identical := check.identical(m.typ, other.Type())
+				if !identical || !check.allowVersion(m.pkg, pos, 1, 14) {
 					var err error_
+					err.code = _DuplicateDecl
+					err.soft = identical // only invalid before go1.14
 					err.errorf(pos, "duplicate method %s", m.name)
 					err.errorf(mpos[other.(*Func)], "other declaration of %s", m.name)
 					check.report(&err)
@@ -266,39 +441,68 @@ func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_
 		var terms termlist
 		switch u := under(typ).(type) {
 		case *Interface:
-			tset := computeInterfaceTypeSet(check, pos, u)
-			// If typ is local, an error was already reported where typ is specified/defined.
-			if check != nil && check.isImportedConstraint(typ) && !check.allowVersion(check.pkg, 1, 18) {
-				check.errorf(pos, "embedding constraint interface %s requires go1.18 or later", typ)
+			var tset *TypeSet
+			if active != nil {
+				tset = lazyTypeSet(pos, u, active)
+			} else {
+				tset = computeInterfaceTypeSet(check, pos, u)
+			}
+			if !embed(pos, tset.depth+1) {
 				continue
 			}
+			// If typ is local, an error was already reported where typ is specified/defined.
 			if tset.comparable {
-				ityp.tset.comparable = true
+				res.comparable = true
+			}
+			if tset.ordered {
+				res.ordered = true
 			}
 			for _, m := range tset.methods {
 				addMethod(pos, m, false) // use embedding position pos rather than m.pos
 			}
+			if tset.partial {
+				res.partial = true
+			}
+			if check != nil && check.isImportedConstraint(typ) && !check.allowVersion(check.pkg, pos, 1, 18) {
+				check.errorf(pos, _Todo, "embedding constraint interface %s requires go1.18 or later", typ)
+				// keep the methods but ignore the type terms
+				res.partial = true
+				continue
+			}
 			terms = tset.terms
 		case *Union:
-			if check != nil && !check.allowVersion(check.pkg, 1, 18) {
-				check.errorf(pos, "embedding interface element %s requires go1.18 or later", u)
+			if check != nil && !check.allowVersion(check.pkg, pos, 1, 18) {
+				check.errorf(pos, _Todo, "embedding interface element %s requires go1.18 or later", u)
+				res.partial = true
 				continue
 			}
-			tset := computeUnionTypeSet(check, pos, u)
+			tset := unionTypeSet(check, pos, u, active)
 			if tset == &invalidTypeSet {
+				res.partial = true
 				continue // ignore invalid unions
 			}
+			if !embed(pos, tset.depth) {
+				continue
+			}
+			if tset.partial {
+				res.partial = true
+			}
+			for _, m := range tset.methods {
+				addMethod(pos, m, false)
+			}
 			terms = tset.terms
 		case *TypeParam:
 			// Embedding stand-alone type parameters is not permitted.
 			// This case is handled during union parsing.
 			unreachable()
 		default:
-			if typ == Typ[Invalid] {
+			if u == Typ[Invalid] {
+				res.partial = true
 				continue
 			}
-			if check != nil && !check.allowVersion(check.pkg, 1, 18) {
-				check.errorf(pos, "embedding non-interface type %s requires go1.18 or later", typ)
+			if check != nil && !check.allowVersion(check.pkg, pos, 1, 18) {
+				check.errorf(pos, _InvalidIfaceEmbed, "embedding non-interface type %s requires go1.18 or later", typ)
+				res.partial = true
 				continue
 			}
 			terms = termlist{{false, typ}}
@@ -309,24 +513,267 @@ func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_
 		// thus cannot overflow.
 		allTerms = allTerms.intersect(terms)
 	}
-	ityp.embedPos = nil // not needed anymore (errors have been reported)
 
 	// process todo's (this only happens if check == nil)
 	for i := 0; i < len(todo); i += 2 {
 		m := todo[i]
 		other := todo[i+1]
 		if !Identical(m.typ, other.typ) {
-			panic(fmt.Sprintf("%s: duplicate method %s", m.pos, m.name))
+			res.partial = true // see explicit duplicates above
+		}
+	}
+
+	// The type set of an interface is the intersection of the type sets of
+	// its methods and elements: a specific type (not a ~T term) is only in
+	// the type set if it has all methods.
+	if len(methods) > 0 && !allTerms.isAll() {
+		allTerms = filterMethodTerms(check, allTerms, methods)
+		if check != nil && !check.methodsKnown {
+			check.early = append(check.early, res) // see dropMethodTerms
 		}
 	}
 
 	if methods != nil {
 		sortMethods(methods)
-		ityp.tset.methods = methods
+		res.methods = methods
 	}
-	ityp.tset.terms = allTerms
+	res.terms = allTerms
+}
 
-	return ityp.tset
+// dropMethodTerms is called once the methods of all package-level types
+// are known, before any delayed actions (which check the uses of type sets)
+// are processed. It removes the specific type terms (not ~T terms) which
+// don't have all methods from all type sets computed before; type sets
+// computed afterwards don't have such terms in the first place (see
+// lacksMethods). Thus, an empty type set is reported as such by
+// TypeSet.IsEmpty, independent of the order in which type sets are
+// computed.
+func (check *Checker) dropMethodTerms() {
+	check.methodsKnown = true
+	for _, tset := range check.early {
+		tset.terms = filterMethodTerms(check, tset.terms, tset.methods)
+	}
+	check.early = nil
+}
+
+// reportEmptyTypeSet reports a soft error at pos if the type set of the
+// interface ityp declared in the source is provably empty, since no type
+// argument can ever satisfy it. If the type set of an embedded element is
+// empty by itself, the error is reported for that element instead.
+func (check *Checker) reportEmptyTypeSet(pos syntax.Pos, ityp *Interface) {
+	tset := ityp.typeSet()
+	if tset.partial || tset.terms.isAll() || !check.emptyTypeSet(tset) {
+		return
+	}
+	for _, typ := range ityp.embeddeds {
+		var s *TypeSet
+		switch u := under(typ).(type) {
+		case *Interface:
+			s = u.typeSet()
+		case *Union:
+			s = computeUnionTypeSet(check, pos, u)
+		}
+		if s != nil && check.emptyTypeSet(s) {
+			return
+		}
+	}
+	check.softErrorf(pos, _Todo, "%s has an empty type set (no type can satisfy it)", ityp)
+}
+
+// reportDroppedTerms reports a soft error for each union embedded in the
+// interface ityp (at the respective position in embedPos) that has terms
+// which are excluded from the type set of ityp because they don't have
+// all methods of ityp.
+func (check *Checker) reportDroppedTerms(ityp *Interface, embedPos []syntax.Pos) {
+	tset := ityp.typeSet()
+	if tset.partial || len(tset.methods) == 0 {
+		return
+	}
+	if check.conf.ReportEmptyTypeSets && check.emptyTypeSet(tset) {
+		return // reported as empty type set
+	}
+	mset := &Interface{complete: true, tset: &TypeSet{methods: tset.methods, terms: allTermlist}}
+	for i, typ := range ityp.embeddeds {
+		u, _ := typ.(*Union)
+		if u == nil {
+			continue
+		}
+		var dropped []string
+		for _, t := range u.terms {
+			if t.tilde || t.typ == Typ[Invalid] {
+				continue
+			}
+			if m, _ := check.missingMethod(t.typ, mset, true); m != nil {
+				dropped = append(dropped, check.sprintf("%s (missing method %s)", t.typ, m.name))
+			}
+		}
+		if dropped != nil {
+			check.softErrorf(embedPos[i], _Todo, "terms of %s are not in the type set of %s: %s", u, ityp, strings.Join(dropped, ", "))
+		}
+	}
+}
+
+// reportUnusedEmbeddeds reports a soft error for each element embedded in
+// the interface ityp (at the respective position in embedPos) which doesn't
+// contribute any methods or type terms to the type set of ityp beyond those
+// of the other methods and elements of ityp. Elements are considered from
+// last to first, and an element reported is not considered further; thus,
+// of two identical elements, only the latter one is reported.
+func (check *Checker) reportUnusedEmbeddeds(ityp *Interface, embedPos []syntax.Pos) {
+	tset := ityp.typeSet()
+	if tset.partial || tset.terms.isEmpty() {
+		return
+	}
+
+	// collect the type sets of the embedded elements
+	sets := make([]*TypeSet, len(ityp.embeddeds))
+	for i, typ := range ityp.embeddeds {
+		switch u := under(typ).(type) {
+		case *Interface:
+			sets[i] = u.typeSet()
+		case *Union:
+			sets[i] = computeUnionTypeSet(check, embedPos[i], u)
+		default:
+			if u == Typ[Invalid] {
+				return
+			}
+			sets[i] = &TypeSet{terms: termlist{{false, typ}}}
+		}
+		if sets[i] == &invalidTypeSet || sets[i].partial {
+			return
+		}
+	}
+
+	unused := make([]bool, len(sets))
+	for i := len(sets) - 1; i >= 0; i-- {
+		// compute the type set of ityp without element i and the
+		// elements found to be unused so far
+		var seen objset
+		for _, m := range ityp.methods {
+			seen.insert(m)
+		}
+		var comparable, ordered bool
+		terms := allTermlist
+		for j, s := range sets {
+			if j == i || unused[j] {
+				continue
+			}
+			for _, m := range s.methods {
+				seen.insert(m)
+			}
+			comparable = comparable || s.comparable
+			ordered = ordered || s.ordered
+			terms = terms.intersect(s.terms)
+		}
+		if len(seen) != len(tset.methods) || comparable != tset.comparable || ordered != tset.ordered {
+			continue // element i contributes methods or a predeclared constraint
+		}
+		if len(tset.methods) > 0 && !terms.isAll() {
+			terms = filterMethodTerms(check, terms, tset.methods)
+		}
+		if !terms.equal(tset.terms) {
+			continue // element i contributes type terms
+		}
+		unused[i] = true
+		check.softErrorf(embedPos[i], _Todo, "%s embedded in %s contributes no methods or type terms", ityp.embeddeds[i], ityp)
+	}
+}
+
+// emptyTypeSet reports whether the type set s is provably empty: either
+// its terms are empty, or each of its terms is a specific type (not a ~T
+// term) which doesn't have all methods of s.
+func (check *Checker) emptyTypeSet(s *TypeSet) bool {
+	if s.terms.isEmpty() {
+		return true
+	}
+	if len(s.methods) == 0 || s.terms.isAll() {
+		return false
+	}
+	mset := &Interface{complete: true, tset: &TypeSet{methods: s.methods, terms: allTermlist}}
+	for _, t := range s.terms {
+		if t.tilde {
+			return false
+		}
+		if m, _ := check.missingMethod(t.typ, mset, true); m == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// sameMethods reports whether the sorted method lists x and y have
+// the same methods with identical signatures.
+func sameMethods(x, y []*Func) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i, m := range x {
+		if m.Id() != y[i].Id() || !Identical(m.typ, y[i].typ) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterMethodTerms returns the terms of xl except for the specific types
+// which are known to miss (or have different) methods. xl is not modified.
+func filterMethodTerms(check *Checker, xl termlist, methods []*Func) termlist {
+	var rl termlist
+	for i, x := range xl {
+		if !x.tilde && lacksMethods(check, x.typ, methods) {
+			if rl == nil {
+				rl = append(termlist{}, xl[:i]...) // make sure rl != nil
+			}
+			continue
+		}
+		if rl != nil {
+			rl = append(rl, x)
+		}
+	}
+	if rl == nil {
+		return xl
+	}
+	return rl
+}
+
+// lacksMethods reports whether typ is known not to have all of the methods.
+// The type set of an interface may be computed before all methods of the
+// types declared in the package being checked are collected; thus, for
+// such types and for instantiated types the result is false, until
+// check.methodsKnown is set.
+func lacksMethods(check *Checker, typ Type, methods []*Func) bool {
+	if check != nil && check.methodsKnown {
+		mset := &Interface{complete: true, tset: &TypeSet{methods: methods, terms: allTermlist}}
+		m, _ := check.missingMethod(typ, mset, true)
+		return m != nil
+	}
+
+	switch t := typ.(type) {
+	case *Basic, *Slice, *Array, *Map, *Chan, *Signature:
+		return true // no methods
+	case *Pointer:
+		_, named := t.base.(*Named)
+		_, isStruct := t.base.(*Struct)
+		return !named && !isStruct
+	case *Struct:
+		for _, f := range t.fields {
+			if f.embedded {
+				return false // may have promoted methods
+			}
+		}
+		return true
+	case *Named:
+		if check == nil || t.obj.pkg == check.pkg || t.targs.Len() > 0 {
+			return false
+		}
+		for _, m := range methods {
+			obj, _, _ := lookupFieldOrMethod(t, false, m.pkg, m.name, nil)
+			if f, _ := obj.(*Func); f == nil || !check.identical(f.typ, m.typ) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func sortMethods(list []*Func) {
@@ -352,46 +799,111 @@ func (a byUniqueMethodName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 // invalidTypeSet is a singleton type set to signal an invalid type set
 // due to an error. It's also a valid empty type set, so consumers of
 // type sets may choose to ignore it.
-var invalidTypeSet _TypeSet
+var invalidTypeSet TypeSet
 
 // computeUnionTypeSet may be called with check == nil.
 // The result is &invalidTypeSet if the union overflows.
-func computeUnionTypeSet(check *Checker, pos syntax.Pos, utyp *Union) *_TypeSet {
-	if utyp.tset != nil {
-		return utyp.tset
+func computeUnionTypeSet(check *Checker, pos syntax.Pos, utyp *Union) *TypeSet {
+	return unionTypeSet(check, pos, utyp, nil)
+}
+
+// unionTypeSet is like computeUnionTypeSet. If active is not nil, check
+// is nil and the type sets of interface terms are computed as by
+// lazyTypeSet.
+//
+// Unions are not associated with a checker and may be shared; as for
+// lazyTypeSet, the type set is recorded only once it is fully computed.
+// Infinite recursion is avoided by the computation of the type sets of
+// the interface terms.
+func unionTypeSet(check *Checker, pos syntax.Pos, utyp *Union, active map[*Interface]*TypeSet) *TypeSet {
+	completeMu.Lock()
+	tset := utyp.tset
+	completeMu.Unlock()
+	if tset != nil {
+		return tset
 	}
 
-	// avoid infinite recursion (see also computeInterfaceTypeSet)
-	utyp.tset = new(_TypeSet)
+	if check != nil {
+		check.counters.Unions++
+	}
 
+	tset = new(TypeSet)
 	var allTerms termlist
+	var plain termlist // terms of the union terms without methods
 	for _, t := range utyp.terms {
 		var terms termlist
 		switch u := under(t.typ).(type) {
 		case *Interface:
-			terms = computeInterfaceTypeSet(check, pos, u).terms
+			var s *TypeSet
+			if active != nil {
+				s = lazyTypeSet(pos, u, active)
+			} else {
+				s = computeInterfaceTypeSet(check, pos, u)
+			}
+			if s.partial {
+				tset.partial = true
+			}
+			if s.depth >= tset.depth {
+				tset.depth = s.depth + 1
+			}
+			terms = s.terms
+			// Interface terms with methods are only permitted with
+			// Config.UnionMethods (checked by parseUnion). They must
+			// all have the same methods, which are the methods of
+			// the union's type set.
+			if len(s.methods) > 0 {
+				if tset.methods == nil {
+					tset.methods = s.methods
+				} else if !sameMethods(tset.methods, s.methods) {
+					tset = &invalidTypeSet
+				}
+			} else {
+				plain = append(plain, terms...)
+			}
 		case *TypeParam:
 			// A stand-alone type parameters is not permitted as union term.
 			// This case is handled during union parsing.
 			unreachable()
 		default:
-			if t.typ == Typ[Invalid] {
+			if u == Typ[Invalid] {
+				tset.partial = true
 				continue
 			}
 			terms = termlist{(*term)(t)}
+			plain = append(plain, terms...)
+		}
+		if tset == &invalidTypeSet {
+			break
 		}
 		// The type set of a union expression is the union
 		// of the type sets of each term.
 		allTerms = allTerms.union(terms)
-		if len(allTerms) > maxTermCount {
+		if max := check.maxUnionTerms(); len(allTerms) > max {
 			if check != nil {
-				check.errorf(pos, "cannot handle more than %d union terms (implementation limitation)", maxTermCount)
+				check.errorf(pos, _Todo, "constraint too complex: cannot handle more than %d union terms", max)
+			}
+			tset = &invalidTypeSet
+			break
+		}
+	}
+	if tset != &invalidTypeSet && tset.methods != nil {
+		// The other terms must be specific types which have the methods
+		// (unknown methods are verified by parseUnion).
+		for _, x := range plain {
+			if x.typ == nil || x.tilde || lacksMethods(check, x.typ, tset.methods) {
+				tset = &invalidTypeSet
+				break
 			}
-			utyp.tset = &invalidTypeSet
-			return utyp.tset
 		}
 	}
-	utyp.tset.terms = allTerms
+	if tset != &invalidTypeSet {
+		tset.terms = allTerms
+	}
 
+	completeMu.Lock()
+	defer completeMu.Unlock()
+	if utyp.tset == nil {
+		utyp.tset = tset
+	}
 	return utyp.tset
 }
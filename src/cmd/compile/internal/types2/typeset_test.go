@@ -24,12 +24,22 @@ func TestTypeSetString(t *testing.T) {
 		"{int|string}":  "{int ∪ string}",
 		"{int; string}": "∅",
 
+		// intersections of unions
+		"{~int|~string|float64; int|~string|~float64}":                                   "{int ∪ ~string ∪ float64}",
+		"{int|string|bool; string|bool|float64; ~string}":                                "{string}",
+		"{A; B}; type A interface{E|string}; type B interface{~int|~string}; type E int": "{p.E ∪ string}",
+		"{~int|~string; E|string|float64}; type E int":                                   "{p.E ∪ string}",
+
 		"{comparable}":              "{comparable}",
 		"{comparable; int}":         "{comparable; int}",
 		"{~int; comparable}":        "{comparable; ~int}",
 		"{int|string; comparable}":  "{comparable; int ∪ string}",
 		"{comparable; int; string}": "∅",
 
+		"{ordered}":             "{ordered}",
+		"{ordered; ~int}":       "{ordered; ~int}",
+		"{comparable; ordered}": "{comparable; ordered}",
+
 		"{m()}":                         "{func (p.T).m()}",
 		"{m1(); m2() int }":             "{func (p.T).m1(); func (p.T).m2() int}",
 		"{error}":                       "{func (error).Error() string}",
@@ -37,8 +47,18 @@ func TestTypeSetString(t *testing.T) {
 		"{m1(); comparable; m2() int }": "{comparable; func (p.T).m1(); func (p.T).m2() int}",
 		"{comparable; error}":           "{comparable; func (error).Error() string}",
 
-		"{m(); comparable; int|float32|string}": "{comparable; func (p.T).m(); int ∪ float32 ∪ string}",
-		"{m1(); int; m2(); comparable }":        "{comparable; func (p.T).m1(); func (p.T).m2(); int}",
+		"{m(); comparable; ~int|~float32|~string}": "{comparable; func (p.T).m(); ~int ∪ ~float32 ∪ ~string}",
+		"{m1(); ~int; m2(); comparable }":          "{comparable; func (p.T).m1(); func (p.T).m2(); ~int}",
+
+		// specific types without the required methods are not in the type set
+		"{m(); int}":                            "∅",
+		"{m(); comparable; int|float32|string}": "∅",
+		"{m(); ~int|float32|~string|[]byte}":    "{func (p.T).m(); ~int ∪ ~string}",
+		"{m(); struct{}|struct{E}}; type E int": "∅",
+		"{m(); *int|*E}; type E int":            "∅",
+		"{m(); E}; type E int":                  "∅", // E is dropped once its methods are known
+		"{m(); E|~string}; type E int":          "{func (p.T).m(); ~string}",
+		"{m(); E}; type E int; func (E) m()":    "{func (p.T).m(); p.E}",
 
 		"{E}; type E interface{}":           "𝓤",
 		"{E}; type E interface{int;string}": "∅",
@@ -53,7 +73,7 @@ func TestTypeSetString(t *testing.T) {
 		}
 
 		// type check
-		var conf Config
+		conf := Config{OperatorConstraints: true}
 		pkg, err := conf.Check(file.PkgName.Value, []*syntax.File{file}, nil)
 		if err != nil {
 			t.Fatalf("%s: %v (invalid test case)", body, err)
@@ -77,4 +97,178 @@ func TestTypeSetString(t *testing.T) {
 	}
 }
 
+func TestEmptyTypeSets(t *testing.T) {
+	for _, test := range []struct {
+		src   string
+		empty string // interface reported as empty, or ""
+	}{
+		{"type _ interface{ ~int; ~string }", "interface{~int; ~string}"},
+		{"type _ interface{ int; float64 }", "interface{int; float64}"},
+		{"type _ interface{ int|string; ~float64 }", "interface{int|string; ~float64}"},
+		{"type _ interface{ int; m() }", "interface{m(); int}"},
+		{"type _ interface{ int|string; m() }", "interface{m(); int|string}"},
+		{"type T int; func (T) m(); type _ interface{ int|T; m() }", ""},
+		{"type T int; func (*T) m(); type _ interface{ T; m() }", "interface{m(); T}"},
+		{"type _ interface{ ~int; m() }", ""},
+		{"type _ interface{ ~int|~string; ~string|float64 }", ""},
+		{"type _ interface{ comparable; int }", ""},
+		{"type T int; func _() { type C interface{ T; m() } }", "interface{m(); T}"},
+
+		// the error is only reported for the innermost empty interface
+		{"type E interface{ int; string }; type _ interface{ E; m() }", "interface{int; string}"},
+		{"type _ interface{ interface{ int; string }; ~float64 }", "interface{int; string}"},
+	} {
+		src := "package p; " + test.src
+		file, err := syntax.Parse(nil, strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+		if err != nil {
+			t.Fatalf("%s: %v (invalid test case)", src, err)
+		}
+
+		var want []string
+		if test.empty != "" {
+			want = []string{test.empty + " has an empty type set (no type can satisfy it)"}
+		}
+		for _, concurrent := range []bool{false, true} {
+			var errs []string
+			conf := Config{
+				ReportEmptyTypeSets:  true,
+				ConcurrentFuncBodies: concurrent,
+				Error:                func(err error) { errs = append(errs, err.(Error).Msg) },
+			}
+			info := Info{Defs: make(map[*syntax.Name]Object)}
+			conf.Check(file.PkgName.Value, []*syntax.File{file}, &info)
+			if strings.Join(errs, "\n") != strings.Join(want, "\n") {
+				t.Errorf("%s (concurrent = %v): got errors %q; want %q", src, concurrent, errs, want)
+			}
+
+			// The recorded type sets are the same independent of
+			// whether function bodies are checked concurrently.
+			for _, obj := range info.Defs {
+				if tname, _ := obj.(*TypeName); tname != nil && tname.Parent() != tname.Pkg().Scope() {
+					if iface, _ := tname.Type().Underlying().(*Interface); iface != nil && iface.TypeSet().IsEmpty() != (test.empty != "") {
+						t.Errorf("%s (concurrent = %v): got type set %s for %s", src, concurrent, iface.TypeSet(), tname.Name())
+					}
+				}
+			}
+		}
+
+		// without ReportEmptyTypeSets, all test cases are valid
+		conf := Config{}
+		if _, err := conf.Check(file.PkgName.Value, []*syntax.File{file}, nil); err != nil {
+			t.Errorf("%s: %v", src, err)
+		}
+	}
+}
+
+func TestDroppedTerms(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		errs []string
+	}{
+		{"type _ interface{ int|~string; m() }", []string{"terms of int|~string are not in the type set of interface{m(); int|~string}: int (missing method m)"}},
+		{"type T int; func (T) m(); type _ interface{ T|int|string; m() }", []string{"terms of T|int|string are not in the type set of interface{m(); T|int|string}: int (missing method m), string (missing method m)"}},
+		{"type T int; func (T) m(); type _ interface{ T|~string; m() }", nil},
+		{"type _ interface{ int|string }", nil},
+		{"type I interface{ m() }; type _ interface{ I; int|~string }", []string{"terms of int|~string are not in the type set of interface{I; int|~string}: int (missing method m)"}},
+
+		// all terms drop out: only report the empty type set
+		{"type _ interface{ int|string; m() }", []string{"interface{m(); int|string} has an empty type set (no type can satisfy it)"}},
+	} {
+		src := "package p; " + test.src
+		file, err := syntax.Parse(nil, strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+		if err != nil {
+			t.Fatalf("%s: %v (invalid test case)", src, err)
+		}
+
+		var errs []string
+		conf := Config{
+			ReportEmptyTypeSets: true,
+			ReportDroppedTerms:  true,
+			Error:               func(err error) { errs = append(errs, err.(Error).Msg) },
+		}
+		conf.Check(file.PkgName.Value, []*syntax.File{file}, nil)
+		if strings.Join(errs, "\n") != strings.Join(test.errs, "\n") {
+			t.Errorf("%s: got errors %q; want %q", src, errs, test.errs)
+		}
+	}
+}
+
+func TestUnusedEmbeddeds(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		errs []string
+	}{
+		{"type R interface{ r() }; type RC interface{ R; c() }; type _ interface{ R; RC }", []string{"R embedded in interface{R; RC} contributes no methods or type terms"}},
+		{"type R interface{ r() }; type _ interface{ R; R }", []string{"R embedded in interface{R; R} contributes no methods or type terms"}},
+		{"type R interface{ r() }; type _ interface{ R; r() }", []string{"R embedded in interface{r(); R} contributes no methods or type terms"}},
+		{"type _ interface{ ~int; int }", []string{"~int embedded in interface{~int; int} contributes no methods or type terms"}},
+		{"type _ interface{ int|string; ~int|float64 }", nil},
+		{"type _ interface{ interface{}; m() }", []string{"interface{} embedded in interface{m(); interface{}} contributes no methods or type terms"}},
+		{"type _ interface{ comparable; int }", nil},
+		{"type R interface{ r() }; type W interface{ w() }; type _ interface{ R; W }", nil},
+		{"type _ interface{ int; string }", nil}, // empty type set
+	} {
+		src := "package p; " + test.src
+		file, err := syntax.Parse(nil, strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+		if err != nil {
+			t.Fatalf("%s: %v (invalid test case)", src, err)
+		}
+
+		var errs []string
+		conf := Config{
+			ReportUnused: true,
+			Error:        func(err error) { errs = append(errs, err.(Error).Msg) },
+		}
+		conf.Check(file.PkgName.Value, []*syntax.File{file}, nil)
+		if strings.Join(errs, "\n") != strings.Join(test.errs, "\n") {
+			t.Errorf("%s: got errors %q; want %q", src, errs, test.errs)
+		}
+	}
+}
+
+func TestUnionMethods(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		tset string   // type set of T if there are no errors
+		errs []string // expected errors
+	}{
+		{"type T interface{ interface{ ~int; m() } | interface{ ~string; m() } }", "{func (interface).m(); ~int ∪ ~string}", nil},
+		{"type E int; func (E) m(); type T interface{ E | interface{ ~string; m() } }", "{func (interface).m(); p.E ∪ ~string}", nil},
+		{"type M interface{ m() }; type T interface{ M | interface{ ~int; m() }; ~int }", "{func (p.M).m(); ~int}", nil},
+		{"type T interface{ int | interface{ ~string; m() } }", "", []string{"cannot use int in union with interface{m(); ~string} (int missing method m)"}},
+		{"type T interface{ ~int | interface{ ~string; m() } }", "", []string{"cannot use ~int in union with interface{m(); ~string} (not all types in ~int have the methods of interface{m(); ~string})"}},
+		{"type T interface{ interface{ m() } | interface{ n() } }", "", []string{"cannot use interface{n()} in union with interface{m()} (different methods)"}},
+		{"type T interface{ int | comparable }", "", []string{"cannot use comparable in union (interface is or embeds comparable)"}},
+	} {
+		src := "package p; " + test.src
+		file, err := syntax.Parse(nil, strings.NewReader(src), nil, nil, syntax.AllowGenerics)
+		if err != nil {
+			t.Fatalf("%s: %v (invalid test case)", src, err)
+		}
+
+		var errs []string
+		conf := Config{
+			UnionMethods: true,
+			Error:        func(err error) { errs = append(errs, err.(Error).Msg) },
+		}
+		pkg, _ := conf.Check(file.PkgName.Value, []*syntax.File{file}, nil)
+		if strings.Join(errs, "\n") != strings.Join(test.errs, "\n") {
+			t.Errorf("%s: got errors %q; want %q", src, errs, test.errs)
+		}
+		if len(errs) == 0 {
+			if got := under(pkg.scope.Lookup("T").Type()).(*Interface).typeSet().String(); got != test.tset {
+				t.Errorf("%s: got type set %s; want %s", src, got, test.tset)
+			}
+		}
+
+		// without UnionMethods, interfaces with methods are not permitted in unions
+		errs = nil
+		conf.UnionMethods = false
+		conf.Check(file.PkgName.Value, []*syntax.File{file}, nil)
+		if len(errs) == 0 || !strings.Contains(errs[0], "in union (interface contains methods)") {
+			t.Errorf("%s: got errors %q without UnionMethods", src, errs)
+		}
+	}
+}
+
 // TODO(gri) add more tests
@@ -227,7 +227,7 @@ func (w *typeWriter) typ(typ Type) {
 		// types. Write them to aid debugging, but don't write
 		// them when we need an instance hash: whether a type
 		// is fully expanded or not doesn't matter for identity.
-		if !w.hash && t.instPos != nil {
+		if !w.hash && t.unexpanded() {
 			w.byte(instanceMarker)
 		}
 		w.typeName(t.obj)
@@ -24,32 +24,43 @@ func (check *Checker) ident(x *operand, e *syntax.Name, def *Named, wantType boo
 
 	// Note that we cannot use check.lookup here because the returned scope
 	// may be different from obj.Parent(). See also Scope.LookupParent doc.
-	scope, obj := check.scope.LookupParent(e.Value, check.pos)
+	scope, obj := check.lookupParent(e.Value, check.pos)
 	switch obj {
 	case nil:
 		if e.Value == "_" {
-			check.error(e, "cannot use _ as value or type")
+			check.error(e, _InvalidBlank, "cannot use _ as value or type")
 		} else {
 			if check.conf.CompilerErrorMessages {
-				check.errorf(e, "undefined: %s", e.Value)
+				check.errorf(e, _UndeclaredName, "undefined: %s", e.Value)
 			} else {
-				check.errorf(e, "undeclared name: %s", e.Value)
+				check.errorf(e, _UndeclaredName, "undeclared name: %s", e.Value)
 			}
 		}
 		return
 	case universeAny, universeComparable:
 		// complain if necessary
-		if !check.allowVersion(check.pkg, 1, 18) {
-			check.errorf(e, "undeclared name: %s (requires version go1.18 or later)", e.Value)
+		if !check.allowVersion(check.pkg, e, 1, 18) {
+			check.errorf(e, _UndeclaredName, "undeclared name: %s (requires version go1.18 or later)", e.Value)
 			return // avoid follow-on errors
 		}
 		if obj == universeAny {
 			// If we allow "any" for general use, this if-statement can be removed (issue #33232).
-			check.softErrorf(e, "cannot use any outside constraint position")
+			check.softErrorf(e, _Todo, "cannot use any outside constraint position")
 			// ok to continue
 		}
+	case universeOrdered:
+		// ordered is only declared with GOEXPERIMENT=operatorconstraints
+		if !check.conf.OperatorConstraints {
+			if check.conf.CompilerErrorMessages {
+				check.errorf(e, _UndeclaredName, "undefined: %s", e.Value)
+			} else {
+				check.errorf(e, _UndeclaredName, "undeclared name: %s", e.Value)
+			}
+			return
+		}
 	}
 	check.recordUse(e, obj)
+	check.addDeclUse(obj)
 
 	// Type-check the object.
 	// Only call Checker.objDecl if the object doesn't have a type yet
@@ -71,12 +82,12 @@ func (check *Checker) ident(x *operand, e *syntax.Name, def *Named, wantType boo
 	// (This code is only needed for dot-imports. Without them,
 	// we only have to mark variables, see *Var case below).
 	if pkgName := check.dotImportMap[dotImportKey{scope, obj.Name()}]; pkgName != nil {
-		pkgName.used = true
+		check.usePkgName(pkgName)
 	}
 
 	switch obj := obj.(type) {
 	case *PkgName:
-		check.errorf(e, "use of package %s not in selector", obj.name)
+		check.errorf(e, _InvalidPkgUse, "use of package %s not in selector", obj.name)
 		return
 
 	case *Const:
@@ -86,24 +97,36 @@ func (check *Checker) ident(x *operand, e *syntax.Name, def *Named, wantType boo
 		}
 		if obj == universeIota {
 			if check.iota == nil {
-				check.error(e, "cannot use iota outside constant declaration")
+				check.error(e, _InvalidIota, "cannot use iota outside constant declaration")
 				return
 			}
 			x.val = check.iota
 		} else {
 			x.val = obj.val
+			check.setTargetValues(e, check.targetConsts[obj])
 		}
 		assert(x.val != nil)
 		x.mode = constant_
 
 	case *TypeName:
+		if obj.tparams != nil {
+			check.errorf(e, _Todo, "cannot use generic type %s without instantiation", obj.name)
+			return
+		}
+		if tpar, _ := typ.(*TypeParam); tpar != nil && check.conf.ReportUnused {
+			if check.usedTParams == nil {
+				check.usedTParams = make(map[*TypeParam]bool)
+			}
+			check.usedTParams[tpar] = true
+		}
 		x.mode = typexpr
 
 	case *Var:
-		// It's ok to mark non-local variables, but ignore variables
-		// from other packages to avoid potential race conditions with
+		// Only local variables are reported if unused. Ignore package-level
+		// variables and variables from other packages to avoid potential
+		// race conditions with concurrently checked function bodies and
 		// dot-imported variables.
-		if obj.pkg == check.pkg {
+		if obj.pkg == check.pkg && obj.parent != check.pkg.scope {
 			obj.used = true
 		}
 		check.addDeclDep(obj)
@@ -151,9 +174,11 @@ func (check *Checker) varType(e syntax.Expr) Type {
 			tset := computeInterfaceTypeSet(check, pos, t) // TODO(gri) is this the correct position?
 			if tset.IsConstraint() {
 				if tset.comparable {
-					check.softErrorf(pos, "interface is (or embeds) comparable")
+					check.softErrorf(pos, _Todo, "interface is (or embeds) comparable")
+				} else if tset.ordered {
+					check.softErrorf(pos, _Todo, "interface is (or embeds) ordered")
 				} else {
-					check.softErrorf(pos, "interface contains type constraints")
+					check.softErrorf(pos, _Todo, "interface contains type constraints")
 				}
 			}
 		}
@@ -171,7 +196,7 @@ func (check *Checker) definedType(e syntax.Expr, def *Named) Type {
 	typ := check.typInternal(e, def)
 	assert(isTyped(typ))
 	if isGeneric(typ) {
-		check.errorf(e, "cannot use generic type %s without instantiation", typ)
+		check.errorf(e, _Todo, "cannot use generic type %s without instantiation", typ)
 		typ = Typ[Invalid]
 	}
 	check.recordTypeAndValue(e, typexpr, typ, nil)
@@ -184,7 +209,7 @@ func (check *Checker) genericType(e syntax.Expr, reportErr bool) Type {
 	assert(isTyped(typ))
 	if typ != Typ[Invalid] && !isGeneric(typ) {
 		if reportErr {
-			check.errorf(e, "%s is not a generic type", typ)
+			check.errorf(e, _Todo, "%s is not a generic type", typ)
 		}
 		typ = Typ[Invalid]
 	}
@@ -240,9 +265,9 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		case invalid:
 			// ignore - error reported before
 		case novalue:
-			check.errorf(&x, "%s used as type", &x)
+			check.errorf(&x, _NotAType, "%s used as type", &x)
 		default:
-			check.errorf(&x, "%s is not a type", &x)
+			check.errorf(&x, _NotAType, "%s is not a type", &x)
 		}
 
 	case *syntax.SelectorExpr:
@@ -257,14 +282,14 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		case invalid:
 			// ignore - error reported before
 		case novalue:
-			check.errorf(&x, "%s used as type", &x)
+			check.errorf(&x, _NotAType, "%s used as type", &x)
 		default:
-			check.errorf(&x, "%s is not a type", &x)
+			check.errorf(&x, _NotAType, "%s is not a type", &x)
 		}
 
 	case *syntax.IndexExpr:
-		if !check.allowVersion(check.pkg, 1, 18) {
-			check.softErrorf(e.Pos(), "type instantiation requires go1.18 or later")
+		if !check.allowVersion(check.pkg, e, 1, 18) {
+			check.softErrorf(e.Pos(), _Todo, "type instantiation requires go1.18 or later")
 		}
 		return check.instantiatedType(e.X, unpackExpr(e.Index), def)
 
@@ -280,7 +305,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 			typ.len = check.arrayLength(e.Len)
 		} else {
 			// [...]array
-			check.error(e, "invalid use of [...] array (outside a composite literal)")
+			check.error(e, _BadDotDotDotSyntax, "invalid use of [...] array (outside a composite literal)")
 			typ.len = -1
 		}
 		typ.elem = check.varType(e.Elem)
@@ -298,7 +323,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 	case *syntax.DotsType:
 		// dots are handled explicitly where they are legal
 		// (array composite literals and parameter lists)
-		check.error(e, "invalid use of '...'")
+		check.error(e, _InvalidDotDotDot, "invalid use of '...'")
 		check.use(e.Elem)
 
 	case *syntax.StructType:
@@ -315,13 +340,13 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 			return typ
 		}
 
-		check.errorf(e0, "%s is not a type", e0)
+		check.errorf(e0, _NotAType, "%s is not a type", e0)
 		check.use(e0)
 
 	case *syntax.FuncType:
 		typ := new(Signature)
 		def.setUnderlying(typ)
-		check.funcType(typ, nil, nil, e)
+		check.funcType(typ, nil, e)
 		return typ
 
 	case *syntax.InterfaceType:
@@ -331,6 +356,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 			typ.obj = def.obj
 		}
 		check.interfaceType(typ, e, def)
+		check.recordDeclaredType(e, typ)
 		return typ
 
 	case *syntax.MapType:
@@ -352,7 +378,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 				if asTypeParam(typ.key) != nil {
 					why = " (missing comparable constraint)"
 				}
-				check.errorf(e.Key, "invalid map key type %s%s", typ.key, why)
+				check.errorf(e.Key, _IncomparableMapKey, "invalid map key type %s%s", typ.key, why)
 			}
 		})
 
@@ -371,7 +397,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		case syntax.RecvOnly:
 			dir = RecvOnly
 		default:
-			check.errorf(e, invalidAST+"unknown channel direction %d", e.Dir)
+			check.errorf(e, _InvalidSyntaxTree, invalidAST+"unknown channel direction %d", e.Dir)
 			// ok to continue
 		}
 
@@ -380,7 +406,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		return typ
 
 	default:
-		check.errorf(e0, "%s is not a type", e0)
+		check.errorf(e0, _NotAType, "%s is not a type", e0)
 		check.use(e0)
 	}
 
@@ -390,6 +416,10 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 }
 
 func (check *Checker) instantiatedType(x syntax.Expr, targsx []syntax.Expr, def *Named) Type {
+	if alias := check.genericAlias(x); alias != nil {
+		return check.instantiatedAlias(x, alias, targsx, def)
+	}
+
 	gtyp := check.genericType(x, true)
 	if gtyp == Typ[Invalid] {
 		return gtyp // error already reported
@@ -412,6 +442,13 @@ func (check *Checker) instantiatedType(x syntax.Expr, targsx []syntax.Expr, def
 		posList[i] = syntax.StartPos(arg)
 	}
 
+	// use default types for omitted type arguments, if possible
+	if tparams := base.TParams().list(); len(targs) < len(tparams) {
+		if dargs, index := check.defaultTArgs(x.Pos(), tparams, targs); index < 0 {
+			targs = dargs
+		}
+	}
+
 	typ := check.instantiate(x.Pos(), base, targs, posList)
 	def.setUnderlying(typ)
 
@@ -424,6 +461,114 @@ func (check *Checker) instantiatedType(x syntax.Expr, targsx []syntax.Expr, def
 	return typ
 }
 
+// genericAlias returns the parameterized alias denoted by the (possibly
+// qualified) identifier x, or nil. If the result is not nil, the use of
+// the alias is recorded and its declaration is type-checked, as is done
+// by Checker.ident and Checker.selector for other type names.
+func (check *Checker) genericAlias(x syntax.Expr) *TypeName {
+	switch e := x.(type) {
+	case *syntax.Name:
+		scope, obj := check.lookupParent(e.Value, check.pos)
+		tname, _ := obj.(*TypeName)
+		if tname == nil || !check.isGenericAlias(tname) {
+			return nil
+		}
+		check.recordUse(e, tname)
+		check.objDecl(tname, nil)
+		if pkgName := check.dotImportMap[dotImportKey{scope, tname.name}]; pkgName != nil {
+			check.usePkgName(pkgName)
+		}
+		return tname
+
+	case *syntax.SelectorExpr:
+		ident, _ := e.X.(*syntax.Name)
+		if ident == nil {
+			return nil
+		}
+		pname, _ := check.lookup(ident.Value).(*PkgName)
+		if pname == nil || pname.imported.cgo {
+			return nil
+		}
+		tname, _ := pname.imported.scope.Lookup(e.Sel.Value).(*TypeName)
+		if tname == nil || tname.tparams == nil || !tname.Exported() {
+			return nil // let Checker.selector report any error
+		}
+		check.recordUse(ident, pname)
+		check.usePkgName(pname)
+		check.recordUse(e.Sel, tname)
+		return tname
+	}
+	return nil
+}
+
+// isGenericAlias reports whether obj is a parameterized alias, possibly
+// one whose declaration is not yet type-checked.
+func (check *Checker) isGenericAlias(obj *TypeName) bool {
+	if obj.tparams != nil {
+		return true
+	}
+	if d := check.objMap[obj]; d != nil && d.tdecl != nil {
+		return d.tdecl.Alias && d.tdecl.TParamList != nil
+	}
+	return false
+}
+
+// instantiatedAlias returns the type denoted by the parameterized alias
+// obj, used in x, instantiated with the type arguments targsx: the aliased
+// type with the type parameters of obj replaced by the type arguments.
+func (check *Checker) instantiatedAlias(x syntax.Expr, obj *TypeName, targsx []syntax.Expr, def *Named) Type {
+	check.recordTypeAndValue(x, typexpr, obj.typ, nil)
+	if obj.typ == Typ[Invalid] || obj.tparams == nil {
+		// invalid alias, or cycle through the type parameters of obj
+		// (error reported before)
+		def.setUnderlying(Typ[Invalid])
+		return Typ[Invalid]
+	}
+
+	// evaluate arguments
+	targs := check.typeList(targsx)
+	if targs == nil {
+		def.setUnderlying(Typ[Invalid])
+		return Typ[Invalid]
+	}
+
+	// determine argument positions
+	posList := make([]syntax.Pos, len(targs))
+	for i, arg := range targsx {
+		posList[i] = syntax.StartPos(arg)
+	}
+
+	// use default types for omitted type arguments, if possible
+	tparams := obj.tparams.list()
+	if len(targs) < len(tparams) {
+		if dargs, index := check.defaultTArgs(x.Pos(), tparams, targs); index < 0 {
+			targs = dargs
+		}
+	}
+	if !check.validateTArgLen(x.Pos(), len(tparams), len(targs)) {
+		def.setUnderlying(Typ[Invalid])
+		return Typ[Invalid]
+	}
+
+	typ := check.subst(x.Pos(), obj.typ, makeSubstMap(tparams, targs), nil)
+	def.setUnderlying(typ)
+
+	pos := x.Pos()
+	check.later(func() {
+		if i, err := check.verify(pos, tparams, targs); err != nil {
+			// best position for error reporting
+			pos := pos
+			if i < len(posList) {
+				pos = posList[i]
+			}
+			check.softErrorf(pos, _Todo, err.Error())
+		}
+		check.validType(typ, nil)
+	})
+
+	return typ
+}
+
 // arrayLength type-checks the array length expression e
 // and returns the constant length >= 0, or a value < 0
 // to indicate an error (and thus an unknown length).
@@ -432,7 +577,7 @@ func (check *Checker) arrayLength(e syntax.Expr) int64 {
 	check.expr(&x, e)
 	if x.mode != constant_ {
 		if x.mode != invalid {
-			check.errorf(&x, "array length %s must be constant", &x)
+			check.errorf(&x, _InvalidArrayLen, "array length %s must be constant", &x)
 		}
 		return -1
 	}
@@ -440,14 +585,17 @@ func (check *Checker) arrayLength(e syntax.Expr) int64 {
 		if val := constant.ToInt(x.val); val.Kind() == constant.Int {
 			if representableConst(val, check, Typ[Int], nil) {
 				if n, ok := constant.Int64Val(val); ok && n >= 0 {
+					if !check.targetArrayLength(&x) {
+						return -1
+					}
 					return n
 				}
-				check.errorf(&x, "invalid array length %s", &x)
+				check.errorf(&x, _InvalidArrayLen, "invalid array length %s", &x)
 				return -1
 			}
 		}
 	}
-	check.errorf(&x, "array length %s must be integer", &x)
+	check.errorf(&x, _InvalidArrayLen, "array length %s must be integer", &x)
 	return -1
 }
 
@@ -438,7 +438,9 @@ func (u *unifier) nify(x, y Type, p *ifacePair) bool {
 			//           in the same package if one of them is nested in a function.
 			//           Extremely unlikely but we need an always correct solution.
 			if x.obj.pkg == y.obj.pkg && x.obj.name == y.obj.name {
-				assert(len(xargs) == len(yargs))
+				if len(xargs) != len(yargs) {
+					return false // an error was reported for the invalid instance
+				}
 				for i, x := range xargs {
 					if !u.nify(x, yargs[i], p) {
 						return false
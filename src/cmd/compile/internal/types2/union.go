@@ -11,8 +11,8 @@ import "cmd/compile/internal/syntax"
 
 // A Union represents a union of terms embedded in an interface.
 type Union struct {
-	terms []*Term   // list of syntactical terms (not a canonicalized termlist)
-	tset  *_TypeSet // type set described by this union, computed lazily
+	terms []*Term  // list of syntactical terms (not a canonicalized termlist)
+	tset  *TypeSet // type set described by this union, computed lazily
 }
 
 // NewUnion returns a new Union type with the given terms.
@@ -24,7 +24,12 @@ func NewUnion(terms []*Term) *Union {
 	return &Union{terms, nil}
 }
 
-func (u *Union) Len() int         { return len(u.terms) }
+// Len returns the number of terms of union u.
+func (u *Union) Len() int { return len(u.terms) }
+
+// Term returns the i'th term of union u for 0 <= i < u.Len().
+// The terms are in source order (or in the order given to NewUnion);
+// they are neither sorted nor simplified.
 func (u *Union) Term(i int) *Term { return u.terms[i] }
 
 func (u *Union) Underlying() Type { return u }
@@ -44,8 +49,18 @@ func (t *Term) String() string { return (*term)(t).String() }
 // Implementation
 
 // Avoid excessive type-checking times due to quadratic termlist operations.
+// This is the default for Config.MaxUnionTerms.
 const maxTermCount = 100
 
+// maxUnionTerms returns the maximum number of terms of a union.
+// check may be nil.
+func (check *Checker) maxUnionTerms() int {
+	if check != nil && check.conf.MaxUnionTerms > 0 {
+		return check.conf.MaxUnionTerms
+	}
+	return maxTermCount
+}
+
 // parseUnion parses the given list of type expressions tlist as a union of
 // those expressions. The result is a Union type, or Typ[Invalid] for some
 // errors.
@@ -56,8 +71,8 @@ func parseUnion(check *Checker, tlist []syntax.Expr) Type {
 		if len(tlist) == 1 && !tilde {
 			return typ // single type (optimization)
 		}
-		if len(terms) >= maxTermCount {
-			check.errorf(x, "cannot handle more than %d union terms (implementation limitation)", maxTermCount)
+		if max := check.maxUnionTerms(); len(terms) >= max {
+			check.errorf(x, _Todo, "constraint too complex: cannot handle more than %d union terms", max)
 			return Typ[Invalid]
 		}
 		terms = append(terms, NewTerm(tilde, typ))
@@ -67,33 +82,42 @@ func parseUnion(check *Checker, tlist []syntax.Expr) Type {
 	// Do this check later because it requires types to be set up.
 	// Note: This is a quadratic algorithm, but unions tend to be short.
 	check.later(func() {
+		var mterm *Term // first interface term with methods, if any
 		for i, t := range terms {
 			if t.typ == Typ[Invalid] {
 				continue
 			}
 
+			// A ~T term starts with the ~ operator, which is at the
+			// position of T if the term was introduced by the type
+			// checker for a type list entry T.
 			x := tlist[i]
-			pos := syntax.StartPos(x)
-			// We may not know the position of x if it was a typechecker-
-			// introduced ~T term for a type list entry T. Use the position
-			// of T instead.
-			// TODO(gri) remove this test once we don't support type lists anymore
-			if !pos.IsKnown() {
-				if op, _ := x.(*syntax.Operation); op != nil {
-					pos = syntax.StartPos(op.X)
-				}
-			}
+			at := spanOf(syntax.StartPos(x), x) // report errors for the entire term
 
 			u := under(t.typ)
 			f, _ := u.(*Interface)
 			if t.tilde {
+				// Report errors about the use of ~ at the operator.
 				if f != nil {
-					check.errorf(x, "invalid use of ~ (%s is an interface)", t.typ)
+					check.errorf(at, _Todo, "invalid use of ~ (%s is an interface)", t.typ)
 					continue // don't report another error for t
 				}
 
 				if !Identical(u, t.typ) {
-					check.errorf(x, "invalid use of ~ (underlying type of %s is %s)", t.typ, u)
+					var err error_
+					err.code = _Todo
+					err.errorf(at, "invalid use of ~ (underlying type of %s is %s)", t.typ, u)
+					// Suggest to replace T with its underlying type, unless x was
+					// introduced for a type list entry (there is no ~ before T).
+					// The end position of T is only known exactly if T is a
+					// (qualified) identifier.
+					if op, _ := x.(*syntax.Operation); op != nil && op.OpPos != syntax.StartPos(op.X) {
+						switch tx := unparen(op.X).(type) {
+						case *syntax.Name, *syntax.SelectorExpr:
+							err.fix = &SuggestedFix{syntax.StartPos(tx), syntax.EndPos(tx), check.sprintf("%s", u)}
+						}
+					}
+					check.report(&err)
 					continue // don't report another error for t
 				}
 			}
@@ -102,21 +126,75 @@ func parseUnion(check *Checker, tlist []syntax.Expr) Type {
 			// in the beginning. Embedded interfaces with tilde are excluded above. If we reach
 			// here, we must have at least two terms in the union.
 			if f != nil && !f.typeSet().IsTypeSet() {
-				check.errorf(pos, "cannot use %s in union (interface contains methods)", t)
-				continue // don't report another error for t
+				tset := f.typeSet()
+				switch {
+				case !check.conf.UnionMethods:
+					check.errorf(at, _Todo, "cannot use %s in union (interface contains methods)", t)
+					continue // don't report another error for t
+				case tset.comparable:
+					check.errorf(at, _Todo, "cannot use %s in union (interface is or embeds comparable)", t)
+					continue
+				case tset.ordered:
+					check.errorf(at, _Todo, "cannot use %s in union (interface is or embeds ordered)", t)
+					continue
+				}
+				if mterm == nil {
+					mterm = t
+				}
 			}
 
 			// Report overlapping (non-disjoint) terms such as
 			// a|a, a|~a, ~a|~a, and ~a|A (where under(A) == a).
 			if j := overlappingTerm(terms[:i], t); j >= 0 {
-				check.softErrorf(pos, "overlapping terms %s and %s", t, terms[j])
+				check.softErrorf(at, _Todo, "overlapping terms %s and %s", t, terms[j])
 			}
 		}
+		if mterm != nil {
+			check.checkUnionMethods(tlist, terms, mterm)
+		}
 	})
 
 	return &Union{terms, nil}
 }
 
+// checkUnionMethods reports an error for each term of the union that
+// prevents the methods of the interface term mterm (see Config.UnionMethods)
+// from being methods of the union's type set: interface terms with different
+// methods, and other terms which are not specific types with all methods.
+func (check *Checker) checkUnionMethods(tlist []syntax.Expr, terms []*Term, mterm *Term) {
+	methods := under(mterm.typ).(*Interface).typeSet().methods
+	mset := &Interface{complete: true, tset: &TypeSet{methods: methods, terms: allTermlist}}
+	for i, t := range terms {
+		if t == mterm || t.typ == Typ[Invalid] {
+			continue
+		}
+		at := tlist[i]
+		var tl termlist
+		if f, _ := under(t.typ).(*Interface); f != nil {
+			tset := f.typeSet()
+			if len(tset.methods) > 0 {
+				if !sameMethods(tset.methods, methods) {
+					check.errorf(at, _Todo, "cannot use %s in union with %s (different methods)", t, mterm)
+				}
+				continue
+			}
+			tl = tset.terms
+		} else {
+			tl = termlist{(*term)(t)}
+		}
+		for _, x := range tl {
+			if x.typ == nil || x.tilde {
+				check.errorf(at, _Todo, "cannot use %s in union with %s (not all types in %s have the methods of %s)", t, mterm, x, mterm)
+				break
+			}
+			if m, _ := check.missingMethod(x.typ, mset, true); m != nil {
+				check.errorf(at, _Todo, "cannot use %s in union with %s (%s missing method %s)", t, mterm, x.typ, m.name)
+				break
+			}
+		}
+	}
+}
+
 func parseTilde(check *Checker, x syntax.Expr) (tilde bool, typ Type) {
 	if op, _ := x.(*syntax.Operation); op != nil && op.Op == syntax.Tilde {
 		x = op.X
@@ -125,7 +203,7 @@ func parseTilde(check *Checker, x syntax.Expr) (tilde bool, typ Type) {
 	typ = check.typ(x)
 	// embedding stand-alone type parameters is not permitted (issue #47127).
 	if _, ok := under(typ).(*TypeParam); ok {
-		check.error(x, "cannot embed a type parameter")
+		check.error(x, _Todo, "cannot embed a type parameter")
 		typ = Typ[Invalid]
 	}
 	return
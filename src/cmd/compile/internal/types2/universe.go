@@ -26,6 +26,7 @@ var (
 	universeAny        Object
 	universeError      Type
 	universeComparable Object
+	universeOrdered    Object
 )
 
 // Typ contains the predeclared *Basic types indexed by their
@@ -88,7 +89,7 @@ func defPredeclaredTypes() {
 		res := NewVar(nopos, nil, "", Typ[String])
 		sig := NewSignature(nil, nil, NewTuple(res), false)
 		err := NewFunc(nopos, nil, "Error", sig)
-		ityp := &Interface{obj, []*Func{err}, nil, nil, true, nil}
+		ityp := &Interface{nil, obj, []*Func{err}, nil, nil, true, false, nil}
 		computeInterfaceTypeSet(nil, nopos, ityp) // prevent races due to lazy computation of tset
 		typ := NewNamed(obj, ityp, nil)
 		sig.recv = NewVar(nopos, nil, "", typ)
@@ -99,7 +100,17 @@ func defPredeclaredTypes() {
 	{
 		obj := NewTypeName(nopos, nil, "comparable", nil)
 		obj.setColor(black)
-		ityp := &Interface{obj, nil, nil, nil, true, &_TypeSet{true, nil, allTermlist}}
+		ityp := &Interface{nil, obj, nil, nil, nil, true, false, &TypeSet{true, false, false, 0, nil, allTermlist}}
+		NewNamed(obj, ityp, nil)
+		def(obj)
+	}
+
+	// type ordered interface{ /* type set marked ordered */ }
+	// (only accessible if Config.OperatorConstraints is set)
+	{
+		obj := NewTypeName(nopos, nil, "ordered", nil)
+		obj.setColor(black)
+		ityp := &Interface{nil, obj, nil, nil, nil, true, false, &TypeSet{false, true, false, 0, nil, allTermlist}}
 		NewNamed(obj, ityp, nil)
 		def(obj)
 	}
@@ -227,6 +238,7 @@ func init() {
 	universeAny = Universe.Lookup("any")
 	universeError = Universe.Lookup("error").Type()
 	universeComparable = Universe.Lookup("comparable")
+	universeOrdered = Universe.Lookup("ordered")
 }
 
 // Objects with names containing blanks are internal and not entered into
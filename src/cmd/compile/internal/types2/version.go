@@ -7,6 +7,7 @@ package types2
 import (
 	"cmd/compile/internal/syntax"
 	"fmt"
+	"go/build/constraint"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,12 +17,12 @@ import (
 // literal is not compatible with the current language version.
 func (check *Checker) langCompat(lit *syntax.BasicLit) {
 	s := lit.Value
-	if len(s) <= 2 || check.allowVersion(check.pkg, 1, 13) {
+	if len(s) <= 2 || check.allowVersion(check.pkg, lit, 1, 13) {
 		return
 	}
 	// len(s) > 2
 	if strings.Contains(s, "_") {
-		check.error(lit, "underscores in numeric literals requires go1.13 or later")
+		check.error(lit, _InvalidLit, "underscores in numeric literals requires go1.13 or later")
 		return
 	}
 	if s[0] != '0' {
@@ -29,30 +30,116 @@ func (check *Checker) langCompat(lit *syntax.BasicLit) {
 	}
 	radix := s[1]
 	if radix == 'b' || radix == 'B' {
-		check.error(lit, "binary literals requires go1.13 or later")
+		check.error(lit, _InvalidLit, "binary literals requires go1.13 or later")
 		return
 	}
 	if radix == 'o' || radix == 'O' {
-		check.error(lit, "0o/0O-style octal literals requires go1.13 or later")
+		check.error(lit, _InvalidLit, "0o/0O-style octal literals requires go1.13 or later")
 		return
 	}
 	if lit.Kind != syntax.IntLit && (radix == 'x' || radix == 'X') {
-		check.error(lit, "hexadecimal floating-point literals requires go1.13 or later")
+		check.error(lit, _InvalidLit, "hexadecimal floating-point literals requires go1.13 or later")
 	}
 }
 
-// allowVersion reports whether the given package
-// is allowed to use version major.minor.
-func (check *Checker) allowVersion(pkg *Package, major, minor int) bool {
+// allowVersion reports whether the given package is allowed to use
+// version major.minor at the position of at. For the package being
+// checked, the version of the file containing that position applies
+// (see Checker.fileVersions).
+func (check *Checker) allowVersion(pkg *Package, at poser, major, minor int) bool {
 	// We assume that imported packages have all been checked,
 	// so we only have to check for the local package.
 	if pkg != check.pkg {
 		return true
 	}
-	ma, mi := check.version.major, check.version.minor
+	v := check.version
+	if fv, found := check.fileVersions[fileBase(at.Pos())]; found {
+		v = fv
+	}
+	ma, mi := v.major, v.minor
 	return ma == 0 && mi == 0 || ma > major || ma == major && mi >= minor
 }
 
+// before reports whether version v is before version u.
+// The version 0.0 (the latest version) is not before any version.
+func (v version) before(u version) bool {
+	if v.major == 0 && v.minor == 0 {
+		return false
+	}
+	if u.major == 0 && u.minor == 0 {
+		return true
+	}
+	return v.major < u.major || v.major == u.major && v.minor < u.minor
+}
+
+// recordFileVersion records the version of file f in check.fileVersions
+// if its //go:build constraint requires a version later than the version
+// of the package (see fileVersion).
+func (check *Checker) recordFileVersion(f *syntax.File) {
+	if v, ok := fileVersion(f); ok && check.version.before(v) {
+		if check.fileVersions == nil {
+			check.fileVersions = make(map[*syntax.PosBase]version)
+		}
+		check.fileVersions[fileBase(f.Pos())] = v
+	}
+}
+
+// fileVersion returns the Go version required by the //go:build constraint
+// of file f, if any: the constraint is satisfied only if the release tag
+// of that version is. If the version is later than the version of the
+// package, the file may use the features of that version; this permits
+// migrating a package to a newer version file by file.
+func fileVersion(f *syntax.File) (v version, ok bool) {
+	if f.GoBuild == "" {
+		return
+	}
+	x, err := constraint.Parse("//go:build " + f.GoBuild)
+	if err != nil {
+		return // reported by MatchFiles, if the driver uses it
+	}
+	return minGoVersion(x)
+}
+
+// minGoVersion returns the minimum Go version for which the build
+// constraint x can be satisfied, if x requires a Go version.
+func minGoVersion(x constraint.Expr) (v version, ok bool) {
+	switch x := x.(type) {
+	case *constraint.TagExpr:
+		if v, err := parseGoVersion(x.Tag); err == nil {
+			return v, true
+		}
+	case *constraint.AndExpr:
+		v, ok := minGoVersion(x.X)
+		u, uok := minGoVersion(x.Y)
+		if !ok || uok && v.before(u) {
+			return u, uok
+		}
+		return v, ok
+	case *constraint.OrExpr:
+		v, ok := minGoVersion(x.X)
+		u, uok := minGoVersion(x.Y)
+		if !ok || !uok {
+			return version{}, false
+		}
+		if u.before(v) {
+			return u, true
+		}
+		return v, true
+	}
+	// *constraint.NotExpr
+	return
+}
+
+// fileBase returns the position base of the file containing pos,
+// ignoring line directives, or nil.
+func fileBase(pos syntax.Pos) *syntax.PosBase {
+	b := pos.Base()
+	for b != nil && !b.IsFileBase() {
+		b = b.Pos().Base()
+	}
+	return b
+}
+
 type version struct {
 	major, minor int
 }
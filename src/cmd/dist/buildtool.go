@@ -58,11 +58,13 @@ var bootstrapDirs = []string{
 	"debug/elf",
 	"debug/macho",
 	"debug/pe",
+	"go/build/constraint",
 	"go/constant",
 	"internal/buildcfg",
 	"internal/goexperiment",
 	"internal/goversion",
 	"internal/race",
+	"internal/types/errors",
 	"internal/unsafeheader",
 	"internal/xcoff",
 	"math/big",
@@ -125,6 +125,9 @@ var tests = []test{
 			`func MultiLineFunc\(x interface{ ... }\) \(r struct{ ... }\)`, // Multi line function.
 			`var LongLine = newLongLine\(("someArgument[1-4]", ){4}...\)`,  // Long list of arguments.
 			`type T1 = T2`,                                                 // Type alias
+			`func GenericMap\[T, U any\]\(s \[\]T, f func\(T\) U\) \[\]U`,  // Generic function.
+			`type GenericList\[T any\] struct{ ... }`,                      // Generic type.
+			`func NewGenericList\[T any\]\(\) \*GenericList\[T\]`,          // Generic constructor.
 		},
 		[]string{
 			`const internalConstant = 2`,       // No internal constants.
@@ -666,6 +669,16 @@ var tests = []test{
 		},
 		nil,
 	},
+	// Method of generic type.
+	{
+		"generic method",
+		[]string{p, `GenericList.Push`},
+		[]string{
+			`func \(l \*GenericList\[T\]\) Push\(v T\)`,
+			`Push adds v to the front of the list.`,
+		},
+		nil,
+	},
 
 	// Field.
 	{
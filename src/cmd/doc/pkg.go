@@ -325,9 +325,10 @@ func (pkg *Package) oneLineNodeDepth(node ast.Node, depth int) string {
 		if n.Assign.IsValid() {
 			sep = " = "
 		}
-		return fmt.Sprintf("type %s%s%s", n.Name.Name, sep, pkg.oneLineNodeDepth(n.Type, depth))
+		return fmt.Sprintf("type %s%s%s%s", n.Name.Name, pkg.oneLineTParams(n.TParams, depth), sep, pkg.oneLineNodeDepth(n.Type, depth))
 
 	case *ast.FuncType:
+		tparam := pkg.oneLineTParams(n.TParams, depth)
 		var params []string
 		if n.Params != nil {
 			for _, field := range n.Params.List {
@@ -346,13 +347,13 @@ func (pkg *Package) oneLineNodeDepth(node ast.Node, depth int) string {
 
 		param := joinStrings(params)
 		if len(results) == 0 {
-			return fmt.Sprintf("func(%s)", param)
+			return fmt.Sprintf("func%s(%s)", tparam, param)
 		}
 		result := joinStrings(results)
 		if !needParens {
-			return fmt.Sprintf("func(%s) %s", param, result)
+			return fmt.Sprintf("func%s(%s) %s", tparam, param, result)
 		}
-		return fmt.Sprintf("func(%s) (%s)", param, result)
+		return fmt.Sprintf("func%s(%s) (%s)", tparam, param, result)
 
 	case *ast.StructType:
 		if n.Fields == nil || len(n.Fields.List) == 0 {
@@ -433,6 +434,19 @@ func (pkg *Package) oneLineField(field *ast.Field, depth int) string {
 	return joinStrings(names) + " " + pkg.oneLineNodeDepth(field.Type, depth)
 }
 
+// oneLineTParams returns a one-line summary of the type parameter list,
+// including the enclosing brackets, or the empty string if there is none.
+func (pkg *Package) oneLineTParams(tparams *ast.FieldList, depth int) string {
+	if tparams == nil || len(tparams.List) == 0 {
+		return ""
+	}
+	var list []string
+	for _, field := range tparams.List {
+		list = append(list, pkg.oneLineField(field, depth))
+	}
+	return "[" + joinStrings(list) + "]"
+}
+
 // joinStrings formats the input as a comma-separated list,
 // but truncates the list at some reasonable length if necessary.
 func joinStrings(ss []string) string {
@@ -238,3 +238,22 @@ type ExportedFormattedType struct {
 	// Text after pre-formatted block.
 	ExportedField int
 }
+
+// GenericList is a generic linked list.
+type GenericList[T any] struct {
+	head *T
+}
+
+// NewGenericList returns an empty list.
+func NewGenericList[T any]() *GenericList[T] {
+	return nil
+}
+
+// Push adds v to the front of the list.
+func (l *GenericList[T]) Push(v T) {
+}
+
+// GenericMap returns the result of applying f to each element of s.
+func GenericMap[T, U any](s []T, f func(T) U) []U {
+	return nil
+}
@@ -26,6 +26,10 @@ named list.  By default fix considers all known rewrites.  Fix's
 rewrites are idempotent, so that it is safe to apply fix to updated
 or partially updated code even without using the -r flag.
 
+Some rewrites, such as the optional rewrite of interface{} type
+parameter constraints to any, are disabled by default. The -force
+flag names rewrites to apply even if they are disabled.
+
 Fix prints the full list of fixes it can apply in its help output;
 to see them, run go tool fix -help.
 
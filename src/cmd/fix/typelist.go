@@ -0,0 +1,176 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+)
+
+func init() {
+	register(typelistFix)
+	register(anyFix)
+}
+
+var typelistFix = fix{
+	name: "typelist",
+	date: "2021-10-14",
+	f:    typelist,
+	desc: `Rewrite interface type lists to unions of ~T terms.
+
+A type list "type T1, T2" is rewritten to the union "~T1 | ~T2".
+Interfaces listing a type whose underlying type cannot be determined
+without type-checking (such as a defined type) are left unchanged.
+`,
+}
+
+var anyFix = fix{
+	name: "any",
+	date: "2021-10-14",
+	f:    anyConstraint,
+	desc: `Rewrite interface{} type parameter constraints to any.`,
+	// Not all code wants this; only run if explicitly requested.
+	disabled: true,
+}
+
+// Old state:
+//   type Number interface {
+//           type int, float64
+//   }
+// New state:
+//   type Number interface {
+//           ~int | ~float64
+//   }
+// Type lists matched all types with the same underlying type as one
+// of the listed types; this is what the ~T terms of a union do, as
+// long as T is its own underlying type.
+func typelist(f *ast.File) bool {
+	fixed := false
+	walk(f, func(n interface{}) {
+		ityp, ok := n.(*ast.InterfaceType)
+		if !ok || ityp.Methods == nil {
+			return
+		}
+
+		var list []*ast.Field
+		var union *ast.Field // embedded union replacing the type list
+		for _, field := range ityp.Methods.List {
+			if len(field.Names) != 1 || field.Names[0].Name != "type" {
+				list = append(list, field)
+				continue
+			}
+			if !isUnderlying(field.Type) {
+				fmt.Fprintf(os.Stderr, "go fix: warning: %s: cannot rewrite type list with %s: underlying type unknown\n",
+					fset.Position(field.Type.Pos()), gofmt(field.Type))
+				return
+			}
+			term := &ast.UnaryExpr{OpPos: field.Type.Pos(), Op: token.TILDE, X: field.Type}
+			if union == nil {
+				union = &ast.Field{Doc: field.Doc, Type: term, Comment: field.Comment}
+				list = append(list, union)
+				continue
+			}
+			union.Type = &ast.BinaryExpr{X: union.Type, OpPos: field.Type.Pos(), Op: token.OR, Y: term}
+			if field.Comment != nil {
+				union.Comment = field.Comment
+			}
+		}
+		if union != nil {
+			ityp.Methods.List = list
+			fixed = true
+		}
+	})
+	return fixed
+}
+
+// predeclaredTypes are the predeclared non-interface types.
+var predeclaredTypes = map[string]bool{
+	"bool":       true,
+	"byte":       true,
+	"complex64":  true,
+	"complex128": true,
+	"float32":    true,
+	"float64":    true,
+	"int":        true,
+	"int8":       true,
+	"int16":      true,
+	"int32":      true,
+	"int64":      true,
+	"rune":       true,
+	"string":     true,
+	"uint":       true,
+	"uint8":      true,
+	"uint16":     true,
+	"uint32":     true,
+	"uint64":     true,
+	"uintptr":    true,
+}
+
+// isUnderlying reports whether the type expression x is known to denote
+// its own underlying type, so that it may be used in a ~x term.
+func isUnderlying(x ast.Expr) bool {
+	switch x := x.(type) {
+	case *ast.Ident:
+		// x.Obj is nil for predeclared names not redeclared in the file
+		return x.Obj == nil && predeclaredTypes[x.Name]
+	case *ast.ParenExpr:
+		return isUnderlying(x.X)
+	case *ast.ArrayType, *ast.StructType, *ast.StarExpr, *ast.FuncType, *ast.MapType, *ast.ChanType:
+		return true
+	}
+	return false
+}
+
+// Old state:
+//   func f[T interface{}](x T)
+// New state:
+//   func f[T any](x T)
+// Only type parameter constraints are rewritten, since any is not
+// available elsewhere.
+func anyConstraint(f *ast.File) bool {
+	if f.Scope != nil && f.Scope.Lookup("any") != nil {
+		return false // any is redeclared
+	}
+
+	fixed := false
+	walk(f, func(n interface{}) {
+		var tparams *ast.FieldList
+		switch n := n.(type) {
+		case *ast.FuncType:
+			tparams = n.TParams
+		case *ast.TypeSpec:
+			tparams = n.TParams
+		}
+		if tparams == nil {
+			return
+		}
+		for _, field := range tparams.List {
+			for _, name := range field.Names {
+				if name.Name == "any" {
+					return // any is redeclared
+				}
+			}
+		}
+		for _, field := range tparams.List {
+			if ityp, ok := field.Type.(*ast.InterfaceType); ok && len(ityp.Methods.List) == 0 && !hasComments(f, ityp) {
+				field.Type = &ast.Ident{NamePos: ityp.Pos(), Name: "any"}
+				fixed = true
+			}
+		}
+	})
+	return fixed
+}
+
+// hasComments reports whether there are comments inside the node n of f.
+func hasComments(f *ast.File, n ast.Node) bool {
+	for _, g := range f.Comments {
+		if n.Pos() <= g.Pos() && g.End() <= n.End() {
+			return true
+		}
+	}
+	return false
+}
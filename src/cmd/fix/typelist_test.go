@@ -0,0 +1,185 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+func init() {
+	addTestCases(typelistTests, typelist)
+	addTestCases(anyTests, anyConstraint)
+}
+
+var typelistTests = []testCase{
+	{
+		Name: "typelist.0",
+		In: `package main
+
+type Number interface {
+	type int, float64
+}
+
+type Stringer interface {
+	String() string
+	type string, []byte // strings
+}
+
+func f[T interface {
+	type *int, map[string]int
+}](x T) {
+	_ = x
+}
+`,
+		Out: `package main
+
+type Number interface {
+	~int | ~float64
+}
+
+type Stringer interface {
+	String() string
+	~string | ~[]byte // strings
+}
+
+func f[T interface {
+	~*int | ~map[string]int
+}](x T) {
+	_ = x
+}
+`,
+	},
+	// Types with unknown underlying types are not rewritten.
+	{
+		Name: "typelist.1",
+		In: `package main
+
+type myInt int
+
+type I interface {
+	type int, myInt
+}
+
+type J interface {
+	type error
+}
+`,
+		Out: `package main
+
+type myInt int
+
+type I interface {
+	type int, myInt
+}
+
+type J interface {
+	type error
+}
+`,
+	},
+	// Redeclared predeclared types are not rewritten.
+	{
+		Name: "typelist.2",
+		In: `package main
+
+type int string
+
+type I interface {
+	type int
+}
+`,
+		Out: `package main
+
+type int string
+
+type I interface {
+	type int
+}
+`,
+	},
+	// Unions are left alone.
+	{
+		Name: "typelist.3",
+		In: `package main
+
+type I interface {
+	~int | string
+}
+`,
+		Out: `package main
+
+type I interface {
+	~int | string
+}
+`,
+	},
+}
+
+var anyTests = []testCase{
+	{
+		Name: "any.0",
+		In: `package main
+
+type List[T interface{}] []T
+
+func Map[T, U interface{}](l List[T], f func(T) U) List[U] { return nil }
+
+func g(x interface{}) interface{} { return x }
+
+var _ = Map[int, interface{}]
+`,
+		Out: `package main
+
+type List[T any] []T
+
+func Map[T, U any](l List[T], f func(T) U) List[U] { return nil }
+
+func g(x interface{}) interface{} { return x }
+
+var _ = Map[int, interface{}]
+`,
+	},
+	// Non-empty interfaces and interfaces with comments are not rewritten.
+	{
+		Name: "any.1",
+		In: `package main
+
+func f[A interface{ m() }, B interface {
+	// anything
+}]() {
+}
+`,
+		Out: `package main
+
+func f[A interface{ m() }, B interface {
+	// anything
+}]() {
+}
+`,
+	},
+	// any is redeclared.
+	{
+		Name: "any.2",
+		In: `package main
+
+type any int
+
+func f[T interface{}]() {}
+`,
+		Out: `package main
+
+type any int
+
+func f[T interface{}]() {}
+`,
+	},
+	{
+		Name: "any.3",
+		In: `package main
+
+func f[any interface{}, T interface{}]() {}
+`,
+		Out: `package main
+
+func f[any interface{}, T interface{}]() {}
+`,
+	},
+}
@@ -19,7 +19,8 @@ import (
 )
 
 // parse parses src, which was read from the named file,
-// as a Go source file, declaration, or statement list.
+// as a Go source file, declaration, statement, or interface
+// element list.
 func parse(fset *token.FileSet, filename string, src []byte, fragmentOk bool) (
 	file *ast.File,
 	sourceAdj func(src []byte, indent int) []byte,
@@ -82,6 +83,35 @@ func parse(fset *token.FileSet, filename string, src []byte, fragmentOk bool) (
 		// Gofmt has also indented the function body one level.
 		// Adjust that with indentAdj.
 		indentAdj = -1
+		return
+	}
+
+	// If this is a list of interface elements, such as the union
+	// ~int | ~string of a type constraint, make it a source file
+	// by inserting a package clause and turning the list into the
+	// body of an interface type. If that fails too, report the
+	// error for the statement list.
+	// Insert and wrap as above so that the line numbers in isrc
+	// match the ones in src.
+	isrc := append(append([]byte("package p; type _ interface {"), src...), '\n', '\n', '}')
+	if ifile, ierr := parser.ParseFile(fset, filename, isrc, parserMode); ierr == nil {
+		file, err = ifile, nil
+		sourceAdj = func(src []byte, indent int) []byte {
+			// Cap adjusted indent to zero.
+			if indent < 0 {
+				indent = 0
+			}
+			// Remove the wrapping.
+			// Gofmt has turned the "; " into a "\n\n".
+			// There will be two non-blank lines with indent, hence 2*indent.
+			src = src[2*indent+len("package p\n\ntype _ interface {"):]
+			// Remove only the "}\n" suffix: remaining whitespaces will be trimmed anyway
+			src = src[:len(src)-len("}\n")]
+			return bytes.TrimSpace(src)
+		}
+		// Gofmt has also indented the interface elements one level.
+		// Adjust that with indentAdj.
+		indentAdj = -1
 	}
 
 	// Succeeded, or out of options.
@@ -64,6 +64,17 @@ type Var struct {
 	IsInAbstract    bool  // variable exists in abstract function
 }
 
+// A TypeParam represents a type parameter of a generic function
+// instantiation. Instantiations are shared between type arguments
+// with the same shape, so Type is the shape type; the concrete type
+// argument is found at run time in entry DictIndex of the dictionary
+// passed to the function.
+type TypeParam struct {
+	Name      string
+	Type      Sym
+	DictIndex int
+}
+
 // A Scope represents a lexical scope. All variables declared within a
 // scope will only be visible to instructions covered by the scope.
 // Lexical scopes are contiguous in source files but can end up being
@@ -96,6 +107,7 @@ type FnState struct {
 	External      bool
 	Scopes        []Scope
 	InlCalls      InlCalls
+	TypeParams    []TypeParam
 	UseBASEntries bool
 }
 
@@ -315,6 +327,10 @@ const (
 	DW_AT_go_runtime_type   = 0x2904
 
 	DW_AT_go_package_name = 0x2905 // Attribute for DW_TAG_compile_unit
+	// Attribute for DW_TAG_template_type_parameter of a function
+	// instantiated with shape types. Its value is the index of the
+	// dictionary entry holding the concrete type argument.
+	DW_AT_go_dict_index = 0x2906
 
 	DW_AT_internal_location = 253 // params and locals; not emitted
 )
@@ -345,6 +361,7 @@ const (
 	DW_ABRV_LEXICAL_BLOCK_SIMPLE
 	DW_ABRV_STRUCTFIELD
 	DW_ABRV_FUNCTYPEPARAM
+	DW_ABRV_TEMPLATE_TYPEPARAM
 	DW_ABRV_DOTDOTDOT
 	DW_ABRV_ARRAYRANGE
 	DW_ABRV_NULLTYPE
@@ -680,6 +697,17 @@ var abbrevs = [DW_NABRV]dwAbbrev{
 		},
 	},
 
+	/* TEMPLATE_TYPEPARAM */
+	{
+		DW_TAG_template_type_parameter,
+		DW_CHILDREN_no,
+		[]dwAttrForm{
+			{DW_AT_name, DW_FORM_string},
+			{DW_AT_type, DW_FORM_ref_addr},
+			{DW_AT_go_dict_index, DW_FORM_udata},
+		},
+	},
+
 	/* DOTDOTDOT */
 	{
 		DW_TAG_unspecified_parameters,
@@ -1257,6 +1285,8 @@ func PutAbstractFunc(ctxt Context, s *FnState) error {
 	}
 	ctxt.RecordChildDieOffsets(s.Absfn, flattened, offsets)
 
+	putTypeParams(ctxt, s.Absfn, s.TypeParams)
+
 	Uleb128put(ctxt, s.Absfn, 0)
 	return nil
 }
@@ -1393,6 +1423,9 @@ func PutDefaultFunc(ctxt Context, s *FnState) error {
 	}
 	putattr(ctxt, s.Info, abbrev, DW_FORM_flag, DW_CLS_FLAG, ev, 0)
 
+	// Type parameters of an instantiation.
+	putTypeParams(ctxt, s.Info, s.TypeParams)
+
 	// Scopes
 	if err := putPrunedScopes(ctxt, s, abbrev); err != nil {
 		return err
@@ -1410,6 +1443,18 @@ func PutDefaultFunc(ctxt Context, s *FnState) error {
 	return nil
 }
 
+// putTypeParams emits a DW_TAG_template_type_parameter DIE for each of
+// the type parameters of an instantiated function.
+func putTypeParams(ctxt Context, info Sym, tparams []TypeParam) {
+	for _, tp := range tparams {
+		abbrev := DW_ABRV_TEMPLATE_TYPEPARAM
+		Uleb128put(ctxt, info, int64(abbrev))
+		putattr(ctxt, info, abbrev, DW_FORM_string, DW_CLS_STRING, int64(len(tp.Name)), tp.Name)
+		putattr(ctxt, info, abbrev, DW_FORM_ref_addr, DW_CLS_REFERENCE, 0, tp.Type)
+		putattr(ctxt, info, abbrev, DW_FORM_udata, DW_CLS_CONSTANT, int64(tp.DictIndex), nil)
+	}
+}
+
 func putscope(ctxt Context, s *FnState, scopes []Scope, curscope int32, fnabbrev int, encbuf []byte) int32 {
 
 	if logDwarf {
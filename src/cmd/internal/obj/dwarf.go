@@ -352,8 +352,9 @@ func (ctxt *Link) populateDWARF(curfn interface{}, s *LSym, myimportpath string)
 	}
 	var scopes []dwarf.Scope
 	var inlcalls dwarf.InlCalls
+	var tparams []dwarf.TypeParam
 	if ctxt.DebugInfo != nil {
-		scopes, inlcalls = ctxt.DebugInfo(s, info, curfn)
+		scopes, inlcalls, tparams = ctxt.DebugInfo(s, info, curfn)
 	}
 	var err error
 	dwctxt := dwCtxt{ctxt}
@@ -371,6 +372,7 @@ func (ctxt *Link) populateDWARF(curfn interface{}, s *LSym, myimportpath string)
 		External:      !s.Static(),
 		Scopes:        scopes,
 		InlCalls:      inlcalls,
+		TypeParams:    tparams,
 		UseBASEntries: ctxt.UseBASEntries,
 	}
 	if absfunc != nil {
@@ -435,7 +437,7 @@ func (ctxt *Link) DwarfAbstractFunc(curfn interface{}, s *LSym, myimportpath str
 	if s.Func() == nil {
 		s.NewFuncInfo()
 	}
-	scopes, _ := ctxt.DebugInfo(s, absfn, curfn)
+	scopes, _, tparams := ctxt.DebugInfo(s, absfn, curfn)
 	dwctxt := dwCtxt{ctxt}
 	filesym := ctxt.fileSymbol(s)
 	fnstate := dwarf.FnState{
@@ -446,6 +448,7 @@ func (ctxt *Link) DwarfAbstractFunc(curfn interface{}, s *LSym, myimportpath str
 		Absfn:         absfn,
 		External:      !s.Static(),
 		Scopes:        scopes,
+		TypeParams:    tparams,
 		UseBASEntries: ctxt.UseBASEntries,
 	}
 	if err := dwarf.PutAbstractFunc(dwctxt, &fnstate); err != nil {
@@ -1758,3 +1758,76 @@ func main() {
 			expected, found)
 	}
 }
+
+func TestTypeParamAttrs(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	if runtime.GOOS == "plan9" {
+		t.Skip("skipping on plan9; no DWARF symbol table in executables")
+	}
+	t.Parallel()
+
+	// This test verifies that instantiations of generic functions
+	// carry a DW_TAG_template_type_parameter child for each type
+	// parameter, giving its name, shape type and dictionary index.
+
+	const prog = `
+package main
+
+//go:noinline
+func F[T any, U comparable](x T, u U) bool {
+	var y T = x
+	return u == u && &y != nil
+}
+
+func main() {
+	println(F(1, "a"))
+}
+`
+	dir := t.TempDir()
+	f := gobuild(t, dir, prog, NoOpt)
+	defer f.Close()
+
+	d, err := f.DWARF()
+	if err != nil {
+		t.Fatalf("error reading DWARF: %v", err)
+	}
+
+	rdr := d.Reader()
+	ex := examiner{}
+	if err := ex.populate(rdr); err != nil {
+		t.Fatalf("error reading DWARF: %v", err)
+	}
+
+	// Locate the DIE for the instantiation of main.F.
+	var fdie *dwarf.Entry
+	for _, die := range ex.dies {
+		if name, ok := die.Val(dwarf.AttrName).(string); ok && die.Tag == dwarf.TagSubprogram && strings.HasPrefix(name, "main.F[") {
+			fdie = die
+			break
+		}
+	}
+	if fdie == nil {
+		t.Fatalf("unable to locate DIE for instantiation of main.F")
+	}
+
+	var found []string
+	for _, child := range ex.Children(ex.idxFromOffset(fdie.Offset)) {
+		if child.Tag != dwarf.TagTemplateTypeParameter {
+			continue
+		}
+		name, _ := child.Val(dwarf.AttrName).(string)
+		typ := "?"
+		if off, ok := child.Val(dwarf.AttrType).(dwarf.Offset); ok {
+			if tdie := ex.entryFromOffset(off); tdie != nil {
+				typ, _ = tdie.Val(dwarf.AttrName).(string)
+			}
+		}
+		found = append(found, fmt.Sprintf("%s:%s:%v", name, typ, child.Val(dwarf.Attr(intdwarf.DW_AT_go_dict_index))))
+	}
+
+	expected := "[T:%2eshape.int:0 U:%2eshape.string:1]"
+	if fmt.Sprintf("%v", found) != expected {
+		t.Errorf("type param check failed, wanted %s got %s", expected, found)
+	}
+}
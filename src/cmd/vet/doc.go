@@ -45,6 +45,8 @@ To list the available checks, run "go tool vet help":
     stdmethods   check signature of methods of well-known interfaces
     structtag    check that struct field tags conform to reflect.StructTag.Get
     tests        check for common mistaken usages of tests and examples
+    tparamassert detect impossible type assertions on type parameter values
+    tparamswitch check type switches on type parameter values for missing cases (opt-in)
     unmarshal    report passing non-pointer or non-interface values to unmarshal
     unreachable  check for unreachable code
     unsafeptr    check for invalid conversions of uintptr to unsafe.Pointer
@@ -52,7 +54,8 @@ To list the available checks, run "go tool vet help":
 
 For details and flags of a particular check, such as printf, run "go tool vet help printf".
 
-By default, all checks are performed.
+By default, all checks except opt-in checks are performed; an opt-in
+check such as tparamswitch only runs if its flag is set to true.
 If any flags are explicitly set to true, only those tests are run.
 Conversely, if any flag is explicitly set to false, only those tests are disabled.
 Thus -printf=true runs the printf check,
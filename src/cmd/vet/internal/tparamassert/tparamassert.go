@@ -0,0 +1,125 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tparamassert defines an Analyzer that flags impossible
+// type assertions on values of type parameter type.
+package tparamassert
+
+import (
+	"go/ast"
+	"go/types"
+
+	"cmd/vet/internal/typesets"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const Doc = `detect impossible type assertions on type parameter values
+
+This checker flags type assertions I(x).(T) and corresponding type-switch
+cases in which x is of a type parameter type P, converted to an interface
+type I, and no type in the type set of P's constraint can be of type T.
+Example:
+
+	func f[P ~int | ~float64](x P) {
+		switch interface{}(x).(type) {
+		case string:
+			...
+		}
+	}
+
+The type set of P contains only types with underlying type int or
+float64, so the string case is never selected. If T is an interface,
+the assertion is flagged if the type set of P consists of specific
+types only, none of which implements T.
+`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "tparamassert",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// possible reports whether a value of a type in the type set of P may
+// have type t. It returns true if this cannot be determined.
+func possible(P *types.TypeParam, t types.Type) bool {
+	terms := typesets.Terms(P)
+	if terms == nil {
+		return true
+	}
+	if T, _ := t.Underlying().(*types.Interface); T != nil {
+		for _, term := range terms {
+			if typesets.MayImplement(term, T) {
+				return true
+			}
+		}
+		return false
+	}
+	if typesets.HasTypeParam(t) {
+		return true
+	}
+	for _, term := range terms {
+		if typesets.Includes(term, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.TypeAssertExpr)(nil),
+		(*ast.TypeSwitchStmt)(nil),
+	}
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		var (
+			assert  *ast.TypeAssertExpr // v.(T) expression
+			targets []ast.Expr          // types T in v.(T)
+		)
+		switch n := n.(type) {
+		case *ast.TypeAssertExpr:
+			// take care of v.(type) in *ast.TypeSwitchStmt
+			if n.Type == nil {
+				return
+			}
+			assert = n
+			targets = append(targets, n.Type)
+		case *ast.TypeSwitchStmt:
+			// retrieve type assertion from type switch's 'assign' field
+			switch t := n.Assign.(type) {
+			case *ast.ExprStmt:
+				assert = t.X.(*ast.TypeAssertExpr)
+			case *ast.AssignStmt:
+				assert = t.Rhs[0].(*ast.TypeAssertExpr)
+			}
+			// gather target types from case clauses
+			for _, c := range n.Body.List {
+				targets = append(targets, c.(*ast.CaseClause).List...)
+			}
+		}
+		P := typesets.Converted(pass.TypesInfo, assert.X)
+		if P == nil {
+			return
+		}
+		for _, target := range targets {
+			tv, ok := pass.TypesInfo.Types[target]
+			if !ok || !tv.IsType() {
+				continue // e.g., nil case
+			}
+			if !possible(P, tv.Type) {
+				T := types.TypeString(tv.Type, types.RelativeTo(pass.Pkg))
+				if types.IsInterface(tv.Type) {
+					pass.Reportf(target.Pos(), "impossible type assertion: no type in the type set of %s implements %s", P.Obj().Name(), T)
+				} else {
+					pass.Reportf(target.Pos(), "impossible type assertion: %s is not in the type set of %s", T, P.Obj().Name())
+				}
+			}
+		}
+	})
+	return nil, nil
+}
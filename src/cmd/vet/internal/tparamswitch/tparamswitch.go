@@ -0,0 +1,147 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tparamswitch defines an Analyzer that checks type switches
+// on type parameter values for missing and unreachable cases.
+package tparamswitch
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"cmd/vet/internal/typesets"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const Doc = `check type switches on type parameter values for missing cases
+
+This checker examines type switches on I(x), where x is of a type
+parameter type P whose constraint restricts its type set with a union,
+and I is an interface type. If the switch has no default case, the
+checker reports the terms of P's type set that no case handles. Example:
+
+	func f[P ~int | string](x P) {
+		switch interface{}(x).(type) {
+		case int:
+			...
+		case string:
+			...
+		}
+	}
+
+The term ~int includes all types with underlying type int, such as
+time.Duration, none of which is handled by the int case.
+
+The checker also reports cases that are unreachable because every
+value they match is matched by an earlier interface case. Cases for
+types outside the type set of P are reported by the tparamassert
+checker.
+`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "tparamswitch",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// handles reports whether a case for type t handles all values of the
+// types in the type set of term.
+func handles(t types.Type, term *types.Term) bool {
+	if T, _ := t.Underlying().(*types.Interface); T != nil {
+		return typesets.Implements(term, T)
+	}
+	return !term.Tilde() && types.Identical(t, term.Type())
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.TypeSwitchStmt)(nil),
+	}
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		sw := n.(*ast.TypeSwitchStmt)
+		// retrieve type assertion from type switch's 'assign' field
+		var assert *ast.TypeAssertExpr
+		switch t := sw.Assign.(type) {
+		case *ast.ExprStmt:
+			assert = t.X.(*ast.TypeAssertExpr)
+		case *ast.AssignStmt:
+			assert = t.Rhs[0].(*ast.TypeAssertExpr)
+		}
+		P := typesets.Converted(pass.TypesInfo, assert.X)
+		if P == nil {
+			return
+		}
+		terms := typesets.Terms(P)
+		if terms == nil {
+			return
+		}
+
+		// collect case types, reporting unreachable cases
+		hasDefault := false
+		type caseType struct {
+			expr ast.Expr
+			typ  types.Type
+		}
+		var cases []caseType
+		for _, c := range sw.Body.List {
+			clause := c.(*ast.CaseClause)
+			if clause.List == nil {
+				hasDefault = true
+			}
+			for _, e := range clause.List {
+				tv, ok := pass.TypesInfo.Types[e]
+				if !ok || !tv.IsType() || typesets.HasTypeParam(tv.Type) && !types.IsInterface(tv.Type) {
+					continue // e.g., nil case
+				}
+				if !types.IsInterface(tv.Type) {
+					for _, prev := range cases {
+						if T, _ := prev.typ.Underlying().(*types.Interface); T != nil && types.Implements(tv.Type, T) {
+							pass.Reportf(e.Pos(), "unreachable case %s: values of this type are handled by the earlier case %s",
+								typeString(pass, tv.Type), typeString(pass, prev.typ))
+							break
+						}
+					}
+				}
+				cases = append(cases, caseType{e, tv.Type})
+			}
+		}
+		if hasDefault {
+			return
+		}
+
+		// report terms not handled by any case
+		var missing []string
+		for _, term := range terms {
+			handled := false
+			for _, c := range cases {
+				if handles(c.typ, term) {
+					handled = true
+					break
+				}
+			}
+			if !handled {
+				s := typeString(pass, term.Type())
+				if term.Tilde() {
+					s = "~" + s
+				}
+				missing = append(missing, s)
+			}
+		}
+		if missing != nil {
+			pass.Reportf(sw.Pos(), "type switch on value of type %s has no default case and misses %s",
+				P.Obj().Name(), strings.Join(missing, ", "))
+		}
+	})
+	return nil, nil
+}
+
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
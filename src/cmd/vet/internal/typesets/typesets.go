@@ -0,0 +1,108 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package typesets provides support for analyzers that reason about
+// the type sets of type parameter constraints.
+package typesets
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// Converted returns the type parameter P if x is a conversion I(y) of a
+// value y of type P to an interface type I, and nil otherwise.
+func Converted(info *types.Info, x ast.Expr) *types.TypeParam {
+	for {
+		p, ok := x.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		x = p.X
+	}
+	call, _ := x.(*ast.CallExpr)
+	if call == nil || len(call.Args) != 1 {
+		return nil
+	}
+	if tv := info.Types[call.Fun]; !tv.IsType() || !types.IsInterface(tv.Type) {
+		return nil // not a conversion to an interface type
+	}
+	tpar, _ := info.TypeOf(call.Args[0]).(*types.TypeParam)
+	return tpar
+}
+
+// Terms returns the terms of the type set of P's constraint in normal
+// form. The result is nil if the type set is not restricted by terms,
+// if it is empty (and P cannot be instantiated), or if a term refers to
+// another type parameter (such as []Q), because nothing useful can be
+// said about such type sets.
+func Terms(P *types.TypeParam) []*types.Term {
+	iface, _ := P.Constraint().Underlying().(*types.Interface)
+	if iface == nil {
+		return nil
+	}
+	terms := iface.NormalTerms()
+	for _, term := range terms {
+		if HasTypeParam(term.Type()) {
+			return nil
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return terms
+}
+
+// Includes reports whether the type set of term includes the
+// non-interface type t.
+func Includes(term *types.Term, t types.Type) bool {
+	return types.Identical(t, term.Type()) || term.Tilde() && types.Identical(t.Underlying(), term.Type())
+}
+
+// MayImplement reports whether some type in the type set of term may
+// implement the interface T.
+func MayImplement(term *types.Term, T *types.Interface) bool {
+	// A ~U term includes defined types which may have any methods.
+	return term.Tilde() || types.Implements(term.Type(), T)
+}
+
+// Implements reports whether each type in the type set of term
+// implements the interface T.
+func Implements(term *types.Term, T *types.Interface) bool {
+	if _, ok := term.Type().(*types.Pointer); ok && term.Tilde() {
+		// Defined pointer types have no methods.
+		return T.NumMethods() == 0
+	}
+	// The types in ~U have (at least) the methods of U,
+	// which may be promoted from embedded fields.
+	return types.Implements(term.Type(), T)
+}
+
+// HasTypeParam reports whether t is or may contain a type parameter.
+func HasTypeParam(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Basic:
+		return false
+	case *types.Pointer:
+		return HasTypeParam(t.Elem())
+	case *types.Slice:
+		return HasTypeParam(t.Elem())
+	case *types.Array:
+		return HasTypeParam(t.Elem())
+	case *types.Chan:
+		return HasTypeParam(t.Elem())
+	case *types.Map:
+		return HasTypeParam(t.Key()) || HasTypeParam(t.Elem())
+	case *types.Named:
+		targs := t.TArgs()
+		for i := 0; i < targs.Len(); i++ {
+			if HasTypeParam(targs.At(i)) {
+				return true
+			}
+		}
+		return false
+	}
+	// be conservative for structs, signatures, interfaces, and type parameters
+	return true
+}
@@ -5,8 +5,15 @@
 package main
 
 import (
+	"os"
+	"strings"
+
 	"cmd/internal/objabi"
 
+	"cmd/vet/internal/tparamassert"
+	"cmd/vet/internal/tparamswitch"
+
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/unitchecker"
 
 	"golang.org/x/tools/go/analysis/passes/asmdecl"
@@ -41,7 +48,7 @@ import (
 func main() {
 	objabi.AddVersionFlag()
 
-	unitchecker.Main(
+	analyzers := []*analysis.Analyzer{
 		asmdecl.Analyzer,
 		assign.Analyzer,
 		atomic.Analyzer,
@@ -65,9 +72,36 @@ func main() {
 		structtag.Analyzer,
 		tests.Analyzer,
 		testinggoroutine.Analyzer,
+		tparamassert.Analyzer,
 		unmarshal.Analyzer,
 		unreachable.Analyzer,
 		unsafeptr.Analyzer,
 		unusedresult.Analyzer,
-	)
+	}
+
+	// The tparamswitch check is not part of the default suite: a switch
+	// that omits terms of the type set is often intentional. It only runs
+	// if requested with -tparamswitch.
+	if optIn(tparamswitch.Analyzer.Name) {
+		analyzers = append(analyzers, tparamswitch.Analyzer)
+	}
+
+	unitchecker.Main(analyzers...)
+}
+
+// optIn reports whether the command line enables the named opt-in
+// analyzer, or queries the available analyzers (with -flags, as the go
+// command does, or help).
+func optIn(name string) bool {
+	for _, arg := range os.Args[1:] {
+		if arg == "-flags" || arg == "help" {
+			return true
+		}
+		arg = strings.TrimPrefix(arg, "-")
+		arg = strings.TrimPrefix(arg, "-")
+		if arg == name || strings.HasPrefix(arg, name+"=") {
+			return true
+		}
+	}
+	return false
 }
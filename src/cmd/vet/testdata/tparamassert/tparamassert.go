@@ -0,0 +1,63 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains tests for the tparamassert checker.
+
+package tparamassert
+
+import (
+	"fmt"
+	"io"
+)
+
+type myInt int
+
+func (myInt) String() string { return "" }
+
+type Number interface {
+	~int | ~float64
+}
+
+func _[P Number](x P) {
+	_ = interface{}(x).(int)
+	_ = interface{}(x).(myInt)
+	_ = interface{}(x).(string)       // ERROR "impossible type assertion: string is not in the type set of P"
+	_, _ = interface{}(x).([]int)     // ERROR "impossible type assertion: \[\]int is not in the type set of P"
+	_ = interface{}(x).(fmt.Stringer) // ~int includes myInt
+
+	switch interface{}(x).(type) {
+	case nil, int, float64, myInt:
+	case bool: // ERROR "impossible type assertion: bool is not in the type set of P"
+	default:
+	}
+}
+
+func _[P int | string](x P) {
+	_ = interface{}(x).(myInt)     // ERROR "impossible type assertion: myInt is not in the type set of P"
+	_ = interface{}(x).(io.Reader) // ERROR "impossible type assertion: no type in the type set of P implements io.Reader"
+	_ = interface{}(x).(interface{})
+
+	switch v := (interface{}(x)).(type) {
+	case string, int:
+		_ = v
+	case fmt.Stringer: // ERROR "impossible type assertion: no type in the type set of P implements fmt.Stringer"
+	}
+}
+
+func _[P myInt | int](x P) {
+	_ = interface{}(x).(fmt.Stringer)
+}
+
+// Type sets depending on other type parameters are not checked.
+func _[P ~[]Q, Q any](x P, y Q) {
+	_ = interface{}(x).([]int)
+	_ = interface{}(x).(string)
+	_ = interface{}(y).(string)
+}
+
+// Unconstrained type parameters and values not of type parameter type are not checked.
+func _[P any](x P, y interface{}) {
+	_ = interface{}(x).(int)
+	_ = y.(string)
+}
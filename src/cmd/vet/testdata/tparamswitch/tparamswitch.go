@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains tests for the tparamswitch checker.
+
+package tparamswitch
+
+import (
+	"fmt"
+	"io"
+)
+
+type myInt int
+
+func (myInt) String() string { return "" }
+
+type myString string
+
+func (myString) String() string { return "" }
+
+func _[P ~int | string](x P) {
+	switch interface{}(x).(type) { // ERROR "type switch on value of type P has no default case and misses ~int"
+	case int:
+	case string:
+	}
+
+	switch interface{}(x).(type) {
+	case int:
+	default:
+	}
+
+	switch v := interface{}(x).(type) { // ERROR "type switch on value of type P has no default case and misses ~int, string"
+	case nil:
+	case myInt:
+		_ = v
+	}
+
+	// An empty interface handles everything.
+	switch interface{}(x).(type) {
+	case string:
+	case interface{}:
+	}
+}
+
+func _[P int | myInt | myString](x P) {
+	switch interface{}(x).(type) {
+	case int, fmt.Stringer:
+	}
+
+	switch interface{}(x).(type) { // ERROR "type switch on value of type P has no default case and misses int"
+	case fmt.Stringer:
+	case myInt: // ERROR "unreachable case myInt: values of this type are handled by the earlier case fmt.Stringer"
+	}
+}
+
+// Promoted methods are shared by all types in the type set of ~U.
+type S struct{ io.Reader }
+
+func _[P ~struct{ io.Reader } | *S](x P) {
+	switch interface{}(x).(type) {
+	case io.Reader:
+	}
+}
+
+// Defined pointer types have no methods.
+func _[P ~*S](x P) {
+	switch interface{}(x).(type) { // ERROR "type switch on value of type P has no default case and misses ~\*S"
+	case io.Reader:
+	}
+}
+
+// Unrestricted type sets, type sets depending on other type parameters,
+// and values not of type parameter type are not checked.
+func _[P any, Q ~[]R, R any](x P, y Q, z interface{}) {
+	switch interface{}(x).(type) {
+	case int:
+	}
+	switch interface{}(y).(type) {
+	case []int:
+	}
+	switch z.(type) {
+	case int:
+	}
+}
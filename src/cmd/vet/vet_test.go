@@ -97,6 +97,8 @@ func TestVet(t *testing.T) {
 		"structtag",
 		"testingpkg",
 		// "testtag" has its own test
+		"tparamassert",
+		"tparamswitch",
 		"unmarshal",
 		"unsafeptr",
 		"unused",
@@ -112,6 +114,11 @@ func TestVet(t *testing.T) {
 
 			cmd := vetCmd(t, "-printfuncs=Warn,Warnf", pkg)
 
+			// tparamswitch is not run by default.
+			if pkg == "tparamswitch" {
+				cmd = vetCmd(t, "-tparamswitch", pkg)
+			}
+
 			// The asm test assumes amd64.
 			if pkg == "asm" {
 				cmd.Env = append(cmd.Env, "GOOS=linux", "GOARCH=amd64")
@@ -74,6 +74,7 @@ var depsRules = `
 	  internal/cfg, internal/cpu, internal/goarch,
 	  internal/goexperiment, internal/goos,
 	  internal/goversion, internal/nettrace,
+	  internal/types/errors,
 	  unicode/utf8, unicode/utf16, unicode,
 	  unsafe;
 
@@ -296,7 +297,7 @@ var depsRules = `
 	math/big, go/token
 	< go/constant;
 
-	container/heap, go/constant, go/parser, regexp
+	container/heap, go/constant, go/parser, internal/types/errors, regexp
 	< go/types;
 
 	FMT, internal/goexperiment
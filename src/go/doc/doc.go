@@ -72,8 +72,8 @@ type Func struct {
 
 	// methods
 	// (for functions, these fields have the respective zero value)
-	Recv  string // actual   receiver "T" or "*T"
-	Orig  string // original receiver "T" or "*T"
+	Recv  string // actual   receiver "T" or "*T" possibly followed by type parameters [P1, ..., Pn]
+	Orig  string // original receiver "T" or "*T" possibly followed by type parameters [P1, ..., Pn]
 	Level int    // embedding level; 0 means not embedded
 
 	// Examples is a sorted list of examples associated with this
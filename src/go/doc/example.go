@@ -225,7 +225,12 @@ func playExample(file *ast.File, f *ast.FuncDecl) *ast.File {
 	for i := 0; i < len(depDecls); i++ {
 		switch d := depDecls[i].(type) {
 		case *ast.FuncDecl:
-			// Inspect types of parameters and results. See #28492.
+			// Inspect types of type parameters, parameters, and results. See #28492.
+			if d.Type.TParams != nil {
+				for _, p := range d.Type.TParams.List {
+					ast.Inspect(p.Type, inspectFunc)
+				}
+			}
 			if d.Type.Params != nil {
 				for _, p := range d.Type.Params.List {
 					ast.Inspect(p.Type, inspectFunc)
@@ -245,6 +250,11 @@ func playExample(file *ast.File, f *ast.FuncDecl) *ast.File {
 			for _, spec := range d.Specs {
 				switch s := spec.(type) {
 				case *ast.TypeSpec:
+					if s.TParams != nil {
+						for _, p := range s.TParams.List {
+							ast.Inspect(p.Type, inspectFunc)
+						}
+					}
 					ast.Inspect(s.Type, inspectFunc)
 
 					depDecls = append(depDecls, typMethods[s.Name.Name]...)
@@ -492,7 +502,11 @@ func classifyExamples(p *Package, examples []*Example) {
 			if !token.IsExported(m.Name) {
 				continue
 			}
-			ids[strings.TrimPrefix(m.Recv, "*")+"_"+m.Name] = &m.Examples
+			recvTypeName := strings.TrimPrefix(m.Recv, "*")
+			if i := strings.IndexByte(recvTypeName, '['); i >= 0 {
+				recvTypeName = recvTypeName[:i] // drop type parameters
+			}
+			ids[recvTypeName+"_"+m.Name] = &m.Examples
 		}
 	}
 
@@ -79,18 +79,18 @@ func hasExportedName(list []*ast.Ident) bool {
 	return false
 }
 
-// removeErrorField removes anonymous fields named "error" from an interface.
-// This is called when "error" has been determined to be a local name,
+// removeAnonymousField removes anonymous fields named name from an interface.
+// This is called when name has been determined to be a local name,
 // not the predeclared type.
 //
-func removeErrorField(ityp *ast.InterfaceType) {
+func removeAnonymousField(name string, ityp *ast.InterfaceType) {
 	list := ityp.Methods.List // we know that ityp.Methods != nil
 	j := 0
 	for _, field := range list {
 		keepField := true
 		if n := len(field.Names); n == 0 {
 			// anonymous field
-			if fname, _ := baseTypeName(field.Type); fname == "error" {
+			if fname, _ := baseTypeName(field.Type); fname == name {
 				keepField = false
 			}
 		}
@@ -119,16 +119,20 @@ func (r *reader) filterFieldList(parent *namedType, fields *ast.FieldList, ityp
 	for _, field := range list {
 		keepField := false
 		if n := len(field.Names); n == 0 {
-			// anonymous field
+			// anonymous field or interface element
 			fname := r.recordAnonymousField(parent, field.Type)
 			if token.IsExported(fname) {
 				keepField = true
-			} else if ityp != nil && fname == "error" {
-				// possibly the predeclared error interface; keep
-				// it for now but remember this interface so that
-				// it can be fixed if error is also defined locally
+			} else if ityp != nil && predeclaredTypes[fname] {
+				// possibly an embedded predeclared type (such as error
+				// or an element of a type set); keep it for now but
+				// remember this interface so that it can be fixed if
+				// the name is also defined locally
+				keepField = true
+				r.remember(fname, ityp)
+			} else if ityp != nil && fname == "" {
+				// union or approximation element (~T), or type literal
 				keepField = true
-				r.remember(ityp)
 			}
 		} else {
 			field.Names = filterIdentList(field.Names)
@@ -222,9 +226,9 @@ func (r *reader) filterSpec(spec ast.Spec) bool {
 		if name := s.Name.Name; token.IsExported(name) {
 			r.filterType(r.lookupType(s.Name.Name), s.Type)
 			return true
-		} else if name == "error" {
-			// special case: remember that error is declared locally
-			r.errorDecl = true
+		} else if predeclaredTypes[name] {
+			// special case: remember that a predeclared type is declared locally
+			r.shadowedPredecl[name] = true
 		}
 	}
 	return false
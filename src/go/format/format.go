@@ -93,7 +93,8 @@ func Node(dst io.Writer, fset *token.FileSet, node interface{}) error {
 
 // Source formats src in canonical gofmt style and returns the result
 // or an (I/O or syntax) error. src is expected to be a syntactically
-// correct Go source file, or a list of Go declarations or statements.
+// correct Go source file, or a list of Go declarations, statements, or
+// interface elements (such as the union ~int | ~string of a constraint).
 //
 // If src is a partial source file, the leading and trailing space of src
 // is applied to the result (such that it has the same leading and trailing
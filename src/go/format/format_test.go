@@ -121,6 +121,17 @@ var tests = []string{
 	"x := 0",
 	"f(a, b, c)\nvar x int = f(1, 2, 3)",
 
+	// generic code
+	"func f[T ~int | ~string](x T) T { return x }",
+	"type List[T any] []T\n\nfunc (l List[T]) Len() int { return len(l) }",
+	"x := Map[int, string](nil, nil)",
+
+	// interface element lists
+	"~int | ~string",
+	"~int | ~string\nm() int",
+	"\t~[]byte | string\n\n",
+	"interface{ ~int }",
+
 	// indentation, leading and trailing space
 	"\tx := 0\n\tgo f()",
 	"\tx := 0\n\tgo f()\n\n\n",
@@ -151,6 +162,7 @@ var tests = []string{
 	// erroneous programs
 	"ERROR1 + 2 +",
 	"ERRORx :=  0",
+	"ERROR~int |",
 
 	// build comments
 	"// copyright\n\n//go:build x\n\npackage p\n",
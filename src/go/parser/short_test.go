@@ -124,6 +124,14 @@ var validWithTParamsOnly = []string{
 	`package p; type I1[T any /* ERROR "expected ']', found any" */ ] interface{}; type I2 interface{ I1[int] }`,
 	`package p; type I1[T any /* ERROR "expected ']', found any" */ ] interface{}; type I2[T any] interface{ I1[T] }`,
 	`package p; type _ interface { f[ /* ERROR "expected ';', found '\['" */ T any]() }`,
+
+	// constraint literals
+	`package p; type _[P ~ /* ERROR "expected ']', found '~'" */ int] struct{}`,
+	`package p; type _[P int /* ERROR "expected ']', found int" */ | string] struct{}`,
+	`package p; func _[ /* ERROR "expected '\(', found '\['" */ P ~int | ~string]()`,
+	`package p; func _[ /* ERROR "expected '\(', found '\['" */ P, Q ~int, R ~[]P | []Q]()`,
+	`package p; func _[ /* ERROR "expected '\(', found '\['" */ P interface{ m() } | int]()`,
+	`package p; type _ interface { m[ /* ERROR "expected ';', found '\['" */ P ~int]() }`,
 }
 
 func TestValid(t *testing.T) {
@@ -245,6 +253,9 @@ var invalidTParamErrs = []string{
 	`package p; var _ func[ /* ERROR "cannot have type parameters" */ T any](T)`,
 	`package p; func _[]/* ERROR "empty type parameter list" */()`,
 
+	`package p; func _[~ /* ERROR "all type parameters must be named" */ int]()`,
+	`package p; type _[P ~int | ... /* ERROR "expected ~ term or type" */ int] struct{}`,
+
 	// TODO(rfindley) a better location would be after the ']'
 	`package p; type _[A/* ERROR "all type parameters must be named" */,] struct{ A }`,
 
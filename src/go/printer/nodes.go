@@ -319,9 +319,17 @@ func (p *printer) exprList(prev0 token.Pos, list []ast.Expr, depth int, mode exp
 	}
 }
 
-func (p *printer) parameters(fields *ast.FieldList, isTypeParam bool) {
+type paramMode int
+
+const (
+	funcParam paramMode = iota
+	funcTParam
+	typeTParam
+)
+
+func (p *printer) parameters(fields *ast.FieldList, mode paramMode) {
 	openTok, closeTok := token.LPAREN, token.RPAREN
-	if isTypeParam {
+	if mode != funcParam {
 		openTok, closeTok = token.LBRACK, token.RBRACK
 	}
 	p.print(fields.Opening, openTok)
@@ -364,7 +372,17 @@ func (p *printer) parameters(fields *ast.FieldList, isTypeParam bool) {
 				p.print(blank)
 			}
 			// parameter type
-			p.expr(stripParensAlways(par.Type))
+			typ := stripParensAlways(par.Type)
+			if mode == typeTParam && i == 0 && len(par.Names) == 1 && combinesWithName(typ) {
+				// The type parameter list would be parsed as an array
+				// length expression (e.g., type T[P *C] ...). Use an
+				// explicit interface to avoid the ambiguity.
+				typ = &ast.InterfaceType{
+					Interface: typ.Pos(),
+					Methods:   &ast.FieldList{Opening: typ.Pos(), List: []*ast.Field{{Type: typ}}, Closing: typ.End()},
+				}
+			}
+			p.expr(typ)
 			prevLine = parLineEnd
 		}
 		// if the closing ")" is on a separate line from the last parameter,
@@ -381,12 +399,25 @@ func (p *printer) parameters(fields *ast.FieldList, isTypeParam bool) {
 	p.print(fields.Closing, closeTok)
 }
 
+// combinesWithName reports whether a type parameter name followed by
+// the constraint x is parsed as an expression, as is the case for
+// P *C or P []int.
+func combinesWithName(x ast.Expr) bool {
+	switch x := x.(type) {
+	case *ast.StarExpr, *ast.ArrayType, *ast.ParenExpr:
+		return true
+	case *ast.BinaryExpr:
+		return combinesWithName(x.X)
+	}
+	return false
+}
+
 func (p *printer) signature(sig *ast.FuncType) {
 	if sig.TParams != nil {
-		p.parameters(sig.TParams, true)
+		p.parameters(sig.TParams, funcTParam)
 	}
 	if sig.Params != nil {
-		p.parameters(sig.Params, false)
+		p.parameters(sig.Params, funcParam)
 	} else {
 		p.print(token.LPAREN, token.RPAREN)
 	}
@@ -400,7 +431,7 @@ func (p *printer) signature(sig *ast.FuncType) {
 			p.expr(stripParensAlways(res.List[0].Type))
 			return
 		}
-		p.parameters(res, false)
+		p.parameters(res, funcParam)
 	}
 }
 
@@ -1633,7 +1664,7 @@ func (p *printer) spec(spec ast.Spec, n int, doIndent bool) {
 		p.setComment(s.Doc)
 		p.expr(s.Name)
 		if s.TParams != nil {
-			p.parameters(s.TParams, true)
+			p.parameters(s.TParams, typeTParam)
 		}
 		if n == 1 {
 			p.print(blank)
@@ -1823,7 +1854,7 @@ func (p *printer) funcDecl(d *ast.FuncDecl) {
 	// FUNC is emitted).
 	startCol := p.out.Column - len("func ")
 	if d.Recv != nil {
-		p.parameters(d.Recv, false) // method: print receiver
+		p.parameters(d.Recv, funcParam) // method: print receiver
 		p.print(blank)
 	}
 	p.expr(d.Name)
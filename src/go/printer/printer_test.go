@@ -822,3 +822,54 @@ func f() {
 		t.Fatalf("got %q, want %q", got, want)
 	}
 }
+
+// TestTypeParamAmbiguity verifies that type parameter lists of type
+// declarations which would be parsed as array length expressions are
+// printed with an explicit interface constraint.
+func TestTypeParamAmbiguity(t *testing.T) {
+	for _, test := range []struct {
+		constraint, want string
+	}{
+		{"any", "type T[P any] struct{}"},
+		{"*C", "type T[P interface{ *C }] struct{}"},
+		{"(*C)", "type T[P interface{ *C }] struct{}"},
+		{"[]int", "type T[P interface{ []int }] struct{}"},
+		{"*C | int", "type T[P interface{ *C | int }] struct{}"},
+		{"(*C) | int", "type T[P interface{ (*C) | int }] struct{}"},
+		{"int | *C", "type T[P int | *C] struct{}"},
+	} {
+		// The parser doesn't accept these type parameter lists;
+		// construct the declaration manually.
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "", "package p; type T[P X, _ any] struct{}", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decl := file.Decls[0].(*ast.GenDecl)
+		spec := decl.Specs[0].(*ast.TypeSpec)
+		spec.TParams.List = spec.TParams.List[:1]
+		if spec.TParams.List[0].Type, err = parser.ParseExprFrom(fset, "", test.constraint, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := Fprint(&buf, fset, decl); err != nil {
+			t.Fatal(err)
+		}
+		got := buf.String()
+		if got != test.want {
+			t.Errorf("got %q; want %q", got, test.want)
+			continue
+		}
+
+		// the result must be parsed as a generic type
+		file, err = parser.ParseFile(token.NewFileSet(), "", "package p; "+got, 0)
+		if err != nil {
+			t.Errorf("%s: %v", got, err)
+			continue
+		}
+		if spec := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec); spec.TParams == nil {
+			t.Errorf("%s: not parsed as a generic type", got)
+		}
+	}
+}
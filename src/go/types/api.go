@@ -49,8 +49,8 @@ type Error struct {
 	// go116code is a future API, unexported as the set of error codes is large
 	// and likely to change significantly during experimentation. Tools wishing
 	// to preview this feature may read go116code using reflection (see
-	// errorcodes_test.go), but beware that there is no guarantee of future
-	// compatibility.
+	// internal/types/errors/codes_test.go), but beware that there is no
+	// guarantee of future compatibility.
 	go116code  errorCode
 	go116start token.Pos
 	go116end   token.Pos
@@ -168,6 +168,17 @@ type Config struct {
 	// If DisableUnusedImportCheck is set, packages are not checked
 	// for unused imports.
 	DisableUnusedImportCheck bool
+
+	// If Freeze is set, the package is frozen at the end of type
+	// checking: all lazily computed information reachable from the
+	// package scope and the recorded Info, such as interface type sets
+	// and the underlying types of instantiated types, is computed. The
+	// package, the Info, and the types and objects reachable from them
+	// may then be used concurrently by multiple goroutines, as long as
+	// they are not modified (e.g., by type-checking more files with
+	// the same Checker, or by calling one of the Set, Add, or Complete
+	// methods of an object or type).
+	Freeze bool
 }
 
 func srcimporter_setUsesCgo(conf *Config) {
@@ -237,6 +248,16 @@ type Info struct {
 	// to their corresponding selections.
 	Selections map[*ast.SelectorExpr]*Selection
 
+	// ImplicitInterfaces maps constraint literals to the implicit
+	// interfaces they stand for. A constraint literal is a type parameter
+	// constraint that is not written as an interface, such as ~int or
+	// int | string in [T ~int, U int | string]; it denotes the interface
+	// embedding it, here interface{ ~int } and interface{ int | string }.
+	// The position of such an interface is the position of the literal.
+	// Interfaces that are written explicitly are not recorded. See also
+	// Interface.IsImplicit.
+	ImplicitInterfaces map[ast.Expr]*Interface
+
 	// Scopes maps ast.Nodes to the scopes they define. Package scopes are not
 	// associated with a specific node but with all files belonging to a package.
 	// Thus, the package scope can be found in the type-checked Package object.
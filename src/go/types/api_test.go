@@ -15,7 +15,9 @@ import (
 	"internal/testenv"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	. "go/types"
@@ -1926,3 +1928,299 @@ func TestInstanceIdentity(t *testing.T) {
 		t.Errorf("mismatching types: a.A: %s, b.B: %s", a.Type(), b.Type())
 	}
 }
+
+func TestPartialInterface(t *testing.T) {
+	const src = genericPkg + `p
+
+type E interface {
+	m()
+	Undefined
+}
+
+type I interface {
+	E
+	n()
+	int | Missing | string
+}
+
+type C interface{ ~int | Missing }
+
+func f[P C]() {}
+
+var _ = f[string]
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, modeForSource(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []string
+	conf := Config{Error: func(err error) { errs = append(errs, err.(Error).Msg) }}
+	pkg, _ := conf.Check("p", fset, []*ast.File{f}, nil)
+
+	// Only the undeclared names are reported; in particular
+	// string is not reported as not satisfying C.
+	sort.Strings(errs)
+	if want := []string{"undeclared name: Missing", "undeclared name: Missing", "undeclared name: Undefined"}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("got errors %q; want %q", errs, want)
+	}
+
+	for _, test := range []struct {
+		name    string
+		methods string
+	}{
+		{"E", "m"},
+		{"I", "m n"},
+		{"C", ""},
+	} {
+		iface := pkg.Scope().Lookup(test.name).Type().Underlying().(*Interface)
+		if !iface.IsPartial() {
+			t.Errorf("%s: interface is not partial", test.name)
+		}
+		var methods []string
+		for i := 0; i < iface.NumMethods(); i++ {
+			methods = append(methods, iface.Method(i).Name())
+		}
+		if got := strings.Join(methods, " "); got != test.methods {
+			t.Errorf("%s: got methods %q; want %q", test.name, got, test.methods)
+		}
+		obj, _, _ := LookupFieldOrMethod(iface, false, pkg, "m")
+		if got := obj != nil; got != strings.Contains(test.methods, "m") {
+			t.Errorf("%s: lookup of m succeeded = %v", test.name, got)
+		}
+	}
+
+	// Valid interfaces are not partial.
+	if Universe.Lookup("error").Type().Underlying().(*Interface).IsPartial() {
+		t.Errorf("error interface is partial")
+	}
+}
+
+func TestImplicitInterfaces(t *testing.T) {
+	const src = genericPkg + `p
+
+type I interface{ ~int }
+
+type T[P ~int, Q int | ~string, R I, S interface{ ~int }] struct{}
+
+func F[A, B ~[]byte | string]() {}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, modeForSource(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &Info{
+		Types:              make(map[ast.Expr]TypeAndValue),
+		ImplicitInterfaces: make(map[ast.Expr]*Interface),
+	}
+	var conf Config
+	pkg, err := conf.Check("p", fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// collect the constraint literals by source position
+	got := make(map[string]string)
+	for x, iface := range info.ImplicitInterfaces {
+		if !iface.IsImplicit() {
+			t.Errorf("%s: interface is not implicit", fset.Position(x.Pos()))
+		}
+		if tv := info.Types[x]; tv.Type != iface {
+			t.Errorf("%s: got type %v; want %v", fset.Position(x.Pos()), tv.Type, iface)
+		}
+		got[fset.Position(x.Pos()).String()] = iface.String()
+	}
+	want := map[string]string{
+		"p.go:5:10": "interface{~int}",
+		"p.go:5:18": "interface{int|~string}",
+		"p.go:7:13": "interface{~[]byte|string}",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d implicit interfaces; want %d", len(got), len(want))
+	}
+	for pos, w := range want {
+		if g := got[pos]; g != w {
+			t.Errorf("%s: got %q; want %q", pos, g, w)
+		}
+	}
+
+	// For type parameters declared together, the implicit interface is shared.
+	sig := pkg.Scope().Lookup("F").Type().(*Signature)
+	if a, b := sig.TParams().At(0).Constraint(), sig.TParams().At(1).Constraint(); a != b {
+		t.Errorf("got different constraints %v and %v", a, b)
+	}
+
+	// Explicitly written interfaces are not implicit.
+	typ := pkg.Scope().Lookup("T").Type().(*Named)
+	for _, i := range []int{2, 3} {
+		if iface := typ.TParams().At(i).Constraint().Underlying().(*Interface); iface.IsImplicit() {
+			t.Errorf("constraint %v is implicit", iface)
+		}
+	}
+}
+
+func TestNormalTerms(t *testing.T) {
+	for _, test := range []struct {
+		src, want string
+	}{
+		{"interface{}", "<nil>"},
+		{"interface{ m() }", "<nil>"},
+		{"interface{ int; string }", "[]"},
+		{"interface{ ~int | string; comparable }", "[~int string]"},
+		{"interface{ ~int | ~string; myInt | string }", "[generic_p.myInt string]"},
+	} {
+		src := genericPkg + "p; type myInt int; type T " + test.src
+		pkg, err := pkgFor("p.go", src, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", test.src, err)
+		}
+		terms := pkg.Scope().Lookup("T").Type().Underlying().(*Interface).NormalTerms()
+		got := "<nil>"
+		if terms != nil {
+			got = fmt.Sprint(terms)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %s; want %s", test.src, got, test.want)
+		}
+	}
+}
+
+type freezeImporter map[string]*Package
+
+func (m freezeImporter) Import(path string) (*Package, error) {
+	if pkg := m[path]; pkg != nil {
+		return pkg, nil
+	}
+	return nil, fmt.Errorf("package %q not found", path)
+}
+
+func TestFreeze(t *testing.T) {
+	const srcA = `package a
+
+type List[T any] struct {
+	next *List[T]
+	val  T
+}
+
+func (l *List[T]) Push(v T) *List[T] { return &List[T]{l, v} }
+`
+	const srcP = `package p
+
+import "a"
+
+type Number interface {
+	~int | ~float64
+	String() string
+}
+
+func Sum[T Number](l *a.List[T]) (s T) {
+	return
+}
+
+var (
+	f = a.F
+	x a.List[string]
+)
+`
+	fset := token.NewFileSet()
+	fa, err := parser.ParseFile(fset, "a.go", srcA, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := new(Config).Check("a", fset, []*ast.File{fa}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Importers create instances lazily; their underlying types
+	// are only computed when needed. Checking p doesn't need the
+	// underlying type of the result of F.
+	list, err := Instantiate(nil, a.Scope().Lookup("List").Type(), []Type{Typ[Int]}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := NewTuple(NewVar(token.NoPos, a, "", list))
+	a.Scope().Insert(NewFunc(token.NoPos, a, "F", NewSignature(nil, nil, res, false)))
+
+	fp, err := parser.ParseFile(fset, "p.go", srcP, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &Info{
+		Types: make(map[ast.Expr]TypeAndValue),
+		Defs:  make(map[*ast.Ident]Object),
+		Uses:  make(map[*ast.Ident]Object),
+	}
+	conf := Config{Importer: freezeImporter{"a": a}, Freeze: true}
+	pkg, err := conf.Check("p", fset, []*ast.File{fp}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Query the package concurrently. With -race, this
+	// reports any lazily computed state that was missed.
+	describe := func() string {
+		var buf bytes.Buffer
+		for _, name := range pkg.Scope().Names() {
+			obj := pkg.Scope().Lookup(name)
+			typ := obj.Type()
+			fmt.Fprintf(&buf, "%s: %s; %s\n", obj, typ.Underlying(), NewMethodSet(NewPointer(typ)))
+			if iface, _ := typ.Underlying().(*Interface); iface != nil {
+				fmt.Fprintf(&buf, "\t%d methods, comparable %v\n", iface.NumMethods(), iface.IsComparable())
+			}
+			if sig, _ := typ.(*Signature); sig != nil && sig.Results().Len() == 1 {
+				r := sig.Results().At(0).Type()
+				fmt.Fprintf(&buf, "\tresult %s: %s; %s\n", r, r.Underlying(), NewMethodSet(NewPointer(r)))
+			}
+		}
+		return buf.String()
+	}
+	const n = 8
+	results := make(chan string, n)
+	for i := 0; i < n; i++ {
+		go func() { results <- describe() }()
+	}
+	want := <-results
+	for i := 1; i < n; i++ {
+		if got := <-results; got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+	}
+}
+
+func TestInterfaceComplete(t *testing.T) {
+	// type I interface{ m() }
+	// type J interface{ I; n() }
+	newMethod := func(name string) *Func {
+		return NewFunc(token.NoPos, nil, name, NewSignature(nil, nil, nil, false))
+	}
+	I := NewInterfaceType([]*Func{newMethod("m")}, nil)
+	J := NewInterfaceType([]*Func{newMethod("n")}, []Type{I})
+
+	if I.IsComplete() || J.IsComplete() {
+		t.Fatalf("interfaces are complete before use")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := J.Complete(); got != J {
+				t.Errorf("Complete returned %v; want the receiver", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !J.IsComplete() || !I.IsComplete() {
+		t.Errorf("interfaces not complete after Complete: I %v, J %v", I.IsComplete(), J.IsComplete())
+	}
+	if got := J.NumMethods(); got != 2 {
+		t.Errorf("got %d methods; want 2", got)
+	}
+	if m := J.Complete().Method(0); m.Name() != "m" {
+		t.Errorf("got method %s; want m", m.Name())
+	}
+}
@@ -273,6 +273,10 @@ func (check *Checker) checkFiles(files []*ast.File) (err error) {
 
 	check.recordUntyped()
 
+	if check.conf.Freeze {
+		check.freeze()
+	}
+
 	check.pkg.complete = true
 
 	// no longer needed - release memory
@@ -434,6 +438,14 @@ func (check *Checker) recordImplicit(node ast.Node, obj Object) {
 	}
 }
 
+func (check *Checker) recordImplicitInterface(x ast.Expr, ityp *Interface) {
+	assert(x != nil)
+	assert(ityp != nil)
+	if m := check.ImplicitInterfaces; m != nil {
+		m[x] = ityp
+	}
+}
+
 func (check *Checker) recordSelection(x *ast.SelectorExpr, kind SelectionKind, recv Type, obj Object, index []int, indirect bool) {
 	assert(obj != nil && (recv == nil || len(index) > 0))
 	check.recordUse(x.Sel, obj)
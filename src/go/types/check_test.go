@@ -34,6 +34,7 @@ import (
 	"internal/testenv"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -152,6 +153,12 @@ func errMap(t *testing.T, files []*ast.File, srcs [][]byte) map[string][]string
 	return errmap
 }
 
+// readCode returns the error code of err.
+func readCode(err Error) int {
+	v := reflect.ValueOf(err)
+	return int(v.FieldByName("go116code").Int())
+}
+
 func eliminate(t *testing.T, errmap map[string][]string, errlist []error) {
 	for _, err := range errlist {
 		pos, gotMsg := splitError(err)
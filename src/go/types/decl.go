@@ -698,6 +698,19 @@ func (check *Checker) boundType(e ast.Expr) Type {
 		return universeAny.Type()
 	}
 
+	// A constraint literal such as ~int or int | string stands for the
+	// implicit interface interface{ ~int } or interface{ int | string }.
+	switch e := e.(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.TILDE {
+			return check.implicitInterface(e)
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.OR {
+			return check.implicitInterface(e)
+		}
+	}
+
 	bound := check.typ(e)
 	check.later(func() {
 		u := under(bound)
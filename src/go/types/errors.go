@@ -85,6 +85,11 @@ func sprintf(fset *token.FileSet, qf Qualifier, format string, args ...interface
 			arg = ObjectString(a, qf)
 		case Type:
 			arg = TypeString(a, qf)
+		case *Term:
+			arg = TypeString(a.typ, qf)
+			if a.tilde {
+				arg = "~" + arg.(string)
+			}
 		}
 		args[i] = arg
 	}
@@ -188,7 +193,7 @@ func (check *Checker) softErrorf(at positioner, code errorCode, format string, a
 }
 
 func (check *Checker) invalidAST(at positioner, format string, args ...interface{}) {
-	check.errorf(at, 0, "invalid AST: "+format, args...)
+	check.errorf(at, _InvalidSyntaxTree, "invalid AST: "+format, args...)
 }
 
 func (check *Checker) invalidArg(at positioner, code errorCode, format string, args ...interface{}) {
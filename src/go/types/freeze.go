@@ -0,0 +1,167 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements freezing of checked packages.
+
+package types
+
+import "go/token"
+
+// freeze computes all lazily computed state of the types and objects
+// reachable from the package scope and the recorded type information,
+// so that they are not mutated when accessed later. See Config.Freeze.
+func (check *Checker) freeze() {
+	f := freezer{seen: make(map[Type]bool)}
+
+	f.scope(check.pkg.scope)
+
+	for _, tv := range check.Types {
+		f.typ(tv.Type)
+	}
+	for _, inf := range check.Inferred {
+		f.typeList(inf.TArgs)
+		f.typ(inf.Sig)
+	}
+	for _, obj := range check.Defs {
+		f.obj(obj)
+	}
+	for _, obj := range check.Uses {
+		f.obj(obj)
+	}
+	for _, obj := range check.Implicits {
+		f.obj(obj)
+	}
+	for _, sel := range check.Selections {
+		f.typ(sel.recv)
+		f.obj(sel.obj)
+	}
+	for _, iface := range check.ImplicitInterfaces {
+		f.typ(iface)
+	}
+	for _, s := range check.Scopes {
+		f.scope(s)
+	}
+}
+
+// A freezer computes the lazily computed state of types.
+type freezer struct {
+	seen map[Type]bool
+}
+
+// scope freezes the objects in s and its children. Looking up
+// each name also resolves lazily imported objects.
+func (f *freezer) scope(s *Scope) {
+	for _, name := range s.Names() {
+		f.obj(s.Lookup(name))
+	}
+	for _, child := range s.children {
+		f.scope(child)
+	}
+}
+
+func (f *freezer) obj(obj Object) {
+	if obj != nil {
+		f.typ(obj.Type())
+	}
+}
+
+func (f *freezer) typeList(list *TypeList) {
+	for i := 0; i < list.Len(); i++ {
+		f.typ(list.At(i))
+	}
+}
+
+func (f *freezer) tparamList(list *TParamList) {
+	for i := 0; i < list.Len(); i++ {
+		f.typ(list.At(i))
+	}
+}
+
+func (f *freezer) tuple(t *Tuple) {
+	if t != nil {
+		for _, v := range t.vars {
+			f.typ(v.typ)
+		}
+	}
+}
+
+func (f *freezer) typ(typ Type) {
+	if typ == nil || f.seen[typ] {
+		return
+	}
+	f.seen[typ] = true
+
+	switch t := typ.(type) {
+	case *Basic:
+		// nothing to do
+
+	case *Array:
+		f.typ(t.elem)
+
+	case *Slice:
+		f.typ(t.elem)
+
+	case *Struct:
+		for _, fld := range t.fields {
+			f.typ(fld.typ)
+		}
+
+	case *Pointer:
+		f.typ(t.base)
+
+	case *Tuple:
+		f.tuple(t)
+
+	case *Signature:
+		if t.recv != nil {
+			f.typ(t.recv.typ)
+		}
+		f.tparamList(t.rparams)
+		f.tparamList(t.tparams)
+		f.tuple(t.params)
+		f.tuple(t.results)
+
+	case *Union:
+		computeUnionTypeSet(nil, token.NoPos, t)
+		for _, term := range t.terms {
+			f.typ(term.typ)
+		}
+
+	case *Interface:
+		t.Complete()
+		for _, m := range t.methods {
+			f.typ(m.typ)
+		}
+		for _, e := range t.embeddeds {
+			f.typ(e)
+		}
+		for _, m := range t.typeSet().methods {
+			f.typ(m.typ)
+		}
+
+	case *Map:
+		f.typ(t.key)
+		f.typ(t.elem)
+
+	case *Chan:
+		f.typ(t.elem)
+
+	case *Named:
+		t.load().expand(nil)
+		f.typ(t.orig)
+		f.tparamList(t.tparams)
+		f.typeList(t.targs)
+		f.typ(t.underlying)
+		for _, m := range t.methods {
+			f.typ(m.typ)
+		}
+
+	case *TypeParam:
+		f.typ(t.Constraint())
+		f.typ(t.iface())
+
+	default:
+		panic("unreachable")
+	}
+}
@@ -254,6 +254,12 @@ func (check *Checker) satisfies(pos token.Pos, targ Type, tpar *TypeParam, smap
 		return nil // nothing to do
 	}
 
+	// If some of the interface's type terms are invalid, an error was reported
+	// already and we don't know the actual type set; don't report follow-on errors.
+	if iface.typeSet().partial {
+		return nil
+	}
+
 	// If targ is itself a type parameter, each of its possible types, but at least one, must be in the
 	// list of iface types (i.e., the targ type list must be a non-empty subset of the iface types).
 	if targ := asTypeParam(targ); targ != nil {
@@ -7,6 +7,7 @@ package types
 import (
 	"go/ast"
 	"go/token"
+	"sync"
 )
 
 // ----------------------------------------------------------------------------
@@ -19,6 +20,7 @@ type Interface struct {
 	embeddeds []Type       // ordered list of explicitly embedded elements
 	embedPos  *[]token.Pos // positions of embedded elements; or nil (for error messages) - use pointer to save space
 	complete  bool         // indicates that obj, methods, and embeddeds are set and type set can be computed
+	implicit  bool         // interface is the implicit interface of a constraint literal
 
 	tset *_TypeSet // type set described by this interface, computed lazily
 }
@@ -113,14 +115,58 @@ func (t *Interface) IsConstraint() bool { return t.typeSet().IsConstraint() }
 // panic occurs. Complete returns the receiver.
 //
 // Interface types that have been completed are safe for concurrent use.
+// Complete may be called more than once, and concurrently; the type set
+// is computed only once.
 func (t *Interface) Complete() *Interface {
-	if !t.complete {
-		t.complete = true
-	}
+	completeMu.Lock()
+	defer completeMu.Unlock()
+	t.complete = true
 	t.typeSet() // checks if t.tset is already set
 	return t
 }
 
+// IsComplete reports whether the type set of t has been computed, by
+// Complete or by a use of t during or after type-checking.
+func (t *Interface) IsComplete() bool {
+	completeMu.Lock()
+	defer completeMu.Unlock()
+	return t.tset != nil
+}
+
+// completeMu serializes Interface.Complete and Interface.IsComplete.
+var completeMu sync.Mutex
+
+// IsPartial reports whether the type set of t was computed ignoring
+// invalid embedded elements or union terms. This can only happen for
+// interfaces of packages with type errors. A partial interface retains
+// all its valid methods and type terms and may be used as usual, but
+// its type set may be larger or smaller than intended.
+func (t *Interface) IsPartial() bool { return t.typeSet().partial }
+
+// IsImplicit reports whether t is the implicit interface of a constraint
+// literal such as ~int in [T ~int], rather than an interface written in
+// the source.
+func (t *Interface) IsImplicit() bool { return t.implicit }
+
+// NormalTerms returns the type terms of the type set of t in normal
+// form: the terms are pairwise disjoint and their union is the set of
+// types permitted by t's embedded elements, ignoring methods. The
+// result is nil if the type set of t is not restricted by type terms,
+// and an empty, non-nil slice if the type set of t is empty.
+func (t *Interface) NormalTerms() []*Term {
+	terms := t.typeSet().terms
+	if terms.isAll() {
+		return nil
+	}
+	list := make([]*Term, 0, len(terms))
+	for _, x := range terms {
+		if x != nil {
+			list = append(list, (*Term)(x))
+		}
+	}
+	return list
+}
+
 func (t *Interface) Underlying() Type { return t }
 func (t *Interface) String() string   { return TypeString(t, nil) }
 
@@ -239,6 +285,20 @@ func (check *Checker) interfaceType(ityp *Interface, iface *ast.InterfaceType, d
 	check.later(func() { computeInterfaceTypeSet(check, iface.Pos(), ityp) })
 }
 
+// implicitInterface type-checks the constraint literal e and returns
+// the implicit interface which embeds e as its only element.
+func (check *Checker) implicitInterface(e ast.Expr) *Interface {
+	ityp := &Interface{implicit: true}
+	ityp.embeddeds = []Type{parseUnion(check, flattenUnion(nil, e))}
+	ityp.embedPos = &[]token.Pos{e.Pos()}
+	ityp.complete = true
+	check.recordTypeAndValue(e, typexpr, ityp, nil)
+	check.recordImplicitInterface(e, ityp)
+
+	check.later(func() { computeInterfaceTypeSet(check, e.Pos(), ityp) })
+	return ityp
+}
+
 func flattenUnion(list []ast.Expr, x ast.Expr) []ast.Expr {
 	if o, _ := x.(*ast.BinaryExpr); o != nil && o.Op == token.OR {
 		list = flattenUnion(list, o.X)
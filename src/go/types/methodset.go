@@ -73,17 +73,20 @@ func NewMethodSet(T Type) *MethodSet {
 	// WARNING: The code in this function is extremely subtle - do not modify casually!
 	//          This function and lookupFieldOrMethod should be kept in sync.
 
-	// TODO(rfindley) confirm that this code is in sync with lookupFieldOrMethod
-	//                with respect to type params.
-
 	// method set up to the current depth, allocated lazily
 	var base methodSet
 
 	typ, isPtr := deref(T)
 
-	// *typ where typ is an interface has no methods.
-	if isPtr && IsInterface(typ) {
-		return &emptyMethodSet
+	// *typ where typ is an interface or type parameter has no methods.
+	if isPtr {
+		// don't look at under(typ) here - was bug (issue #47747)
+		if _, ok := typ.(*TypeParam); ok {
+			return &emptyMethodSet
+		}
+		if _, ok := under(typ).(*Interface); ok {
+			return &emptyMethodSet
+		}
 	}
 
 	// Start with typ as single entry at shallowest depth.
@@ -160,7 +163,7 @@ func NewMethodSet(T Type) *MethodSet {
 				mset = mset.add(t.typeSet().methods, e.index, true, e.multiples)
 
 			case *TypeParam:
-				mset = mset.add(t.iface().typeSet().methods, e.index, true, e.multiples)
+				mset = mset.add(t.Interface().typeSet().methods, e.index, true, e.multiples)
 			}
 		}
 
@@ -48,6 +48,11 @@ func TestNewMethodSet(t *testing.T) {
 		// By convention, look up a in the scope of "g"
 		"type C interface{ f() }; func g[T C](a T){}":               {{"f", []int{0}, true}},
 		"type C interface{ f() }; func g[T C]() { var a T; _ = a }": {{"f", []int{0}, true}},
+		"type C interface{ f() }; func g[T C](a *T){}":              {},
+
+		// Methods of embedded constraints
+		"type ( C interface{ D; h() }; D interface{ f() } ); func g[T C](a T){}": {{"f", []int{0}, true}, {"h", []int{1}, true}},
+		"type D interface{ f() }; func g[T interface{ D; ~int }](a T){}":         {{"f", []int{0}, true}},
 
 		// Issue #43621: We don't allow this anymore. Keep this code in case we
 		// decide to revisit this decision.
@@ -85,6 +90,18 @@ func TestNewMethodSet(t *testing.T) {
 			t.Errorf("%s: got %d methods, want %d", src, got, want)
 			return
 		}
+		if tpar, _ := obj.Type().(*TypeParam); tpar != nil {
+			// The method set of a type parameter is the method set of its constraint interface.
+			ims := NewMethodSet(tpar.Interface())
+			for i := 0; i < ms.Len() && i < ims.Len(); i++ {
+				if got, want := ms.At(i).Obj(), ims.At(i).Obj(); got != want {
+					t.Errorf("%s [method %d]: got %s, want constraint method %s", src, i, got, want)
+				}
+			}
+			if got, want := ms.Len(), ims.Len(); got != want {
+				t.Errorf("%s: got %d methods, want %d constraint methods", src, got, want)
+			}
+		}
 		for i, m := range methods {
 			sel := ms.At(i)
 			if got, want := sel.Obj().Name(), m.name; got != want {
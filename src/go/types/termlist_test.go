@@ -97,6 +97,9 @@ func TestTermlistNorm(t *testing.T) {
 		{"int ∪ ~string ∪ int", "int ∪ ~string"},
 		{"~int ∪ string ∪ 𝓤 ∪ ~string ∪ int", "𝓤"},
 		{"~int ∪ string ∪ myInt ∪ ~string ∪ int", "~int ∪ ~string"},
+		{"myInt ∪ int ∪ ~int", "~int"},
+		{"myInt ∪ string ∪ int ∪ ~int", "~int ∪ string"},
+		{"int ∪ myInt ∪ string ∪ ~string ∪ ~int", "~int ∪ ~string"},
 	} {
 		xl := maketl(test.xl)
 		got := maketl(test.xl).norm()
@@ -185,6 +188,11 @@ func TestTermlistIntersect(t *testing.T) {
 		{"~int ∪ myInt ∪ ∅", "~string ∪ int", "int"},
 		{"~int ∪ string ∪ 𝓤", "~string ∪ int", "int ∪ ~string"},
 		{"~int ∪ string ∪ myInt", "~string ∪ int", "int ∪ string"},
+		{"~int ∪ ~string", "myInt ∪ string", "myInt ∪ string"},
+		{"int ∪ myInt ∪ ~string", "~int ∪ string", "int ∪ myInt ∪ string"},
+		{"~int ∪ ~string", "~string ∪ ~int", "~int ∪ ~string"},
+		{"int ∪ string", "myInt ∪ ~string", "string"},
+		{"myInt ∪ int ∪ ~int", "~int ∪ string", "~int"},
 	} {
 		xl := maketl(test.xl)
 		yl := maketl(test.yl)
@@ -195,6 +203,51 @@ func TestTermlistIntersect(t *testing.T) {
 	}
 }
 
+// TestTermlistIntersectExact verifies for all pairs of small term lists
+// that the intersection is in normal form and contains exactly the types
+// contained in both operands.
+func TestTermlistIntersectExact(t *testing.T) {
+	var names []string
+	for name, x := range testTerms {
+		if x != nil && x.typ != nil {
+			names = append(names, name)
+		}
+	}
+	// all term lists with up to 3 terms
+	var lists []string
+	for _, n1 := range names {
+		lists = append(lists, n1)
+		for _, n2 := range names {
+			lists = append(lists, n1+" ∪ "+n2)
+			for _, n3 := range names {
+				lists = append(lists, n1+" ∪ "+n2+" ∪ "+n3)
+			}
+		}
+	}
+	types := []Type{Typ[Int], Typ[String], myInt, Typ[Bool]}
+
+	for _, xs := range lists {
+		xl := maketl(xs).norm()
+		for _, ys := range lists {
+			yl := maketl(ys).norm()
+			rl := xl.intersect(yl)
+			// rl is in normal form: its terms are pairwise disjoint
+			for i, x := range rl {
+				for _, y := range rl[i+1:] {
+					if !x.disjoint(y) {
+						t.Errorf("(%v).intersect(%v) = %v is not normalized", xs, ys, rl)
+					}
+				}
+			}
+			for _, typ := range types {
+				if got, want := rl.includes(typ), xl.includes(typ) && yl.includes(typ); got != want {
+					t.Errorf("(%v).intersect(%v) = %v: includes(%v) = %v; want %v", xs, ys, rl, typ, got, want)
+				}
+			}
+		}
+	}
+}
+
 func TestTermlistEqual(t *testing.T) {
 	for _, test := range []struct {
 		xl, yl string
@@ -47,13 +47,13 @@ func (check *Checker) NewTypeParam(obj *TypeName, bound Type) *TypeParam {
 	return typ
 }
 
-// TODO(rfindley): remove or export these placeholder APIs.
-
 // Index returns the index of the type param within its param list.
-func (t *TypeParam) _Index() int {
+func (t *TypeParam) Index() int {
 	return t.index
 }
 
+// TODO(rfindley): remove or export this placeholder API.
+
 // SetId sets the unique id of a type param. Should only be used for type params
 // in imported generic types.
 func (t *TypeParam) _SetId(id uint64) {
@@ -85,6 +85,17 @@ func (t *TypeParam) SetConstraint(bound Type) {
 	t.bound = bound
 }
 
+// Interface returns the interface of the type constraint of t, with
+// its type set computed. If the constraint is not an interface, the
+// result is the empty interface. The method set of t is the method
+// set of this interface, including the methods of embedded
+// constraints: NewMethodSet and LookupFieldOrMethod find exactly
+// those methods for operands of type t, and none for operands of
+// type *t.
+func (t *TypeParam) Interface() *Interface {
+	return t.iface()
+}
+
 func (t *TypeParam) Underlying() Type { return t }
 func (t *TypeParam) String() string   { return TypeString(t, nil) }
 
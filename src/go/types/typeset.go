@@ -17,6 +17,7 @@ import (
 // A _TypeSet represents the type set of an interface.
 type _TypeSet struct {
 	comparable bool // if set, the interface is or embeds comparable
+	partial    bool // if set, invalid embedded elements or union terms were ignored
 	// TODO(gri) consider using a set for the methods for faster lookup
 	methods []*Func  // all methods of the interface; sorted by unique ID
 	terms   termlist // type terms of the type set
@@ -266,26 +267,36 @@ func computeInterfaceTypeSet(check *Checker, pos token.Pos, ityp *Interface) *_T
 		case *Interface:
 			tset := computeInterfaceTypeSet(check, pos, u)
 			// If typ is local, an error was already reported where typ is specified/defined.
-			if check != nil && check.isImportedConstraint(typ) && !check.allowVersion(check.pkg, 1, 18) {
-				check.errorf(atPos(pos), _Todo, "embedding constraint interface %s requires go1.18 or later", typ)
-				continue
-			}
 			if tset.comparable {
 				ityp.tset.comparable = true
 			}
 			for _, m := range tset.methods {
 				addMethod(pos, m, false) // use embedding position pos rather than m.pos
 			}
+			if tset.partial {
+				ityp.tset.partial = true
+			}
+			if check != nil && check.isImportedConstraint(typ) && !check.allowVersion(check.pkg, 1, 18) {
+				check.errorf(atPos(pos), _Todo, "embedding constraint interface %s requires go1.18 or later", typ)
+				// keep the methods but ignore the type terms
+				ityp.tset.partial = true
+				continue
+			}
 			terms = tset.terms
 		case *Union:
 			if check != nil && !check.allowVersion(check.pkg, 1, 18) {
 				check.errorf(atPos(pos), _Todo, "embedding interface element %s requires go1.18 or later", u)
+				ityp.tset.partial = true
 				continue
 			}
 			tset := computeUnionTypeSet(check, pos, u)
 			if tset == &invalidTypeSet {
+				ityp.tset.partial = true
 				continue // ignore invalid unions
 			}
+			if tset.partial {
+				ityp.tset.partial = true
+			}
 			terms = tset.terms
 		case *TypeParam:
 			// Embedding stand-alone type parameters is not permitted.
@@ -293,10 +304,12 @@ func computeInterfaceTypeSet(check *Checker, pos token.Pos, ityp *Interface) *_T
 			unreachable()
 		default:
 			if typ == Typ[Invalid] {
+				ityp.tset.partial = true
 				continue
 			}
 			if check != nil && !check.allowVersion(check.pkg, 1, 18) {
 				check.errorf(atPos(pos), _InvalidIfaceEmbed, "embedding non-interface type %s requires go1.18 or later", typ)
+				ityp.tset.partial = true
 				continue
 			}
 			terms = termlist{{false, typ}}
@@ -318,6 +331,13 @@ func computeInterfaceTypeSet(check *Checker, pos token.Pos, ityp *Interface) *_T
 		}
 	}
 
+	// The type set of an interface is the intersection of the type sets of
+	// its methods and elements: a specific type (not a ~T term) is only in
+	// the type set if it has all methods.
+	if len(methods) > 0 && !allTerms.isAll() {
+		allTerms = filterMethodTerms(check, allTerms, methods)
+	}
+
 	if methods != nil {
 		sort.Sort(byUniqueMethodName(methods))
 		ityp.tset.methods = methods
@@ -327,6 +347,60 @@ func computeInterfaceTypeSet(check *Checker, pos token.Pos, ityp *Interface) *_T
 	return ityp.tset
 }
 
+// filterMethodTerms returns the terms of xl except for the specific types
+// which are known to miss (or have different) methods. xl is not modified.
+func filterMethodTerms(check *Checker, xl termlist, methods []*Func) termlist {
+	var rl termlist
+	for i, x := range xl {
+		if !x.tilde && lacksMethods(check, x.typ, methods) {
+			if rl == nil {
+				rl = append(termlist{}, xl[:i]...) // make sure rl != nil
+			}
+			continue
+		}
+		if rl != nil {
+			rl = append(rl, x)
+		}
+	}
+	if rl == nil {
+		return xl
+	}
+	return rl
+}
+
+// lacksMethods reports whether typ is known not to have all of the methods.
+// The type set of an interface may be computed before all methods of the
+// types declared in the package being checked are collected; thus, for
+// such types and for instantiated types the result is false.
+func lacksMethods(check *Checker, typ Type, methods []*Func) bool {
+	switch t := typ.(type) {
+	case *Basic, *Slice, *Array, *Map, *Chan, *Signature:
+		return true // no methods
+	case *Pointer:
+		_, named := t.base.(*Named)
+		_, isStruct := t.base.(*Struct)
+		return !named && !isStruct
+	case *Struct:
+		for _, f := range t.fields {
+			if f.embedded {
+				return false // may have promoted methods
+			}
+		}
+		return true
+	case *Named:
+		if check == nil || t.obj.pkg == check.pkg || t.targs.Len() > 0 {
+			return false
+		}
+		for _, m := range methods {
+			obj, _, _ := lookupFieldOrMethod(t, false, m.pkg, m.name)
+			if f, _ := obj.(*Func); f == nil || !Identical(f.typ, m.typ) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func sortMethods(list []*Func) {
 	sort.Sort(byUniqueMethodName(list))
 }
@@ -367,13 +441,18 @@ func computeUnionTypeSet(check *Checker, pos token.Pos, utyp *Union) *_TypeSet {
 		var terms termlist
 		switch u := under(t.typ).(type) {
 		case *Interface:
-			terms = computeInterfaceTypeSet(check, pos, u).terms
+			tset := computeInterfaceTypeSet(check, pos, u)
+			if tset.partial {
+				utyp.tset.partial = true
+			}
+			terms = tset.terms
 		case *TypeParam:
 			// A stand-alone type parameters is not permitted as union term.
 			// This case is handled during union parsing.
 			unreachable()
 		default:
 			if t.typ == Typ[Invalid] {
+				utyp.tset.partial = true
 				continue
 			}
 			terms = termlist{(*term)(t)}
@@ -25,6 +25,12 @@ func TestTypeSetString(t *testing.T) {
 		"{int|string}":  "{int ∪ string}",
 		"{int; string}": "∅",
 
+		// intersections of unions
+		"{~int|~string|float64; int|~string|~float64}":                                   "{int ∪ ~string ∪ float64}",
+		"{int|string|bool; string|bool|float64; ~string}":                                "{string}",
+		"{A; B}; type A interface{E|string}; type B interface{~int|~string}; type E int": "{p.E ∪ string}",
+		"{~int|~string; E|string|float64}; type E int":                                   "{p.E ∪ string}",
+
 		"{comparable}":              "{comparable}",
 		"{comparable; int}":         "{comparable; int}",
 		"{~int; comparable}":        "{comparable; ~int}",
@@ -38,8 +44,16 @@ func TestTypeSetString(t *testing.T) {
 		"{m1(); comparable; m2() int }": "{comparable; func (p.T).m1(); func (p.T).m2() int}",
 		"{comparable; error}":           "{comparable; func (error).Error() string}",
 
-		"{m(); comparable; int|float32|string}": "{comparable; func (p.T).m(); int ∪ float32 ∪ string}",
-		"{m1(); int; m2(); comparable }":        "{comparable; func (p.T).m1(); func (p.T).m2(); int}",
+		"{m(); comparable; ~int|~float32|~string}": "{comparable; func (p.T).m(); ~int ∪ ~float32 ∪ ~string}",
+		"{m1(); ~int; m2(); comparable }":          "{comparable; func (p.T).m1(); func (p.T).m2(); ~int}",
+
+		// specific types without the required methods are not in the type set
+		"{m(); int}":                            "∅",
+		"{m(); comparable; int|float32|string}": "∅",
+		"{m(); ~int|float32|~string|[]byte}":    "{func (p.T).m(); ~int ∪ ~string}",
+		"{m(); struct{}|struct{E}}; type E int": "{func (p.T).m(); struct{p.E}}",
+		"{m(); *int|*E}; type E int":            "{func (p.T).m(); *p.E}",
+		"{m(); E}; type E int":                  "{func (p.T).m(); p.E}", // methods of E may not be known yet
 
 		"{E}; type E interface{}":           "𝓤",
 		"{E}; type E interface{int;string}": "∅",
@@ -63,6 +63,27 @@ type Flags struct {
 	// experiment.
 	Unified bool
 
+	// OperatorConstraints enables the predeclared constraint
+	// "ordered", which permits the ordering operators on values
+	// of type parameters constrained by it.
+	OperatorConstraints bool
+
+	// DefaultTypeArgs enables default types for type parameters,
+	// as in [K comparable, V any = int], which are used for type
+	// arguments that are omitted and cannot be inferred.
+	DefaultTypeArgs bool
+
+	// MethodTypeParams permits methods to declare their own type
+	// parameters. Such methods are type-checked only; the compiler
+	// cannot generate code for them yet.
+	MethodTypeParams bool
+
+	// AliasTypeParams permits alias declarations to declare type
+	// parameters, as in type A[P any] = []P. Such aliases are
+	// type-checked only; the compiler cannot generate code for
+	// packages declaring them yet.
+	AliasTypeParams bool
+
 	// Regabi is split into several sub-experiments that can be
 	// enabled individually. Not all combinations work.
 	// The "regabi" GOEXPERIMENT is an alias for all "working"
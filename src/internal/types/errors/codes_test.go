@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package types_test
+package errors_test
 
 import (
 	"fmt"
@@ -11,11 +11,10 @@ import (
 	"go/importer"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"reflect"
 	"strings"
 	"testing"
-
-	. "go/types"
 )
 
 func TestErrorCodeExamples(t *testing.T) {
@@ -29,7 +28,7 @@ func TestErrorCodeExamples(t *testing.T) {
 				if err == nil {
 					t.Fatalf("no error in example #%d", i)
 				}
-				typerr, ok := err.(Error)
+				typerr, ok := err.(types.Error)
 				if !ok {
 					t.Fatalf("not a types.Error: %v", err)
 				}
@@ -44,17 +43,18 @@ func TestErrorCodeExamples(t *testing.T) {
 func walkCodes(t *testing.T, f func(string, int, *ast.ValueSpec)) {
 	t.Helper()
 	fset := token.NewFileSet()
-	files, err := pkgFiles(fset, ".", parser.ParseComments) // from self_test.go
+	file, err := parser.ParseFile(fset, "codes.go", nil, parser.ParseComments)
 	if err != nil {
 		t.Fatal(err)
 	}
-	conf := Config{Importer: importer.Default()}
-	info := &Info{
-		Types: make(map[ast.Expr]TypeAndValue),
-		Defs:  make(map[*ast.Ident]Object),
-		Uses:  make(map[*ast.Ident]Object),
+	files := []*ast.File{file}
+	conf := types.Config{Importer: importer.Default()}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
 	}
-	_, err = conf.Check("types", fset, files, info)
+	_, err = conf.Check("errors", fset, files, info)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -70,12 +70,12 @@ func walkCodes(t *testing.T, f func(string, int, *ast.ValueSpec)) {
 					continue
 				}
 				obj := info.ObjectOf(spec.Names[0])
-				if named, ok := obj.Type().(*Named); ok && named.Obj().Name() == "errorCode" {
+				if named, ok := obj.Type().(*types.Named); ok && named.Obj().Name() == "Code" {
 					if len(spec.Names) != 1 {
 						t.Fatalf("bad Code declaration for %q: got %d names, want exactly 1", spec.Names[0].Name, len(spec.Names))
 					}
 					codename := spec.Names[0].Name
-					value := int(constant.Val(obj.(*Const).Val()).(int64))
+					value := int(constant.Val(obj.(*types.Const).Val()).(int64))
 					f(codename, value, spec)
 				}
 			}
@@ -83,7 +83,7 @@ func walkCodes(t *testing.T, f func(string, int, *ast.ValueSpec)) {
 	}
 }
 
-func readCode(err Error) int {
+func readCode(err types.Error) int {
 	v := reflect.ValueOf(err)
 	return int(v.FieldByName("go116code").Int())
 }
@@ -96,7 +96,7 @@ func checkExample(t *testing.T, example string) error {
 	if err != nil {
 		t.Fatal(err)
 	}
-	conf := Config{
+	conf := types.Config{
 		FakeImportC: true,
 		Importer:    importer.Default(),
 	}
@@ -156,13 +156,8 @@ func TestErrorCodeStyle(t *testing.T) {
 		if len(name) > len(longestName) {
 			longestName = name
 		}
-		if token.IsExported(name) {
-			// This is an experimental API, and errorCode values should not be
-			// exported.
-			t.Errorf("%q is exported", name)
-		}
-		if name[0] != '_' || !token.IsExported(name[1:]) {
-			t.Errorf("%q should start with _, followed by an exported identifier", name)
+		if !token.IsExported(name) {
+			t.Errorf("%q should be exported", name)
 		}
 		lower := strings.ToLower(name)
 		for _, bad := range forbiddenInIdent {
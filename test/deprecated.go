@@ -0,0 +1,38 @@
+// errorcheck -0 -d=deprecated
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Check that -d=deprecated reports uses of deprecated objects.
+
+package p
+
+// Deprecated: Use New instead.
+const Old = 0
+
+const New = 1
+
+// Deprecated: Use U.
+const (
+	A = 2
+)
+
+// T is a type.
+//
+// Deprecated: Do not use T,
+// it is obsolete.
+type T struct{}
+
+// Deprecated: Use N.
+func (T) M() {} // ERROR "T is deprecated"
+
+func (T) N() {} // ERROR "T is deprecated"
+
+func _() {
+	_ = Old + New // ERROR "Old is deprecated: Use New instead."
+	_ = A         // ERROR "A is deprecated: Use U."
+	var t T       // ERROR "T is deprecated: Do not use T, it is obsolete."
+	t.M()         // ERROR "M is deprecated: Use N."
+	t.N()
+}
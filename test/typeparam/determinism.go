@@ -0,0 +1,48 @@
+// compile -G=3 -d=checkdeterminism
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Type-check generic code with embedded interfaces
+// and inferred type arguments twice, and compare the results.
+
+package p
+
+type I interface{ m() }
+
+type J interface {
+	I
+	n()
+}
+
+type K interface {
+	~int | ~string
+	J
+}
+
+type T int
+
+func (T) m() {}
+func (T) n() {}
+
+func f[P K](x P) { x.m(); x.n() }
+
+type List[E any] struct {
+	next *List[E]
+	val  E
+}
+
+func (l *List[E]) Push(v E) *List[E] { return &List[E]{l, v} }
+
+func Map[E, F any](l *List[E], fn func(E) F) *List[F] {
+	var r *List[F]
+	for ; l != nil; l = l.next {
+		r = r.Push(fn(l.val))
+	}
+	return r
+}
+
+var _ = Map(new(List[int]), func(i int) string { return "" })
+
+func g() { f(T(0)) }
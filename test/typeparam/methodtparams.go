@@ -0,0 +1,21 @@
+// errorcheck -G=3 -goexperiment methodtypeparams
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that methods with type parameters, which are only available
+// with GOEXPERIMENT=methodtypeparams, are type-checked but not compiled.
+
+package p
+
+type S struct{}
+
+func (S) Map[T any](x T) T { return x } // ERROR "cannot compile method Map with type parameters"
+
+type L[E any] []E
+
+func (l L[E]) Map[F any](f func(E) F) L[F] { return nil } // ERROR "cannot compile method Map with type parameters"
+
+var _ int = S{}.Map(1)
+var _ L[string] = L[int]{}.Map(func(int) string { return "" })
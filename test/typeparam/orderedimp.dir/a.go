@@ -0,0 +1,27 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package a
+
+type Ordered interface {
+	ordered
+}
+
+func Max[T ordered](x, y T) T {
+	if x < y {
+		return y
+	}
+	return x
+}
+
+type Pair[T Ordered] struct {
+	X, Y T
+}
+
+func (p Pair[T]) Min() T {
+	if p.X < p.Y {
+		return p.X
+	}
+	return p.Y
+}
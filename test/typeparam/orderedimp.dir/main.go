@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// Don't import any std packages: they are installed without
+// GOEXPERIMENT=operatorconstraints.
+import "a"
+
+type myString string
+
+func main() {
+	if got, want := a.Max(1, 2), 2; got != want {
+		panic(got)
+	}
+	if got, want := a.Max[myString]("b", "a"), myString("b"); got != want {
+		panic(got)
+	}
+	if got, want := (a.Pair[float64]{2.5, -1}).Min(), -1.0; got != want {
+		panic(got)
+	}
+}
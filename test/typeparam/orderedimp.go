@@ -0,0 +1,11 @@
+// compiledir -G=3 -goexperiment operatorconstraints
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test importing generic code constrained by the predeclared
+// constraint ordered. The installed runtime isn't built with
+// GOEXPERIMENT=operatorconstraints, so don't link.
+
+package ignored
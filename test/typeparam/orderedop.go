@@ -0,0 +1,60 @@
+// run -gcflags=-G=3 -goexperiment operatorconstraints
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the predeclared constraint ordered, which is only
+// available with GOEXPERIMENT=operatorconstraints.
+
+package main
+
+import "fmt"
+
+type myString string
+
+func min[T ordered](x, y T) T {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+func max[T ordered](x, y T) T {
+	if min(x, y) == x {
+		return y
+	}
+	return x
+}
+
+type pair[T ordered] struct {
+	lo, hi T
+}
+
+func makePair[T ordered](x, y T) pair[T] {
+	if x > y {
+		x, y = y, x
+	}
+	return pair[T]{x, y}
+}
+
+func (p pair[T]) contains(x T) bool {
+	return p.lo <= x && x <= p.hi
+}
+
+func main() {
+	if got, want := min(3, 2), 2; got != want {
+		panic(fmt.Sprintf("got %d, want %d", got, want))
+	}
+	if got, want := max(1.5, -2.5), 1.5; got != want {
+		panic(fmt.Sprintf("got %v, want %v", got, want))
+	}
+	if got, want := max[myString]("a", "b"), myString("b"); got != want {
+		panic(fmt.Sprintf("got %q, want %q", got, want))
+	}
+
+	p := makePair(10, 5)
+	if !p.contains(7) || p.contains(11) {
+		panic(fmt.Sprintf("bad pair %v", p))
+	}
+}
@@ -0,0 +1,62 @@
+// run -gcflags=-G=3 -goexperiment defaulttypeargs
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test default type arguments, which are only available
+// with GOEXPERIMENT=defaulttypeargs.
+
+package main
+
+import "fmt"
+
+type Map[K comparable, V any = int] map[K]V
+
+func (m Map[K, V]) Keys() []K {
+	var keys []K
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type List[T any, S ~[]T = []T] struct {
+	elems S
+}
+
+func (l *List[T, S]) Push(x T) {
+	l.elems = append(l.elems, x)
+}
+
+func Zero[T any, R any = T](T) R {
+	var r R
+	return r
+}
+
+func main() {
+	m := Map[string]{"a": 1}
+	m["b"] = 2
+	if got := fmt.Sprintf("%T", m); got != "main.Map[string,int]" {
+		panic(fmt.Sprintf("got type %s", got))
+	}
+	if got := len(m.Keys()); got != 2 {
+		panic(fmt.Sprintf("got %d keys, want 2", got))
+	}
+
+	var l List[byte]
+	l.Push('x')
+	if got := string(l.elems); got != "x" {
+		panic(fmt.Sprintf("got %q, want %q", got, "x"))
+	}
+
+	if got := Zero(1.5); got != 0 {
+		panic(fmt.Sprintf("got %v, want 0", got))
+	}
+	if got := Zero[int](1); got != 0 {
+		panic(fmt.Sprintf("got %v, want 0", got))
+	}
+	if got := Zero[int, string](1); got != "" {
+		panic(fmt.Sprintf("got %q, want empty string", got))
+	}
+}
@@ -0,0 +1,18 @@
+// compile -G=3 -d=typeddump=List.Push
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Print the typed syntax tree of a generic method.
+
+package p
+
+type List[E any] struct {
+	next *List[E]
+	val  E
+}
+
+func (l *List[E]) Push(v E) *List[E] { return &List[E]{l, v} }
+
+var _ = new(List[int]).Push(1)